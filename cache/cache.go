@@ -36,9 +36,13 @@ type Client interface {
 	IStaking
 	IReceipts
 	IDashboard
+	IStream
+	IGas
+	IRateLimit
 
 	InsertBlock(ctx context.Context, block *types.Block) error
 	InsertTxsOfBlock(ctx context.Context, block *types.Block) error
+	DeleteBlocksFromHeight(ctx context.Context, height uint64) error
 	BlockByHeight(ctx context.Context, blockHeight uint64) (*types.Block, error)
 	BlockByHash(ctx context.Context, blockHash string) (*types.Block, error)
 	TxsByBlockHash(ctx context.Context, blockHash string, pagination *types.Pagination) ([]*types.Transaction, uint64, error)
@@ -71,6 +75,7 @@ type Client interface {
 
 	SMCAbi(ctx context.Context, key string) (string, error)
 	UpdateSMCAbi(ctx context.Context, key, abi string) error
+	RemoveSMCAbi(ctx context.Context, key string) error
 
 	KRCTokenInfo(ctx context.Context, krcTokenAddr string) (*types.KRCTokenInfo, error)
 	UpdateKRCTokenInfo(ctx context.Context, krcTokenInfo *types.KRCTokenInfo) error
@@ -78,6 +83,8 @@ type Client interface {
 	AddressInfo(ctx context.Context, addr string) (*types.Address, error)
 	UpdateAddressInfo(ctx context.Context, addrInfo *types.Address) error
 
+	// Ping check Redis is reachable
+	Ping(ctx context.Context) error
 	ServerStatus(ctx context.Context) (*types.ServerStatus, error)
 	UpdateServerStatus(ctx context.Context, serverStatus *types.ServerStatus) error
 
@@ -99,6 +106,8 @@ func newRedis(cfg Config) (Client, error) {
 		DB:   cfg.DB,
 	})
 
+	redisClient.AddHook(tracingHook{})
+
 	if _, err := redisClient.Ping(context.Background()).Result(); err != nil {
 		return nil, err
 	}
@@ -117,3 +126,27 @@ func newRedis(cfg Config) (Client, error) {
 	client.cfg = cfg
 	return client, nil
 }
+
+// queues are the work lists exported as queue_length gauges
+var queues = map[string]string{
+	"error_blocks":            KeyErrorBlocks,
+	"persistent_error_blocks": KeyPersistentErrorBlocks,
+	"unverified_blocks":       KeyUnverifiedBlocks,
+	"pending_receipts":        KeyPendingReceipts,
+	"bad_receipts":            KeyBadReceipts,
+	"trace_retries":           KeyTraceRetries,
+}
+
+// QueueLengths return length of work queues by name, queues which cannot be read are omitted
+func QueueLengths(ctx context.Context, c Client) map[string]int64 {
+	lengths := make(map[string]int64, len(queues))
+	for name, key := range queues {
+		length, err := c.ListSize(ctx, key)
+		// ListSize report empty lists with a zero length and an error
+		if err != nil && length != 0 {
+			continue
+		}
+		lengths[name] = length
+	}
+	return lengths
+}
@@ -0,0 +1,70 @@
+// Package cache
+package cache
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+const (
+	KeyGasOracle       = "#gas#oracle"
+	KeyBlockGasHistory = "#gas#blocks" // List
+)
+
+type IGas interface {
+	// PushBlockGasStats add stats to rolling history, which keep only `size` latest blocks
+	PushBlockGasStats(ctx context.Context, stats *types.BlockGasStats, size int64) error
+	BlockGasHistory(ctx context.Context) ([]*types.BlockGasStats, error)
+	UpdateGasOracle(ctx context.Context, oracle *types.GasOracle) error
+	GasOracle(ctx context.Context) (*types.GasOracle, error)
+}
+
+func (c *Redis) PushBlockGasStats(ctx context.Context, stats *types.BlockGasStats, size int64) error {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	if err := c.client.LPush(ctx, KeyBlockGasHistory, data).Err(); err != nil {
+		return err
+	}
+	return c.client.LTrim(ctx, KeyBlockGasHistory, 0, size-1).Err()
+}
+
+// BlockGasHistory return stats of blocks in history, latest first
+func (c *Redis) BlockGasHistory(ctx context.Context) ([]*types.BlockGasStats, error) {
+	items, err := c.client.LRange(ctx, KeyBlockGasHistory, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	history := make([]*types.BlockGasStats, 0, len(items))
+	for _, item := range items {
+		var stats types.BlockGasStats
+		if err := json.Unmarshal([]byte(item), &stats); err != nil {
+			return nil, err
+		}
+		history = append(history, &stats)
+	}
+	return history, nil
+}
+
+func (c *Redis) UpdateGasOracle(ctx context.Context, oracle *types.GasOracle) error {
+	data, err := json.Marshal(oracle)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, KeyGasOracle, data, 0).Err()
+}
+
+func (c *Redis) GasOracle(ctx context.Context) (*types.GasOracle, error) {
+	result, err := c.client.Get(ctx, KeyGasOracle).Result()
+	if err != nil {
+		return nil, err
+	}
+	var oracle types.GasOracle
+	if err := json.Unmarshal([]byte(result), &oracle); err != nil {
+		return nil, err
+	}
+	return &oracle, nil
+}
@@ -0,0 +1,147 @@
+// Package cache
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+const (
+	KeyRateLimit  = "#ratelimit#%s"      // GCRA theoretical arrival time of a bucket
+	KeyUsage      = "#usage#%s#%s"       // Hash of requests per endpoint of a subject in a day
+	KeyUsageTotal = "#usage#total#%s#%s" // Requests of a subject in a day
+	KeyAPIKey     = "#apikey#%s"         // API key by hash
+
+	usageDayLayout = "20060102"
+	usageRetention = 90 * 24 * time.Hour
+	apiKeyTTL      = 5 * time.Minute
+	// unknown keys are remembered shortly, so random keys do not reach database
+	missingAPIKeyTTL = time.Minute
+)
+
+// gcraScript implement generic cell rate algorithm, a request is accepted when theoretical arrival time
+// stay within burst tolerance. Returns allowed flag, remaining requests and retry after in milliseconds.
+var gcraScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local tolerance = interval * tonumber(ARGV[3])
+local tat = tonumber(redis.call("GET", KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+local newTat = tat + interval
+local diff = newTat - now
+if diff > tolerance then
+	return {0, 0, diff - tolerance}
+end
+redis.call("SET", KEYS[1], newTat, "PX", math.ceil(diff))
+return {1, math.floor((tolerance - diff) / interval), 0}
+`)
+
+type IRateLimit interface {
+	// RateLimit take a request from bucket, buckets are shared by every API replica
+	RateLimit(ctx context.Context, bucket string, tier *types.RateLimitTier, now time.Time) (*types.RateLimitResult, error)
+	// IncrUsage count a request of subject to endpoint and return requests of subject in the day
+	IncrUsage(ctx context.Context, subject, endpoint string, now time.Time) (int64, error)
+	Usage(ctx context.Context, subject string, day time.Time) ([]*types.EndpointUsage, error)
+
+	// APIKey return cached key by hash, nil key is cached for unknown keys
+	APIKey(ctx context.Context, keyHash string) (*types.APIKey, bool, error)
+	SetAPIKey(ctx context.Context, keyHash string, key *types.APIKey) error
+	DeleteAPIKey(ctx context.Context, keyHash string) error
+}
+
+func (c *Redis) RateLimit(ctx context.Context, bucket string, tier *types.RateLimitTier, now time.Time) (*types.RateLimitResult, error) {
+	interval := time.Minute.Milliseconds() / int64(tier.RequestsPerMinute)
+	burst := tier.Burst
+	if burst < 1 {
+		burst = 1
+	}
+	result, err := gcraScript.Run(ctx, c.client, []string{fmt.Sprintf(KeyRateLimit, bucket)},
+		now.UnixNano()/int64(time.Millisecond), interval, burst).Result()
+	if err != nil {
+		return nil, err
+	}
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 3 {
+		return nil, fmt.Errorf("unexpected rate limit result %v", result)
+	}
+	allowed, _ := values[0].(int64)
+	remaining, _ := values[1].(int64)
+	retryAfter, _ := values[2].(int64)
+	return &types.RateLimitResult{
+		Allowed:    allowed == 1,
+		Limit:      tier.RequestsPerMinute,
+		Remaining:  int(remaining),
+		RetryAfter: time.Duration(retryAfter) * time.Millisecond,
+	}, nil
+}
+
+func (c *Redis) IncrUsage(ctx context.Context, subject, endpoint string, now time.Time) (int64, error) {
+	day := now.UTC().Format(usageDayLayout)
+	usageKey := fmt.Sprintf(KeyUsage, subject, day)
+	totalKey := fmt.Sprintf(KeyUsageTotal, subject, day)
+	pipe := c.client.TxPipeline()
+	pipe.HIncrBy(ctx, usageKey, endpoint, 1)
+	pipe.Expire(ctx, usageKey, usageRetention)
+	total := pipe.Incr(ctx, totalKey)
+	pipe.Expire(ctx, totalKey, 48*time.Hour)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return total.Val(), nil
+}
+
+func (c *Redis) Usage(ctx context.Context, subject string, day time.Time) ([]*types.EndpointUsage, error) {
+	dayStr := day.UTC().Format(usageDayLayout)
+	result, err := c.client.HGetAll(ctx, fmt.Sprintf(KeyUsage, subject, dayStr)).Result()
+	if err != nil {
+		return nil, err
+	}
+	usage := make([]*types.EndpointUsage, 0, len(result))
+	for endpoint, value := range result {
+		var requests int64
+		if _, err := fmt.Sscan(value, &requests); err != nil {
+			return nil, err
+		}
+		usage = append(usage, &types.EndpointUsage{Day: dayStr, Endpoint: endpoint, Requests: requests})
+	}
+	return usage, nil
+}
+
+func (c *Redis) APIKey(ctx context.Context, keyHash string) (*types.APIKey, bool, error) {
+	result, err := c.client.Get(ctx, fmt.Sprintf(KeyAPIKey, keyHash)).Result()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var key *types.APIKey
+	if err := json.Unmarshal([]byte(result), &key); err != nil {
+		return nil, false, err
+	}
+	return key, true, nil
+}
+
+func (c *Redis) SetAPIKey(ctx context.Context, keyHash string, key *types.APIKey) error {
+	data, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	ttl := apiKeyTTL
+	if key == nil {
+		ttl = missingAPIKeyTTL
+	}
+	return c.client.Set(ctx, fmt.Sprintf(KeyAPIKey, keyHash), data, ttl).Err()
+}
+
+func (c *Redis) DeleteAPIKey(ctx context.Context, keyHash string) error {
+	return c.client.Del(ctx, fmt.Sprintf(KeyAPIKey, keyHash)).Err()
+}
@@ -8,6 +8,8 @@ import (
 const (
 	KeyPendingReceipts = "receipts#pending"
 	KeyBadReceipts     = "receipts#bad"
+	// KeyTraceRetries hold hashes of txs whose internal calls could not be traced
+	KeyTraceRetries = "receipts#trace_retries"
 )
 
 type IReceipts interface {
@@ -15,6 +17,8 @@ type IReceipts interface {
 	PopReceipt(ctx context.Context) (string, error)
 	PushBadReceipts(ctx context.Context, hashes []string) error
 	PopBadReceipt(ctx context.Context) (string, error)
+	PushTraceRetries(ctx context.Context, hashes []string) error
+	PopTraceRetry(ctx context.Context) (string, error)
 }
 
 func (c *Redis) PushReceipts(ctx context.Context, hashes []string) error {
@@ -58,3 +62,25 @@ func (c *Redis) PopBadReceipt(ctx context.Context) (string, error) {
 
 	return hash, nil
 }
+
+// PushTraceRetries append hashes so they are retried after the ones already waiting
+func (c *Redis) PushTraceRetries(ctx context.Context, hashes []string) error {
+	var insertList []interface{}
+	for _, h := range hashes {
+		insertList = append(insertList, h)
+	}
+	_, err := c.client.RPush(ctx, KeyTraceRetries, insertList...).Result()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Redis) PopTraceRetry(ctx context.Context) (string, error) {
+	hash, err := c.client.LPop(ctx, KeyTraceRetries).Result()
+	if err != nil {
+		return "", err
+	}
+
+	return hash, nil
+}
@@ -283,6 +283,54 @@ func (c *Redis) InsertBlock(ctx context.Context, block *types.Block) error {
 	return nil
 }
 
+// DeleteBlocksFromHeight evicts every cached block, and its transactions, whose height is
+// greater than or equal to `height`. It is used to drop orphaned blocks after a chain reorg.
+func (c *Redis) DeleteBlocksFromHeight(ctx context.Context, height uint64) error {
+	blockStrList, err := c.client.LRange(ctx, KeyBlocks, 0, -1).Result()
+	if err != nil {
+		return err
+	}
+	for _, blockStr := range blockStrList {
+		var block types.Block
+		if err := json.Unmarshal([]byte(blockStr), &block); err != nil {
+			return err
+		}
+		if block.Height < height {
+			continue
+		}
+		if err := c.client.LRem(ctx, KeyBlocks, 0, blockStr).Err(); err != nil {
+			return err
+		}
+		if err := c.deleteKeysOfBlock(ctx, &block); err != nil {
+			return err
+		}
+	}
+
+	txStrList, err := c.client.LRange(ctx, KeyLatestTxs, 0, -1).Result()
+	if err != nil {
+		return err
+	}
+	for _, txStr := range txStrList {
+		var tx types.Transaction
+		if err := json.Unmarshal([]byte(txStr), &tx); err != nil {
+			return err
+		}
+		if tx.BlockNumber < height {
+			continue
+		}
+		if err := c.client.LRem(ctx, KeyLatestTxs, 0, txStr).Err(); err != nil {
+			return err
+		}
+	}
+
+	if c.LatestBlockHeight(ctx) >= height {
+		if err := c.client.Set(ctx, KeyLatestBlockHeight, height-1, 0).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *Redis) BlockByHash(ctx context.Context, blockHash string) (*types.Block, error) {
 	return c.getBlockInCache(ctx, 0, blockHash)
 }
@@ -555,6 +603,15 @@ func (c *Redis) UpdateSMCAbi(ctx context.Context, key, abi string) error {
 	return nil
 }
 
+// RemoveSMCAbi drop cached abi, used when a proxy is upgraded to another implementation
+func (c *Redis) RemoveSMCAbi(ctx context.Context, key string) error {
+	keyABI := fmt.Sprintf(KeyContractABI, key)
+	if _, err := c.client.Del(ctx, keyABI).Result(); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (c *Redis) KRCTokenInfo(ctx context.Context, krcTokenAddr string) (*types.KRCTokenInfo, error) {
 	keyKRC := fmt.Sprintf(KeyKRCTokenInfo, krcTokenAddr)
 	result, err := c.client.Get(ctx, keyKRC).Result()
@@ -616,6 +673,10 @@ func (c *Redis) UpdateServerStatus(ctx context.Context, serverStatus *types.Serv
 	return nil
 }
 
+func (c *Redis) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
 func (c *Redis) ServerStatus(ctx context.Context) (*types.ServerStatus, error) {
 	result, err := c.client.Get(ctx, KeyServerStatus).Result()
 	if err != nil {
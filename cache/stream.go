@@ -0,0 +1,68 @@
+// Package cache
+package cache
+
+import (
+	"context"
+	"encoding/json"
+
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+const (
+	ChannelStreamEvents = "#stream#events" // PubSub
+)
+
+type IStream interface {
+	PublishStreamEvent(ctx context.Context, event *types.StreamEvent) error
+	SubscribeStreamEvents(ctx context.Context) (<-chan *types.StreamEvent, error)
+}
+
+func (c *Redis) PublishStreamEvent(ctx context.Context, event *types.StreamEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return c.client.Publish(ctx, ChannelStreamEvents, data).Err()
+}
+
+// SubscribeStreamEvents return a channel of published events, it's closed when ctx is done
+func (c *Redis) SubscribeStreamEvents(ctx context.Context) (<-chan *types.StreamEvent, error) {
+	pubSub := c.client.Subscribe(ctx, ChannelStreamEvents)
+	// wait for confirmation that subscription is created
+	if _, err := pubSub.Receive(ctx); err != nil {
+		return nil, err
+	}
+	events := make(chan *types.StreamEvent)
+	go func() {
+		defer close(events)
+		defer func() {
+			if err := pubSub.Close(); err != nil {
+				c.logger.Warn("cannot close stream subscription", zap.Error(err))
+			}
+		}()
+		msgCh := pubSub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-msgCh:
+				if !ok {
+					return
+				}
+				var event types.StreamEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					c.logger.Warn("cannot unmarshal stream event", zap.Error(err))
+					continue
+				}
+				select {
+				case events <- &event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
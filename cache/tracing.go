@@ -0,0 +1,60 @@
+// Package cache
+package cache
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/kardiachain/kardia-explorer-backend/tracing"
+)
+
+// tracingHook trace every Redis command as a child span of the command context
+type tracingHook struct{}
+
+func (tracingHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	ctx, _ = tracing.Start(ctx, "redis."+cmd.Name(),
+		label.String("db.system", "redis"),
+		label.String("db.operation", cmd.Name()),
+	)
+	return ctx, nil
+}
+
+func (tracingHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	tracing.End(ctx, trace.SpanFromContext(ctx), cmdErr(cmd))
+	return nil
+}
+
+func (tracingHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	names := make([]string, len(cmds))
+	for i, cmd := range cmds {
+		names[i] = cmd.Name()
+	}
+	ctx, _ = tracing.Start(ctx, "redis.pipeline",
+		label.String("db.system", "redis"),
+		label.String("db.operation", strings.Join(names, " ")),
+	)
+	return ctx, nil
+}
+
+func (tracingHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if err = cmdErr(cmd); err != nil {
+			break
+		}
+	}
+	tracing.End(ctx, trace.SpanFromContext(ctx), err)
+	return nil
+}
+
+// cmdErr return error of cmd, missing keys are not errors
+func cmdErr(cmd redis.Cmder) error {
+	if err := cmd.Err(); err != nil && err != redis.Nil {
+		return err
+	}
+	return nil
+}
@@ -37,6 +37,12 @@ type ExplorerConfig struct {
 	ServerMode        string
 	Port              string
 	HttpRequestSecret string
+	MetricsAddr       string
+
+	AdminTokenSecret string
+	AdminTokenTTL    time.Duration
+	AdminUsername    string
+	AdminPassword    string
 
 	LogLevel string
 
@@ -73,9 +79,45 @@ type ExplorerConfig struct {
 	ListenerInterval time.Duration
 	BackfillInterval time.Duration
 	VerifierInterval time.Duration
+	RollupInterval   time.Duration
+
+	GasOracleBlocks      int
+	GasOracleMinGasPrice uint64
+
+	// GenesisFile is the node config with the genesis section, which seed opening balances of the balance ledger
+	GenesisFile string
+
+	MarketRefreshInterval time.Duration
+	MarketCMCAPIKey       string
+	MarketCMCID           int
+	MarketCoinGeckoID     string
+	MarketWKAIAddress     string
+
+	RateLimitEnabled             bool
+	RateLimitAnonymousRPM        int
+	RateLimitAnonymousBurst      int
+	RateLimitAnonymousDailyQuota int64
+
+	GraphQLMaxDepth      int
+	GraphQLMaxComplexity int
+
+	TracingEndpoint    string
+	TracingInsecure    bool
+	TracingSampleRatio float64
+
+	HealthMaxBlockLag uint64
+	HealthMaxBlockAge time.Duration
 
 	VerifyBlockParam *types.VerifyBlockParam
 
+	IndexerFromHeight uint64
+	IndexerToHeight   uint64
+	IndexerWorkers    int
+	IndexerBatchSize  int
+
+	SolcCacheDir  string
+	SolcBinaryURL string
+
 	AwsAccessKeyId     string
 	AwsSecretAccessKey string
 	AwsSecretRegion    string
@@ -175,6 +217,11 @@ func New() (ExplorerConfig, error) {
 	if err != nil {
 		verifierInterval = 2 * time.Second
 	}
+	rollupIntervalStr := os.Getenv("ROLLUP_INTERVAL")
+	rollupInterval, err := time.ParseDuration(rollupIntervalStr)
+	if err != nil {
+		rollupInterval = 1 * time.Minute
+	}
 
 	storageMinConnStr := os.Getenv("STORAGE_MIN_CONN")
 	storageMinConn, err := strconv.Atoi(storageMinConnStr)
@@ -204,6 +251,140 @@ func New() (ExplorerConfig, error) {
 	if err != nil {
 		verifyBlockHash = true
 	}
+	verifyParentHashStr := os.Getenv("VERIFY_PARENT_HASH")
+	verifyParentHash, err := strconv.ParseBool(verifyParentHashStr)
+	if err != nil {
+		verifyParentHash = false
+	}
+	verifyReceiptsRootStr := os.Getenv("VERIFY_RECEIPTS_ROOT")
+	verifyReceiptsRoot, err := strconv.ParseBool(verifyReceiptsRootStr)
+	if err != nil {
+		verifyReceiptsRoot = false
+	}
+	verifyGasUsedStr := os.Getenv("VERIFY_GAS_USED")
+	verifyGasUsed, err := strconv.ParseBool(verifyGasUsedStr)
+	if err != nil {
+		verifyGasUsed = false
+	}
+	verifyTxHashesStr := os.Getenv("VERIFY_TX_HASHES")
+	verifyTxHashes, err := strconv.ParseBool(verifyTxHashesStr)
+	if err != nil {
+		verifyTxHashes = false
+	}
+	verifyReceiptsStr := os.Getenv("VERIFY_RECEIPTS")
+	verifyReceipts, err := strconv.ParseBool(verifyReceiptsStr)
+	if err != nil {
+		verifyReceipts = false
+	}
+
+	gasOracleBlocksStr := os.Getenv("GAS_ORACLE_BLOCKS")
+	gasOracleBlocks, err := strconv.Atoi(gasOracleBlocksStr)
+	if err != nil || gasOracleBlocks <= 0 {
+		gasOracleBlocks = 200
+	}
+	gasOracleMinGasPriceStr := os.Getenv("GAS_ORACLE_MIN_GAS_PRICE")
+	gasOracleMinGasPrice, err := strconv.ParseUint(gasOracleMinGasPriceStr, 10, 64)
+	if err != nil {
+		// 1 OXY, minimum gas price accepted by network
+		gasOracleMinGasPrice = 1000000000
+	}
+
+	marketRefreshIntervalStr := os.Getenv("MARKET_REFRESH_INTERVAL")
+	marketRefreshInterval, err := time.ParseDuration(marketRefreshIntervalStr)
+	if err != nil || marketRefreshInterval <= 0 {
+		marketRefreshInterval = 5 * time.Minute
+	}
+	marketCMCIDStr := os.Getenv("MARKET_CMC_ID")
+	marketCMCID, err := strconv.Atoi(marketCMCIDStr)
+	if err != nil {
+		marketCMCID = 0
+	}
+
+	adminTokenTTLStr := os.Getenv("ADMIN_TOKEN_TTL")
+	adminTokenTTL, err := time.ParseDuration(adminTokenTTLStr)
+	if err != nil || adminTokenTTL <= 0 {
+		adminTokenTTL = time.Hour
+	}
+
+	rateLimitEnabledStr := os.Getenv("RATE_LIMIT_ENABLED")
+	rateLimitEnabled, err := strconv.ParseBool(rateLimitEnabledStr)
+	if err != nil {
+		rateLimitEnabled = true
+	}
+	rateLimitAnonymousRPMStr := os.Getenv("RATE_LIMIT_ANONYMOUS_RPM")
+	rateLimitAnonymousRPM, err := strconv.Atoi(rateLimitAnonymousRPMStr)
+	if err != nil || rateLimitAnonymousRPM <= 0 {
+		rateLimitAnonymousRPM = 60
+	}
+	rateLimitAnonymousBurstStr := os.Getenv("RATE_LIMIT_ANONYMOUS_BURST")
+	rateLimitAnonymousBurst, err := strconv.Atoi(rateLimitAnonymousBurstStr)
+	if err != nil || rateLimitAnonymousBurst < 0 {
+		rateLimitAnonymousBurst = 20
+	}
+	rateLimitAnonymousDailyQuotaStr := os.Getenv("RATE_LIMIT_ANONYMOUS_DAILY_QUOTA")
+	rateLimitAnonymousDailyQuota, err := strconv.ParseInt(rateLimitAnonymousDailyQuotaStr, 10, 64)
+	if err != nil || rateLimitAnonymousDailyQuota < 0 {
+		rateLimitAnonymousDailyQuota = 0
+	}
+
+	graphQLMaxDepthStr := os.Getenv("GRAPHQL_MAX_DEPTH")
+	graphQLMaxDepth, err := strconv.Atoi(graphQLMaxDepthStr)
+	if err != nil || graphQLMaxDepth <= 0 {
+		graphQLMaxDepth = 8
+	}
+	graphQLMaxComplexityStr := os.Getenv("GRAPHQL_MAX_COMPLEXITY")
+	graphQLMaxComplexity, err := strconv.Atoi(graphQLMaxComplexityStr)
+	if err != nil || graphQLMaxComplexity <= 0 {
+		graphQLMaxComplexity = 5000
+	}
+
+	tracingInsecureStr := os.Getenv("TRACING_INSECURE")
+	tracingInsecure, err := strconv.ParseBool(tracingInsecureStr)
+	if err != nil {
+		tracingInsecure = true
+	}
+	tracingSampleRatioStr := os.Getenv("TRACING_SAMPLE_RATIO")
+	tracingSampleRatio, err := strconv.ParseFloat(tracingSampleRatioStr, 64)
+	if err != nil || tracingSampleRatio < 0 || tracingSampleRatio > 1 {
+		tracingSampleRatio = 1
+	}
+
+	healthMaxBlockLagStr := os.Getenv("HEALTH_MAX_BLOCK_LAG")
+	healthMaxBlockLag, err := strconv.ParseUint(healthMaxBlockLagStr, 10, 64)
+	if err != nil || healthMaxBlockLag == 0 {
+		healthMaxBlockLag = 30
+	}
+	healthMaxBlockAgeStr := os.Getenv("HEALTH_MAX_BLOCK_AGE")
+	healthMaxBlockAge, err := time.ParseDuration(healthMaxBlockAgeStr)
+	if err != nil || healthMaxBlockAge <= 0 {
+		healthMaxBlockAge = 5 * time.Minute
+	}
+
+	indexerFromHeightStr := os.Getenv("INDEXER_FROM_HEIGHT")
+	indexerFromHeight, err := strconv.ParseUint(indexerFromHeightStr, 10, 64)
+	if err != nil {
+		indexerFromHeight = 1
+	}
+	indexerToHeightStr := os.Getenv("INDEXER_TO_HEIGHT")
+	indexerToHeight, err := strconv.ParseUint(indexerToHeightStr, 10, 64)
+	if err != nil {
+		indexerToHeight = 0
+	}
+	indexerWorkersStr := os.Getenv("INDEXER_WORKERS")
+	indexerWorkers, err := strconv.Atoi(indexerWorkersStr)
+	if err != nil {
+		indexerWorkers = 8
+	}
+	indexerBatchSizeStr := os.Getenv("INDEXER_BATCH_SIZE")
+	indexerBatchSize, err := strconv.Atoi(indexerBatchSizeStr)
+	if err != nil {
+		indexerBatchSize = 100
+	}
+
+	solcCacheDir := os.Getenv("SOLC_CACHE_DIR")
+	if solcCacheDir == "" {
+		solcCacheDir = "./solc"
+	}
 
 	AwsAccessKeyId := os.Getenv("AWS_ACCESS_KEY_ID")
 	AwsSecretAccessKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
@@ -218,6 +399,11 @@ func New() (ExplorerConfig, error) {
 		ServerMode:              os.Getenv("SERVER_MODE"),
 		Port:                    os.Getenv("PORT"),
 		HttpRequestSecret:       os.Getenv("HTTP_REQUEST_SECRET"),
+		MetricsAddr:             os.Getenv("METRICS_ADDR"),
+		AdminTokenSecret:        os.Getenv("ADMIN_TOKEN_SECRET"),
+		AdminTokenTTL:           adminTokenTTL,
+		AdminUsername:           os.Getenv("ADMIN_USERNAME"),
+		AdminPassword:           os.Getenv("ADMIN_PASSWORD"),
 		LogLevel:                os.Getenv("LOG_LEVEL"),
 		IsReloadBootData:        isReloadBootData,
 		IsReloadStakingBootData: isReloadStakingBootData,
@@ -248,11 +434,51 @@ func New() (ExplorerConfig, error) {
 		ListenerInterval: listenerInterval,
 		BackfillInterval: backfillInterval,
 		VerifierInterval: verifierInterval,
+		RollupInterval:   rollupInterval,
+
+		GasOracleBlocks:      gasOracleBlocks,
+		GasOracleMinGasPrice: gasOracleMinGasPrice,
+
+		GenesisFile: os.Getenv("GENESIS_FILE"),
+
+		MarketRefreshInterval: marketRefreshInterval,
+		MarketCMCAPIKey:       os.Getenv("MARKET_CMC_API_KEY"),
+		MarketCMCID:           marketCMCID,
+		MarketCoinGeckoID:     os.Getenv("MARKET_COINGECKO_ID"),
+		MarketWKAIAddress:     os.Getenv("MARKET_WKAI_ADDRESS"),
+
+		RateLimitEnabled:             rateLimitEnabled,
+		RateLimitAnonymousRPM:        rateLimitAnonymousRPM,
+		RateLimitAnonymousBurst:      rateLimitAnonymousBurst,
+		RateLimitAnonymousDailyQuota: rateLimitAnonymousDailyQuota,
+
+		GraphQLMaxDepth:      graphQLMaxDepth,
+		GraphQLMaxComplexity: graphQLMaxComplexity,
+
+		TracingEndpoint:    os.Getenv("TRACING_ENDPOINT"),
+		TracingInsecure:    tracingInsecure,
+		TracingSampleRatio: tracingSampleRatio,
+
+		HealthMaxBlockLag: healthMaxBlockLag,
+		HealthMaxBlockAge: healthMaxBlockAge,
 
 		VerifyBlockParam: &types.VerifyBlockParam{
-			VerifyTxCount:   verifyTxCount,
-			VerifyBlockHash: verifyBlockHash,
+			VerifyTxCount:      verifyTxCount,
+			VerifyBlockHash:    verifyBlockHash,
+			VerifyParentHash:   verifyParentHash,
+			VerifyReceiptsRoot: verifyReceiptsRoot,
+			VerifyGasUsed:      verifyGasUsed,
+			VerifyTxHashes:     verifyTxHashes,
+			VerifyReceipts:     verifyReceipts,
 		},
+		IndexerFromHeight: indexerFromHeight,
+		IndexerToHeight:   indexerToHeight,
+		IndexerWorkers:    indexerWorkers,
+		IndexerBatchSize:  indexerBatchSize,
+
+		SolcCacheDir:  solcCacheDir,
+		SolcBinaryURL: os.Getenv("SOLC_BINARY_URL"),
+
 		AwsAccessKeyId:     AwsAccessKeyId,
 		AwsSecretAccessKey: AwsSecretAccessKey,
 		AwsSecretRegion:    AwsSecretRegion,
@@ -57,6 +57,7 @@ const (
 	ParamsContractAddr        = "0x910cbd665263306807e5ace0351e4358dc6164d8"
 	ParamsContractName        = "Params Contract"
 	UpdateStatsInterval       = 10
+	MaxReorgDepth             = 100
 
 	SMCTypePrefix         = "SMCType:"
 	SMCTypeKRC20          = "KRC20"
@@ -68,6 +69,9 @@ const (
 	SMCTypeTreasury       = "Treasury"
 	KRCTransferTopic      = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
 	KRCTransferMethodName = "Transfer"
+	// Upgraded(address) and BeaconUpgraded(address) emitted by EIP-1967 proxies and beacons
+	ProxyUpgradedTopic  = "0xbc7cd75a20ee27fd9adebab32041f755214dbc6bffa90cc0225b39da2e5c2d3b"
+	BeaconUpgradedTopic = "0x1cf3b03a6cf19fa2baba4df148e9dcabedea7f8a5c07840e207e5c089be95d3e"
 
 	FilterLogsInterval  uint64 = 1000 // number of blocks
 	DefaultKRCTokenLogo        = "https://kardiachain-explorer.s3-ap-southeast-1.amazonaws.com/explorer.kardiachain.io/logo/default.png"
@@ -6,6 +6,7 @@ import (
 	"os/signal"
 	"runtime"
 	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kardiachain/kardia-explorer-backend/kardia"
@@ -16,6 +17,10 @@ import (
 	"github.com/kardiachain/kardia-explorer-backend/cache"
 	"github.com/kardiachain/kardia-explorer-backend/cfg"
 	"github.com/kardiachain/kardia-explorer-backend/db"
+	"github.com/kardiachain/kardia-explorer-backend/driver/solc"
+	"github.com/kardiachain/kardia-explorer-backend/external"
+	"github.com/kardiachain/kardia-explorer-backend/metrics"
+	"github.com/kardiachain/kardia-explorer-backend/tracing"
 	"github.com/kardiachain/kardia-explorer-backend/utils"
 )
 
@@ -85,13 +90,48 @@ func main() {
 		lgr.Error("cannot create cache client", zap.Error(err))
 		panic(err)
 	}
+	metrics.RegisterQueueLengths(func(ctx context.Context) map[string]int64 {
+		return cache.QueueLengths(ctx, cacheClient)
+	})
+	metrics.Serve(serviceCfg.MetricsAddr, lgr)
+	stopTracing, err := tracing.Init(tracing.Config{
+		ServiceName: "explorer-api",
+		Endpoint:    serviceCfg.TracingEndpoint,
+		Insecure:    serviceCfg.TracingInsecure,
+		SampleRatio: serviceCfg.TracingSampleRatio,
+		Logger:      lgr,
+	})
+	if err != nil {
+		panic(err)
+	}
+	defer stopTracing()
 	srv := new(api.Server).
 		SetSecret(serviceCfg.HttpRequestSecret).
+		SetAdminAuth(serviceCfg.AdminTokenSecret, serviceCfg.AdminTokenTTL).
+		SetGraphQLLimits(serviceCfg.GraphQLMaxDepth, serviceCfg.GraphQLMaxComplexity).
+		SetHealthThresholds(serviceCfg.HealthMaxBlockLag, serviceCfg.HealthMaxBlockAge).
 		SetLogger(lgr).
 		SetStorage(dbClient).
 		SetCache(cacheClient).
 		SetKaiClient(kaiClient).
-		SetNode(node)
+		SetNode(node).
+		SetCompiler(solc.NewCompiler(solc.Config{
+			CacheDir:  serviceCfg.SolcCacheDir,
+			BinaryURL: serviceCfg.SolcBinaryURL,
+			Logger:    lgr,
+		})).
+		SetMarket(external.NewMarket(external.MarketConfig{
+			Providers: external.DefaultProviders(
+				external.CoinMarketCapConfig{APIKey: serviceCfg.MarketCMCAPIKey, ID: serviceCfg.MarketCMCID},
+				external.CoinGeckoConfig{ID: serviceCfg.MarketCoinGeckoID}),
+			// grabber keep token info in cache, api only fetch on cache miss
+			MinInterval: time.Minute,
+			Logger:      lgr,
+		}))
+
+	if err := srv.EnsureSuperAdmin(ctx, serviceCfg.AdminUsername, serviceCfg.AdminPassword); err != nil {
+		lgr.Panic("cannot create superadmin", zap.Error(err))
+	}
 
 	if serviceCfg.IsReloadBootData {
 		if err := srv.LoadBootData(ctx); err != nil {
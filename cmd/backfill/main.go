@@ -31,7 +31,9 @@ import (
 	"github.com/kardiachain/kardia-explorer-backend/cache"
 	"github.com/kardiachain/kardia-explorer-backend/cfg"
 	"github.com/kardiachain/kardia-explorer-backend/db"
+	"github.com/kardiachain/kardia-explorer-backend/metrics"
 	"github.com/kardiachain/kardia-explorer-backend/server"
+	"github.com/kardiachain/kardia-explorer-backend/tracing"
 )
 
 func main() {
@@ -94,6 +96,18 @@ func main() {
 	if err != nil {
 		logger.Panic(err.Error())
 	}
+	metrics.Serve(serviceCfg.MetricsAddr, logger)
+	stopTracing, err := tracing.Init(tracing.Config{
+		ServiceName: "explorer-backfill",
+		Endpoint:    serviceCfg.TracingEndpoint,
+		Insecure:    serviceCfg.TracingInsecure,
+		SampleRatio: serviceCfg.TracingSampleRatio,
+		Logger:      logger,
+	})
+	if err != nil {
+		logger.Panic(err.Error())
+	}
+	defer stopTracing()
 
 	go backfill(ctx, srv, serviceCfg.BackfillInterval)
 	<-waitExit
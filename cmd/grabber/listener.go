@@ -22,10 +22,13 @@ import (
 	"context"
 	"time"
 
+	"go.opentelemetry.io/otel/label"
 	"go.uber.org/zap"
 
 	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/metrics"
 	"github.com/kardiachain/kardia-explorer-backend/server"
+	"github.com/kardiachain/kardia-explorer-backend/tracing"
 )
 
 var prevHeader uint64 = 0 // the highest persistent block in database, don't need to backfill blocks have blockHeight < prevHeader
@@ -56,6 +59,7 @@ func listener(ctx context.Context, srv *server.Server, interval time.Duration) {
 			if latest != 0 {
 				latest--
 			}
+			metrics.SetChainHead(latest, prevHeader)
 			lgr := srv.Logger.With(zap.Uint64("block", latest))
 			if latest <= prevHeader {
 				continue
@@ -75,34 +79,57 @@ func listener(ctx context.Context, srv *server.Server, interval time.Duration) {
 					lgr.Error("Block not found")
 					continue
 				}
+				// check if our head still belongs to canonical chain before importing new block
+				isReorg, ancestor, err := srv.DetectReorg(ctx, prevHeader, block)
+				if err != nil {
+					lgr.Error("Failed to detect chain reorg", zap.Error(err))
+					continue
+				}
+				if isReorg {
+					lgr.Warn("Chain reorg detected, rolling back orphaned blocks", zap.Uint64("ancestor", ancestor), zap.Uint64("head", prevHeader))
+					if err := srv.RollbackBlocks(ctx, ancestor+1, prevHeader); err != nil {
+						lgr.Error("Failed to rollback orphaned blocks", zap.Error(err))
+						continue
+					}
+					// canonical blocks in range (ancestor, latest) will be re-imported by backfill
+					prevHeader = ancestor
+				}
 				// insert current block height to cache for re-verifying later
 				// temp remove insert new unverified blocks
 				err = srv.InsertUnverifiedBlocks(ctx, latest)
 				if err != nil {
 					lgr.Error("Failed to insert unverified block", zap.Error(err))
 				}
-				// import this latest block to cache and database
+				// import this latest block to cache and database, stages are traced under one span per block
 				totalImportTime := time.Now()
-				if err := srv.ImportBlock(ctx, block, true); err != nil {
+				importCtx, span := tracing.Start(ctx, "grabber.import", label.Uint64("block.height", latest))
+				if err := srv.ImportBlock(importCtx, block, true); err != nil {
 					lgr.Debug("Failed to import block", zap.Error(err))
+					tracing.End(importCtx, span, err)
 					continue
 				}
 
-				if err := srv.ProcessTxs(ctx, block, true); err != nil {
+				err = srv.ProcessTxs(importCtx, block, true)
+				if err != nil {
 					lgr.Debug("Failed to process txs", zap.Error(err))
 				}
+				tracing.End(importCtx, span, err)
 
 				go func() {
-					if err := srv.ProcessLogsOfTxs(ctx, block.Txs, block.Time); err != nil {
+					// the import span is already ended, logs are traced under their own span
+					logsCtx, span := tracing.Start(ctx, "grabber.process_logs", label.Uint64("block.height", block.Height))
+					err := srv.ProcessLogsOfTxs(logsCtx, block.Txs, block.Time)
+					if err != nil {
 						lgr.Debug("cannot process logs", zap.Error(err))
 					}
 
-					if err := srv.FilterProposalEvent(ctx, block.Txs); err != nil {
+					if err := srv.FilterProposalEvent(logsCtx, block.Txs); err != nil {
 						lgr.Debug("filter proposal event failed", zap.Error(err))
 					}
-					if err := srv.ProcessActiveAddress(ctx, block.Txs); err != nil {
+					if err := srv.ProcessActiveAddress(logsCtx, block.Txs); err != nil {
 						lgr.Debug("failed to process active address", zap.Error(err))
 					}
+					tracing.End(logsCtx, span, err)
 				}()
 
 				lgr.Debug("Total import block time", zap.Duration("TotalTime", time.Since(totalImportTime)))
@@ -122,3 +149,22 @@ func listener(ctx context.Context, srv *server.Server, interval time.Duration) {
 		}
 	}
 }
+
+// pendingTxs poll node mempool every interval and push new pending txs to realtime API subscribers
+func pendingTxs(ctx context.Context, srv *server.Server, interval time.Duration) {
+	lgr := srv.Logger.With(zap.String("task", "pendingTxs"))
+	published := make(map[string]bool)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			var err error
+			if published, err = srv.PublishPendingTxs(ctx, published); err != nil {
+				lgr.Warn("cannot publish pending txs", zap.Error(err))
+			}
+		}
+	}
+}
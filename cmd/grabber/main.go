@@ -31,7 +31,9 @@ import (
 	"github.com/kardiachain/kardia-explorer-backend/cache"
 	"github.com/kardiachain/kardia-explorer-backend/cfg"
 	"github.com/kardiachain/kardia-explorer-backend/db"
+	"github.com/kardiachain/kardia-explorer-backend/metrics"
 	"github.com/kardiachain/kardia-explorer-backend/server"
+	"github.com/kardiachain/kardia-explorer-backend/tracing"
 )
 
 func main() {
@@ -86,6 +88,12 @@ func main() {
 		CacheIsFlush: serviceCfg.CacheIsFlush,
 		BlockBuffer:  serviceCfg.BufferedBlocks,
 
+		GasOracleBlocks:      serviceCfg.GasOracleBlocks,
+		GasOracleMinGasPrice: serviceCfg.GasOracleMinGasPrice,
+
+		MarketCMCAPIKey: serviceCfg.MarketCMCAPIKey,
+		WKAIAddress:     serviceCfg.MarketWKAIAddress,
+
 		Metrics: nil,
 		Logger:  logger.With(zap.String("service", "listener")),
 	}
@@ -93,9 +101,30 @@ func main() {
 	if err != nil {
 		logger.Panic(err.Error())
 	}
+	metrics.Serve(serviceCfg.MetricsAddr, logger)
+	stopTracing, err := tracing.Init(tracing.Config{
+		ServiceName: "explorer-grabber",
+		Endpoint:    serviceCfg.TracingEndpoint,
+		Insecure:    serviceCfg.TracingInsecure,
+		SampleRatio: serviceCfg.TracingSampleRatio,
+		Logger:      logger,
+	})
+	if err != nil {
+		logger.Panic(err.Error())
+	}
+	defer stopTracing()
+
+	if serviceCfg.GenesisFile != "" {
+		if err := srv.SeedGenesisBalances(ctx, serviceCfg.GenesisFile); err != nil {
+			logger.Warn("Cannot seed genesis balances", zap.Error(err))
+		}
+	}
 
 	// Start listener in new go routine
 	go listener(ctx, srv, serviceCfg.ListenerInterval)
+	go pendingTxs(ctx, srv, serviceCfg.ListenerInterval)
+	go market(ctx, srv, serviceCfg)
+	go tokenPrices(ctx, srv, serviceCfg.MarketRefreshInterval)
 	<-waitExit
 	logger.Info("Stopped")
 }
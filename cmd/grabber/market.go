@@ -0,0 +1,61 @@
+/*
+ *  Copyright 2018 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+// Package main
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/external"
+	"github.com/kardiachain/kardia-explorer-backend/server"
+)
+
+// market refresh KAI market data every MarketRefreshInterval, CoinMarketCap is used first when api key is set
+func market(ctx context.Context, srv *server.Server, serviceCfg cfg.ExplorerConfig) {
+	lgr := srv.Logger.With(zap.String("task", "market"))
+	m := external.NewMarket(external.MarketConfig{
+		Providers: external.DefaultProviders(
+			external.CoinMarketCapConfig{APIKey: serviceCfg.MarketCMCAPIKey, ID: serviceCfg.MarketCMCID},
+			external.CoinGeckoConfig{ID: serviceCfg.MarketCoinGeckoID}),
+		Logger: lgr,
+	})
+	lgr.Info("Start refreshing market data...", zap.Duration("interval", serviceCfg.MarketRefreshInterval))
+	m.Run(ctx, serviceCfg.MarketRefreshInterval, srv.SaveMarketQuote)
+}
+
+// tokenPrices refresh prices of KRC20 tokens which have a price source every interval
+func tokenPrices(ctx context.Context, srv *server.Server, interval time.Duration) {
+	lgr := srv.Logger.With(zap.String("task", "tokenPrices"))
+	lgr.Info("Start refreshing token prices...", zap.Duration("interval", interval))
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := srv.RefreshTokenPrices(ctx); err != nil {
+				lgr.Warn("cannot refresh token prices", zap.Error(err))
+			}
+		}
+	}
+}
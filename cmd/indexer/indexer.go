@@ -0,0 +1,179 @@
+/*
+ *  Copyright 2018 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+// Package main
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/server"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+const (
+	checkpointNameFormat = "indexer#%d" // one checkpoint per range start
+	maxFetchAttempts     = 3
+)
+
+type fetchBlockFunc func(ctx context.Context, height uint64) (*types.Block, error)
+
+// index import blocks in range [from, to] batch by batch. Blocks in a batch are fetched concurrently
+// but written in height order, then checkpoint is saved so a restart resumes from next batch.
+func index(ctx context.Context, srv *server.Server, from, to uint64, workers, batchSize int) error {
+	if from == 0 {
+		from = 1
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	if to == 0 {
+		latest, err := srv.LatestBlockHeight(ctx)
+		if err != nil {
+			return err
+		}
+		// same as listener, stay 1 block behind network
+		if latest != 0 {
+			to = latest - 1
+		}
+	}
+	checkpointName := fmt.Sprintf(checkpointNameFormat, from)
+	start := from
+	if checkpoint := srv.Checkpoint(ctx, checkpointName); checkpoint >= from {
+		start = checkpoint + 1
+	}
+	lgr := srv.Logger.With(zap.String("checkpoint", checkpointName))
+	lgr.Info("Indexing range", zap.Uint64("start", start), zap.Uint64("to", to))
+
+	startTime := time.Now()
+	for batchStart := start; batchStart <= to; batchStart += uint64(batchSize) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batchEnd := batchStart + uint64(batchSize) - 1
+		if batchEnd > to {
+			batchEnd = to
+		}
+		batchTime := time.Now()
+		blocks, err := fetchBlocks(ctx, batchStart, batchEnd, workers, srv.BlockByHeightFromRPC)
+		if err != nil {
+			return err
+		}
+		if err := srv.ImportBlocks(ctx, blocks); err != nil {
+			return err
+		}
+		var txs []*types.Transaction
+		for _, b := range blocks {
+			txs = append(txs, b.Txs...)
+			// decode events, token transfers and proposals as listener and backfill do
+			if err := srv.ProcessLogsOfTxs(ctx, b.Txs, b.Time); err != nil {
+				lgr.Warn("cannot process logs", zap.Uint64("height", b.Height), zap.Error(err))
+			}
+		}
+		if err := srv.FilterProposalEvent(ctx, txs); err != nil {
+			lgr.Warn("filter proposal event failed", zap.Error(err))
+		}
+		if err := srv.ProcessActiveAddress(ctx, txs); err != nil {
+			lgr.Warn("failed to process active address", zap.Error(err))
+		}
+		if err := srv.SaveCheckpoint(ctx, checkpointName, batchEnd); err != nil {
+			return err
+		}
+		blocksPerSec := float64(batchEnd-start+1) / time.Since(startTime).Seconds()
+		lgr.Info("Indexed batch", zap.Uint64("from", batchStart), zap.Uint64("to", batchEnd), zap.Int("txs", len(txs)),
+			zap.Duration("batchTime", time.Since(batchTime)), zap.Float64("blocksPerSec", blocksPerSec))
+	}
+	// refresh total txs in cache with indexed data
+	srv.GetCurrentStats(ctx)
+	lgr.Info("Finish indexing range", zap.Duration("TotalTime", time.Since(startTime)))
+	return nil
+}
+
+// fetchBlocks get blocks in range [from, to] with `workers` concurrent requests.
+// Result keeps height order, the whole range fails if any block cannot be fetched.
+func fetchBlocks(ctx context.Context, from, to uint64, workers int, fetch fetchBlockFunc) ([]*types.Block, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		blocks   = make([]*types.Block, to-from+1)
+		jobs     = make(chan uint64)
+		errOnce  sync.Once
+		fetchErr error
+		wg       sync.WaitGroup
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for height := range jobs {
+				block, err := fetchWithRetry(ctx, height, fetch)
+				if err != nil {
+					errOnce.Do(func() {
+						fetchErr = err
+						cancel()
+					})
+					continue
+				}
+				blocks[height-from] = block
+			}
+		}()
+	}
+	for height := from; height <= to && ctx.Err() == nil; height++ {
+		select {
+		case jobs <- height:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+func fetchWithRetry(ctx context.Context, height uint64, fetch fetchBlockFunc) (*types.Block, error) {
+	var err error
+	for attempt := 1; attempt <= maxFetchAttempts; attempt++ {
+		var block *types.Block
+		block, err = fetch(ctx, height)
+		if err == nil && block == nil {
+			err = errors.New("block not found")
+		}
+		if err == nil {
+			return block, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Duration(attempt) * 500 * time.Millisecond):
+		}
+	}
+	return nil, fmt.Errorf("cannot fetch block %d: %v", height, err)
+}
@@ -0,0 +1,40 @@
+// Package main
+package main
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+func TestFetchBlocks_KeepOrder(t *testing.T) {
+	fetch := func(ctx context.Context, height uint64) (*types.Block, error) {
+		return &types.Block{Height: height}, nil
+	}
+	blocks, err := fetchBlocks(context.Background(), 10, 59, 8, fetch)
+	assert.Nil(t, err)
+	assert.Len(t, blocks, 50)
+	for i, b := range blocks {
+		assert.Equal(t, uint64(10+i), b.Height)
+	}
+}
+
+func TestFetchBlocks_RetryThenFail(t *testing.T) {
+	var calls int32
+	fetch := func(ctx context.Context, height uint64) (*types.Block, error) {
+		if height == 3 {
+			atomic.AddInt32(&calls, 1)
+			return nil, errors.New("rpc error")
+		}
+		return &types.Block{Height: height}, nil
+	}
+	blocks, err := fetchBlocks(context.Background(), 1, 5, 2, fetch)
+	assert.NotNil(t, err)
+	assert.Nil(t, blocks)
+	assert.Equal(t, int32(maxFetchAttempts), atomic.LoadInt32(&calls))
+}
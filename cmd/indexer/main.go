@@ -0,0 +1,109 @@
+/*
+ *  Copyright 2018 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+// Package main
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+
+	"github.com/joho/godotenv"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/cache"
+	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/db"
+	"github.com/kardiachain/kardia-explorer-backend/server"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		panic(err.Error())
+	}
+
+	runtime.GOMAXPROCS(runtime.NumCPU())
+	serviceCfg, err := cfg.New()
+	if err != nil {
+		panic(err.Error())
+	}
+	// command line flags override env config
+	from := flag.Uint64("from", serviceCfg.IndexerFromHeight, "first block height to index")
+	to := flag.Uint64("to", serviceCfg.IndexerToHeight, "last block height to index, 0 means latest block")
+	workers := flag.Int("workers", serviceCfg.IndexerWorkers, "number of concurrent RPC workers")
+	batchSize := flag.Int("batch", serviceCfg.IndexerBatchSize, "number of blocks written per batch")
+	flag.Parse()
+
+	logger, err := newLogger(serviceCfg)
+	if err != nil {
+		panic("cannot init logger")
+	}
+	logger.Info("Start indexer...", zap.Uint64("from", *from), zap.Uint64("to", *to), zap.Int("workers", *workers), zap.Int("batch", *batchSize))
+
+	defer func() {
+		if err := recover(); err != nil {
+			logger.Error("cannot recover")
+		}
+		if err := logger.Sync(); err != nil {
+			logger.Error("cannot sync log")
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		for range sigCh {
+			cancel()
+		}
+	}()
+
+	srvCfg := server.Config{
+		StorageAdapter: db.Adapter(serviceCfg.StorageDriver),
+		StorageURI:     serviceCfg.StorageURI,
+		StorageDB:      serviceCfg.StorageDB,
+		StorageIsFlush: false,
+		MinConn:        serviceCfg.StorageMinConn,
+		MaxConn:        serviceCfg.StorageMaxConn,
+
+		KardiaURLs:         serviceCfg.KardiaPublicNodes,
+		KardiaTrustedNodes: serviceCfg.KardiaTrustedNodes,
+
+		CacheAdapter: cache.Adapter(serviceCfg.CacheEngine),
+		CacheURL:     serviceCfg.CacheURL,
+		CacheDB:      serviceCfg.CacheDB,
+		CacheIsFlush: false,
+		BlockBuffer:  serviceCfg.BufferedBlocks,
+
+		Metrics: nil,
+		Logger:  logger.With(zap.String("service", "indexer")),
+	}
+	srv, err := server.New(srvCfg)
+	if err != nil {
+		logger.Panic(err.Error())
+	}
+
+	if err := index(ctx, srv, *from, *to, *workers, *batchSize); err != nil {
+		logger.Error("Indexer stopped, restart to resume from last checkpoint", zap.Error(err))
+		return
+	}
+	logger.Info("Stopped")
+}
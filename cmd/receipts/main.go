@@ -13,7 +13,10 @@ import (
 	"github.com/kardiachain/kardia-explorer-backend/cache"
 	"github.com/kardiachain/kardia-explorer-backend/cfg"
 	"github.com/kardiachain/kardia-explorer-backend/db"
+	"github.com/kardiachain/kardia-explorer-backend/kardia"
+	"github.com/kardiachain/kardia-explorer-backend/metrics"
 	"github.com/kardiachain/kardia-explorer-backend/server/receipts"
+	"github.com/kardiachain/kardia-explorer-backend/tracing"
 	"github.com/kardiachain/kardia-explorer-backend/utils"
 	"go.uber.org/zap"
 )
@@ -65,6 +68,16 @@ func main() {
 	}
 
 	node, err := kClient.NewNode(serviceCfg.KardiaTrustedNodes[0], lgr)
+	if err != nil {
+		lgr.Error("cannot create node", zap.Error(err))
+		panic(err)
+	}
+	// kaiClient is used to trace internal calls of contract txs
+	kaiClient, err := kardia.NewKaiClient(kardia.NewConfig(serviceCfg.KardiaPublicNodes, serviceCfg.KardiaTrustedNodes, lgr))
+	if err != nil {
+		lgr.Error("cannot create kai client", zap.Error(err))
+		panic(err)
+	}
 	cacheCfg := cache.Config{
 		Adapter:     cache.RedisAdapter,
 		URL:         serviceCfg.CacheURL,
@@ -79,15 +92,32 @@ func main() {
 		lgr.Error("cannot create cache client", zap.Error(err))
 		panic(err)
 	}
+	metrics.RegisterQueueLengths(func(ctx context.Context) map[string]int64 {
+		return cache.QueueLengths(ctx, cacheClient)
+	})
+	metrics.Serve(serviceCfg.MetricsAddr, lgr)
+	stopTracing, err := tracing.Init(tracing.Config{
+		ServiceName: "explorer-receipts",
+		Endpoint:    serviceCfg.TracingEndpoint,
+		Insecure:    serviceCfg.TracingInsecure,
+		SampleRatio: serviceCfg.TracingSampleRatio,
+		Logger:      lgr,
+	})
+	if err != nil {
+		panic(err)
+	}
+	defer stopTracing()
 
 	srv := new(receipts.Server).
 		SetLogger(lgr).
 		SetStorage(dbClient).
 		SetCache(cacheClient).
-		SetNode(node)
+		SetNode(node).
+		SetKaiClient(kaiClient)
 
 	// Start listener in new go routine
 	go srv.HandleReceipts(ctx, serviceCfg.ListenerInterval)
+	go srv.HandleTraceRetries(ctx, serviceCfg.ListenerInterval)
 	<-waitExit
 	lgr.Info("Stopped")
 }
@@ -0,0 +1,127 @@
+/*
+ *  Copyright 2018 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+// Package main
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/cache"
+	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/db"
+	"github.com/kardiachain/kardia-explorer-backend/server"
+)
+
+const dateLayout = "2006-01-02"
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		panic(err.Error())
+	}
+
+	runtime.GOMAXPROCS(runtime.NumCPU())
+	serviceCfg, err := cfg.New()
+	if err != nil {
+		panic(err.Error())
+	}
+	rebuildFrom := flag.String("rebuild-from", "", "first day (YYYY-MM-DD) to rebuild")
+	rebuildTo := flag.String("rebuild-to", "", "last day (YYYY-MM-DD) to rebuild, default is rebuild-from")
+	flag.Parse()
+
+	logger, err := newLogger(serviceCfg)
+	if err != nil {
+		panic("cannot init logger")
+	}
+	logger.Info("Start rollups...")
+
+	defer func() {
+		if err := recover(); err != nil {
+			logger.Error("cannot recover")
+		}
+		if err := logger.Sync(); err != nil {
+			logger.Error("cannot sync log")
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sigCh := make(chan os.Signal, 1)
+	waitExit := make(chan bool)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		for range sigCh {
+			cancel()
+			waitExit <- true
+		}
+	}()
+
+	srvConfig := server.Config{
+		StorageAdapter: db.Adapter(serviceCfg.StorageDriver),
+		StorageURI:     serviceCfg.StorageURI,
+		StorageDB:      serviceCfg.StorageDB,
+		StorageIsFlush: false,
+		MinConn:        serviceCfg.StorageMinConn,
+		MaxConn:        serviceCfg.StorageMaxConn,
+
+		KardiaURLs:         serviceCfg.KardiaPublicNodes,
+		KardiaTrustedNodes: serviceCfg.KardiaTrustedNodes,
+
+		CacheAdapter: cache.Adapter(serviceCfg.CacheEngine),
+		CacheURL:     serviceCfg.CacheURL,
+		CacheDB:      serviceCfg.CacheDB,
+		CacheIsFlush: false,
+		BlockBuffer:  serviceCfg.BufferedBlocks,
+
+		Metrics: nil,
+		Logger:  logger.With(zap.String("service", "rollups")),
+	}
+	srv, err := server.New(srvConfig)
+	if err != nil {
+		logger.Panic(err.Error())
+	}
+
+	if *rebuildFrom != "" {
+		if *rebuildTo == "" {
+			rebuildTo = rebuildFrom
+		}
+		from, err := time.Parse(dateLayout, *rebuildFrom)
+		if err != nil {
+			logger.Panic("invalid rebuild-from", zap.Error(err))
+		}
+		to, err := time.Parse(dateLayout, *rebuildTo)
+		if err != nil {
+			logger.Panic("invalid rebuild-to", zap.Error(err))
+		}
+		if err := srv.RebuildRollups(ctx, from, to.AddDate(0, 0, 1).Add(-time.Second)); err != nil {
+			logger.Panic("cannot mark rollups to rebuild", zap.Error(err))
+		}
+		logger.Info("Marked rollups to rebuild", zap.String("from", *rebuildFrom), zap.String("to", *rebuildTo))
+	}
+
+	go rollup(ctx, srv, serviceCfg.RollupInterval)
+	<-waitExit
+	logger.Info("Stopped")
+}
@@ -0,0 +1,48 @@
+/*
+ *  Copyright 2018 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+// Package main
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/server"
+)
+
+func rollup(ctx context.Context, srv *server.Server, interval time.Duration) {
+	lgr := srv.Logger.With(zap.String("task", "rollup"))
+	lgr.Info("Start building rollups...")
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		startTime := time.Now()
+		if err := srv.BuildRollups(ctx, startTime); err != nil {
+			lgr.Warn("cannot build rollups", zap.Error(err))
+		} else {
+			lgr.Debug("Built rollups", zap.Duration("TotalTime", time.Since(startTime)))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/tracing"
 )
 
 func main() {
@@ -25,6 +26,18 @@ func main() {
 		panic(err.Error())
 	}
 
+	stopTracing, err := tracing.Init(tracing.Config{
+		ServiceName: "explorer-watcher",
+		Endpoint:    serviceCfg.TracingEndpoint,
+		Insecure:    serviceCfg.TracingInsecure,
+		SampleRatio: serviceCfg.TracingSampleRatio,
+		Logger:      zap.L(),
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+	defer stopTracing()
+
 	zap.L().Info("Start subscribe event")
 	ctx, cancel := context.WithCancel(context.Background())
 	sigCh := make(chan os.Signal, 1)
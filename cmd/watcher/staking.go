@@ -7,6 +7,7 @@ import (
 	"github.com/kardiachain/kardia-explorer-backend/cfg"
 	"github.com/kardiachain/kardia-explorer-backend/db"
 	"github.com/kardiachain/kardia-explorer-backend/handler"
+	"github.com/kardiachain/kardia-explorer-backend/metrics"
 	"github.com/kardiachain/kardia-explorer-backend/utils"
 )
 
@@ -16,6 +17,7 @@ func runStakingSubscriber(ctx context.Context, serviceCfg cfg.ExplorerConfig) er
 	if err != nil {
 		panic(err.Error())
 	}
+	metrics.Serve(serviceCfg.MetricsAddr, logger)
 
 	handlerCfg := handler.Config{
 		TrustedNodes: serviceCfg.KardiaTrustedNodes,
@@ -18,6 +18,7 @@ type IAddress interface {
 
 	// Address
 	AddressByHash(ctx context.Context, addressHash string) (*types.Address, error)
+	AddressesByHashes(ctx context.Context, addressHashes []string) ([]*types.Address, error)
 	InsertAddress(ctx context.Context, address *types.Address) error
 	UpdateAddresses(ctx context.Context, addresses []*types.Address) error
 	GetTotalAddresses(ctx context.Context) (uint64, uint64, error)
@@ -32,7 +33,7 @@ type IAddress interface {
 }
 
 func (m *mongoDB) CountAddresses(ctx context.Context) (int64, error) {
-	totalAddr, err := m.wrapper.C(cAddresses).Count(bson.M{})
+	totalAddr, err := m.wrapper.C(cAddresses).Count(ctx, bson.M{})
 	if err != nil {
 		return 0, err
 	}
@@ -40,7 +41,7 @@ func (m *mongoDB) CountAddresses(ctx context.Context) (int64, error) {
 }
 
 func (m *mongoDB) RemoveNilAddresses(ctx context.Context) error {
-	if _, err := m.wrapper.C(cAddresses).RemoveAll(bson.M{"address": ""}); err != nil {
+	if _, err := m.wrapper.C(cAddresses).RemoveAll(ctx, bson.M{"address": ""}); err != nil {
 		return err
 	}
 	return nil
@@ -48,13 +49,32 @@ func (m *mongoDB) RemoveNilAddresses(ctx context.Context) error {
 
 func (m *mongoDB) AddressByHash(ctx context.Context, address string) (*types.Address, error) {
 	var c types.Address
-	err := m.wrapper.C(cAddresses).FindOne(bson.M{"address": address}).Decode(&c)
+	err := m.wrapper.C(cAddresses).FindOne(ctx, bson.M{"address": address}).Decode(&c)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get address: %v", err)
 	}
 	return &c, nil
 }
 
+// AddressesByHashes return found addresses of input hashes, in no particular order
+func (m *mongoDB) AddressesByHashes(ctx context.Context, addressHashes []string) ([]*types.Address, error) {
+	var addresses []*types.Address
+	cursor, err := m.wrapper.C(cAddresses).Find(ctx, bson.M{"address": bson.M{"$in": addressHashes}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get addresses: %v", err)
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	if err := cursor.All(ctx, &addresses); err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
 func (m *mongoDB) InsertAddress(ctx context.Context, address *types.Address) error {
 	if address.Address != "0x" {
 		address.Address = common.HexToAddress(address.Address).String()
@@ -64,7 +84,7 @@ func (m *mongoDB) InsertAddress(ctx context.Context, address *types.Address) err
 	if address.Address == "" {
 		return nil
 	}
-	_, err := m.wrapper.C(cAddresses).Insert(address)
+	_, err := m.wrapper.C(cAddresses).Insert(ctx, address)
 	if err != nil {
 		return err
 	}
@@ -86,18 +106,18 @@ func (m *mongoDB) UpdateAddresses(ctx context.Context, addresses []*types.Addres
 		updateAddressOperations = append(updateAddressOperations,
 			mongo.NewUpdateOneModel().SetUpsert(true).SetFilter(bson.M{"address": info.Address}).SetUpdate(bson.M{"$set": info}))
 	}
-	if _, err := m.wrapper.C(cAddresses).BulkWrite(updateAddressOperations); err != nil {
+	if _, err := m.wrapper.C(cAddresses).BulkWrite(ctx, updateAddressOperations); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (m *mongoDB) GetTotalAddresses(ctx context.Context) (uint64, uint64, error) {
-	totalAddr, err := m.wrapper.C(cAddresses).Count(bson.M{"isContract": false})
+	totalAddr, err := m.wrapper.C(cAddresses).Count(ctx, bson.M{"isContract": false})
 	if err != nil {
 		return 0, 0, err
 	}
-	totalContractAddr, err := m.wrapper.C(cAddresses).Count(bson.M{"isContract": true})
+	totalContractAddr, err := m.wrapper.C(cAddresses).Count(ctx, bson.M{"isContract": true})
 	if err != nil {
 		return 0, 0, err
 	}
@@ -115,7 +135,7 @@ func (m *mongoDB) GetListAddresses(ctx context.Context, sortDirection int, pagin
 		rank  = uint64(pagination.Skip + 1)
 		addrs []*types.Address
 	)
-	cursor, err := m.wrapper.C(cAddresses).Find(bson.D{}, opts...)
+	cursor, err := m.wrapper.C(cAddresses).Find(ctx, bson.D{}, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -140,7 +160,7 @@ func (m *mongoDB) GetListAddresses(ctx context.Context, sortDirection int, pagin
 
 func (m *mongoDB) Addresses(ctx context.Context) ([]*types.Address, error) {
 	var addresses []*types.Address
-	cursor, err := m.wrapper.C(cAddresses).Find(bson.M{})
+	cursor, err := m.wrapper.C(cAddresses).Find(ctx, bson.M{})
 	if err != nil {
 		return nil, err
 	}
@@ -163,7 +183,7 @@ func (m *mongoDB) Addresses(ctx context.Context) ([]*types.Address, error) {
 
 func (m *mongoDB) GetAddressInfo(ctx context.Context, hash string) (*types.Address, error) {
 	var address *types.Address
-	if err := m.wrapper.C(cAddresses).FindOne(bson.M{"address": hash}).Decode(&address); err != nil {
+	if err := m.wrapper.C(cAddresses).FindOne(ctx, bson.M{"address": hash}).Decode(&address); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,75 @@
+// Package db
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+var cAdminUsers = "AdminUsers"
+
+type IAdminUser interface {
+	createAdminUsersCollectionIndexes() []mongo.IndexModel
+
+	InsertAdminUser(ctx context.Context, user *types.AdminUser) error
+	UpdateAdminUser(ctx context.Context, user *types.AdminUser) error
+	AdminUser(ctx context.Context, username string) (*types.AdminUser, error)
+	AdminUsers(ctx context.Context) ([]*types.AdminUser, error)
+}
+
+func (m *mongoDB) createAdminUsersCollectionIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.M{"username": 1}, Options: options.Index().SetUnique(true)},
+	}
+}
+
+func (m *mongoDB) InsertAdminUser(ctx context.Context, user *types.AdminUser) error {
+	if _, err := m.wrapper.C(cAdminUsers).Insert(ctx, user); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *mongoDB) UpdateAdminUser(ctx context.Context, user *types.AdminUser) error {
+	if _, err := m.wrapper.C(cAdminUsers).Upsert(ctx, bson.M{"username": user.Username}, user); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AdminUser return nil when no user match
+func (m *mongoDB) AdminUser(ctx context.Context, username string) (*types.AdminUser, error) {
+	var user *types.AdminUser
+	err := m.wrapper.C(cAdminUsers).FindOne(ctx, bson.M{"username": username}).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (m *mongoDB) AdminUsers(ctx context.Context) ([]*types.AdminUser, error) {
+	var users []*types.AdminUser
+	cursor, err := m.wrapper.C(cAdminUsers).Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"username": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
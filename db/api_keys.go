@@ -0,0 +1,98 @@
+// Package db
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+var cAPIKeys = "APIKeys"
+
+type IAPIKey interface {
+	createAPIKeysCollectionIndexes() []mongo.IndexModel
+
+	InsertAPIKey(ctx context.Context, key *types.APIKey) error
+	UpdateAPIKey(ctx context.Context, key *types.APIKey) error
+	APIKeyByHash(ctx context.Context, keyHash string) (*types.APIKey, error)
+	APIKeyByID(ctx context.Context, id string) (*types.APIKey, error)
+	APIKeys(ctx context.Context, pagination *types.Pagination) ([]*types.APIKey, uint64, error)
+}
+
+func (m *mongoDB) createAPIKeysCollectionIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.M{"id": 1}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.M{"keyHash": 1}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.M{"owner": 1}},
+	}
+}
+
+func (m *mongoDB) InsertAPIKey(ctx context.Context, key *types.APIKey) error {
+	if _, err := m.wrapper.C(cAPIKeys).Insert(ctx, key); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *mongoDB) UpdateAPIKey(ctx context.Context, key *types.APIKey) error {
+	if _, err := m.wrapper.C(cAPIKeys).Upsert(ctx, bson.M{"id": key.ID}, key); err != nil {
+		return err
+	}
+	return nil
+}
+
+// APIKeyByHash return nil when no key match
+func (m *mongoDB) APIKeyByHash(ctx context.Context, keyHash string) (*types.APIKey, error) {
+	return m.findAPIKey(ctx, bson.M{"keyHash": keyHash})
+}
+
+// APIKeyByID return nil when no key match
+func (m *mongoDB) APIKeyByID(ctx context.Context, id string) (*types.APIKey, error) {
+	return m.findAPIKey(ctx, bson.M{"id": id})
+}
+
+func (m *mongoDB) findAPIKey(ctx context.Context, crit bson.M) (*types.APIKey, error) {
+	var key *types.APIKey
+	err := m.wrapper.C(cAPIKeys).FindOne(ctx, crit).Decode(&key)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (m *mongoDB) APIKeys(ctx context.Context, pagination *types.Pagination) ([]*types.APIKey, uint64, error) {
+	var keys []*types.APIKey
+	opts := []*options.FindOptions{
+		options.Find().SetSort(bson.M{"createdAt": -1}),
+	}
+	if pagination != nil {
+		pagination.Sanitize()
+		opts = append(opts, options.Find().SetSkip(int64(pagination.Skip)), options.Find().SetLimit(int64(pagination.Limit)))
+	}
+	cursor, err := m.wrapper.C(cAPIKeys).Find(ctx, bson.M{}, opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, 0, err
+	}
+	total, err := m.wrapper.C(cAPIKeys).Count(ctx, bson.M{})
+	if err != nil {
+		return nil, 0, err
+	}
+	return keys, uint64(total), nil
+}
@@ -0,0 +1,85 @@
+// Package db
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+var cAuditLogs = "AuditLogs"
+
+// IAuditLog is append-only, audit logs must not be updated nor removed through the client
+type IAuditLog interface {
+	createAuditLogsCollectionIndexes() []mongo.IndexModel
+
+	InsertAuditLog(ctx context.Context, log *types.AuditLog) error
+	AuditLogs(ctx context.Context, filter *types.AuditLogsFilter) ([]*types.AuditLog, uint64, error)
+}
+
+func (m *mongoDB) createAuditLogsCollectionIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.M{"time": -1}},
+		{Keys: bson.D{{Key: "actor", Value: 1}, {Key: "time", Value: -1}}},
+		{Keys: bson.D{{Key: "resource", Value: 1}, {Key: "time", Value: -1}}},
+	}
+}
+
+func (m *mongoDB) InsertAuditLog(ctx context.Context, log *types.AuditLog) error {
+	if _, err := m.wrapper.C(cAuditLogs).Insert(ctx, log); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AuditLogs return logs match filter, latest first
+func (m *mongoDB) AuditLogs(ctx context.Context, filter *types.AuditLogsFilter) ([]*types.AuditLog, uint64, error) {
+	var logs []*types.AuditLog
+	crit := bson.M{}
+	if filter.Actor != "" {
+		crit["actor"] = filter.Actor
+	}
+	if filter.Resource != "" {
+		crit["resource"] = filter.Resource
+	}
+	timeCrit := bson.M{}
+	if !filter.StartTime.IsZero() {
+		timeCrit["$gte"] = filter.StartTime
+	}
+	if !filter.EndTime.IsZero() {
+		timeCrit["$lte"] = filter.EndTime
+	}
+	if len(timeCrit) > 0 {
+		crit["time"] = timeCrit
+	}
+	opts := []*options.FindOptions{
+		options.Find().SetSort(bson.M{"time": -1}),
+	}
+	if filter.Pagination != nil {
+		filter.Pagination.Sanitize()
+		opts = append(opts, options.Find().SetSkip(int64(filter.Pagination.Skip)), options.Find().SetLimit(int64(filter.Pagination.Limit)))
+	}
+	cursor, err := m.wrapper.C(cAuditLogs).Find(ctx, crit, opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	if err := cursor.All(ctx, &logs); err != nil {
+		return nil, 0, err
+	}
+	total, err := m.wrapper.C(cAuditLogs).Count(ctx, crit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return logs, uint64(total), nil
+}
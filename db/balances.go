@@ -0,0 +1,208 @@
+// Package db
+package db
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+var cBalanceEntries = "BalanceEntries"
+
+type IBalanceEntry interface {
+	createBalanceEntriesCollectionIndexes() []mongo.IndexModel
+	UpsertBalanceEntries(ctx context.Context, entries []*types.BalanceEntry) error
+	BalanceAt(ctx context.Context, address string, blockHeight uint64) (*big.Int, error)
+	BalanceChanges(ctx context.Context, filter *types.BalanceChangesFilter) ([]*types.BalanceChange, uint64, error)
+	RemoveBalanceEntriesByBlockHeight(ctx context.Context, blockHeight uint64) error
+}
+
+// balanceEntryDoc store amount as decimal128 as well, so balances are summed by mongo
+type balanceEntryDoc struct {
+	types.BalanceEntry `bson:",inline"`
+	AmountDecimal      primitive.Decimal128 `bson:"amountDecimal"`
+}
+
+func (m *mongoDB) createBalanceEntriesCollectionIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.D{{Key: "txHash", Value: 1}, {Key: "address", Value: 1}, {Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "address", Value: 1}, {Key: "blockHeight", Value: 1}}},
+		{Keys: bson.D{{Key: "address", Value: 1}, {Key: "time", Value: 1}}},
+		{Keys: bson.M{"blockHeight": -1}},
+	}
+}
+
+// UpsertBalanceEntries store entries by tx hash, address and key, so a block imported again
+// does not count twice
+func (m *mongoDB) UpsertBalanceEntries(ctx context.Context, entries []*types.BalanceEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	models := make([]mongo.WriteModel, len(entries))
+	for i, entry := range entries {
+		amount, err := primitive.ParseDecimal128(entry.Amount)
+		if err != nil {
+			return err
+		}
+		doc := &balanceEntryDoc{BalanceEntry: *entry, AmountDecimal: amount}
+		models[i] = mongo.NewUpdateOneModel().SetUpsert(true).
+			SetFilter(bson.M{"txHash": entry.TxHash, "address": entry.Address, "key": entry.Key}).
+			SetUpdate(bson.M{"$set": doc})
+	}
+	if _, err := m.wrapper.C(cBalanceEntries).BulkUpsert(ctx, models); err != nil {
+		return err
+	}
+	return nil
+}
+
+// BalanceAt return balance of address after block blockHeight
+func (m *mongoDB) BalanceAt(ctx context.Context, address string, blockHeight uint64) (*big.Int, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"address": address, "blockHeight": bson.M{"$lte": blockHeight}}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "balance": bson.M{"$sum": "$amountDecimal"}}}},
+	}
+	cursor, err := m.wrapper.C(cBalanceEntries).Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	var result []struct {
+		Balance primitive.Decimal128 `bson:"balance"`
+	}
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return big.NewInt(0), nil
+	}
+	return decimalToBigInt(result[0].Balance)
+}
+
+// BalanceChanges return net change of address balance per block in time range, oldest first.
+// Balance field is not set.
+func (m *mongoDB) BalanceChanges(ctx context.Context, filter *types.BalanceChangesFilter) ([]*types.BalanceChange, uint64, error) {
+	crit := bson.M{"address": filter.Address}
+	timeCrit := bson.M{}
+	if !filter.StartTime.IsZero() {
+		timeCrit["$gte"] = filter.StartTime
+	}
+	if !filter.EndTime.IsZero() {
+		timeCrit["$lte"] = filter.EndTime
+	}
+	if len(timeCrit) > 0 {
+		crit["time"] = timeCrit
+	}
+	total, err := m.countBalanceBlocks(ctx, crit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: crit}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    "$blockHeight",
+			"time":   bson.M{"$first": "$time"},
+			"change": bson.M{"$sum": "$amountDecimal"},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+	if filter.Pagination != nil {
+		filter.Pagination.Sanitize()
+		pipeline = append(pipeline,
+			bson.D{{Key: "$skip", Value: filter.Pagination.Skip}},
+			bson.D{{Key: "$limit", Value: filter.Pagination.Limit}})
+	}
+	cursor, err := m.wrapper.C(cBalanceEntries).Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	var rows []struct {
+		BlockHeight uint64               `bson:"_id"`
+		Time        time.Time            `bson:"time"`
+		Change      primitive.Decimal128 `bson:"change"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, 0, err
+	}
+	changes := make([]*types.BalanceChange, len(rows))
+	for i, row := range rows {
+		change, err := decimalToBigInt(row.Change)
+		if err != nil {
+			return nil, 0, err
+		}
+		changes[i] = &types.BalanceChange{
+			BlockHeight: row.BlockHeight,
+			Time:        row.Time,
+			Change:      change.String(),
+		}
+	}
+	return changes, total, nil
+}
+
+// countBalanceBlocks return number of blocks which have entries match crit
+func (m *mongoDB) countBalanceBlocks(ctx context.Context, crit bson.M) (uint64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: crit}},
+		{{Key: "$group", Value: bson.M{"_id": "$blockHeight"}}},
+		{{Key: "$count", Value: "total"}},
+	}
+	cursor, err := m.wrapper.C(cBalanceEntries).Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	var result []struct {
+		Total int64 `bson:"total"`
+	}
+	if err := cursor.All(ctx, &result); err != nil {
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, nil
+	}
+	return uint64(result[0].Total), nil
+}
+
+func (m *mongoDB) RemoveBalanceEntriesByBlockHeight(ctx context.Context, blockHeight uint64) error {
+	if _, err := m.wrapper.C(cBalanceEntries).RemoveAll(ctx, bson.M{"blockHeight": blockHeight}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func decimalToBigInt(d primitive.Decimal128) (*big.Int, error) {
+	value, ok := new(big.Int).SetString(d.String(), 10)
+	if !ok {
+		// sum of integers has no fraction, but may be printed with exponent
+		f, _, err := big.ParseFloat(d.String(), 10, 256, big.ToNearestEven)
+		if err != nil {
+			return nil, err
+		}
+		value, _ = f.Int(nil)
+	}
+	return value, nil
+}
@@ -0,0 +1,47 @@
+// Package db
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+var cCheckpoints = "Checkpoints"
+
+type ICheckpoint interface {
+	createCheckpointsCollectionIndexes() []mongo.IndexModel
+	Checkpoint(ctx context.Context, name string) (*types.Checkpoint, error)
+	UpsertCheckpoint(ctx context.Context, name string, height uint64) error
+}
+
+func (m *mongoDB) createCheckpointsCollectionIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.M{"name": 1}, Options: options.Index().SetUnique(true).SetSparse(true)},
+	}
+}
+
+func (m *mongoDB) Checkpoint(ctx context.Context, name string) (*types.Checkpoint, error) {
+	var checkpoint *types.Checkpoint
+	if err := m.wrapper.C(cCheckpoints).FindOne(ctx, bson.M{"name": name}).Decode(&checkpoint); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+func (m *mongoDB) UpsertCheckpoint(ctx context.Context, name string, height uint64) error {
+	checkpoint := &types.Checkpoint{
+		Name:      name,
+		Height:    height,
+		UpdatedAt: time.Now(),
+	}
+	if _, err := m.wrapper.C(cCheckpoints).Upsert(ctx, bson.M{"name": name}, checkpoint); err != nil {
+		return err
+	}
+	return nil
+}
@@ -23,6 +23,7 @@ var (
 type IContract interface {
 	InsertContract(ctx context.Context, contract *types.Contract, addrInfo *types.Address) error
 	Contract(ctx context.Context, contractAddr string) (*types.Contract, *types.Address, error)
+	ContractsByAddresses(ctx context.Context, contractAddrs []string) ([]*types.Contract, error)
 	UpdateContract(ctx context.Context, contract *types.Contract, addrInfo *types.Address) error
 	UpdateKRCTotalSupply(ctx context.Context, krcTokenAddress, totalSupply string) error
 	Contracts(ctx context.Context, filter *types.ContractsFilter) ([]*types.Contract, uint64, error)
@@ -39,7 +40,7 @@ type IContract interface {
 }
 
 func (m *mongoDB) CountContracts(ctx context.Context) (int64, error) {
-	total, err := m.wrapper.C(cContract).Count(bson.M{})
+	total, err := m.wrapper.C(cContract).Count(ctx, bson.M{})
 	if err != nil {
 		return 0, err
 	}
@@ -50,13 +51,13 @@ func (m *mongoDB) CountContracts(ctx context.Context) (int64, error) {
 func (m *mongoDB) InsertContract(ctx context.Context, contract *types.Contract, addrInfo *types.Address) error {
 	if contract != nil {
 		contract.CreatedAt = time.Now().Unix()
-		if _, err := m.wrapper.C(cContract).Insert(contract); err != nil {
+		if _, err := m.wrapper.C(cContract).Insert(ctx, contract); err != nil {
 			return err
 		}
 	}
 	//if addrInfo != nil {
 	//	addrInfo.UpdatedAt = time.Now().Unix()
-	//	if _, err := m.wrapper.C(cAddresses).Insert(addrInfo); err != nil {
+	//	if _, err := m.wrapper.C(cAddresses).Insert(ctx, addrInfo); err != nil {
 	//		return err
 	//	}
 	//}
@@ -64,14 +65,14 @@ func (m *mongoDB) InsertContract(ctx context.Context, contract *types.Contract,
 }
 
 func (m *mongoDB) RemoveContract(ctx context.Context, contractAddress string) error {
-	if _, err := m.wrapper.C(cContract).Remove(bson.M{"address": contractAddress}); err != nil {
+	if _, err := m.wrapper.C(cContract).Remove(ctx, bson.M{"address": contractAddress}); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (m *mongoDB) RemoveContracts(ctx context.Context) error {
-	if _, err := m.wrapper.C(cContract).Remove(bson.M{"address": ""}); err != nil {
+	if _, err := m.wrapper.C(cContract).Remove(ctx, bson.M{"address": ""}); err != nil {
 		return err
 	}
 	return nil
@@ -80,7 +81,7 @@ func (m *mongoDB) RemoveContracts(ctx context.Context) error {
 func (m *mongoDB) AllContracts(ctx context.Context) ([]*types.Contract, error) {
 	var contracts []*types.Contract
 	var opts []*options.FindOptions
-	cursor, err := m.wrapper.C(cContract).Find(bson.M{}, opts...)
+	cursor, err := m.wrapper.C(cContract).Find(ctx, bson.M{}, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +102,7 @@ func (m *mongoDB) AllContracts(ctx context.Context) ([]*types.Contract, error) {
 func (m *mongoDB) ContractByType(ctx context.Context, contractType string) ([]*types.Contract, error) {
 	var contracts []*types.Contract
 	var opts []*options.FindOptions
-	cursor, err := m.wrapper.C(cContract).Find(bson.M{"type": contractType}, opts...)
+	cursor, err := m.wrapper.C(cContract).Find(ctx, bson.M{"type": contractType}, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -148,7 +149,7 @@ func (m *mongoDB) Contracts(ctx context.Context, filter *types.ContractsFilter)
 		filter.Pagination.Sanitize()
 		opts = append(opts, options.Find().SetSkip(int64(filter.Pagination.Skip)), options.Find().SetLimit(int64(filter.Pagination.Limit)))
 	}
-	cursor, err := m.wrapper.C(cContract).Find(crit, opts...)
+	cursor, err := m.wrapper.C(cContract).Find(ctx, crit, opts...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -163,7 +164,7 @@ func (m *mongoDB) Contracts(ctx context.Context, filter *types.ContractsFilter)
 		return nil, 0, err
 	}
 
-	total, err := m.wrapper.C(cContract).Count(crit)
+	total, err := m.wrapper.C(cContract).Count(ctx, crit)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -176,13 +177,13 @@ func (m *mongoDB) Contract(ctx context.Context, contractAddr string) (*types.Con
 		contract *types.Contract
 		addr     *types.Address
 	)
-	err := m.wrapper.C(cContract).FindOne(bson.M{"address": contractAddr}).Decode(&contract)
+	err := m.wrapper.C(cContract).FindOne(ctx, bson.M{"address": contractAddr}).Decode(&contract)
 	if err != nil {
 		return nil, nil, err
 	}
 	if contract.ABI == "" && contract.Type != "" && contract.Type != cfg.SMCTypeNormal {
 		var smcABI *types.ContractABI
-		err = m.wrapper.C(cABI).FindOne(bson.M{"type": contract.Type}).Decode(&smcABI)
+		err = m.wrapper.C(cABI).FindOne(ctx, bson.M{"type": contract.Type}).Decode(&smcABI)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -195,9 +196,44 @@ func (m *mongoDB) Contract(ctx context.Context, contractAddr string) (*types.Con
 	return contract, addr, nil
 }
 
+// ContractsByAddresses return found contracts of input addresses, in no particular order.
+// Like Contract, contracts without own ABI get the ABI of their type.
+func (m *mongoDB) ContractsByAddresses(ctx context.Context, contractAddrs []string) ([]*types.Contract, error) {
+	var contracts []*types.Contract
+	cursor, err := m.wrapper.C(cContract).Find(ctx, bson.M{"address": bson.M{"$in": contractAddrs}})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	if err := cursor.All(ctx, &contracts); err != nil {
+		return nil, err
+	}
+
+	abiByType := make(map[string]string)
+	for _, contract := range contracts {
+		if contract.ABI != "" || contract.Type == "" || contract.Type == cfg.SMCTypeNormal {
+			continue
+		}
+		smcABI, ok := abiByType[contract.Type]
+		if !ok {
+			if smcABI, err = m.SMCABIByType(ctx, contract.Type); err != nil {
+				return nil, err
+			}
+			abiByType[contract.Type] = smcABI
+		}
+		contract.ABI = smcABI
+	}
+	return contracts, nil
+}
+
 func (m *mongoDB) UpdateContract(ctx context.Context, contract *types.Contract, addrInfo *types.Address) error {
 	contract.CreatedAt = time.Now().Unix()
-	if _, err := m.wrapper.C(cContract).Upsert(bson.M{"address": contract.Address}, contract); err != nil {
+	if _, err := m.wrapper.C(cContract).Upsert(ctx, bson.M{"address": contract.Address}, contract); err != nil {
 		return err
 	}
 	if addrInfo != nil {
@@ -205,7 +241,7 @@ func (m *mongoDB) UpdateContract(ctx context.Context, contract *types.Contract,
 			return nil
 		}
 		addrInfo.UpdatedAt = time.Now().Unix()
-		if _, err := m.wrapper.C(cAddresses).Upsert(bson.M{"address": addrInfo.Address}, addrInfo); err != nil {
+		if _, err := m.wrapper.C(cAddresses).Upsert(ctx, bson.M{"address": addrInfo.Address}, addrInfo); err != nil {
 			return err
 		}
 	}
@@ -223,7 +259,7 @@ func (m *mongoDB) UpdateKRCTotalSupply(ctx context.Context, krcTokenAddress, tot
 	}
 	addrInfo.TotalSupply = totalSupply
 	addrInfo.UpdatedAt = time.Now().Unix()
-	if _, err := m.wrapper.C(cAddresses).Upsert(bson.M{"address": addrInfo.Address}, addrInfo); err != nil {
+	if _, err := m.wrapper.C(cAddresses).Upsert(ctx, bson.M{"address": addrInfo.Address}, addrInfo); err != nil {
 		return err
 	}
 	return nil
@@ -236,7 +272,7 @@ func (m *mongoDB) UpsertSMCABIByType(ctx context.Context, smcType, abi string) e
 			ABI:  abi,
 		}}),
 	}
-	if _, err := m.wrapper.C(cABI).BulkWrite(upsertModel); err != nil {
+	if _, err := m.wrapper.C(cABI).BulkWrite(ctx, upsertModel); err != nil {
 		m.logger.Warn("cannot upsert new abi", zap.Error(err))
 		return err
 	}
@@ -245,7 +281,7 @@ func (m *mongoDB) UpsertSMCABIByType(ctx context.Context, smcType, abi string) e
 
 func (m *mongoDB) SMCABIByType(ctx context.Context, smcType string) (string, error) {
 	var currABI *types.ContractABI
-	if err := m.wrapper.C(cABI).FindOne(bson.M{"type": smcType}).Decode(&currABI); err != nil {
+	if err := m.wrapper.C(cABI).FindOne(ctx, bson.M{"type": smcType}).Decode(&currABI); err != nil {
 		return "", err
 	}
 	return currABI.ABI, nil
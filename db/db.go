@@ -4,6 +4,7 @@ package db
 import (
 	"context"
 	"errors"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -45,8 +46,24 @@ type Client interface {
 	ITxs
 	IAddress
 	IKRC721Holder
+	IVerifyAudit
+	IProxyUpgrade
+	ISignature
+	IInternalCall
+	IBalanceEntry
+	IRollup
+	ITokenPrice
+	IExport
+	IAPIKey
+	IAdminUser
+	IAuditLog
+	ICheckpoint
+	ISearch
+	ILabel
 
 	ping() error
+	// Ping check the primary is reachable
+	Ping(ctx context.Context) error
 	dropCollection(collectionName string)
 	dropDatabase(ctx context.Context) error
 
@@ -57,11 +74,15 @@ type Client interface {
 	// Block details
 	BlockByHeight(ctx context.Context, blockHeight uint64) (*types.Block, error)
 	BlockByHash(ctx context.Context, blockHash string) (*types.Block, error)
+	BlocksByHeights(ctx context.Context, blockHeights []uint64) ([]*types.Block, error)
+	BlockHeightByTime(ctx context.Context, t time.Time, before bool) (uint64, error)
 	IsBlockExist(ctx context.Context, blockHeight uint64) (bool, error)
 
 	// Interact with blocks
 	Blocks(ctx context.Context, pagination *types.Pagination) ([]*types.Block, error)
 	InsertBlock(ctx context.Context, block *types.Block) error
+	ReplaceBlock(ctx context.Context, block *types.Block) error
+	UpsertBlocks(ctx context.Context, blocks []*types.Block) error
 	DeleteLatestBlock(ctx context.Context) (uint64, error)
 	DeleteBlockByHeight(ctx context.Context, blockHeight uint64) error
 	BlocksByProposer(ctx context.Context, proposer string, pagination *types.Pagination) ([]*types.Block, uint64, error)
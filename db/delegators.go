@@ -50,13 +50,13 @@ func (m *mongoDB) CountDelegators(ctx context.Context, filter DelegatorFilter) (
 		err   error
 	)
 	if len(mgoFilter) == 0 {
-		total, err = m.wrapper.C(cValidators).Count(bson.M{})
+		total, err = m.wrapper.C(cValidators).Count(ctx, bson.M{})
 		if err != nil {
 			return 0, err
 		}
 		return total, nil
 	} else {
-		total, err = m.wrapper.C(cValidators).Count(bson.M{"$and": mgoFilter})
+		total, err = m.wrapper.C(cValidators).Count(ctx, bson.M{"$and": mgoFilter})
 	}
 	if err != nil {
 		return 0, err
@@ -77,9 +77,9 @@ func (m *mongoDB) Delegators(ctx context.Context, filter DelegatorFilter) ([]*ty
 		err    error
 	)
 	if len(mgoFilter) == 0 {
-		cursor, err = m.wrapper.C(cDelegator).Find(bson.M{}, opts...)
+		cursor, err = m.wrapper.C(cDelegator).Find(ctx, bson.M{}, opts...)
 	} else {
-		cursor, err = m.wrapper.C(cDelegator).Find(bson.M{"$and": mgoFilter}, opts...)
+		cursor, err = m.wrapper.C(cDelegator).Find(ctx, bson.M{"$and": mgoFilter}, opts...)
 	}
 	if err != nil {
 		return nil, err
@@ -103,7 +103,7 @@ func (m *mongoDB) UpsertDelegator(ctx context.Context, delegator *types.Delegato
 	var models []mongo.WriteModel
 	models = append(models, mongo.NewUpdateOneModel().SetUpsert(true).SetFilter(bson.M{"validatorSMCAddress": delegator.ValidatorSMCAddress, "address": delegator.Address}).SetUpdate(bson.M{"$set": delegator}))
 
-	if _, err := m.wrapper.C(cDelegator).BulkUpsert(models); err != nil {
+	if _, err := m.wrapper.C(cDelegator).BulkUpsert(ctx, models); err != nil {
 		lgr.Error("Cannot write list model", zap.Error(err))
 		return err
 	}
@@ -119,7 +119,7 @@ func (m *mongoDB) UpsertDelegators(ctx context.Context, delegators []*types.Dele
 		models = append(models, mongo.NewUpdateOneModel().SetUpsert(true).SetFilter(bson.M{"validatorSMCAddress": d.ValidatorSMCAddress, "address": d.Address}).SetUpdate(bson.M{"$set": d}))
 	}
 
-	if _, err := m.wrapper.C(cDelegator).BulkUpsert(models); err != nil {
+	if _, err := m.wrapper.C(cDelegator).BulkUpsert(ctx, models); err != nil {
 		lgr.Warn("cannot write delegators", zap.Error(err))
 		return err
 	}
@@ -128,7 +128,7 @@ func (m *mongoDB) UpsertDelegators(ctx context.Context, delegators []*types.Dele
 }
 
 func (m *mongoDB) ClearDelegators(ctx context.Context, validatorSMCAddr string) error {
-	if _, err := m.wrapper.C(cDelegator).RemoveAll(bson.M{"validatorAddress": validatorSMCAddr}); err != nil {
+	if _, err := m.wrapper.C(cDelegator).RemoveAll(ctx, bson.M{"validatorAddress": validatorSMCAddr}); err != nil {
 		return err
 	}
 
@@ -136,7 +136,7 @@ func (m *mongoDB) ClearDelegators(ctx context.Context, validatorSMCAddr string)
 }
 
 func (m *mongoDB) UniqueDelegators(ctx context.Context) (int, error) {
-	data, err := m.wrapper.C(cDelegator).Distinct("address", bson.M{})
+	data, err := m.wrapper.C(cDelegator).Distinct(ctx, "address", bson.M{})
 	if err != nil {
 		return 0, err
 	}
@@ -145,7 +145,7 @@ func (m *mongoDB) UniqueDelegators(ctx context.Context) (int, error) {
 }
 
 func (m *mongoDB) GetStakedOfAddresses(ctx context.Context, addresses []string) (string, error) {
-	cursor, err := m.wrapper.C(cDelegator).Find(bson.M{"address": bson.M{"$in": addresses}})
+	cursor, err := m.wrapper.C(cDelegator).Find(ctx, bson.M{"address": bson.M{"$in": addresses}})
 	if err != nil {
 		return "", err
 	}
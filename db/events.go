@@ -20,9 +20,10 @@ var (
 
 type IEvents interface {
 	createEventsCollectionIndexes() []mongo.IndexModel
-	InsertEvents(events []types.Log) error
+	InsertEvents(ctx context.Context, events []types.Log) error
 	GetListEvents(ctx context.Context, filter *types.EventsFilter) ([]*types.Log, uint64, error)
 	DeleteEmptyEvents(ctx context.Context, contractAddress string) error
+	DeleteEventsByBlockHeight(ctx context.Context, blockHeight uint64) error
 	RemoveDuplicateEvents(ctx context.Context) ([]*types.Log, error)
 }
 
@@ -35,14 +36,14 @@ func (m *mongoDB) createEventsCollectionIndexes() []mongo.IndexModel {
 	}
 }
 
-func (m *mongoDB) InsertEvents(events []types.Log) error {
+func (m *mongoDB) InsertEvents(ctx context.Context, events []types.Log) error {
 	eventsBulkWriter := make([]mongo.WriteModel, len(events))
 	for i := range events {
 		txModel := mongo.NewInsertOneModel().SetDocument(events[i])
 		eventsBulkWriter[i] = txModel
 	}
 	if len(eventsBulkWriter) > 0 {
-		if _, err := m.wrapper.C(cEvents).BulkWrite(eventsBulkWriter); err != nil {
+		if _, err := m.wrapper.C(cEvents).BulkWrite(ctx, eventsBulkWriter); err != nil {
 			return err
 		}
 	}
@@ -74,7 +75,7 @@ func (m *mongoDB) RemoveDuplicateEvents(ctx context.Context) ([]*types.Log, erro
 	opts := []*options.AggregateOptions{
 		options.Aggregate().SetAllowDiskUse(true),
 	}
-	row, err := m.wrapper.C(cEvents).Aggregate(mongo.Pipeline{groupStage, matchStage}, opts...)
+	row, err := m.wrapper.C(cEvents).Aggregate(ctx, mongo.Pipeline{groupStage, matchStage}, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -118,7 +119,7 @@ func (m *mongoDB) RemoveDuplicateEvents(ctx context.Context) ([]*types.Log, erro
 	}
 
 	if len(groupIDRowDuplicates) > 0 {
-		_, err = m.wrapper.C(cEvents).RemoveAll(bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: groupIDRowDuplicates}}}})
+		_, err = m.wrapper.C(cEvents).RemoveAll(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: groupIDRowDuplicates}}}})
 		if err != nil {
 			return nil, err
 		}
@@ -152,7 +153,7 @@ func (m *mongoDB) GetListEvents(ctx context.Context, filter *types.EventsFilter)
 		opts = append(opts, options.Find().SetLimit(int64(filter.Pagination.Limit)))
 	}
 	cursor, err := m.wrapper.C(cEvents).
-		Find(crit, opts...)
+		Find(ctx, crit, opts...)
 	defer func() {
 		err = cursor.Close(ctx)
 		if err != nil {
@@ -169,7 +170,7 @@ func (m *mongoDB) GetListEvents(ctx context.Context, filter *types.EventsFilter)
 		}
 		events = append(events, event)
 	}
-	total, err := m.wrapper.C(cEvents).Count(crit)
+	total, err := m.wrapper.C(cEvents).Count(ctx, crit)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -177,6 +178,11 @@ func (m *mongoDB) GetListEvents(ctx context.Context, filter *types.EventsFilter)
 }
 
 func (m *mongoDB) DeleteEmptyEvents(ctx context.Context, contractAddress string) error {
-	_, err := m.wrapper.C(cEvents).RemoveAll(bson.M{"address": contractAddress, "methodName": ""})
+	_, err := m.wrapper.C(cEvents).RemoveAll(ctx, bson.M{"address": contractAddress, "methodName": ""})
+	return err
+}
+
+func (m *mongoDB) DeleteEventsByBlockHeight(ctx context.Context, blockHeight uint64) error {
+	_, err := m.wrapper.C(cEvents).RemoveAll(ctx, bson.M{"blockHeight": blockHeight})
 	return err
 }
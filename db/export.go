@@ -0,0 +1,117 @@
+// Package db
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+// exportBatchSize is the number of documents fetched per round trip while exporting
+const exportBatchSize = 500
+
+// IExport stream records of an address oldest first, fn is called for each record and a non nil error stop the export
+type IExport interface {
+	ExportTxs(ctx context.Context, filter *types.ExportFilter, fn func(tx *types.Transaction) error) error
+	ExportTokenTransfers(ctx context.Context, filter *types.ExportFilter, fn func(transfer *types.TokenTransfer) error) error
+}
+
+func (m *mongoDB) ExportTxs(ctx context.Context, filter *types.ExportFilter, fn func(tx *types.Transaction) error) error {
+	crit := exportCrit(filter, "blockNumber")
+	if len(filter.ToAddresses) > 0 {
+		crit["from"] = filter.Address
+		crit["to"] = bson.M{"$in": filter.ToAddresses}
+	} else {
+		crit["$or"] = []bson.M{{"from": filter.Address}, {"to": filter.Address}}
+	}
+	cursor, err := m.exportCursor(ctx, cTxs, crit, filter.Limit)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	for cursor.Next(ctx) {
+		tx := &types.Transaction{}
+		if err := cursor.Decode(tx); err != nil {
+			return err
+		}
+		if err := fn(tx); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func (m *mongoDB) ExportTokenTransfers(ctx context.Context, filter *types.ExportFilter, fn func(transfer *types.TokenTransfer) error) error {
+	crit := exportCrit(filter, "blockHeight")
+	crit["$or"] = []bson.M{{"from": filter.Address}, {"to": filter.Address}}
+	switch filter.TokenType {
+	case cfg.SMCTypeKRC20:
+		crit["tokenID"] = bson.M{"$exists": false}
+	case cfg.SMCTypeKRC721:
+		crit["tokenID"] = bson.M{"$exists": true}
+	}
+	cursor, err := m.exportCursor(ctx, cInternalTxs, crit, filter.Limit)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	for cursor.Next(ctx) {
+		transfer := &types.TokenTransfer{}
+		if err := cursor.Decode(transfer); err != nil {
+			return err
+		}
+		if err := fn(transfer); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func (m *mongoDB) exportCursor(ctx context.Context, collection string, crit bson.M, limit int) (*mongo.Cursor, error) {
+	return m.wrapper.C(collection).Find(ctx, crit,
+		options.Find().SetSort(bson.M{"time": 1}),
+		options.Find().SetLimit(int64(limit)),
+		options.Find().SetBatchSize(exportBatchSize))
+}
+
+// exportCrit build time and block range criteria, heightField is block height field of the collection
+func exportCrit(filter *types.ExportFilter, heightField string) bson.M {
+	crit := bson.M{}
+	timeCrit := bson.M{}
+	if !filter.StartTime.IsZero() {
+		timeCrit["$gte"] = filter.StartTime
+	}
+	if !filter.EndTime.IsZero() {
+		timeCrit["$lte"] = filter.EndTime
+	}
+	if len(timeCrit) > 0 {
+		crit["time"] = timeCrit
+	}
+	heightCrit := bson.M{}
+	if filter.FromBlock > 0 {
+		heightCrit["$gte"] = filter.FromBlock
+	}
+	if filter.ToBlock > 0 {
+		heightCrit["$lte"] = filter.ToBlock
+	}
+	if len(heightCrit) > 0 {
+		crit[heightField] = heightCrit
+	}
+	return crit
+}
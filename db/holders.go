@@ -9,7 +9,7 @@ import (
 )
 
 func (m *mongoDB) AllOldHolders(ctx context.Context) ([]*types.KRC20Holder, error) {
-	cursor, err := m.wrapper.C("Holders").Find(bson.M{})
+	cursor, err := m.wrapper.C("Holders").Find(ctx, bson.M{})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,121 @@
+// Package db
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+var cInternalCalls = "InternalCalls"
+
+type IInternalCall interface {
+	createInternalCallsCollectionIndexes() []mongo.IndexModel
+	UpsertInternalCalls(ctx context.Context, calls []*types.InternalCall) error
+	InternalCalls(ctx context.Context, filter *types.InternalCallsFilter) ([]*types.InternalCall, uint64, error)
+	InternalCallsByTxHashes(ctx context.Context, txHashes []string) (map[string][]*types.InternalCall, error)
+	RemoveInternalCallsByBlockHeight(ctx context.Context, blockHeight uint64) error
+}
+
+func (m *mongoDB) createInternalCallsCollectionIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.D{{Key: "txHash", Value: 1}, {Key: "path", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "from", Value: 1}, {Key: "time", Value: -1}}},
+		{Keys: bson.D{{Key: "to", Value: 1}, {Key: "time", Value: -1}}},
+		{Keys: bson.M{"blockHeight": -1}},
+	}
+}
+
+// UpsertInternalCalls store calls by tx hash and path, so tracing a tx again is idempotent
+func (m *mongoDB) UpsertInternalCalls(ctx context.Context, calls []*types.InternalCall) error {
+	if len(calls) == 0 {
+		return nil
+	}
+	models := make([]mongo.WriteModel, len(calls))
+	for i, call := range calls {
+		models[i] = mongo.NewUpdateOneModel().SetUpsert(true).SetFilter(bson.M{"txHash": call.TxHash, "path": call.Path}).SetUpdate(bson.M{"$set": call})
+	}
+	if _, err := m.wrapper.C(cInternalCalls).BulkUpsert(ctx, models); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *mongoDB) InternalCalls(ctx context.Context, filter *types.InternalCallsFilter) ([]*types.InternalCall, uint64, error) {
+	var (
+		calls []*types.InternalCall
+		crit  = bson.M{}
+		opts  []*options.FindOptions
+	)
+	if filter.TxHash != "" {
+		crit["txHash"] = filter.TxHash
+		// calls of a tx are listed in execution order
+		opts = append(opts, options.Find().SetSort(bson.M{"index": 1}))
+	}
+	if filter.Address != "" {
+		crit["$or"] = []bson.M{{"from": filter.Address}, {"to": filter.Address}}
+		opts = append(opts, options.Find().SetSort(bson.D{{Key: "time", Value: -1}, {Key: "index", Value: 1}}))
+	}
+	if filter.Pagination != nil {
+		filter.Pagination.Sanitize()
+		opts = append(opts, options.Find().SetSkip(int64(filter.Pagination.Skip)), options.Find().SetLimit(int64(filter.Pagination.Limit)))
+	}
+	cursor, err := m.wrapper.C(cInternalCalls).Find(ctx, crit, opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	if err := cursor.All(ctx, &calls); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := m.wrapper.C(cInternalCalls).Count(ctx, crit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return calls, uint64(total), nil
+}
+
+// InternalCallsByTxHashes return calls of each tx in execution order
+func (m *mongoDB) InternalCallsByTxHashes(ctx context.Context, txHashes []string) (map[string][]*types.InternalCall, error) {
+	result := make(map[string][]*types.InternalCall, len(txHashes))
+	if len(txHashes) == 0 {
+		return result, nil
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "txHash", Value: 1}, {Key: "index", Value: 1}})
+	cursor, err := m.wrapper.C(cInternalCalls).Find(ctx, bson.M{"txHash": bson.M{"$in": txHashes}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	var calls []*types.InternalCall
+	if err := cursor.All(ctx, &calls); err != nil {
+		return nil, err
+	}
+	for _, call := range calls {
+		result[call.TxHash] = append(result[call.TxHash], call)
+	}
+	return result, nil
+}
+
+func (m *mongoDB) RemoveInternalCallsByBlockHeight(ctx context.Context, blockHeight uint64) error {
+	if _, err := m.wrapper.C(cInternalCalls).RemoveAll(ctx, bson.M{"blockHeight": blockHeight}); err != nil {
+		return err
+	}
+	return nil
+}
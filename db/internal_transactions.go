@@ -12,6 +12,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"github.com/kardiachain/kardia-explorer-backend/cfg"
 	"github.com/kardiachain/kardia-explorer-backend/types"
 )
 
@@ -33,6 +34,7 @@ func (m *mongoDB) createInternalTxsCollectionIndexes() []mongo.IndexModel {
 		{Keys: bson.M{"to": 1}, Options: options.Index().SetSparse(true)},
 		{Keys: bson.M{"txHash": 1}, Options: options.Index().SetSparse(true)},
 		{Keys: bson.M{"time": -1}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.M{"blockHeight": -1}, Options: options.Index().SetSparse(true)},
 	}
 }
 
@@ -43,7 +45,7 @@ func (m *mongoDB) InsertInternalTxs(ctx context.Context, internalTx *types.Token
 	internalTx.To = common.HexToAddress(internalTx.To).String()
 	internalTx.TransferID = fmt.Sprintf("%s-%s-%d", internalTx.TransactionHash, internalTx.Contract, internalTx.LogIndex)
 
-	if _, err := m.wrapper.C(cInternalTxs).Insert(internalTx); err != nil {
+	if _, err := m.wrapper.C(cInternalTxs).Insert(ctx, internalTx); err != nil {
 		return err
 	}
 	return nil
@@ -59,7 +61,7 @@ func (m *mongoDB) UpdateInternalTxs(ctx context.Context, internalTxs []*types.To
 		iTxsBulkWriter[i] = iTxs
 	}
 	if len(iTxsBulkWriter) > 0 {
-		if _, err := m.wrapper.C(cInternalTxs).BulkUpsert(iTxsBulkWriter); err != nil {
+		if _, err := m.wrapper.C(cInternalTxs).BulkUpsert(ctx, iTxsBulkWriter); err != nil {
 			return err
 		}
 	}
@@ -76,7 +78,7 @@ func (m *mongoDB) RemoveInternalTxs(ctx context.Context, filter *types.InternalT
 	if err != nil {
 		m.logger.Warn("Cannot unmarshal txs filter criteria", zap.Error(err))
 	}
-	if _, err = m.wrapper.C(cInternalTxs).RemoveAll(crit); err != nil {
+	if _, err = m.wrapper.C(cInternalTxs).RemoveAll(ctx, crit); err != nil {
 		return err
 	}
 	return nil
@@ -99,6 +101,12 @@ func (m *mongoDB) GetListInternalTxs(ctx context.Context, filter *types.Internal
 		//opts = append(opts, options.Find().SetHint(bson.M{"from": 1}))
 		//opts = append(opts, options.Find().SetHint(bson.M{"to": 1}))
 	}
+	if len(filter.Addresses) > 0 {
+		andCrit = append(andCrit, bson.M{"$or": []bson.M{
+			{"from": bson.M{"$in": filter.Addresses}},
+			{"to": bson.M{"$in": filter.Addresses}},
+		}})
+	}
 	if filter.Contract != "" {
 		andCrit = append(andCrit, bson.M{"contractAddress": filter.Contract})
 		//opts = append(opts, options.Find().SetHint(bson.M{"contractAddress": 1}))
@@ -107,13 +115,32 @@ func (m *mongoDB) GetListInternalTxs(ctx context.Context, filter *types.Internal
 		andCrit = append(andCrit, bson.M{"txHash": filter.TransactionHash})
 		opts = append(opts, options.Find().SetHint(bson.M{"txHash": 1}))
 	}
+	if filter.BlockHeight != 0 {
+		andCrit = append(andCrit, bson.M{"blockHeight": filter.BlockHeight})
+	}
+	if r := filter.BlockRange; r != nil {
+		heightCrit := bson.M{"$gte": r.FromBlock}
+		if r.ToBlock != 0 {
+			heightCrit["$lte"] = r.ToBlock
+		}
+		andCrit = append(andCrit, bson.M{"blockHeight": heightCrit})
+		if r.Asc {
+			opts[0] = options.Find().SetSort(bson.M{"time": 1})
+		}
+	}
+	switch filter.TokenType {
+	case cfg.SMCTypeKRC20:
+		andCrit = append(andCrit, bson.M{"tokenID": bson.M{"$exists": false}})
+	case cfg.SMCTypeKRC721:
+		andCrit = append(andCrit, bson.M{"tokenID": bson.M{"$exists": true}})
+	}
 	crit := bson.M{"$and": andCrit}
 
 	if filter.Pagination != nil {
 		filter.Pagination.Sanitize()
 		opts = append(opts, options.Find().SetSkip(int64(filter.Pagination.Skip)), options.Find().SetLimit(int64(filter.Pagination.Limit)))
 	}
-	cursor, err := m.wrapper.C(cInternalTxs).Find(crit, opts...)
+	cursor, err := m.wrapper.C(cInternalTxs).Find(ctx, crit, opts...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -128,7 +155,7 @@ func (m *mongoDB) GetListInternalTxs(ctx context.Context, filter *types.Internal
 		return nil, 0, err
 	}
 
-	total, err := m.wrapper.C(cInternalTxs).Count(crit)
+	total, err := m.wrapper.C(cInternalTxs).Count(ctx, crit)
 	if err != nil {
 		return nil, 0, err
 	}
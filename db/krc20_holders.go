@@ -33,7 +33,7 @@ func (m *mongoDB) createKRC20HoldersCollectionIndexes() []mongo.IndexModel {
 }
 
 func (m *mongoDB) RemoveKRC20Holders(ctx context.Context) error {
-	if _, err := m.wrapper.C(cKRC20Holders).RemoveAll(bson.M{"balance": "0"}); err != nil {
+	if _, err := m.wrapper.C(cKRC20Holders).RemoveAll(ctx, bson.M{"balance": "0"}); err != nil {
 		return err
 	}
 
@@ -48,7 +48,7 @@ func (m *mongoDB) UpsertKRC20Holders(ctx context.Context, holdersInfo []*types.K
 		holdersBulkWriter[i] = txModel
 	}
 	if len(holdersBulkWriter) > 0 {
-		if _, err := m.wrapper.C(cKRC20Holders).BulkWrite(holdersBulkWriter); err != nil {
+		if _, err := m.wrapper.C(cKRC20Holders).BulkWrite(ctx, holdersBulkWriter); err != nil {
 			return err
 		}
 	}
@@ -57,7 +57,7 @@ func (m *mongoDB) UpsertKRC20Holders(ctx context.Context, holdersInfo []*types.K
 
 func (m *mongoDB) RemoveKRC20Holder(ctx context.Context, holder *types.KRC20Holder) error {
 	holderToRemove := common.HexToAddress(holder.HolderAddress).String()
-	if _, err := m.wrapper.C(cKRC20Holders).Remove(bson.M{"holderAddress": holderToRemove, "contractAddress": holder.ContractAddress}); err != nil {
+	if _, err := m.wrapper.C(cKRC20Holders).Remove(ctx, bson.M{"holderAddress": holderToRemove, "contractAddress": holder.ContractAddress}); err != nil {
 		return err
 	}
 	return nil
@@ -70,7 +70,7 @@ func (m *mongoDB) UpdateKRC20Holders(ctx context.Context, holdersInfo []*types.K
 		holdersBulkWriter[i] = txModel
 	}
 	if len(holdersBulkWriter) > 0 {
-		if _, err := m.wrapper.C(cKRC20Holders).BulkWrite(holdersBulkWriter); err != nil {
+		if _, err := m.wrapper.C(cKRC20Holders).BulkWrite(ctx, holdersBulkWriter); err != nil {
 			return err
 		}
 	}
@@ -97,7 +97,7 @@ func (m *mongoDB) KRC20Holders(ctx context.Context, filter *types.KRC20HolderFil
 		filter.Pagination.Sanitize()
 		opts = append(opts, options.Find().SetSkip(int64(filter.Pagination.Skip)), options.Find().SetLimit(int64(filter.Pagination.Limit)))
 	}
-	cursor, err := m.wrapper.C(cKRC20Holders).Find(crit, opts...)
+	cursor, err := m.wrapper.C(cKRC20Holders).Find(ctx, crit, opts...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -112,7 +112,7 @@ func (m *mongoDB) KRC20Holders(ctx context.Context, filter *types.KRC20HolderFil
 		return nil, 0, err
 	}
 
-	total, err := m.wrapper.C(cKRC20Holders).Count(crit)
+	total, err := m.wrapper.C(cKRC20Holders).Count(ctx, crit)
 	if err != nil {
 		return nil, 0, err
 	}
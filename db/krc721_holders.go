@@ -21,6 +21,7 @@ type IKRC721Holder interface {
 	UpdateKRC721Holders(ctx context.Context, holdersInfo []*types.KRC721Holder) error
 	KRC721Holders(ctx context.Context, filter types.KRC721HolderFilter) ([]*types.KRC721Holder, uint64, error)
 	RemoveKRC721Holder(ctx context.Context, holder *types.KRC721Holder) error
+	RemoveKRC721HolderByTokenID(ctx context.Context, contractAddress, tokenID string) error
 }
 
 func (m *mongoDB) createKRC721HolderCollectionIndexes() []mongo.IndexModel {
@@ -39,7 +40,7 @@ func (m *mongoDB) UpdateKRC721Holders(ctx context.Context, holdersInfo []*types.
 		holdersBulkWriter[i] = txModel
 	}
 	if len(holdersBulkWriter) > 0 {
-		if _, err := m.wrapper.C(cKRC721Holders).BulkWrite(holdersBulkWriter); err != nil {
+		if _, err := m.wrapper.C(cKRC721Holders).BulkWrite(ctx, holdersBulkWriter); err != nil {
 			return err
 		}
 	}
@@ -70,7 +71,7 @@ func (m *mongoDB) KRC721Holders(ctx context.Context, filter types.KRC721HolderFi
 		filter.Pagination.Sanitize()
 		opts = append(opts, options.Find().SetSkip(int64(filter.Pagination.Skip)), options.Find().SetLimit(int64(filter.Pagination.Limit)))
 	}
-	cursor, err := m.wrapper.C(cKRC721Holders).Find(crit, opts...)
+	cursor, err := m.wrapper.C(cKRC721Holders).Find(ctx, crit, opts...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -85,7 +86,7 @@ func (m *mongoDB) KRC721Holders(ctx context.Context, filter types.KRC721HolderFi
 		return nil, 0, err
 	}
 
-	total, err := m.wrapper.C(cKRC721Holders).Count(crit)
+	total, err := m.wrapper.C(cKRC721Holders).Count(ctx, crit)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -95,7 +96,15 @@ func (m *mongoDB) KRC721Holders(ctx context.Context, filter types.KRC721HolderFi
 
 func (m *mongoDB) RemoveKRC721Holder(ctx context.Context, holder *types.KRC721Holder) error {
 	holderToRemove := common.HexToAddress(holder.Address).String()
-	if _, err := m.wrapper.C(cKRC721Holders).Remove(bson.M{"holderAddress": holderToRemove, "contractAddress": holder.ContractAddress}); err != nil {
+	if _, err := m.wrapper.C(cKRC721Holders).Remove(ctx, bson.M{"holderAddress": holderToRemove, "contractAddress": holder.ContractAddress}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *mongoDB) RemoveKRC721HolderByTokenID(ctx context.Context, contractAddress, tokenID string) error {
+	holderID := fmt.Sprintf("%s-%s", contractAddress, tokenID)
+	if _, err := m.wrapper.C(cKRC721Holders).RemoveAll(ctx, bson.M{"holderID": holderID}); err != nil {
 		return err
 	}
 	return nil
@@ -110,7 +119,7 @@ func (m *mongoDB) UpsertKRC721Holders(ctx context.Context, holders []*types.KRC7
 		holdersBulkWriter[i] = txModel
 	}
 	if len(holdersBulkWriter) > 0 {
-		if _, err := m.wrapper.C(cKRC721Holders).BulkWrite(holdersBulkWriter); err != nil {
+		if _, err := m.wrapper.C(cKRC721Holders).BulkWrite(ctx, holdersBulkWriter); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,164 @@
+// Package db
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+var cAddressLabels = "AddressLabels"
+
+type ILabel interface {
+	createAddressLabelsCollectionIndexes() []mongo.IndexModel
+
+	UpsertAddressLabels(ctx context.Context, labels []*types.AddressLabel) error
+	RemoveAddressLabel(ctx context.Context, address, category, name string) (bool, error)
+	AddressLabels(ctx context.Context, filter *types.AddressLabelsFilter) ([]*types.AddressLabel, uint64, error)
+	// LabelsOfAddresses return labels of addresses by address, unlabeled addresses are missing
+	LabelsOfAddresses(ctx context.Context, addresses []string) (map[string][]*types.Label, error)
+	// LabeledAddresses return at most limit distinct addresses carrying a label matching filter
+	LabeledAddresses(ctx context.Context, filter *types.AddressLabelsFilter, limit int) ([]string, error)
+}
+
+func (m *mongoDB) createAddressLabelsCollectionIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.D{{Key: "address", Value: 1}, {Key: "category", Value: 1}, {Key: "name", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "category", Value: 1}, {Key: "name", Value: 1}}},
+		{Keys: bson.M{"source": 1}},
+	}
+}
+
+func (m *mongoDB) UpsertAddressLabels(ctx context.Context, labels []*types.AddressLabel) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	models := make([]mongo.WriteModel, len(labels))
+	for i, label := range labels {
+		models[i] = mongo.NewReplaceOneModel().SetUpsert(true).
+			SetFilter(bson.M{"address": label.Address, "category": label.Category, "name": label.Name}).
+			SetReplacement(label)
+	}
+	if _, err := m.wrapper.C(cAddressLabels).BulkWrite(ctx, models); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RemoveAddressLabel return false when the address had no such label
+func (m *mongoDB) RemoveAddressLabel(ctx context.Context, address, category, name string) (bool, error) {
+	res, err := m.wrapper.C(cAddressLabels).Remove(ctx, bson.M{"address": address, "category": category, "name": name})
+	if err != nil {
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
+
+func (m *mongoDB) AddressLabels(ctx context.Context, filter *types.AddressLabelsFilter) ([]*types.AddressLabel, uint64, error) {
+	var (
+		labels []*types.AddressLabel
+		crit   = addressLabelsCriteria(filter)
+		opts   = []*options.FindOptions{
+			options.Find().SetSort(bson.D{{Key: "address", Value: 1}, {Key: "category", Value: 1}, {Key: "name", Value: 1}}),
+		}
+	)
+	if filter.Pagination != nil {
+		filter.Pagination.Sanitize()
+		opts = append(opts, options.Find().SetSkip(int64(filter.Pagination.Skip)), options.Find().SetLimit(int64(filter.Pagination.Limit)))
+	}
+	cursor, err := m.wrapper.C(cAddressLabels).Find(ctx, crit, opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	if err := cursor.All(ctx, &labels); err != nil {
+		return nil, 0, err
+	}
+	total, err := m.wrapper.C(cAddressLabels).Count(ctx, crit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return labels, uint64(total), nil
+}
+
+func (m *mongoDB) LabelsOfAddresses(ctx context.Context, addresses []string) (map[string][]*types.Label, error) {
+	result := make(map[string][]*types.Label)
+	if len(addresses) == 0 {
+		return result, nil
+	}
+	cursor, err := m.wrapper.C(cAddressLabels).Find(ctx, bson.M{"address": bson.M{"$in": addresses}},
+		options.Find().SetSort(bson.D{{Key: "confidence", Value: -1}, {Key: "category", Value: 1}, {Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	for cursor.Next(ctx) {
+		label := &types.AddressLabel{}
+		if err := cursor.Decode(label); err != nil {
+			return nil, err
+		}
+		result[label.Address] = append(result[label.Address], &label.Label)
+	}
+	return result, cursor.Err()
+}
+
+func (m *mongoDB) LabeledAddresses(ctx context.Context, filter *types.AddressLabelsFilter, limit int) ([]string, error) {
+	cursor, err := m.wrapper.C(cAddressLabels).Find(ctx, addressLabelsCriteria(filter),
+		options.Find().SetProjection(bson.M{"address": 1}).SetSort(bson.M{"address": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	var (
+		addresses []string
+		seen      = make(map[string]bool)
+	)
+	for len(addresses) < limit && cursor.Next(ctx) {
+		var label types.AddressLabel
+		if err := cursor.Decode(&label); err != nil {
+			return nil, err
+		}
+		if !seen[label.Address] {
+			seen[label.Address] = true
+			addresses = append(addresses, label.Address)
+		}
+	}
+	return addresses, cursor.Err()
+}
+
+func addressLabelsCriteria(filter *types.AddressLabelsFilter) bson.M {
+	crit := bson.M{}
+	if filter.Address != "" {
+		crit["address"] = filter.Address
+	}
+	if filter.Category != "" {
+		crit["category"] = filter.Category
+	}
+	if filter.Name != "" {
+		crit["name"] = filter.Name
+	}
+	if filter.Source != "" {
+		crit["source"] = filter.Source
+	}
+	return crit
+}
@@ -25,7 +25,7 @@ func (m *mongoDB) InsertLogs(ctx context.Context, logs []*types.Log) error {
 		models = append(models, mongo.NewUpdateOneModel().SetUpsert(true).SetFilter(bson.M{"address": l.Address, "txHash": l.TxHash, "index": l.Index}).SetUpdate(bson.M{"$set": l}))
 	}
 
-	if _, err := m.wrapper.C(cLog).BulkWrite(models); err != nil {
+	if _, err := m.wrapper.C(cLog).BulkWrite(ctx, models); err != nil {
 		lgr.Error("cannot insert logs", zap.Error(err))
 		return err
 	}
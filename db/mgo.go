@@ -21,14 +21,13 @@ package db
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/kardiachain/go-kardia/lib/common"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"go.uber.org/zap"
 	"gopkg.in/mgo.v2"
 
@@ -103,6 +102,7 @@ func createIndexes(dbClient *mongoDB) error {
 		{c: cBlocks, model: []mongo.IndexModel{{Keys: bson.M{"height": -1}, Options: options.Index().SetUnique(true).SetSparse(true)}}},
 		{c: cBlocks, model: []mongo.IndexModel{{Keys: bson.M{"hash": 1}, Options: options.Index().SetUnique(true).SetSparse(true)}}},
 		{c: cBlocks, model: []mongo.IndexModel{{Keys: bson.D{{Key: "proposerAddress", Value: 1}, {Key: "time", Value: -1}}, Options: options.Index().SetSparse(true)}}},
+		{c: cBlocks, model: []mongo.IndexModel{{Keys: bson.M{"time": -1}, Options: options.Index().SetSparse(true)}}},
 		// indexing addresses collection
 		{c: cAddresses, model: []mongo.IndexModel{{Keys: bson.M{"address": 1}, Options: options.Index().SetUnique(true).SetSparse(true)}}},
 		{c: cAddresses, model: []mongo.IndexModel{{Keys: bson.M{"name": 1}, Options: options.Index().SetSparse(true)}}},
@@ -119,6 +119,8 @@ func createIndexes(dbClient *mongoDB) error {
 		{c: cContract, model: []mongo.IndexModel{{Keys: bson.M{"name": 1}, Options: options.Index().SetSparse(true)}}},
 		{c: cContract, model: []mongo.IndexModel{{Keys: bson.M{"type": 1}, Options: options.Index().SetSparse(true)}}},
 		{c: cContract, model: []mongo.IndexModel{{Keys: bson.M{"address": 1}, Options: options.Index().SetUnique(true).SetSparse(true)}}},
+		{c: cContract, model: []mongo.IndexModel{{Keys: bson.M{"implementation": 1}, Options: options.Index().SetSparse(true)}}},
+		{c: cContract, model: []mongo.IndexModel{{Keys: bson.M{"beacon": 1}, Options: options.Index().SetSparse(true)}}},
 		{c: cABI, model: []mongo.IndexModel{{Keys: bson.M{"type": 1}, Options: options.Index().SetUnique(true).SetSparse(true)}}},
 		// indexing contract events collection
 		{c: cEvents, model: dbClient.createEventsCollectionIndexes()},
@@ -128,6 +130,24 @@ func createIndexes(dbClient *mongoDB) error {
 		// indexing internal txs collection
 		{c: cInternalTxs, model: dbClient.createInternalTxsCollectionIndexes()},
 		{c: cDelegator, model: createDelegatorCollectionIndexes()},
+		{c: cVerifyAudits, model: dbClient.createVerifyAuditsCollectionIndexes()},
+		{c: cCheckpoints, model: dbClient.createCheckpointsCollectionIndexes()},
+		{c: cProxyUpgrades, model: dbClient.createProxyUpgradesCollectionIndexes()},
+		{c: cSignatures, model: dbClient.createSignaturesCollectionIndexes()},
+		{c: cInternalCalls, model: dbClient.createInternalCallsCollectionIndexes()},
+		{c: cBalanceEntries, model: dbClient.createBalanceEntriesCollectionIndexes()},
+		{c: cRollups, model: dbClient.createRollupsCollectionIndexes()},
+		{c: cAddressFirstSeen, model: dbClient.createAddressFirstSeenCollectionIndexes()},
+		{c: cTokenPrices, model: dbClient.createTokenPricesCollectionIndexes()},
+		{c: cTokenPriceSources, model: dbClient.createTokenPriceSourcesCollectionIndexes()},
+		{c: cAPIKeys, model: dbClient.createAPIKeysCollectionIndexes()},
+		{c: cAdminUsers, model: dbClient.createAdminUsersCollectionIndexes()},
+		{c: cAuditLogs, model: dbClient.createAuditLogsCollectionIndexes()},
+		{c: cAddressLabels, model: dbClient.createAddressLabelsCollectionIndexes()},
+		// case-insensitive prefix and text indexes of universal search
+		{c: cAddresses, model: append(createSearchIndexes("name"), addressPrefixIndex)},
+		{c: cContract, model: append(createSearchIndexes("name", "symbol"), addressPrefixIndex)},
+		{c: cValidators, model: append(createSearchIndexes("name"), addressPrefixIndex)},
 	}
 	for _, cIdx := range indexes {
 		if err := dbClient.wrapper.C(cIdx.c).EnsureIndex(cIdx.model); err != nil {
@@ -143,8 +163,12 @@ func (m *mongoDB) ping() error {
 	return nil
 }
 
+func (m *mongoDB) Ping(ctx context.Context) error {
+	return m.wrapper.DB.Client().Ping(ctx, readpref.Primary())
+}
+
 func (m *mongoDB) dropCollection(collectionName string) {
-	if _, err := m.wrapper.C(collectionName).RemoveAll(nil); err != nil {
+	if _, err := m.wrapper.C(collectionName).RemoveAll(context.Background(), nil); err != nil {
 		return
 	}
 }
@@ -158,12 +182,12 @@ func (m *mongoDB) dropDatabase(ctx context.Context) error {
 // region Stats
 
 func (m *mongoDB) UpdateStats(ctx context.Context, stats *types.Stats) error {
-	_, err := m.wrapper.C(cStats).Insert(stats)
+	_, err := m.wrapper.C(cStats).Insert(ctx, stats)
 	if err != nil {
 		return err
 	}
 	// remove old stats
-	if _, err := m.wrapper.C(cStats).RemoveAll(bson.M{"updatedAtBlock": bson.M{"$lt": stats.UpdatedAtBlock}}); err != nil {
+	if _, err := m.wrapper.C(cStats).RemoveAll(ctx, bson.M{"updatedAtBlock": bson.M{"$lt": stats.UpdatedAtBlock}}); err != nil {
 		m.logger.Warn("cannot remove old stats", zap.Error(err), zap.Uint64("latest updated block", stats.UpdatedAtBlock))
 		return err
 	}
@@ -172,7 +196,7 @@ func (m *mongoDB) UpdateStats(ctx context.Context, stats *types.Stats) error {
 
 func (m *mongoDB) Stats(ctx context.Context) *types.Stats {
 	var stats *types.Stats
-	if err := m.wrapper.C(cStats).FindOne(bson.M{}).Decode(&stats); err == nil {
+	if err := m.wrapper.C(cStats).FindOne(ctx, bson.M{}).Decode(&stats); err == nil {
 		// remove blocks after checkpoint
 		latestBlock, err := m.Blocks(ctx, &types.Pagination{
 			Skip:  0,
@@ -256,7 +280,7 @@ func (m *mongoDB) Blocks(ctx context.Context, pagination *types.Pagination) ([]*
 	}
 
 	cursor, err := m.wrapper.C(cBlocks).
-		Find(bson.D{}, opts...)
+		Find(ctx, bson.D{}, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get latest blocks: %v", err)
 	}
@@ -279,7 +303,7 @@ func (m *mongoDB) Blocks(ctx context.Context, pagination *types.Pagination) ([]*
 
 func (m *mongoDB) BlockByHeight(ctx context.Context, blockNumber uint64) (*types.Block, error) {
 	var block types.Block
-	if err := m.wrapper.C(cBlocks).FindOne(bson.M{"height": blockNumber},
+	if err := m.wrapper.C(cBlocks).FindOne(ctx, bson.M{"height": blockNumber},
 		options.FindOne().SetProjection(bson.M{"txs": 0, "receipts": 0}),
 		options.FindOne().SetHint(bson.M{"height": -1})).Decode(&block); err != nil {
 		return nil, err
@@ -287,9 +311,48 @@ func (m *mongoDB) BlockByHeight(ctx context.Context, blockNumber uint64) (*types
 	return &block, nil
 }
 
+// BlockHeightByTime return the closest block mined before (or after) input time
+func (m *mongoDB) BlockHeightByTime(ctx context.Context, t time.Time, before bool) (uint64, error) {
+	var (
+		block types.Block
+		crit  = bson.M{"time": bson.M{"$gte": t}}
+		order = 1
+	)
+	if before {
+		crit = bson.M{"time": bson.M{"$lte": t}}
+		order = -1
+	}
+	if err := m.wrapper.C(cBlocks).FindOne(ctx, crit,
+		options.FindOne().SetProjection(bson.M{"height": 1}),
+		options.FindOne().SetSort(bson.M{"time": order})).Decode(&block); err != nil {
+		return 0, err
+	}
+	return block.Height, nil
+}
+
+// BlocksByHeights return found blocks of input heights, in no particular order
+func (m *mongoDB) BlocksByHeights(ctx context.Context, blockHeights []uint64) ([]*types.Block, error) {
+	var blocks []*types.Block
+	cursor, err := m.wrapper.C(cBlocks).Find(ctx, bson.M{"height": bson.M{"$in": blockHeights}},
+		options.Find().SetProjection(bson.M{"txs": 0, "receipts": 0}))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	if err := cursor.All(ctx, &blocks); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
 func (m *mongoDB) BlockByHash(ctx context.Context, blockHash string) (*types.Block, error) {
 	var block types.Block
-	err := m.wrapper.C(cBlocks).FindOne(bson.M{"hash": blockHash},
+	err := m.wrapper.C(cBlocks).FindOne(ctx, bson.M{"hash": blockHash},
 		options.FindOne().SetProjection(bson.M{"txs": 0, "receipts": 0}),
 		options.FindOne().SetHint(bson.M{"hash": 1})).Decode(&block)
 	if err != nil {
@@ -301,7 +364,7 @@ func (m *mongoDB) BlockByHash(ctx context.Context, blockHash string) (*types.Blo
 func (m *mongoDB) IsBlockExist(ctx context.Context, blockHeight uint64) (bool, error) {
 	var dbBlock types.Block
 	m.logger.Info("Find block with height", zap.Uint64("Height", blockHeight))
-	err := m.wrapper.C(cBlocks).FindOne(bson.M{"height": blockHeight}, options.FindOne().SetProjection(bson.M{"txs": 0, "receipts": 0})).Decode(&dbBlock)
+	err := m.wrapper.C(cBlocks).FindOne(ctx, bson.M{"height": blockHeight}, options.FindOne().SetProjection(bson.M{"txs": 0, "receipts": 0})).Decode(&dbBlock)
 	if err != nil {
 		m.logger.Error("find error")
 		if err == mongo.ErrNoDocuments {
@@ -317,13 +380,13 @@ func (m *mongoDB) InsertBlock(ctx context.Context, block *types.Block) error {
 	logger := m.logger
 	// Upsert block into Blocks
 	block.ProposerAddress = common.HexToAddress(block.ProposerAddress).String()
-	_, err := m.wrapper.C(cBlocks).Insert(block)
+	_, err := m.wrapper.C(cBlocks).Insert(ctx, block)
 	if err != nil {
 		logger.Warn("cannot insert new block", zap.Error(err))
 		return fmt.Errorf("cannot insert new block")
 	}
 
-	if _, err := m.wrapper.C(cTxs).RemoveAll(bson.M{"blockNumber": block.Height}); err != nil {
+	if _, err := m.wrapper.C(cTxs).RemoveAll(ctx, bson.M{"blockNumber": block.Height}); err != nil {
 		logger.Warn("cannot remove old block txs", zap.Error(err))
 		return err
 	}
@@ -331,6 +394,32 @@ func (m *mongoDB) InsertBlock(ctx context.Context, block *types.Block) error {
 	return nil
 }
 
+// ReplaceBlock overwrite block header at the same height without touching its txs
+func (m *mongoDB) ReplaceBlock(ctx context.Context, block *types.Block) error {
+	block.ProposerAddress = common.HexToAddress(block.ProposerAddress).String()
+	if _, err := m.wrapper.C(cBlocks).Upsert(ctx, bson.M{"height": block.Height}, block); err != nil {
+		m.logger.Warn("cannot replace block", zap.Error(err), zap.Uint64("height", block.Height))
+		return err
+	}
+	return nil
+}
+
+// UpsertBlocks write a batch of blocks, existing blocks at the same height are replaced
+func (m *mongoDB) UpsertBlocks(ctx context.Context, blocks []*types.Block) error {
+	var blocksBulkWriter []mongo.WriteModel
+	for _, block := range blocks {
+		block.ProposerAddress = common.HexToAddress(block.ProposerAddress).String()
+		blockModel := mongo.NewReplaceOneModel().SetUpsert(true).SetFilter(bson.M{"height": block.Height}).SetReplacement(block)
+		blocksBulkWriter = append(blocksBulkWriter, blockModel)
+	}
+	if len(blocksBulkWriter) > 0 {
+		if _, err := m.wrapper.C(cBlocks).BulkWrite(ctx, blocksBulkWriter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *mongoDB) DeleteLatestBlock(ctx context.Context) (uint64, error) {
 	blocks, err := m.Blocks(ctx, &types.Pagination{
 		Skip:  0,
@@ -352,11 +441,11 @@ func (m *mongoDB) DeleteLatestBlock(ctx context.Context) (uint64, error) {
 }
 
 func (m *mongoDB) DeleteBlockByHeight(ctx context.Context, blockHeight uint64) error {
-	if _, err := m.wrapper.C(cBlocks).RemoveAll(bson.M{"height": blockHeight}); err != nil {
+	if _, err := m.wrapper.C(cBlocks).RemoveAll(ctx, bson.M{"height": blockHeight}); err != nil {
 		m.logger.Warn("cannot remove old latest block", zap.Error(err), zap.Uint64("latest block height", blockHeight))
 		return err
 	}
-	if _, err := m.wrapper.C(cTxs).RemoveAll(bson.M{"blockNumber": blockHeight}); err != nil {
+	if _, err := m.wrapper.C(cTxs).RemoveAll(ctx, bson.M{"blockNumber": blockHeight}); err != nil {
 		m.logger.Warn("cannot remove old latest block txs", zap.Error(err), zap.Uint64("latest block height", blockHeight))
 		return err
 	}
@@ -375,7 +464,7 @@ func (m *mongoDB) BlocksByProposer(ctx context.Context, proposer string, paginat
 		}
 	}
 	cursor, err := m.wrapper.C(cBlocks).
-		Find(bson.M{"proposerAddress": proposer}, opts...)
+		Find(ctx, bson.M{"proposerAddress": proposer}, opts...)
 	defer func() {
 		err = cursor.Close(ctx)
 		if err != nil {
@@ -396,7 +485,7 @@ func (m *mongoDB) BlocksByProposer(ctx context.Context, proposer string, paginat
 		blocks = append(blocks, block)
 	}
 	// get total transaction in block in database
-	total, err := m.wrapper.C(cBlocks).Count(bson.M{"proposerAddress": proposer})
+	total, err := m.wrapper.C(cBlocks).Count(ctx, bson.M{"proposerAddress": proposer})
 	if err != nil {
 		return nil, 0, err
 	}
@@ -404,7 +493,7 @@ func (m *mongoDB) BlocksByProposer(ctx context.Context, proposer string, paginat
 }
 
 func (m *mongoDB) CountBlocksOfProposer(ctx context.Context, proposerAddress string) (int64, error) {
-	total, err := m.wrapper.C(cBlocks).Count(bson.M{"proposerAddress": proposerAddress})
+	total, err := m.wrapper.C(cBlocks).Count(ctx, bson.M{"proposerAddress": proposerAddress})
 	if err != nil {
 		return 0, err
 	}
@@ -436,7 +525,7 @@ func (m *mongoDB) AddVoteToProposal(ctx context.Context, proposalInfo *types.Pro
 		proposalInfo.NumberOfVoteAbstain = currentProposal.NumberOfVoteAbstain
 		proposalInfo.NumberOfVoteYes = currentProposal.NumberOfVoteYes
 	}
-	if err := m.upsertProposal(proposalInfo); err != nil {
+	if err := m.upsertProposal(ctx, proposalInfo); err != nil {
 		return err
 	}
 	return nil
@@ -450,7 +539,7 @@ func (m *mongoDB) UpsertProposal(ctx context.Context, proposalInfo *types.Propos
 		proposalInfo.NumberOfVoteYes = currentProposal.NumberOfVoteYes
 		proposalInfo.NumberOfVoteNo = currentProposal.NumberOfVoteNo
 	}
-	if err := m.upsertProposal(proposalInfo); err != nil {
+	if err := m.upsertProposal(ctx, proposalInfo); err != nil {
 		return err
 	}
 	return nil
@@ -458,20 +547,20 @@ func (m *mongoDB) UpsertProposal(ctx context.Context, proposalInfo *types.Propos
 
 func (m *mongoDB) ProposalInfo(ctx context.Context, proposalID uint64) (*types.ProposalDetail, error) {
 	var result *types.ProposalDetail
-	err := m.wrapper.C(cProposal).FindOne(bson.M{"id": proposalID}).Decode(&result)
+	err := m.wrapper.C(cProposal).FindOne(ctx, bson.M{"id": proposalID}).Decode(&result)
 	if err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (m *mongoDB) upsertProposal(proposalInfo *types.ProposalDetail) error {
+func (m *mongoDB) upsertProposal(ctx context.Context, proposalInfo *types.ProposalDetail) error {
 	proposalInfo.UpdateTime = time.Now().Unix()
 	m.logger.Warn("upsertProposal", zap.Any("proposal", proposalInfo))
 	model := []mongo.WriteModel{
 		mongo.NewUpdateOneModel().SetUpsert(true).SetFilter(bson.M{"id": proposalInfo.ID}).SetUpdate(bson.M{"$set": proposalInfo}).SetHint(bson.M{"id": -1}),
 	}
-	if _, err := m.wrapper.C(cProposal).BulkWrite(model); err != nil {
+	if _, err := m.wrapper.C(cProposal).BulkWrite(ctx, model); err != nil {
 		return err
 	}
 	return nil
@@ -490,7 +579,7 @@ func (m *mongoDB) GetListProposals(ctx context.Context, pagination *types.Pagina
 		}
 	}
 	cursor, err := m.wrapper.C(cProposal).
-		Find(bson.M{}, opts...)
+		Find(ctx, bson.M{}, opts...)
 	defer func() {
 		err = cursor.Close(ctx)
 		if err != nil {
@@ -508,7 +597,7 @@ func (m *mongoDB) GetListProposals(ctx context.Context, pagination *types.Pagina
 		proposals = append(proposals, proposal)
 	}
 	// get total transaction in block in database
-	total, err := m.wrapper.C(cProposal).Count(bson.M{})
+	total, err := m.wrapper.C(cProposal).Count(ctx, bson.M{})
 	if err != nil {
 		return nil, 0, err
 	}
@@ -517,73 +606,14 @@ func (m *mongoDB) GetListProposals(ctx context.Context, pagination *types.Pagina
 
 // end region Proposal
 
+// AddressByName return named addresses whose name start with or contain a word of name, or addresses which start
+// with name
 func (m *mongoDB) AddressByName(ctx context.Context, name string) ([]*types.Address, error) {
-	var (
-		addrs []*types.Address
-		opts  = []*options.FindOptions{
-			options.Find().SetHint(bson.M{"address": 1}),
-			options.Find().SetHint(bson.M{"name": 1}),
-		}
-	)
-	crit := []bson.M{
-		{"name": bson.D{{"$regex", primitive.Regex{Pattern: name, Options: "i"}}}},
-		{"tokenSymbol": bson.D{{"$regex", primitive.Regex{Pattern: name, Options: "i"}}}},
-	}
-	if strings.HasPrefix(name, "0x") {
-		crit = append(crit, bson.M{"address": bson.D{{"$regex", primitive.Regex{Pattern: name, Options: "i"}}}})
-	}
-	cursor, err := m.wrapper.C(cAddresses).Find(bson.M{"$or": crit}, opts...)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		err = cursor.Close(ctx)
-		if err != nil {
-			m.logger.Warn("Error when close cursor", zap.Error(err))
-		}
-	}()
-	for cursor.Next(ctx) {
-		addr := &types.Address{}
-		if err := cursor.Decode(&addr); err != nil {
-			return nil, err
-		}
-		addrs = append(addrs, addr)
-	}
-
-	return addrs, nil
+	return m.SearchAddresses(ctx, name, nameSearchLimit)
 }
 
+// ContractByName return contracts whose name or token symbol start with or contain a word of name, or whose
+// address start with name
 func (m *mongoDB) ContractByName(ctx context.Context, name string) ([]*types.Contract, error) {
-	var (
-		contracts []*types.Contract
-		opts      = []*options.FindOptions{
-			options.Find().SetHint(bson.M{"address": 1}),
-			options.Find().SetHint(bson.M{"name": 1}),
-		}
-	)
-	crit := []bson.M{
-		{"name": bson.D{{"$regex", primitive.Regex{Pattern: name, Options: "i"}}}},
-	}
-	if strings.HasPrefix(name, "0x") {
-		crit = append(crit, bson.M{"address": bson.D{{"$regex", primitive.Regex{Pattern: name, Options: "i"}}}})
-	}
-	cursor, err := m.wrapper.C(cContract).Find(bson.M{"$or": crit}, opts...)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		err = cursor.Close(ctx)
-		if err != nil {
-			m.logger.Warn("Error when close cursor", zap.Error(err))
-		}
-	}()
-	for cursor.Next(ctx) {
-		smc := &types.Contract{}
-		if err := cursor.Decode(&smc); err != nil {
-			return nil, err
-		}
-		contracts = append(contracts, smc)
-	}
-
-	return contracts, nil
+	return m.SearchContracts(ctx, name, nameSearchLimit)
 }
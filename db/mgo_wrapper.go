@@ -25,6 +25,10 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/label"
+
+	"github.com/kardiachain/kardia-explorer-backend/metrics"
+	"github.com/kardiachain/kardia-explorer-backend/tracing"
 )
 
 type KaiMgo struct {
@@ -41,6 +45,23 @@ func (w *KaiMgo) C(name string) *KaiMgo {
 	return w
 }
 
+// observe start a span of operation on the current collection, finish record its latency and end the span
+func (w *KaiMgo) observe(ctx context.Context, operation string) (context.Context, func(err error)) {
+	collection, start := w.col.Name(), time.Now()
+	ctx, span := tracing.Start(ctx, "mongo."+operation,
+		label.String("db.system", "mongodb"),
+		label.String("db.mongodb.collection", collection),
+		label.String("db.operation", operation),
+	)
+	return ctx, func(err error) {
+		metrics.ObserveMongo(collection, operation, start)
+		if err == mongo.ErrNoDocuments {
+			err = nil
+		}
+		tracing.End(ctx, span, err)
+	}
+}
+
 func (w *KaiMgo) Ping() error {
 	return nil
 }
@@ -56,92 +77,127 @@ func (w *KaiMgo) EnsureIndex(model []mongo.IndexModel) error {
 	return err
 }
 
-func (w *KaiMgo) Update(filter interface{}, update interface{},
-	opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
-	return w.col.UpdateOne(context.Background(), filter, update, opts...)
+func (w *KaiMgo) Update(ctx context.Context, filter interface{}, update interface{},
+	opts ...*options.UpdateOptions) (res *mongo.UpdateResult, err error) {
+	ctx, finish := w.observe(ctx, "update_one")
+	defer func() { finish(err) }()
+	return w.col.UpdateOne(ctx, filter, update, opts...)
 }
 
-func (w *KaiMgo) UpdateMany(filter interface{}, update interface{},
-	opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
-	return w.col.UpdateMany(context.Background(), filter, update, opts...)
+func (w *KaiMgo) UpdateMany(ctx context.Context, filter interface{}, update interface{},
+	opts ...*options.UpdateOptions) (res *mongo.UpdateResult, err error) {
+	ctx, finish := w.observe(ctx, "update_many")
+	defer func() { finish(err) }()
+	return w.col.UpdateMany(ctx, filter, update, opts...)
 }
 
-func (w *KaiMgo) Upsert(filter interface{}, update interface{},
-	opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+func (w *KaiMgo) Upsert(ctx context.Context, filter interface{}, update interface{},
+	opts ...*options.UpdateOptions) (res *mongo.UpdateResult, err error) {
 	opts = append(opts, options.Update().SetUpsert(true))
-	return w.col.UpdateOne(context.Background(), filter, bson.M{"$set": update}, opts...)
+	ctx, finish := w.observe(ctx, "update_one")
+	defer func() { finish(err) }()
+	return w.col.UpdateOne(ctx, filter, bson.M{"$set": update}, opts...)
 }
 
-func (w *KaiMgo) Aggregate(pipeline interface{},
-	opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
-	return w.col.Aggregate(context.Background(), pipeline, opts...)
+func (w *KaiMgo) Aggregate(ctx context.Context, pipeline interface{},
+	opts ...*options.AggregateOptions) (res *mongo.Cursor, err error) {
+	ctx, finish := w.observe(ctx, "aggregate")
+	defer func() { finish(err) }()
+	return w.col.Aggregate(ctx, pipeline, opts...)
 }
 
-func (w *KaiMgo) RemoveAll(filter interface{},
-	opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
-	return w.col.DeleteMany(context.Background(), filter, opts...)
+func (w *KaiMgo) RemoveAll(ctx context.Context, filter interface{},
+	opts ...*options.DeleteOptions) (res *mongo.DeleteResult, err error) {
+	ctx, finish := w.observe(ctx, "delete_many")
+	defer func() { finish(err) }()
+	return w.col.DeleteMany(ctx, filter, opts...)
 }
 
-func (w *KaiMgo) Remove(filter interface{},
-	opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
-	return w.col.DeleteOne(context.Background(), filter, opts...)
+func (w *KaiMgo) Remove(ctx context.Context, filter interface{},
+	opts ...*options.DeleteOptions) (res *mongo.DeleteResult, err error) {
+	ctx, finish := w.observe(ctx, "delete_one")
+	defer func() { finish(err) }()
+	return w.col.DeleteOne(ctx, filter, opts...)
 }
 
-func (w *KaiMgo) Find(filter interface{},
-	opts ...*options.FindOptions) (*mongo.Cursor, error) {
-	return w.col.Find(context.Background(), filter, opts...)
+func (w *KaiMgo) Find(ctx context.Context, filter interface{},
+	opts ...*options.FindOptions) (res *mongo.Cursor, err error) {
+	ctx, finish := w.observe(ctx, "find")
+	defer func() { finish(err) }()
+	return w.col.Find(ctx, filter, opts...)
 }
 
-func (w *KaiMgo) FindOne(filter interface{},
+func (w *KaiMgo) FindOne(ctx context.Context, filter interface{},
 	opts ...*options.FindOneOptions) *mongo.SingleResult {
-	return w.col.FindOne(context.Background(), filter, opts...)
+	ctx, finish := w.observe(ctx, "find_one")
+	res := w.col.FindOne(ctx, filter, opts...)
+	finish(res.Err())
+	return res
 }
 
-func (w *KaiMgo) Select(filter interface{},
-	opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
-	return w.col.DeleteMany(context.Background(), filter, opts...)
+func (w *KaiMgo) Select(ctx context.Context, filter interface{},
+	opts ...*options.DeleteOptions) (res *mongo.DeleteResult, err error) {
+	ctx, finish := w.observe(ctx, "delete_many")
+	defer func() { finish(err) }()
+	return w.col.DeleteMany(ctx, filter, opts...)
 }
 
-func (w *KaiMgo) Sort(filter interface{},
-	opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
-	return w.col.DeleteMany(context.Background(), filter, opts...)
+func (w *KaiMgo) Sort(ctx context.Context, filter interface{},
+	opts ...*options.DeleteOptions) (res *mongo.DeleteResult, err error) {
+	ctx, finish := w.observe(ctx, "delete_many")
+	defer func() { finish(err) }()
+	return w.col.DeleteMany(ctx, filter, opts...)
 }
 
-func (w *KaiMgo) One(filter interface{},
-	opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
-	return w.col.DeleteMany(context.Background(), filter, opts...)
+func (w *KaiMgo) One(ctx context.Context, filter interface{},
+	opts ...*options.DeleteOptions) (res *mongo.DeleteResult, err error) {
+	ctx, finish := w.observe(ctx, "delete_many")
+	defer func() { finish(err) }()
+	return w.col.DeleteMany(ctx, filter, opts...)
 }
 
-func (w *KaiMgo) BulkWrite(models []mongo.WriteModel,
-	opts ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error) {
+func (w *KaiMgo) BulkWrite(ctx context.Context, models []mongo.WriteModel,
+	opts ...*options.BulkWriteOptions) (res *mongo.BulkWriteResult, err error) {
 	opts = append(opts, options.BulkWrite().SetOrdered(false), options.BulkWrite().SetBypassDocumentValidation(true))
-	return w.col.BulkWrite(context.Background(), models, opts...)
+	ctx, finish := w.observe(ctx, "bulk_write")
+	defer func() { finish(err) }()
+	return w.col.BulkWrite(ctx, models, opts...)
 }
 
-func (w *KaiMgo) BulkInsert(models []mongo.WriteModel,
-	opts ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error) {
+func (w *KaiMgo) BulkInsert(ctx context.Context, models []mongo.WriteModel,
+	opts ...*options.BulkWriteOptions) (res *mongo.BulkWriteResult, err error) {
 	opts = append(opts, options.BulkWrite().SetOrdered(false), options.BulkWrite().SetBypassDocumentValidation(true))
-	return w.col.BulkWrite(context.Background(), models, opts...)
+	ctx, finish := w.observe(ctx, "bulk_write")
+	defer func() { finish(err) }()
+	return w.col.BulkWrite(ctx, models, opts...)
 }
 
-func (w *KaiMgo) BulkUpsert(models []mongo.WriteModel,
-	opts ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error) {
+func (w *KaiMgo) BulkUpsert(ctx context.Context, models []mongo.WriteModel,
+	opts ...*options.BulkWriteOptions) (res *mongo.BulkWriteResult, err error) {
 	opts = append(opts, options.BulkWrite().SetOrdered(false), options.BulkWrite().SetBypassDocumentValidation(true))
-	return w.col.BulkWrite(context.Background(), models, opts...)
+	ctx, finish := w.observe(ctx, "bulk_write")
+	defer func() { finish(err) }()
+	return w.col.BulkWrite(ctx, models, opts...)
 }
 
-func (w *KaiMgo) Distinct(field string, filter interface{}, opts ...*options.DistinctOptions) ([]interface{}, error) {
-	return w.col.Distinct(context.Background(), field, filter, opts...)
+func (w *KaiMgo) Distinct(ctx context.Context, field string, filter interface{}, opts ...*options.DistinctOptions) (res []interface{}, err error) {
+	ctx, finish := w.observe(ctx, "distinct")
+	defer func() { finish(err) }()
+	return w.col.Distinct(ctx, field, filter, opts...)
 }
 
-func (w *KaiMgo) Count(filter interface{},
-	opts ...*options.CountOptions) (int64, error) {
-	return w.col.CountDocuments(context.Background(), filter, opts...)
+func (w *KaiMgo) Count(ctx context.Context, filter interface{},
+	opts ...*options.CountOptions) (res int64, err error) {
+	ctx, finish := w.observe(ctx, "count")
+	defer func() { finish(err) }()
+	return w.col.CountDocuments(ctx, filter, opts...)
 }
 
-func (w *KaiMgo) Insert(document interface{},
-	opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
-	return w.col.InsertOne(context.Background(), document, opts...)
+func (w *KaiMgo) Insert(ctx context.Context, document interface{},
+	opts ...*options.InsertOneOptions) (res *mongo.InsertOneResult, err error) {
+	ctx, finish := w.observe(ctx, "insert_one")
+	defer func() { finish(err) }()
+	return w.col.InsertOne(ctx, document, opts...)
 }
 
 func (w *KaiMgo) FindSetSort(data string) *options.FindOptions {
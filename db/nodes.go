@@ -16,7 +16,7 @@ var (
 
 func (m *mongoDB) UpsertNode(ctx context.Context, node *types.NodeInfo) error {
 	filter := bson.M{"id": node.ID}
-	if _, err := m.wrapper.C(cNodes).Upsert(filter, node); err != nil {
+	if _, err := m.wrapper.C(cNodes).Upsert(ctx, filter, node); err != nil {
 		return err
 	}
 	return nil
@@ -25,7 +25,7 @@ func (m *mongoDB) UpsertNode(ctx context.Context, node *types.NodeInfo) error {
 func (m *mongoDB) Nodes(ctx context.Context) ([]*types.NodeInfo, error) {
 	lgr := m.logger.With(zap.String("method", "Nodes"))
 	filter := bson.M{}
-	cursor, err := m.wrapper.C(cNodes).Find(filter)
+	cursor, err := m.wrapper.C(cNodes).Find(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -49,7 +49,7 @@ func (m *mongoDB) Nodes(ctx context.Context) ([]*types.NodeInfo, error) {
 
 func (m *mongoDB) RemoveNode(ctx context.Context, id string) error {
 	filter := bson.M{"id": id}
-	if _, err := m.wrapper.C(cNodes).RemoveAll(filter); err != nil {
+	if _, err := m.wrapper.C(cNodes).RemoveAll(ctx, filter); err != nil {
 		return err
 	}
 	return nil
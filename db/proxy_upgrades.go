@@ -0,0 +1,99 @@
+// Package db
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+var cProxyUpgrades = "ProxyUpgrades"
+
+type IProxyUpgrade interface {
+	createProxyUpgradesCollectionIndexes() []mongo.IndexModel
+	InsertProxyUpgrade(ctx context.Context, upgrade *types.ProxyUpgrade) error
+	ProxyUpgrades(ctx context.Context, filter *types.ProxyUpgradesFilter) ([]*types.ProxyUpgrade, uint64, error)
+	ProxiesOf(ctx context.Context, address string) ([]*types.Contract, error)
+}
+
+func (m *mongoDB) createProxyUpgradesCollectionIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.D{{Key: "address", Value: 1}, {Key: "createdAt", Value: -1}}},
+		{Keys: bson.M{"blockHeight": -1}, Options: options.Index().SetSparse(true)},
+	}
+}
+
+func (m *mongoDB) InsertProxyUpgrade(ctx context.Context, upgrade *types.ProxyUpgrade) error {
+	if _, err := m.wrapper.C(cProxyUpgrades).Insert(ctx, upgrade); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *mongoDB) ProxyUpgrades(ctx context.Context, filter *types.ProxyUpgradesFilter) ([]*types.ProxyUpgrade, uint64, error) {
+	var (
+		upgrades []*types.ProxyUpgrade
+		crit     = bson.M{}
+	)
+	critBytes, err := bson.Marshal(filter)
+	if err != nil {
+		m.logger.Warn("Cannot marshal proxy upgrades filter criteria", zap.Error(err))
+	}
+	err = bson.Unmarshal(critBytes, &crit)
+	if err != nil {
+		m.logger.Warn("Cannot unmarshal proxy upgrades filter criteria", zap.Error(err))
+	}
+
+	opts := []*options.FindOptions{
+		options.Find().SetSort(bson.M{"createdAt": -1}),
+	}
+	if filter.Pagination != nil {
+		filter.Pagination.Sanitize()
+		opts = append(opts, options.Find().SetSkip(int64(filter.Pagination.Skip)), options.Find().SetLimit(int64(filter.Pagination.Limit)))
+	}
+	cursor, err := m.wrapper.C(cProxyUpgrades).Find(ctx, crit, opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	if err := cursor.All(ctx, &upgrades); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := m.wrapper.C(cProxyUpgrades).Count(ctx, crit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return upgrades, uint64(total), nil
+}
+
+// ProxiesOf return proxies which use address as implementation or beacon
+func (m *mongoDB) ProxiesOf(ctx context.Context, address string) ([]*types.Contract, error) {
+	var contracts []*types.Contract
+	crit := bson.M{"$or": []bson.M{{"implementation": address}, {"beacon": address}}}
+	cursor, err := m.wrapper.C(cContract).Find(ctx, crit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+
+	if err := cursor.All(ctx, &contracts); err != nil {
+		return nil, err
+	}
+	return contracts, nil
+}
@@ -0,0 +1,376 @@
+// Package db
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+var (
+	cRollups          = "Rollups"
+	cAddressFirstSeen = "AddressFirstSeen"
+)
+
+type IRollup interface {
+	createRollupsCollectionIndexes() []mongo.IndexModel
+	createAddressFirstSeenCollectionIndexes() []mongo.IndexModel
+
+	UpsertRollup(ctx context.Context, rollup *types.Rollup) error
+	Rollups(ctx context.Context, filter *types.RollupsFilter) ([]*types.Rollup, error)
+	LatestRollup(ctx context.Context, interval string) (*types.Rollup, error)
+	DirtyRollups(ctx context.Context, limit int64) ([]*types.Rollup, error)
+	MarkRollupsDirty(ctx context.Context, times []time.Time) error
+
+	AggregateRollup(ctx context.Context, interval string, start time.Time) (*types.Rollup, error)
+	ActiveAddressesFirstSeen(ctx context.Context, start, end time.Time) (map[string]time.Time, error)
+	UpdateAddressesFirstSeen(ctx context.Context, firstSeen map[string]time.Time) ([]time.Time, error)
+	CountAddressesFirstSeen(ctx context.Context, start, end time.Time) (uint64, error)
+}
+
+func (m *mongoDB) createRollupsCollectionIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.D{{Key: "interval", Value: 1}, {Key: "time", Value: -1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.M{"dirty": 1}, Options: options.Index().SetPartialFilterExpression(bson.M{"dirty": true})},
+	}
+}
+
+func (m *mongoDB) createAddressFirstSeenCollectionIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.M{"address": 1}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.M{"time": 1}},
+	}
+}
+
+func (m *mongoDB) UpsertRollup(ctx context.Context, rollup *types.Rollup) error {
+	if _, err := m.wrapper.C(cRollups).Upsert(ctx, bson.M{"interval": rollup.Interval, "time": rollup.Time}, rollup); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Rollups return rollups of interval in [StartTime, EndTime], oldest first
+func (m *mongoDB) Rollups(ctx context.Context, filter *types.RollupsFilter) ([]*types.Rollup, error) {
+	var rollups []*types.Rollup
+	// buckets marked dirty before they were ever built have no data
+	crit := bson.M{"interval": filter.Interval, "updatedAt": bson.M{"$exists": true}}
+	timeCrit := bson.M{}
+	if !filter.StartTime.IsZero() {
+		timeCrit["$gte"] = filter.StartTime
+	}
+	if !filter.EndTime.IsZero() {
+		timeCrit["$lte"] = filter.EndTime
+	}
+	if len(timeCrit) > 0 {
+		crit["time"] = timeCrit
+	}
+	cursor, err := m.wrapper.C(cRollups).Find(ctx, crit, options.Find().SetSort(bson.M{"time": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	if err := cursor.All(ctx, &rollups); err != nil {
+		return nil, err
+	}
+	return rollups, nil
+}
+
+// LatestRollup return the latest built rollup of interval, nil if none was built
+func (m *mongoDB) LatestRollup(ctx context.Context, interval string) (*types.Rollup, error) {
+	var rollup *types.Rollup
+	err := m.wrapper.C(cRollups).FindOne(ctx, bson.M{"interval": interval, "dirty": false},
+		options.FindOne().SetSort(bson.M{"time": -1})).Decode(&rollup)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rollup, nil
+}
+
+// DirtyRollups return rollups need to be rebuilt, hour buckets first since day buckets count
+// new addresses found while building hours
+func (m *mongoDB) DirtyRollups(ctx context.Context, limit int64) ([]*types.Rollup, error) {
+	var rollups []*types.Rollup
+	cursor, err := m.wrapper.C(cRollups).Find(ctx, bson.M{"dirty": true},
+		options.Find().SetSort(bson.D{{Key: "interval", Value: -1}, {Key: "time", Value: 1}}),
+		options.Find().SetLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	if err := cursor.All(ctx, &rollups); err != nil {
+		return nil, err
+	}
+	return rollups, nil
+}
+
+// MarkRollupsDirty flag hour and day buckets which contain times to be rebuilt
+func (m *mongoDB) MarkRollupsDirty(ctx context.Context, times []time.Time) error {
+	buckets := make(map[string]map[time.Time]bool)
+	for _, interval := range []string{types.RollupIntervalHour, types.RollupIntervalDay} {
+		buckets[interval] = make(map[time.Time]bool)
+		for _, t := range times {
+			buckets[interval][types.RollupStart(interval, t)] = true
+		}
+	}
+	var models []mongo.WriteModel
+	for interval, starts := range buckets {
+		for start := range starts {
+			models = append(models, mongo.NewUpdateOneModel().SetUpsert(true).
+				SetFilter(bson.M{"interval": interval, "time": start}).
+				SetUpdate(bson.M{"$set": bson.M{"dirty": true}}))
+		}
+	}
+	if len(models) == 0 {
+		return nil
+	}
+	if _, err := m.wrapper.C(cRollups).BulkUpsert(ctx, models); err != nil {
+		return err
+	}
+	return nil
+}
+
+// rollupBlockStats is the $group result of blocks in a bucket
+type rollupBlockStats struct {
+	Blocks  uint64    `bson:"blocks"`
+	GasUsed uint64    `bson:"gasUsed"`
+	First   time.Time `bson:"first"`
+	Last    time.Time `bson:"last"`
+}
+
+// rollupTxTotals is the $group result of txs in a bucket
+type rollupTxTotals struct {
+	Txs                 uint64               `bson:"txs"`
+	Fees                primitive.Decimal128 `bson:"fees"`
+	AvgGasPrice         float64              `bson:"avgGasPrice"`
+	ContractDeployments uint64               `bson:"contractDeployments"`
+}
+
+// rollupTxStats is the $facet result of txs in a bucket, each facet has at most one document
+type rollupTxStats struct {
+	Totals    []*rollupTxTotals `bson:"totals"`
+	Addresses []struct {
+		Total uint64 `bson:"total"`
+	} `bson:"addresses"`
+}
+
+// activeAddressesStages unwind senders and receivers of matched txs then group them by address
+func activeAddressesStages(group bson.M) bson.A {
+	group["_id"] = "$address"
+	return bson.A{
+		bson.M{"$project": bson.M{"time": 1, "address": bson.A{"$from", "$to"}}},
+		bson.M{"$unwind": "$address"},
+		// contract creations have no receiver
+		bson.M{"$match": bson.M{"address": bson.M{"$gt": ""}}},
+		bson.M{"$group": group},
+	}
+}
+
+// AggregateRollup compute the bucket of interval start at start from blocks and txs in db,
+// NewAddresses is not set
+func (m *mongoDB) AggregateRollup(ctx context.Context, interval string, start time.Time) (*types.Rollup, error) {
+	match := bson.M{"$match": bson.M{"time": bson.M{"$gte": start, "$lt": types.RollupEnd(interval, start)}}}
+
+	cursor, err := m.wrapper.C(cBlocks).Aggregate(ctx, bson.A{
+		match,
+		bson.M{"$group": bson.M{
+			"_id":     nil,
+			"blocks":  bson.M{"$sum": 1},
+			"gasUsed": bson.M{"$sum": "$gasUsed"},
+			"first":   bson.M{"$min": "$time"},
+			"last":    bson.M{"$max": "$time"},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var blocks []*rollupBlockStats
+	err = cursor.All(ctx, &blocks)
+	if closeErr := cursor.Close(ctx); closeErr != nil {
+		m.logger.Warn("Error when close cursor", zap.Error(closeErr))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	zero, _ := primitive.ParseDecimal128("0")
+	cursor, err = m.wrapper.C(cTxs).Aggregate(ctx, bson.A{
+		match,
+		bson.M{"$facet": bson.M{
+			"totals": bson.A{bson.M{"$group": bson.M{
+				"_id": nil,
+				"txs": bson.M{"$sum": 1},
+				"fees": bson.M{"$sum": bson.M{"$convert": bson.M{
+					"input": "$txfee", "to": "decimal", "onError": zero, "onNull": zero}}},
+				"avgGasPrice": bson.M{"$avg": "$gasPrice"},
+				// failed creations do not deploy a contract
+				"contractDeployments": bson.M{"$sum": bson.M{"$cond": bson.A{
+					bson.M{"$and": bson.A{
+						bson.M{"$gt": bson.A{"$contractAddress", ""}},
+						bson.M{"$eq": bson.A{"$status", types.TransactionStatusSuccess}},
+					}}, 1, 0}}},
+			}}},
+			"addresses": append(activeAddressesStages(bson.M{}), bson.M{"$count": "total"}),
+		}},
+	}, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, err
+	}
+	var txs []*rollupTxStats
+	err = cursor.All(ctx, &txs)
+	if closeErr := cursor.Close(ctx); closeErr != nil {
+		m.logger.Warn("Error when close cursor", zap.Error(closeErr))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rollup := &types.Rollup{Interval: interval, Time: start}
+	var blockStats *rollupBlockStats
+	if len(blocks) > 0 {
+		blockStats = blocks[0]
+	}
+	txStats := &rollupTxStats{}
+	if len(txs) > 0 {
+		txStats = txs[0]
+	}
+	if err := fillRollup(rollup, blockStats, txStats); err != nil {
+		return nil, err
+	}
+	return rollup, nil
+}
+
+// fillRollup set aggregated stats into rollup, blocks is nil when the bucket has no block
+func fillRollup(rollup *types.Rollup, blocks *rollupBlockStats, txs *rollupTxStats) error {
+	rollup.Fees = "0"
+	if blocks != nil {
+		rollup.Blocks = blocks.Blocks
+		rollup.GasUsed = blocks.GasUsed
+		if blocks.Blocks > 1 {
+			rollup.AvgBlockTime = blocks.Last.Sub(blocks.First).Seconds() / float64(blocks.Blocks-1)
+		}
+	}
+	if len(txs.Totals) > 0 {
+		totals := txs.Totals[0]
+		fees, err := decimalToBigInt(totals.Fees)
+		if err != nil {
+			return err
+		}
+		rollup.Txs = totals.Txs
+		rollup.Fees = fees.String()
+		rollup.AvgGasPrice = totals.AvgGasPrice
+		rollup.ContractDeployments = totals.ContractDeployments
+	}
+	if len(txs.Addresses) > 0 {
+		rollup.ActiveAddresses = txs.Addresses[0].Total
+	}
+	return nil
+}
+
+// ActiveAddressesFirstSeen return the first tx time of each address sent or received txs in [start, end)
+func (m *mongoDB) ActiveAddressesFirstSeen(ctx context.Context, start, end time.Time) (map[string]time.Time, error) {
+	pipeline := append(bson.A{bson.M{"$match": bson.M{"time": bson.M{"$gte": start, "$lt": end}}}},
+		activeAddressesStages(bson.M{"time": bson.M{"$min": "$time"}})...)
+	cursor, err := m.wrapper.C(cTxs).Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	var rows []struct {
+		Address string    `bson:"_id"`
+		Time    time.Time `bson:"time"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	firstSeen := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		firstSeen[row.Address] = row.Time
+	}
+	return firstSeen, nil
+}
+
+// UpdateAddressesFirstSeen keep the earliest time of each address. Return previous first seen times
+// which moved earlier, buckets of these times have lost new addresses.
+func (m *mongoDB) UpdateAddressesFirstSeen(ctx context.Context, firstSeen map[string]time.Time) ([]time.Time, error) {
+	if len(firstSeen) == 0 {
+		return nil, nil
+	}
+	addresses := make([]string, 0, len(firstSeen))
+	for address := range firstSeen {
+		addresses = append(addresses, address)
+	}
+	cursor, err := m.wrapper.C(cAddressFirstSeen).Find(ctx, bson.M{"address": bson.M{"$in": addresses}})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	var current []*types.AddressFirstSeen
+	if err := cursor.All(ctx, &current); err != nil {
+		return nil, err
+	}
+	currentTimes := make(map[string]time.Time, len(current))
+	for _, c := range current {
+		currentTimes[c.Address] = c.Time
+	}
+
+	var (
+		moved  []time.Time
+		models []mongo.WriteModel
+	)
+	for address, t := range firstSeen {
+		if prev, ok := currentTimes[address]; ok {
+			if !t.Before(prev) {
+				continue
+			}
+			moved = append(moved, prev)
+		}
+		models = append(models, mongo.NewUpdateOneModel().SetUpsert(true).
+			SetFilter(bson.M{"address": address}).
+			SetUpdate(bson.M{"$min": bson.M{"time": t}}))
+	}
+	if len(models) == 0 {
+		return nil, nil
+	}
+	if _, err := m.wrapper.C(cAddressFirstSeen).BulkUpsert(ctx, models); err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
+
+func (m *mongoDB) CountAddressesFirstSeen(ctx context.Context, start, end time.Time) (uint64, error) {
+	total, err := m.wrapper.C(cAddressFirstSeen).Count(ctx, bson.M{"time": bson.M{"$gte": start, "$lt": end}})
+	if err != nil {
+		return 0, err
+	}
+	return uint64(total), nil
+}
@@ -0,0 +1,41 @@
+// Package db
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+func TestFillRollup(t *testing.T) {
+	start := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+	fees, err := primitive.ParseDecimal128("84020")
+	require.NoError(t, err)
+	txs := &rollupTxStats{Totals: []*rollupTxTotals{{Txs: 4, Fees: fees, AvgGasPrice: 2, ContractDeployments: 1}}}
+	txs.Addresses = append(txs.Addresses, struct {
+		Total uint64 `bson:"total"`
+	}{Total: 3})
+
+	rollup := &types.Rollup{Interval: types.RollupIntervalHour, Time: start}
+	require.NoError(t, fillRollup(rollup, &rollupBlockStats{Blocks: 3, GasUsed: 300, First: start, Last: start.Add(10 * time.Second)}, txs))
+	assert.Equal(t, uint64(3), rollup.Blocks)
+	assert.Equal(t, uint64(4), rollup.Txs)
+	assert.Equal(t, uint64(300), rollup.GasUsed)
+	assert.Equal(t, 5.0, rollup.AvgBlockTime)
+	assert.Equal(t, 2.0, rollup.AvgGasPrice)
+	assert.Equal(t, "84020", rollup.Fees)
+	assert.Equal(t, uint64(1), rollup.ContractDeployments)
+	assert.Equal(t, uint64(3), rollup.ActiveAddresses)
+
+	// an empty bucket has no blocks nor txs
+	empty := &types.Rollup{Interval: types.RollupIntervalHour, Time: start}
+	require.NoError(t, fillRollup(empty, nil, &rollupTxStats{}))
+	assert.Equal(t, uint64(0), empty.Blocks)
+	assert.Equal(t, 0.0, empty.AvgBlockTime)
+	assert.Equal(t, "0", empty.Fees)
+}
@@ -0,0 +1,172 @@
+// Package db
+package db
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+// nameSearchLimit cap the results of AddressByName and ContractByName
+const nameSearchLimit = 50
+
+// searchCollation compare strings case-insensitively, prefix queries must use it to match the search indexes
+var searchCollation = &options.Collation{Locale: "en", Strength: 2}
+
+// ISearch also match the address of documents when query is the beginning of an address, e.g. "0x4e3f"
+type ISearch interface {
+	// SearchValidators return validators whose name start with query, then those having a word of query in their name
+	SearchValidators(ctx context.Context, query string, limit int) ([]*types.Validator, error)
+	// SearchContracts return contracts whose name or token symbol start with query, then those having a word of query in them
+	SearchContracts(ctx context.Context, query string, limit int) ([]*types.Contract, error)
+	// SearchAddresses return named addresses whose name start with query, then those having a word of query in their name
+	SearchAddresses(ctx context.Context, query string, limit int) ([]*types.Address, error)
+}
+
+// partialAddressPattern match the beginning of an address, full addresses are looked up by the caller
+var partialAddressPattern = regexp.MustCompile(`^0[xX][0-9a-fA-F]{1,39}$`)
+
+// addressPrefixIndex index address under the search collation, so addresses are matched lower-cased
+var addressPrefixIndex = mongo.IndexModel{
+	Keys:    bson.M{"address": 1},
+	Options: options.Index().SetName("address_ci").SetCollation(searchCollation),
+}
+
+// searchPrefixes return prefix criteria of query over fields, and over address when query is the beginning of one
+func searchPrefixes(query string, fields ...string) []bson.M {
+	var prefix []bson.M
+	// U+FFFF sort after every character under the collation, the range hold every string starting with query
+	for _, field := range fields {
+		prefix = append(prefix, bson.M{field: bson.M{"$gte": query, "$lt": query + "\uffff"}})
+	}
+	if partialAddressPattern.MatchString(query) {
+		lower := strings.ToLower(query)
+		prefix = append(prefix, bson.M{"address": bson.M{"$gte": lower, "$lt": lower + "\uffff"}})
+	}
+	return prefix
+}
+
+// createSearchIndexes index fields for case-insensitive prefix queries and a text index over all of them for word queries
+func createSearchIndexes(fields ...string) []mongo.IndexModel {
+	var (
+		models []mongo.IndexModel
+		text   bson.D
+	)
+	for _, field := range fields {
+		models = append(models, mongo.IndexModel{
+			Keys:    bson.M{field: 1},
+			Options: options.Index().SetName(field + "_ci").SetCollation(searchCollation).SetSparse(true),
+		})
+		text = append(text, bson.E{Key: field, Value: "text"})
+	}
+	return append(models, mongo.IndexModel{Keys: text, Options: options.Index().SetName("search_text")})
+}
+
+func (m *mongoDB) SearchValidators(ctx context.Context, query string, limit int) ([]*types.Validator, error) {
+	var validators []*types.Validator
+	err := m.search(ctx, cValidators, []string{"name"}, query, limit, func(cursor *mongo.Cursor) (string, error) {
+		v := &types.Validator{}
+		if err := cursor.Decode(v); err != nil {
+			return "", err
+		}
+		validators = append(validators, v)
+		return v.Address, nil
+	})
+	return validators, err
+}
+
+func (m *mongoDB) SearchContracts(ctx context.Context, query string, limit int) ([]*types.Contract, error) {
+	var contracts []*types.Contract
+	err := m.search(ctx, cContract, []string{"name", "symbol"}, query, limit, func(cursor *mongo.Cursor) (string, error) {
+		smc := &types.Contract{}
+		if err := cursor.Decode(smc); err != nil {
+			return "", err
+		}
+		contracts = append(contracts, smc)
+		return smc.Address, nil
+	})
+	return contracts, err
+}
+
+func (m *mongoDB) SearchAddresses(ctx context.Context, query string, limit int) ([]*types.Address, error) {
+	var addresses []*types.Address
+	err := m.search(ctx, cAddresses, []string{"name"}, query, limit, func(cursor *mongo.Cursor) (string, error) {
+		addr := &types.Address{}
+		if err := cursor.Decode(addr); err != nil {
+			return "", err
+		}
+		addresses = append(addresses, addr)
+		return addr.Address, nil
+	})
+	return addresses, err
+}
+
+// search run a prefix query over fields then a text query for the remaining slots, decode is called once per
+// distinct document and return its address.
+func (m *mongoDB) search(ctx context.Context, collection string, fields []string, query string, limit int,
+	decode func(cursor *mongo.Cursor) (string, error)) error {
+	seen := make(map[string]bool)
+	next := func(cursor *mongo.Cursor) error {
+		defer func() {
+			if err := cursor.Close(ctx); err != nil {
+				m.logger.Warn("Error when close cursor", zap.Error(err))
+			}
+		}()
+		for len(seen) < limit && cursor.Next(ctx) {
+			var doc struct {
+				Address string `bson:"address"`
+			}
+			if err := cursor.Decode(&doc); err != nil {
+				return err
+			}
+			if seen[doc.Address] {
+				continue
+			}
+			address, err := decode(cursor)
+			if err != nil {
+				return err
+			}
+			seen[address] = true
+		}
+		return cursor.Err()
+	}
+
+	cursor, err := m.wrapper.C(collection).Find(ctx, bson.M{"$or": searchPrefixes(query, fields...)},
+		options.Find().SetCollation(searchCollation).SetLimit(int64(limit)))
+	if err != nil {
+		return err
+	}
+	if err := next(cursor); err != nil {
+		return err
+	}
+	if len(seen) >= limit {
+		return nil
+	}
+
+	words := textSearchWords(query)
+	if words == "" {
+		return nil
+	}
+	cursor, err = m.wrapper.C(collection).Find(ctx, bson.M{"$text": bson.M{"$search": words}},
+		options.Find().
+			SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}}).
+			SetSort(bson.M{"score": bson.M{"$meta": "textScore"}}).
+			SetLimit(int64(limit)))
+	if err != nil {
+		return err
+	}
+	return next(cursor)
+}
+
+// textSearchWords drop the phrase and negation operators of $text from query
+func textSearchWords(query string) string {
+	words := strings.Fields(strings.NewReplacer(`"`, " ", "-", " ").Replace(query))
+	return strings.Join(words, " ")
+}
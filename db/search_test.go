@@ -0,0 +1,25 @@
+// Package db
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSearchPrefixes(t *testing.T) {
+	assert.Equal(t, []bson.M{
+		{"name": bson.M{"$gte": "Kai", "$lt": "Kai\uffff"}},
+	}, searchPrefixes("Kai", "name"))
+
+	// the beginning of an address also match addresses, lower-cased
+	assert.Equal(t, []bson.M{
+		{"name": bson.M{"$gte": "0x4E3F", "$lt": "0x4E3F\uffff"}},
+		{"address": bson.M{"$gte": "0x4e3f", "$lt": "0x4e3f\uffff"}},
+	}, searchPrefixes("0x4E3F", "name"))
+
+	for _, query := range []string{"0x", "0xkai", "4e3f"} {
+		assert.Len(t, searchPrefixes(query, "name"), 1, query)
+	}
+}
@@ -0,0 +1,66 @@
+// Package db
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+var cSignatures = "Signatures"
+
+type ISignature interface {
+	createSignaturesCollectionIndexes() []mongo.IndexModel
+	UpsertSignatures(ctx context.Context, sigs []*types.Signature) error
+	SignaturesByHash(ctx context.Context, hash string) ([]*types.Signature, error)
+}
+
+func (m *mongoDB) createSignaturesCollectionIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.D{{Key: "hash", Value: 1}, {Key: "text", Value: 1}}, Options: options.Index().SetUnique(true)},
+	}
+}
+
+// UpsertSignatures store signatures by hash and text. Signatures from verified contracts replace
+// existing ones since they carry argument names, bundled signatures never overwrite.
+func (m *mongoDB) UpsertSignatures(ctx context.Context, sigs []*types.Signature) error {
+	if len(sigs) == 0 {
+		return nil
+	}
+	models := make([]mongo.WriteModel, len(sigs))
+	for i, sig := range sigs {
+		update := bson.M{"$setOnInsert": sig}
+		if sig.Source == types.SignatureSourceVerified {
+			update = bson.M{"$set": sig}
+		}
+		models[i] = mongo.NewUpdateOneModel().SetUpsert(true).SetFilter(bson.M{"hash": sig.Hash, "text": sig.Text}).SetUpdate(update)
+	}
+	if _, err := m.wrapper.C(cSignatures).BulkUpsert(ctx, models); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *mongoDB) SignaturesByHash(ctx context.Context, hash string) ([]*types.Signature, error) {
+	var sigs []*types.Signature
+	cursor, err := m.wrapper.C(cSignatures).Find(ctx, bson.M{"hash": hash})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+
+	if err := cursor.All(ctx, &sigs); err != nil {
+		return nil, err
+	}
+	return sigs, nil
+}
@@ -0,0 +1,146 @@
+// Package db
+package db
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+var (
+	cTokenPrices       = "TokenPrices"
+	cTokenPriceSources = "TokenPriceSources"
+)
+
+type ITokenPrice interface {
+	createTokenPricesCollectionIndexes() []mongo.IndexModel
+	createTokenPriceSourcesCollectionIndexes() []mongo.IndexModel
+
+	UpsertTokenPrice(ctx context.Context, price *types.TokenPrice) error
+	TokenPrices(ctx context.Context, filter *types.TokenPricesFilter) ([]*types.TokenPrice, error)
+	LatestTokenPrices(ctx context.Context, tokens []string, at time.Time) (map[string]*types.TokenPrice, error)
+
+	UpsertTokenPriceSource(ctx context.Context, source *types.TokenPriceSource) error
+	RemoveTokenPriceSource(ctx context.Context, token string) error
+	TokenPriceSources(ctx context.Context) ([]*types.TokenPriceSource, error)
+}
+
+func (m *mongoDB) createTokenPricesCollectionIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token", Value: 1}, {Key: "time", Value: -1}}, Options: options.Index().SetUnique(true)},
+	}
+}
+
+func (m *mongoDB) createTokenPriceSourcesCollectionIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.M{"token": 1}, Options: options.Index().SetUnique(true)},
+	}
+}
+
+func (m *mongoDB) UpsertTokenPrice(ctx context.Context, price *types.TokenPrice) error {
+	if _, err := m.wrapper.C(cTokenPrices).Upsert(ctx, bson.M{"token": price.Token, "time": price.Time}, price); err != nil {
+		return err
+	}
+	return nil
+}
+
+// TokenPrices return hourly prices of token in [StartTime, EndTime], oldest first
+func (m *mongoDB) TokenPrices(ctx context.Context, filter *types.TokenPricesFilter) ([]*types.TokenPrice, error) {
+	var prices []*types.TokenPrice
+	crit := bson.M{"token": filter.Token}
+	timeCrit := bson.M{}
+	if !filter.StartTime.IsZero() {
+		timeCrit["$gte"] = filter.StartTime
+	}
+	if !filter.EndTime.IsZero() {
+		timeCrit["$lte"] = filter.EndTime
+	}
+	if len(timeCrit) > 0 {
+		crit["time"] = timeCrit
+	}
+	cursor, err := m.wrapper.C(cTokenPrices).Find(ctx, crit, options.Find().SetSort(bson.M{"time": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	if err := cursor.All(ctx, &prices); err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+// LatestTokenPrices return the latest price at or before time of each token, tokens without price are omitted
+func (m *mongoDB) LatestTokenPrices(ctx context.Context, tokens []string, at time.Time) (map[string]*types.TokenPrice, error) {
+	prices := make(map[string]*types.TokenPrice)
+	if len(tokens) == 0 {
+		return prices, nil
+	}
+	pipeline := []bson.M{
+		{"$match": bson.M{"token": bson.M{"$in": tokens}, "time": bson.M{"$lte": at}}},
+		{"$sort": bson.D{{Key: "token", Value: 1}, {Key: "time", Value: -1}}},
+		{"$group": bson.M{"_id": "$token", "price": bson.M{"$first": "$$ROOT"}}},
+	}
+	cursor, err := m.wrapper.C(cTokenPrices).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	var results []struct {
+		Price *types.TokenPrice `bson:"price"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	for _, r := range results {
+		prices[r.Price.Token] = r.Price
+	}
+	return prices, nil
+}
+
+func (m *mongoDB) UpsertTokenPriceSource(ctx context.Context, source *types.TokenPriceSource) error {
+	if _, err := m.wrapper.C(cTokenPriceSources).Upsert(ctx, bson.M{"token": source.Token}, source); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *mongoDB) RemoveTokenPriceSource(ctx context.Context, token string) error {
+	if _, err := m.wrapper.C(cTokenPriceSources).Remove(ctx, bson.M{"token": token}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *mongoDB) TokenPriceSources(ctx context.Context) ([]*types.TokenPriceSource, error) {
+	var sources []*types.TokenPriceSource
+	cursor, err := m.wrapper.C(cTokenPriceSources).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	if err := cursor.All(ctx, &sources); err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
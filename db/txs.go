@@ -20,21 +20,29 @@ type FindTxsFilter struct {
 
 type ITxs interface {
 	InsertTxs(ctx context.Context, txs []*types.Transaction) error
+	ReplaceTxs(ctx context.Context, txs []*types.Transaction) error
+	ReplaceTxsOfBlock(ctx context.Context, blockHeight uint64, txs []*types.Transaction) error
 
 	LatestTxs(ctx context.Context, pagination *types.Pagination) ([]*types.Transaction, error)
 	TxsByAddress(ctx context.Context, address string, pagination *types.Pagination) ([]*types.Transaction, uint64, error)
+	// TxsByAddresses return txs sent from or to any of addresses, latest first
+	TxsByAddresses(ctx context.Context, addresses []string, pagination *types.Pagination) ([]*types.Transaction, uint64, error)
+	TxsByAddressInRange(ctx context.Context, address string, blockRange *types.BlockRangeFilter, pagination *types.Pagination) ([]*types.Transaction, uint64, error)
 	TxsByBlockHash(ctx context.Context, blockHash string, pagination *types.Pagination) ([]*types.Transaction, uint64, error)
 	TxsByBlockHeight(ctx context.Context, blockNumber uint64, pagination *types.Pagination) ([]*types.Transaction, uint64, error)
+	// TxsByBlockHeights return a page of txs of each block, heights without txs are missing from result
+	TxsByBlockHeights(ctx context.Context, blockHeights []uint64, pagination *types.Pagination) (map[uint64][]*types.Transaction, error)
 
 	TxsCount(ctx context.Context) (uint64, error)
 	TxByHash(ctx context.Context, txHash string) (*types.Transaction, error)
+	TxsByHashes(ctx context.Context, txHashes []string) ([]*types.Transaction, error)
 	FilterTxs(ctx context.Context, filter *types.TxsFilter) ([]*types.Transaction, uint64, error)
 
 	FindContractCreationTxs(ctx context.Context) ([]*types.Transaction, error)
 }
 
 func (m *mongoDB) TxsCount(ctx context.Context) (uint64, error) {
-	total, err := m.wrapper.C(cTxs).Count(bson.M{})
+	total, err := m.wrapper.C(cTxs).Count(ctx, bson.M{})
 	if err != nil {
 		return 0, err
 	}
@@ -50,7 +58,7 @@ func (m *mongoDB) FindContractCreationTxs(ctx context.Context) ([]*types.Transac
 	mgoFilters = append(mgoFilters, bson.M{"contractAddress": bson.M{"$ne": "0x"}})
 	mgoFilters = append(mgoFilters, bson.M{"status": types.TransactionStatusSuccess})
 
-	cursor, err := m.wrapper.C(cTxs).Find(bson.M{"$and": mgoFilters}, opts...)
+	cursor, err := m.wrapper.C(cTxs).Find(ctx, bson.M{"$and": mgoFilters}, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +87,7 @@ func (m *mongoDB) TxsByBlockHash(ctx context.Context, blockHash string, paginati
 		}
 	}
 	cursor, err := m.wrapper.C(cTxs).
-		Find(bson.M{"blockHash": blockHash}, opts...)
+		Find(ctx, bson.M{"blockHash": blockHash}, opts...)
 	defer func() {
 		err = cursor.Close(ctx)
 		if err != nil {
@@ -100,7 +108,7 @@ func (m *mongoDB) TxsByBlockHash(ctx context.Context, blockHash string, paginati
 		txs = append(txs, tx)
 	}
 	// get total transaction in block in database
-	total, err := m.wrapper.C(cTxs).Count(bson.M{"blockHash": blockHash})
+	total, err := m.wrapper.C(cTxs).Count(ctx, bson.M{"blockHash": blockHash})
 	if err != nil {
 		return nil, 0, err
 	}
@@ -119,7 +127,7 @@ func (m *mongoDB) TxsByBlockHeight(ctx context.Context, blockHeight uint64, pagi
 	}
 
 	cursor, err := m.wrapper.C(cTxs).
-		Find(bson.M{"blockNumber": blockHeight}, opts...)
+		Find(ctx, bson.M{"blockNumber": blockHeight}, opts...)
 	defer func() {
 		err = cursor.Close(ctx)
 		if err != nil {
@@ -140,13 +148,52 @@ func (m *mongoDB) TxsByBlockHeight(ctx context.Context, blockHeight uint64, pagi
 		txs = append(txs, tx)
 	}
 	// get total transaction in block in database
-	total, err := m.wrapper.C(cTxs).Count(bson.M{"blockNumber": blockHeight})
+	total, err := m.wrapper.C(cTxs).Count(ctx, bson.M{"blockNumber": blockHeight})
 	if err != nil {
 		return nil, 0, err
 	}
 	return txs, uint64(total), nil
 }
 
+func (m *mongoDB) TxsByBlockHeights(ctx context.Context, blockHeights []uint64, pagination *types.Pagination) (map[uint64][]*types.Transaction, error) {
+	result := make(map[uint64][]*types.Transaction, len(blockHeights))
+	if len(blockHeights) == 0 {
+		return result, nil
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"blockNumber": bson.M{"$in": blockHeights}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "blockNumber", Value: 1}, {Key: "transactionindex", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$blockNumber", "txs": bson.M{"$push": "$$ROOT"}}}},
+	}
+	if pagination != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$project", Value: bson.M{
+			"txs": bson.M{"$slice": bson.A{"$txs", pagination.Skip, pagination.Limit}}}}})
+	}
+	cursor, err := m.wrapper.C(cTxs).Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	var rows []struct {
+		Height uint64               `bson:"_id"`
+		Txs    []*types.Transaction `bson:"txs"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if len(row.Txs) > 0 {
+			result[row.Height] = row.Txs
+		}
+	}
+	return result, nil
+}
+
 // TxsByAddress return txs match input address in FROM/TO field
 func (m *mongoDB) TxsByAddress(ctx context.Context, address string, pagination *types.Pagination) ([]*types.Transaction, uint64, error) {
 	var txs []*types.Transaction
@@ -157,7 +204,7 @@ func (m *mongoDB) TxsByAddress(ctx context.Context, address string, pagination *
 		opts = append(opts, options.Find().SetSkip(int64(pagination.Skip)), options.Find().SetLimit(int64(pagination.Limit)))
 	}
 	cursor, err := m.wrapper.C(cTxs).
-		Find(bson.M{"$or": []bson.M{{"from": address}, {"to": address}}}, opts...)
+		Find(ctx, bson.M{"$or": []bson.M{{"from": address}, {"to": address}}}, opts...)
 	defer func() {
 		err = cursor.Close(ctx)
 		if err != nil {
@@ -177,7 +224,7 @@ func (m *mongoDB) TxsByAddress(ctx context.Context, address string, pagination *
 		}
 		txs = append(txs, tx)
 	}
-	total, err := m.wrapper.C(cTxs).Count(bson.M{"$or": []bson.M{{"from": address}, {"to": address}}}, nil)
+	total, err := m.wrapper.C(cTxs).Count(ctx, bson.M{"$or": []bson.M{{"from": address}, {"to": address}}}, nil)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -185,9 +232,84 @@ func (m *mongoDB) TxsByAddress(ctx context.Context, address string, pagination *
 	return txs, uint64(total), nil
 }
 
+func (m *mongoDB) TxsByAddresses(ctx context.Context, addresses []string, pagination *types.Pagination) ([]*types.Transaction, uint64, error) {
+	var (
+		txs  []*types.Transaction
+		crit = bson.M{"$or": []bson.M{{"from": bson.M{"$in": addresses}}, {"to": bson.M{"$in": addresses}}}}
+		opts = []*options.FindOptions{
+			options.Find().SetSort(bson.M{"time": -1}),
+		}
+	)
+	if pagination != nil {
+		pagination.Sanitize()
+		opts = append(opts, options.Find().SetSkip(int64(pagination.Skip)), options.Find().SetLimit(int64(pagination.Limit)))
+	}
+	cursor, err := m.wrapper.C(cTxs).Find(ctx, crit, opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	if err := cursor.All(ctx, &txs); err != nil {
+		return nil, 0, err
+	}
+	total, err := m.wrapper.C(cTxs).Count(ctx, crit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return txs, uint64(total), nil
+}
+
+// TxsByAddressInRange return txs match input address in FROM/TO field and mined in block range, ordered by block number
+func (m *mongoDB) TxsByAddressInRange(ctx context.Context, address string, blockRange *types.BlockRangeFilter, pagination *types.Pagination) ([]*types.Transaction, uint64, error) {
+	var (
+		txs        []*types.Transaction
+		order      = -1
+		heightCrit = bson.M{"$gte": blockRange.FromBlock}
+	)
+	if blockRange.ToBlock != 0 {
+		heightCrit["$lte"] = blockRange.ToBlock
+	}
+	if blockRange.Asc {
+		order = 1
+	}
+	crit := bson.M{
+		"$or":         []bson.M{{"from": address}, {"to": address}},
+		"blockNumber": heightCrit,
+	}
+	opts := []*options.FindOptions{
+		options.Find().SetSort(bson.M{"blockNumber": order}),
+	}
+	if pagination != nil {
+		opts = append(opts, options.Find().SetSkip(int64(pagination.Skip)), options.Find().SetLimit(int64(pagination.Limit)))
+	}
+	cursor, err := m.wrapper.C(cTxs).Find(ctx, crit, opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	if err = cursor.All(ctx, &txs); err != nil {
+		return nil, 0, err
+	}
+	total, err := m.wrapper.C(cTxs).Count(ctx, crit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return txs, uint64(total), nil
+}
+
 func (m *mongoDB) TxByHash(ctx context.Context, txHash string) (*types.Transaction, error) {
 	var tx *types.Transaction
-	err := m.wrapper.C(cTxs).FindOne(bson.M{"hash": txHash}, options.FindOne().SetHint(bson.M{"hash": -1})).Decode(&tx)
+	err := m.wrapper.C(cTxs).FindOne(ctx, bson.M{"hash": txHash}, options.FindOne().SetHint(bson.M{"hash": -1})).Decode(&tx)
 	if err != nil {
 		if err == mgo.ErrNotFound {
 			return nil, nil
@@ -197,6 +319,25 @@ func (m *mongoDB) TxByHash(ctx context.Context, txHash string) (*types.Transacti
 	return tx, nil
 }
 
+// TxsByHashes return found txs of input hashes, in no particular order
+func (m *mongoDB) TxsByHashes(ctx context.Context, txHashes []string) ([]*types.Transaction, error) {
+	var txs []*types.Transaction
+	cursor, err := m.wrapper.C(cTxs).Find(ctx, bson.M{"hash": bson.M{"$in": txHashes}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get txs: %v", err)
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	if err := cursor.All(ctx, &txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
 func (m *mongoDB) FilterTxs(ctx context.Context, filter *types.TxsFilter) ([]*types.Transaction, uint64, error) {
 	var (
 		txs  []*types.Transaction
@@ -215,7 +356,7 @@ func (m *mongoDB) FilterTxs(ctx context.Context, filter *types.TxsFilter) ([]*ty
 		filter.Pagination.Sanitize()
 		opts = append(opts, options.Find().SetSkip(int64(filter.Pagination.Skip)), options.Find().SetLimit(int64(filter.Pagination.Limit)))
 	}
-	cursor, err := m.wrapper.C(cTxs).Find(crit, opts...)
+	cursor, err := m.wrapper.C(cTxs).Find(ctx, crit, opts...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -231,7 +372,7 @@ func (m *mongoDB) FilterTxs(ctx context.Context, filter *types.TxsFilter) ([]*ty
 		return nil, 0, err
 	}
 
-	total, err := m.wrapper.C(cTxs).Count(crit)
+	total, err := m.wrapper.C(cTxs).Count(ctx, crit)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -249,7 +390,7 @@ func (m *mongoDB) InsertTxs(ctx context.Context, txs []*types.Transaction) error
 		txsBulkWriter = append(txsBulkWriter, txModel)
 	}
 	if len(txsBulkWriter) > 0 {
-		if _, err := m.wrapper.C(cTxs).BulkWrite(txsBulkWriter); err != nil {
+		if _, err := m.wrapper.C(cTxs).BulkWrite(ctx, txsBulkWriter); err != nil {
 			return err
 		}
 	}
@@ -257,6 +398,30 @@ func (m *mongoDB) InsertTxs(ctx context.Context, txs []*types.Transaction) error
 	return nil
 }
 
+// ReplaceTxs overwrite txs which have the same hash
+func (m *mongoDB) ReplaceTxs(ctx context.Context, txs []*types.Transaction) error {
+	var txsBulkWriter []mongo.WriteModel
+	for _, tx := range txs {
+		txModel := mongo.NewReplaceOneModel().SetUpsert(true).SetFilter(bson.M{"hash": tx.Hash}).SetReplacement(tx)
+		txsBulkWriter = append(txsBulkWriter, txModel)
+	}
+	if len(txsBulkWriter) > 0 {
+		if _, err := m.wrapper.C(cTxs).BulkWrite(ctx, txsBulkWriter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReplaceTxsOfBlock remove all stored txs of block then insert the given ones
+func (m *mongoDB) ReplaceTxsOfBlock(ctx context.Context, blockHeight uint64, txs []*types.Transaction) error {
+	if _, err := m.wrapper.C(cTxs).RemoveAll(ctx, bson.M{"blockNumber": blockHeight}); err != nil {
+		m.logger.Warn("cannot remove old block txs", zap.Error(err), zap.Uint64("height", blockHeight))
+		return err
+	}
+	return m.InsertTxs(ctx, txs)
+}
+
 func (m *mongoDB) UpsertTxs(ctx context.Context, txs []*types.Transaction) error {
 	var txsBulkWriter []mongo.WriteModel
 	for _, tx := range txs {
@@ -264,7 +429,7 @@ func (m *mongoDB) UpsertTxs(ctx context.Context, txs []*types.Transaction) error
 		txsBulkWriter = append(txsBulkWriter, txModel)
 	}
 
-	if _, err := m.wrapper.C(cTxs).BulkWrite(txsBulkWriter); err != nil {
+	if _, err := m.wrapper.C(cTxs).BulkWrite(ctx, txsBulkWriter); err != nil {
 		return err
 	}
 	return nil
@@ -279,7 +444,7 @@ func (m *mongoDB) LatestTxs(ctx context.Context, pagination *types.Pagination) (
 	}
 
 	var txs []*types.Transaction
-	cursor, err := m.wrapper.C(cTxs).Find(bson.D{}, opts...)
+	cursor, err := m.wrapper.C(cTxs).Find(ctx, bson.D{}, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -62,15 +62,15 @@ func (m *mongoDB) UpsertValidators(ctx context.Context, validators []*types.Vali
 		contractModels = append(contractModels, mongo.NewUpdateOneModel().SetUpsert(true).SetFilter(bson.M{"address": contractInfo.Address}).SetUpdate(bson.M{"$set": contractInfo}))
 	}
 
-	if _, err := m.wrapper.C(cValidators).BulkUpsert(models); err != nil {
+	if _, err := m.wrapper.C(cValidators).BulkUpsert(ctx, models); err != nil {
 		lgr.Error("Cannot write validator models", zap.Error(err))
 		return err
 	}
-	if _, err := m.wrapper.C(cAddresses).BulkUpsert(addressModels); err != nil {
+	if _, err := m.wrapper.C(cAddresses).BulkUpsert(ctx, addressModels); err != nil {
 		lgr.Error("Cannot write address info models", zap.Error(err))
 		return err
 	}
-	if _, err := m.wrapper.C(cContract).BulkUpsert(contractModels); err != nil {
+	if _, err := m.wrapper.C(cContract).BulkUpsert(ctx, contractModels); err != nil {
 		fmt.Println("Cannot write contract info models", err)
 		return err
 	}
@@ -96,9 +96,9 @@ func (m *mongoDB) Validators(ctx context.Context, filter ValidatorsFilter) ([]*t
 		err    error
 	)
 	if len(mgoFilter) == 0 {
-		cursor, err = m.wrapper.C(cValidators).Find(bson.M{})
+		cursor, err = m.wrapper.C(cValidators).Find(ctx, bson.M{})
 	} else {
-		cursor, err = m.wrapper.C(cValidators).Find(bson.M{"$and": mgoFilter})
+		cursor, err = m.wrapper.C(cValidators).Find(ctx, bson.M{"$and": mgoFilter})
 	}
 	if err != nil {
 		return nil, err
@@ -122,14 +122,14 @@ func (m *mongoDB) Validator(ctx context.Context, validatorAddress string) (*type
 	// todo: better to force all address into lowercase before insert into db
 	validatorAddress = common.HexToAddress(validatorAddress).String()
 	var validator *types.Validator
-	if err := m.wrapper.C(cValidators).FindOne(bson.M{"address": validatorAddress}).Decode(&validator); err != nil {
+	if err := m.wrapper.C(cValidators).FindOne(ctx, bson.M{"address": validatorAddress}).Decode(&validator); err != nil {
 		return nil, err
 	}
 	return validator, nil
 }
 
 func (m *mongoDB) ClearValidators(ctx context.Context) error {
-	if _, err := m.wrapper.C(cValidators).RemoveAll(bson.M{}); err != nil {
+	if _, err := m.wrapper.C(cValidators).RemoveAll(ctx, bson.M{}); err != nil {
 		return err
 	}
 	return nil
@@ -139,7 +139,7 @@ func (m *mongoDB) UpsertValidator(ctx context.Context, validator *types.Validato
 	var models []mongo.WriteModel
 	models = append(models, mongo.NewUpdateOneModel().SetUpsert(true).SetFilter(bson.M{"smcAddress": validator.SmcAddress}).SetUpdate(bson.M{"$set": validator}))
 
-	if _, err := m.wrapper.C(cValidators).BulkUpsert(models); err != nil {
+	if _, err := m.wrapper.C(cValidators).BulkUpsert(ctx, models); err != nil {
 		fmt.Println("Cannot write list model", err)
 		return err
 	}
@@ -148,17 +148,17 @@ func (m *mongoDB) UpsertValidator(ctx context.Context, validator *types.Validato
 
 func (m *mongoDB) UpdateProposers(ctx context.Context, proposerAddresses []string) error {
 	// Bind array
-	if _, err := m.wrapper.C(cValidators).UpdateMany(bson.M{}, bson.M{"$set": bson.M{"status": 0, "role": 0}}); err != nil {
+	if _, err := m.wrapper.C(cValidators).UpdateMany(ctx, bson.M{}, bson.M{"$set": bson.M{"status": 0, "role": 0}}); err != nil {
 		return err
 	}
-	if _, err := m.wrapper.C(cValidators).UpdateMany(bson.M{"address": bson.M{"$in": proposerAddresses}}, bson.M{"$set": bson.M{"status": 2, "role": 2}}); err != nil {
+	if _, err := m.wrapper.C(cValidators).UpdateMany(ctx, bson.M{"address": bson.M{"$in": proposerAddresses}}, bson.M{"$set": bson.M{"status": 2, "role": 2}}); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (m *mongoDB) RemoveValidator(ctx context.Context, validatorSMCAddress string) error {
-	if _, err := m.wrapper.C(cValidators).Remove(bson.M{"smcAddress": validatorSMCAddress}); err != nil {
+	if _, err := m.wrapper.C(cValidators).Remove(ctx, bson.M{"smcAddress": validatorSMCAddress}); err != nil {
 		return err
 	}
 	return nil
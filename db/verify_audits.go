@@ -0,0 +1,77 @@
+// Package db
+package db
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+var cVerifyAudits = "VerifyAudits"
+
+type IVerifyAudit interface {
+	createVerifyAuditsCollectionIndexes() []mongo.IndexModel
+	InsertVerifyAudit(ctx context.Context, audit *types.VerifyAudit) error
+	VerifyAudits(ctx context.Context, filter *types.VerifyAuditsFilter) ([]*types.VerifyAudit, uint64, error)
+}
+
+func (m *mongoDB) createVerifyAuditsCollectionIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{Keys: bson.M{"blockHeight": -1}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.M{"createdAt": -1}, Options: options.Index().SetSparse(true)},
+	}
+}
+
+func (m *mongoDB) InsertVerifyAudit(ctx context.Context, audit *types.VerifyAudit) error {
+	if _, err := m.wrapper.C(cVerifyAudits).Insert(ctx, audit); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *mongoDB) VerifyAudits(ctx context.Context, filter *types.VerifyAuditsFilter) ([]*types.VerifyAudit, uint64, error) {
+	var (
+		audits []*types.VerifyAudit
+		crit   = bson.M{}
+	)
+	critBytes, err := bson.Marshal(filter)
+	if err != nil {
+		m.logger.Warn("Cannot marshal verify audits filter criteria", zap.Error(err))
+	}
+	err = bson.Unmarshal(critBytes, &crit)
+	if err != nil {
+		m.logger.Warn("Cannot unmarshal verify audits filter criteria", zap.Error(err))
+	}
+
+	opts := []*options.FindOptions{
+		options.Find().SetSort(bson.M{"createdAt": -1}),
+	}
+	if filter.Pagination != nil {
+		filter.Pagination.Sanitize()
+		opts = append(opts, options.Find().SetSkip(int64(filter.Pagination.Skip)), options.Find().SetLimit(int64(filter.Pagination.Limit)))
+	}
+	cursor, err := m.wrapper.C(cVerifyAudits).Find(ctx, crit, opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		err = cursor.Close(ctx)
+		if err != nil {
+			m.logger.Warn("Error when close cursor", zap.Error(err))
+		}
+	}()
+	if err := cursor.All(ctx, &audits); err != nil {
+		return nil, 0, err
+	}
+
+	total, err := m.wrapper.C(cVerifyAudits).Count(ctx, crit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return audits, uint64(total), nil
+}
@@ -0,0 +1,166 @@
+// Package solc compile Solidity sources with official solc binaries, cached locally by version
+package solc
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const DefaultBinaryURL = "https://binaries.soliditylang.org/linux-amd64"
+
+var ErrVersionNotFound = errors.New("solc version not found")
+
+// Compiler compile a standard JSON input with a specific solc version
+type Compiler interface {
+	Compile(ctx context.Context, version string, input *Input) (*Output, error)
+}
+
+type Config struct {
+	// CacheDir store downloaded solc binaries
+	CacheDir string
+	// BinaryURL point to a solc-bin mirror, which serve list.json and binaries
+	BinaryURL string
+	Logger    *zap.Logger
+}
+
+type compiler struct {
+	cacheDir  string
+	binaryURL string
+	client    *http.Client
+	logger    *zap.Logger
+
+	mu sync.Mutex
+}
+
+func NewCompiler(cfg Config) Compiler {
+	if cfg.BinaryURL == "" {
+		cfg.BinaryURL = DefaultBinaryURL
+	}
+	return &compiler{
+		cacheDir:  cfg.CacheDir,
+		binaryURL: strings.TrimSuffix(cfg.BinaryURL, "/"),
+		client:    &http.Client{Timeout: 2 * time.Minute},
+		logger:    cfg.Logger,
+	}
+}
+
+func (c *compiler) Compile(ctx context.Context, version string, input *Input) (*Output, error) {
+	bin, err := c.binary(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	inputBytes, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, bin, "--standard-json")
+	cmd.Stdin = bytes.NewReader(inputBytes)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("cannot run solc %s: %v %s", version, err, stderr.String())
+	}
+	var output Output
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+type build struct {
+	Path        string `json:"path"`
+	Version     string `json:"version"`
+	LongVersion string `json:"longVersion"`
+	Sha256      string `json:"sha256"`
+}
+
+// binary return the path of cached solc binary, download it when missing
+func (c *compiler) binary(ctx context.Context, version string) (string, error) {
+	version = strings.TrimPrefix(version, "v")
+	if version == "" || strings.ContainsAny(version, `/\`) {
+		return "", ErrVersionNotFound
+	}
+	bin := filepath.Join(c.cacheDir, "solc-"+version)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := os.Stat(bin); err == nil {
+		return bin, nil
+	}
+
+	b, err := c.findBuild(ctx, version)
+	if err != nil {
+		return "", err
+	}
+	c.logger.Info("Download solc binary", zap.String("version", version), zap.String("path", b.Path))
+	data, err := c.get(ctx, c.binaryURL+"/"+b.Path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	if b.Sha256 != "" && strings.TrimPrefix(b.Sha256, "0x") != hex.EncodeToString(sum[:]) {
+		return "", fmt.Errorf("checksum mismatch for solc %s", version)
+	}
+	if err := os.MkdirAll(c.cacheDir, 0755); err != nil {
+		return "", err
+	}
+	tmp := bin + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0755); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, bin); err != nil {
+		return "", err
+	}
+	return bin, nil
+}
+
+func (c *compiler) findBuild(ctx context.Context, version string) (*build, error) {
+	data, err := c.get(ctx, c.binaryURL+"/list.json")
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Builds []*build `json:"builds"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	for _, b := range list.Builds {
+		if b.LongVersion == version || b.Version == version {
+			return b, nil
+		}
+	}
+	return nil, ErrVersionNotFound
+}
+
+func (c *compiler) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cannot get %s, status %d", url, resp.StatusCode)
+	}
+	return ioutil.ReadAll(io.LimitReader(resp.Body, 128<<20))
+}
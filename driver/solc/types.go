@@ -0,0 +1,200 @@
+// Package solc
+package solc
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidInput = errors.New("invalid standard JSON input")
+
+// outputSelection is the compiler output needed for verification
+var outputSelection = map[string]map[string][]string{
+	"*": {"*": {"abi", "evm.bytecode.object", "evm.deployedBytecode.object", "evm.deployedBytecode.immutableReferences"}},
+}
+
+// Input is the solc standard JSON input
+type Input struct {
+	Language string             `json:"language"`
+	Sources  map[string]*Source `json:"sources"`
+	Settings *Settings          `json:"settings"`
+}
+
+type Source struct {
+	Content string `json:"content"`
+}
+
+type Settings struct {
+	Remappings []string   `json:"remappings,omitempty"`
+	Optimizer  *Optimizer `json:"optimizer,omitempty"`
+	EVMVersion string     `json:"evmVersion,omitempty"`
+	ViaIR      bool       `json:"viaIR,omitempty"`
+	// Metadata settings change the appended metadata, they are kept as submitted
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+	// Libraries map source file to library name and address, empty file name means global
+	Libraries       map[string]map[string]string   `json:"libraries,omitempty"`
+	OutputSelection map[string]map[string][]string `json:"outputSelection"`
+}
+
+type Optimizer struct {
+	Enabled bool `json:"enabled"`
+	Runs    int  `json:"runs"`
+	// Details override optimizer steps, kept as submitted
+	Details json.RawMessage `json:"details,omitempty"`
+}
+
+// Options are the compiler settings of a verification request
+type Options struct {
+	Optimize   bool
+	Runs       int
+	EVMVersion string
+	Remappings []string
+	Libraries  map[string]map[string]string
+}
+
+// NewInput build a standard JSON input which compile all sources, keyed by file name
+func NewInput(sources map[string]string, opts Options) *Input {
+	if opts.Runs <= 0 {
+		opts.Runs = 200
+	}
+	input := &Input{
+		Language: "Solidity",
+		Sources:  make(map[string]*Source, len(sources)),
+		Settings: &Settings{
+			Remappings:      opts.Remappings,
+			Optimizer:       &Optimizer{Enabled: opts.Optimize, Runs: opts.Runs},
+			EVMVersion:      opts.EVMVersion,
+			Libraries:       opts.Libraries,
+			OutputSelection: outputSelection,
+		},
+	}
+	for name, content := range sources {
+		input.Sources[name] = &Source{Content: content}
+	}
+	return input
+}
+
+// NewSingleFileInput build a standard JSON input which compile one source file
+func NewSingleFileInput(fileName, source string, optimize bool, runs int) *Input {
+	return NewInput(map[string]string{fileName: source}, Options{Optimize: optimize, Runs: runs})
+}
+
+// ParseStandardJSON read a standard JSON input as produced by hardhat/truffle build info.
+// Sources must be inlined, output selection is replaced by what verification needs.
+func ParseStandardJSON(data []byte) (*Input, error) {
+	var input Input
+	if err := json.Unmarshal(data, &input); err != nil {
+		return nil, ErrInvalidInput
+	}
+	if input.Language == "" {
+		input.Language = "Solidity"
+	}
+	if input.Language != "Solidity" || len(input.Sources) == 0 {
+		return nil, ErrInvalidInput
+	}
+	for _, src := range input.Sources {
+		if src == nil || src.Content == "" {
+			return nil, ErrInvalidInput
+		}
+	}
+	if input.Settings == nil {
+		input.Settings = &Settings{}
+	}
+	input.Settings.OutputSelection = outputSelection
+	return &input, nil
+}
+
+// FileNames return sorted names of source files
+func (in *Input) FileNames() []string {
+	names := make([]string, 0, len(in.Sources))
+	for name := range in.Sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Output is the solc standard JSON output
+type Output struct {
+	Errors    []*Error                        `json:"errors"`
+	Contracts map[string]map[string]*Contract `json:"contracts"`
+}
+
+type Error struct {
+	Severity         string `json:"severity"`
+	Message          string `json:"message"`
+	FormattedMessage string `json:"formattedMessage"`
+}
+
+type Contract struct {
+	ABI json.RawMessage `json:"abi"`
+	EVM struct {
+		Bytecode         Bytecode `json:"bytecode"`
+		DeployedBytecode Bytecode `json:"deployedBytecode"`
+	} `json:"evm"`
+}
+
+type Bytecode struct {
+	Object string `json:"object"`
+	// ImmutableReferences locate immutable values by AST id, they are zero in compiled runtime code
+	// and written by constructor in deployed code (solc >= 0.6.5)
+	ImmutableReferences map[string][]ImmutableReference `json:"immutableReferences,omitempty"`
+}
+
+type ImmutableReference struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+// CompileErrors return messages of errors which made compilation failed, warnings are ignored
+func (o *Output) CompileErrors() []string {
+	var errs []string
+	for _, e := range o.Errors {
+		if e.Severity != "error" {
+			continue
+		}
+		if e.FormattedMessage != "" {
+			errs = append(errs, e.FormattedMessage)
+			continue
+		}
+		errs = append(errs, e.Message)
+	}
+	return errs
+}
+
+// namedContract is a compiled contract with its fully qualified name "file:Name"
+type namedContract struct {
+	file string
+	name string
+	*Contract
+}
+
+// findContracts return compiled contracts match the name, which may be qualified by
+// source file ("contracts/Token.sol:Token"). Empty name match every contract.
+func (o *Output) findContracts(name string) []*namedContract {
+	file := ""
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		file, name = name[:i], name[i+1:]
+	}
+	var contracts []*namedContract
+	for f, byName := range o.Contracts {
+		if file != "" && f != file {
+			continue
+		}
+		for n, c := range byName {
+			if name != "" && n != name {
+				continue
+			}
+			contracts = append(contracts, &namedContract{file: f, name: n, Contract: c})
+		}
+	}
+	sort.Slice(contracts, func(i, j int) bool {
+		if contracts[i].file != contracts[j].file {
+			return contracts[i].file < contracts[j].file
+		}
+		return contracts[i].name < contracts[j].name
+	})
+	return contracts
+}
@@ -0,0 +1,142 @@
+// Package solc
+package solc
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrCompilation      = errors.New("compilation failed")
+	ErrBytecodeMismatch = errors.New("compiled bytecode does not match deployed bytecode")
+	ErrConstructorArgs  = errors.New("constructor arguments do not match contract creation input")
+)
+
+// VerifyRequest describe a contract verification against its deployed code
+type VerifyRequest struct {
+	CompilerVersion string
+	Input           *Input
+	// ContractName may be qualified by source file, when empty every compiled contract is tried
+	ContractName string
+	// ConstructorArgs is the hex encoded ABI arguments appended to creation code, optional
+	ConstructorArgs string
+
+	// DeployedCode is the runtime code returned by GetCode
+	DeployedCode []byte
+	// CreationInput is the input of the contract creation tx, used to check constructor args when available
+	CreationInput []byte
+}
+
+type VerifyResult struct {
+	// File is the source file which declare the verified contract
+	File            string
+	ContractName    string
+	ABI             string
+	ConstructorArgs string
+	CompileErrors   []string
+}
+
+// Verify compile sources and compare runtime bytecode with deployed code, ignoring the metadata hash
+func Verify(ctx context.Context, c Compiler, req *VerifyRequest) (*VerifyResult, error) {
+	output, err := c.Compile(ctx, req.CompilerVersion, req.Input)
+	if err != nil {
+		return nil, err
+	}
+	if errs := output.CompileErrors(); len(errs) > 0 {
+		return &VerifyResult{CompileErrors: errs}, ErrCompilation
+	}
+	for _, contract := range output.findContracts(req.ContractName) {
+		deployed, err := decodeHex(contract.EVM.DeployedBytecode.Object)
+		if err != nil || len(deployed) == 0 {
+			continue
+		}
+		immutables := contract.EVM.DeployedBytecode.ImmutableReferences
+		if !MatchBytecode(MaskImmutables(deployed, immutables), MaskImmutables(req.DeployedCode, immutables)) {
+			continue
+		}
+		result := &VerifyResult{
+			File:         contract.file,
+			ContractName: contract.name,
+			ABI:          string(contract.ABI),
+		}
+		args, err := constructorArgs(contract, req)
+		if err != nil {
+			return nil, err
+		}
+		result.ConstructorArgs = args
+		return result, nil
+	}
+	return nil, ErrBytecodeMismatch
+}
+
+// constructorArgs extract constructor args from creation input, which is creation code followed by args.
+// Creation input must start with the compiled creation code and end with provided args, if any.
+func constructorArgs(contract *namedContract, req *VerifyRequest) (string, error) {
+	provided, err := decodeHex(req.ConstructorArgs)
+	if err != nil {
+		return "", ErrConstructorArgs
+	}
+	if len(req.CreationInput) == 0 {
+		return hex.EncodeToString(provided), nil
+	}
+	creation, err := decodeHex(contract.EVM.Bytecode.Object)
+	if err != nil || len(creation) > len(req.CreationInput) || !MatchBytecode(creation, req.CreationInput[:len(creation)]) {
+		return "", ErrConstructorArgs
+	}
+	args := req.CreationInput[len(creation):]
+	if len(provided) > 0 && !bytes.Equal(provided, args) {
+		return "", ErrConstructorArgs
+	}
+	return hex.EncodeToString(args), nil
+}
+
+// MatchBytecode compare two runtime bytecodes without their trailing CBOR metadata,
+// which embed source hash and differ between builds of the same code
+func MatchBytecode(compiled, deployed []byte) bool {
+	return bytes.Equal(StripMetadata(compiled), StripMetadata(deployed))
+}
+
+// StripMetadata remove CBOR metadata appended by solc. The last 2 bytes of runtime code
+// hold the length of metadata, which is a CBOR map (0xa1..0xa7 header).
+func StripMetadata(code []byte) []byte {
+	if len(code) < 2 {
+		return code
+	}
+	size := int(binary.BigEndian.Uint16(code[len(code)-2:]))
+	start := len(code) - 2 - size
+	if size == 0 || start < 0 {
+		return code
+	}
+	if header := code[start]; header < 0xa1 || header > 0xa7 {
+		return code
+	}
+	return code[:start]
+}
+
+// MaskImmutables return a copy of runtime code with immutable values zeroed, as they are in compiled code.
+// References out of code are ignored, the code won't match anyway.
+func MaskImmutables(code []byte, refs map[string][]ImmutableReference) []byte {
+	if len(refs) == 0 {
+		return code
+	}
+	masked := append([]byte{}, code...)
+	for _, ranges := range refs {
+		for _, r := range ranges {
+			if r.Start < 0 || r.Length < 0 || r.Start+r.Length > len(masked) {
+				continue
+			}
+			for i := r.Start; i < r.Start+r.Length; i++ {
+				masked[i] = 0
+			}
+		}
+	}
+	return masked
+}
+
+func decodeHex(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
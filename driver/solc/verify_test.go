@@ -0,0 +1,181 @@
+// Package solc
+package solc
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fixtureCompiler return a recorded solc output instead of running solc
+type fixtureCompiler struct {
+	output *Output
+}
+
+func (c *fixtureCompiler) Compile(ctx context.Context, version string, input *Input) (*Output, error) {
+	return c.output, nil
+}
+
+func loadFixtureHex(t *testing.T, name string) []byte {
+	data, err := ioutil.ReadFile("testdata/" + name)
+	assert.Nil(t, err)
+	code, err := decodeHex(strings.TrimSpace(string(data)))
+	assert.Nil(t, err)
+	return code
+}
+
+func TestVerify(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/storage_output.json")
+	assert.Nil(t, err)
+	var output Output
+	assert.Nil(t, json.Unmarshal(data, &output))
+	compiler := &fixtureCompiler{output: &output}
+
+	deployed := loadFixtureHex(t, "storage_deployed.hex")
+	creation := loadFixtureHex(t, "storage_creation.hex")
+	args := "000000000000000000000000000000000000000000000000000000000000002a"
+	input := NewSingleFileInput("Storage.sol", "contract Storage {}", true, 200)
+
+	type testCase struct {
+		name         string
+		req          *VerifyRequest
+		contractName string
+		args         string
+		err          error
+	}
+	cases := []testCase{
+		{
+			name:         "match ignoring metadata hash",
+			req:          &VerifyRequest{Input: input, ContractName: "Storage", DeployedCode: deployed},
+			contractName: "Storage",
+		},
+		{
+			name:         "find contract when name is omitted",
+			req:          &VerifyRequest{Input: input, DeployedCode: deployed, CreationInput: creation},
+			contractName: "Storage",
+			args:         args,
+		},
+		{
+			name:         "qualified contract name and constructor args",
+			req:          &VerifyRequest{Input: input, ContractName: "Storage.sol:Storage", DeployedCode: deployed, CreationInput: creation, ConstructorArgs: "0x" + args},
+			contractName: "Storage",
+			args:         args,
+		},
+		{
+			name: "wrong constructor args",
+			req:  &VerifyRequest{Input: input, ContractName: "Storage", DeployedCode: deployed, CreationInput: creation, ConstructorArgs: strings.Repeat("0", 64)},
+			err:  ErrConstructorArgs,
+		},
+		{
+			name: "creation input of other code",
+			req:  &VerifyRequest{Input: input, ContractName: "Storage", DeployedCode: deployed, CreationInput: append([]byte{0x00}, creation[1:]...)},
+			err:  ErrConstructorArgs,
+		},
+		{
+			name: "other contract does not match",
+			req:  &VerifyRequest{Input: input, ContractName: "Helper", DeployedCode: deployed},
+			err:  ErrBytecodeMismatch,
+		},
+		{
+			name: "modified code does not match",
+			req:  &VerifyRequest{Input: input, DeployedCode: append([]byte{0x00}, deployed...)},
+			err:  ErrBytecodeMismatch,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result, err := Verify(context.Background(), compiler, c.req)
+			assert.Equal(t, c.err, err)
+			if c.err != nil {
+				return
+			}
+			assert.Equal(t, c.contractName, result.ContractName)
+			assert.Equal(t, c.args, result.ConstructorArgs)
+			assert.True(t, strings.Contains(result.ABI, "retrieve"))
+		})
+	}
+
+	t.Run("compile errors", func(t *testing.T) {
+		failed := &fixtureCompiler{output: &Output{Errors: []*Error{
+			{Severity: "warning", Message: "unused variable"},
+			{Severity: "error", Message: "Expected ';' but got '}'", FormattedMessage: "ParserError: Expected ';' but got '}'"},
+		}}}
+		result, err := Verify(context.Background(), failed, &VerifyRequest{Input: input, DeployedCode: deployed})
+		assert.Equal(t, ErrCompilation, err)
+		assert.Equal(t, []string{"ParserError: Expected ';' but got '}'"}, result.CompileErrors)
+	})
+}
+
+func TestVerify_Immutables(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/immutable_output.json")
+	assert.Nil(t, err)
+	var output Output
+	assert.Nil(t, json.Unmarshal(data, &output))
+	compiler := &fixtureCompiler{output: &output}
+	input := NewSingleFileInput("Owned.sol", "contract Owned {}", true, 200)
+
+	// constructor wrote the owner address where compiled code hold zeros
+	deployed := loadFixtureHex(t, "immutable_deployed.hex")
+	compiled, err := decodeHex(output.Contracts["Owned.sol"]["Owned"].EVM.DeployedBytecode.Object)
+	assert.Nil(t, err)
+	assert.False(t, MatchBytecode(compiled, deployed))
+
+	result, err := Verify(context.Background(), compiler, &VerifyRequest{Input: input, DeployedCode: deployed})
+	assert.Nil(t, err)
+	assert.Equal(t, "Owned", result.ContractName)
+
+	// only immutable ranges are masked
+	modified := append([]byte{}, deployed...)
+	modified[0] = 0x61
+	_, err = Verify(context.Background(), compiler, &VerifyRequest{Input: input, DeployedCode: modified})
+	assert.Equal(t, ErrBytecodeMismatch, err)
+}
+
+func TestMaskImmutables(t *testing.T) {
+	code := []byte{0x7f, 0x01, 0x02, 0x03, 0xfe}
+	refs := map[string][]ImmutableReference{"3": {{Start: 1, Length: 2}}, "7": {{Start: 4, Length: 9}}}
+	assert.Equal(t, []byte{0x7f, 0x00, 0x00, 0x03, 0xfe}, MaskImmutables(code, refs))
+	// input code is kept
+	assert.Equal(t, byte(0x01), code[1])
+	assert.Equal(t, code, MaskImmutables(code, nil))
+}
+
+func TestStripMetadata(t *testing.T) {
+	code := []byte{0x60, 0x80, 0xfe}
+	// too short or without CBOR map header, keep code as is
+	assert.Equal(t, code, StripMetadata(code))
+	assert.Equal(t, []byte{0x00, 0x01}, StripMetadata([]byte{0x00, 0x01}))
+	withMetadata := append(append([]byte{}, code...), 0xa1, 0x65, 0x62, 0x7a, 0x7a, 0x72, 0x30, 0x00, 0x07)
+	assert.Equal(t, code, StripMetadata(withMetadata))
+}
+
+func TestParseStandardJSON(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/standard_input.json")
+	assert.Nil(t, err)
+	input, err := ParseStandardJSON(data)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"@openzeppelin/contracts/token/ERC20/ERC20.sol", "contracts/Token.sol"}, input.FileNames())
+	assert.Equal(t, 1000, input.Settings.Optimizer.Runs)
+	assert.Equal(t, "istanbul", input.Settings.EVMVersion)
+	assert.Equal(t, []string{"@openzeppelin/=node_modules/@openzeppelin/"}, input.Settings.Remappings)
+	// output selection is limited to what verification needs, other settings are kept
+	assert.Equal(t, outputSelection, input.Settings.OutputSelection)
+	encoded, err := json.Marshal(input)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(string(encoded), `"metadata":{"bytecodeHash":"none"}`))
+	assert.True(t, strings.Contains(string(encoded), `"details":{"yul":true}`))
+
+	for _, invalid := range []string{
+		`not json`,
+		`{"language": "Vyper", "sources": {"a.vy": {"content": "x"}}}`,
+		`{"language": "Solidity", "sources": {}}`,
+		`{"language": "Solidity", "sources": {"a.sol": {"urls": ["ipfs://"]}}}`,
+	} {
+		_, err := ParseStandardJSON([]byte(invalid))
+		assert.Equal(t, ErrInvalidInput, err, invalid)
+	}
+}
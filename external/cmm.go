@@ -1,2 +1,144 @@
 // Package external
 package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultCoinMarketCapURL = "https://pro-api.coinmarketcap.com"
+	DefaultCoinMarketCapID  = 5453 // KAI
+
+	// CoinMarketCap error codes of rate limits
+	cmcMinuteRateLimit  = 1008
+	cmcDailyRateLimit   = 1009
+	cmcMonthlyRateLimit = 1010
+	cmcIPRateLimit      = 1011
+)
+
+type CoinMarketCapConfig struct {
+	APIKey  string
+	ID      int
+	BaseURL string
+}
+
+type coinMarketCap struct {
+	apiKey  string
+	id      string
+	baseURL string
+	client  *http.Client
+}
+
+func NewCoinMarketCap(cfg CoinMarketCapConfig) Provider {
+	if cfg.ID == 0 {
+		cfg.ID = DefaultCoinMarketCapID
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultCoinMarketCapURL
+	}
+	return &coinMarketCap{
+		apiKey:  cfg.APIKey,
+		id:      strconv.Itoa(cfg.ID),
+		baseURL: strings.TrimSuffix(cfg.BaseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *coinMarketCap) Name() string {
+	return "coinmarketcap"
+}
+
+type cmcQuote struct {
+	Price            float64 `json:"price"`
+	Volume24h        float64 `json:"volume_24h"`
+	PercentChange1h  float64 `json:"percent_change_1h"`
+	PercentChange24h float64 `json:"percent_change_24h"`
+	PercentChange7d  float64 `json:"percent_change_7d"`
+	MarketCap        float64 `json:"market_cap"`
+	LastUpdated      string  `json:"last_updated"`
+}
+
+type cmcTokenInfo struct {
+	ID                int                 `json:"id"`
+	Name              string              `json:"name"`
+	Symbol            string              `json:"symbol"`
+	CirculatingSupply float64             `json:"circulating_supply"`
+	TotalSupply       float64             `json:"total_supply"`
+	Quote             map[string]cmcQuote `json:"quote"`
+}
+
+type cmcResponse struct {
+	Status struct {
+		ErrorCode    int    `json:"error_code"`
+		ErrorMessage string `json:"error_message"`
+		CreditCount  int    `json:"credit_count"`
+	} `json:"status"`
+	Data map[string]cmcTokenInfo `json:"data"`
+}
+
+func (c *coinMarketCap) Quote(ctx context.Context) (*Quote, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/cryptocurrency/quotes/latest?id="+c.id, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-CMC_PRO_API_KEY", c.apiKey)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var cmResp cmcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&cmResp); err != nil {
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return nil, &RateLimitError{Provider: c.Name(), RetryAfter: retryAfter(resp.Header)}
+		}
+		return nil, fmt.Errorf("%s: cannot decode response with status %d: %v", c.Name(), resp.StatusCode, err)
+	}
+	switch cmResp.Status.ErrorCode {
+	case 0:
+	case cmcMinuteRateLimit, cmcIPRateLimit:
+		return nil, &RateLimitError{Provider: c.Name(), RetryAfter: retryAfter(resp.Header)}
+	case cmcDailyRateLimit:
+		now := time.Now().UTC()
+		nextDay := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+		return nil, &RateLimitError{Provider: c.Name(), RetryAfter: nextDay.Sub(now)}
+	case cmcMonthlyRateLimit:
+		return nil, &RateLimitError{Provider: c.Name(), RetryAfter: 24 * time.Hour}
+	default:
+		return nil, fmt.Errorf("%s: error %d %s", c.Name(), cmResp.Status.ErrorCode, cmResp.Status.ErrorMessage)
+	}
+
+	data, ok := cmResp.Data[c.id]
+	if !ok {
+		return nil, fmt.Errorf("%s: coin %s not found", c.Name(), c.id)
+	}
+	usd, ok := data.Quote["USD"]
+	if !ok {
+		return nil, fmt.Errorf("%s: USD quote not found", c.Name())
+	}
+	updatedAt, err := time.Parse(time.RFC3339, usd.LastUpdated)
+	if err != nil {
+		updatedAt = time.Now()
+	}
+	return &Quote{
+		Name:              data.Name,
+		Symbol:            data.Symbol,
+		Price:             usd.Price,
+		Volume24h:         usd.Volume24h,
+		MarketCap:         usd.MarketCap,
+		Change1h:          usd.PercentChange1h,
+		Change24h:         usd.PercentChange24h,
+		Change7d:          usd.PercentChange7d,
+		TotalSupply:       int64(data.TotalSupply),
+		CirculatingSupply: int64(data.CirculatingSupply),
+		UpdatedAt:         updatedAt,
+	}, nil
+}
@@ -0,0 +1,108 @@
+// Package external
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultCoinGeckoURL = "https://api.coingecko.com"
+	DefaultCoinGeckoID  = "kardiachain"
+)
+
+type CoinGeckoConfig struct {
+	ID      string
+	BaseURL string
+}
+
+type coinGecko struct {
+	id      string
+	baseURL string
+	client  *http.Client
+}
+
+func NewCoinGecko(cfg CoinGeckoConfig) Provider {
+	if cfg.ID == "" {
+		cfg.ID = DefaultCoinGeckoID
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultCoinGeckoURL
+	}
+	return &coinGecko{
+		id:      cfg.ID,
+		baseURL: strings.TrimSuffix(cfg.BaseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *coinGecko) Name() string {
+	return "coingecko"
+}
+
+type coinGeckoMarket struct {
+	ID                string    `json:"id"`
+	Symbol            string    `json:"symbol"`
+	Name              string    `json:"name"`
+	CurrentPrice      float64   `json:"current_price"`
+	MarketCap         float64   `json:"market_cap"`
+	TotalVolume       float64   `json:"total_volume"`
+	CirculatingSupply float64   `json:"circulating_supply"`
+	TotalSupply       float64   `json:"total_supply"`
+	Change1h          float64   `json:"price_change_percentage_1h_in_currency"`
+	Change24h         float64   `json:"price_change_percentage_24h_in_currency"`
+	Change7d          float64   `json:"price_change_percentage_7d_in_currency"`
+	LastUpdated       time.Time `json:"last_updated"`
+}
+
+func (c *coinGecko) Quote(ctx context.Context) (*Quote, error) {
+	query := url.Values{}
+	query.Set("vs_currency", "usd")
+	query.Set("ids", c.id)
+	query.Set("price_change_percentage", "1h,24h,7d")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v3/coins/markets?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, &RateLimitError{Provider: c.Name(), RetryAfter: retryAfter(resp.Header)}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: unexpected status %d", c.Name(), resp.StatusCode)
+	}
+
+	var markets []coinGeckoMarket
+	if err := json.NewDecoder(resp.Body).Decode(&markets); err != nil {
+		return nil, err
+	}
+	for _, m := range markets {
+		if m.ID != c.id {
+			continue
+		}
+		return &Quote{
+			Name:              m.Name,
+			Symbol:            strings.ToUpper(m.Symbol),
+			Price:             m.CurrentPrice,
+			Volume24h:         m.TotalVolume,
+			MarketCap:         m.MarketCap,
+			Change1h:          m.Change1h,
+			Change24h:         m.Change24h,
+			Change7d:          m.Change7d,
+			TotalSupply:       int64(m.TotalSupply),
+			CirculatingSupply: int64(m.CirculatingSupply),
+			UpdatedAt:         m.LastUpdated,
+		}, nil
+	}
+	return nil, fmt.Errorf("%s: coin %s not found", c.Name(), c.id)
+}
@@ -0,0 +1,170 @@
+// Package external
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+const defaultRetryAfter = time.Minute
+
+var ErrNoProvider = errors.New("no market data provider available")
+
+// Quote is USD market data of a coin
+type Quote struct {
+	Name              string    `json:"name"`
+	Symbol            string    `json:"symbol"`
+	Price             float64   `json:"price"`
+	Volume24h         float64   `json:"volume24h"`
+	MarketCap         float64   `json:"marketCap"`
+	Change1h          float64   `json:"change1h"`
+	Change24h         float64   `json:"change24h"`
+	Change7d          float64   `json:"change7d"`
+	TotalSupply       int64     `json:"totalSupply"`
+	CirculatingSupply int64     `json:"circulatingSupply"`
+	Source            string    `json:"source"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+// TokenInfo convert quote to token info served by dashboard, supplies are adjusted by cache
+func (q *Quote) TokenInfo() *types.TokenInfo {
+	return &types.TokenInfo{
+		Name:                   q.Name,
+		Symbol:                 q.Symbol,
+		Decimal:                18,
+		TotalSupply:            q.TotalSupply,
+		ERC20CirculatingSupply: q.CirculatingSupply,
+		Price:                  q.Price,
+		Volume24h:              q.Volume24h,
+		Change1h:               q.Change1h,
+		Change24h:              q.Change24h,
+		Change7d:               q.Change7d,
+		MarketCap:              q.MarketCap,
+	}
+}
+
+// Provider fetch latest quote from a market data API
+type Provider interface {
+	Name() string
+	Quote(ctx context.Context) (*Quote, error)
+}
+
+// RateLimitError is returned by providers when API reject requests for exceeding its limits
+type RateLimitError struct {
+	Provider   string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s rate limited, retry after %s", e.Provider, e.RetryAfter)
+}
+
+// DefaultProviders return CoinMarketCap, skipped when api key is empty, then CoinGecko as fallback
+func DefaultProviders(cmc CoinMarketCapConfig, coinGecko CoinGeckoConfig) []Provider {
+	var providers []Provider
+	if cmc.APIKey != "" {
+		providers = append(providers, NewCoinMarketCap(cmc))
+	}
+	return append(providers, NewCoinGecko(coinGecko))
+}
+
+type MarketConfig struct {
+	// Providers are tried in order, a provider is skipped while rate limited
+	Providers []Provider
+	// MinInterval is the minimum time between two requests, a newer cached quote is returned instead
+	MinInterval time.Duration
+	Logger      *zap.Logger
+}
+
+// Market fetch quotes with fallback between providers
+type Market struct {
+	providers   []Provider
+	minInterval time.Duration
+	logger      *zap.Logger
+	now         func() time.Time
+
+	mu           sync.Mutex
+	blockedUntil map[string]time.Time
+	last         *Quote
+	lastFetch    time.Time
+}
+
+func NewMarket(cfg MarketConfig) *Market {
+	return &Market{
+		providers:    cfg.Providers,
+		minInterval:  cfg.MinInterval,
+		logger:       cfg.Logger,
+		now:          time.Now,
+		blockedUntil: make(map[string]time.Time),
+	}
+}
+
+// Quote return quote of the first provider which succeed
+func (m *Market) Quote(ctx context.Context) (*Quote, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	if m.last != nil && now.Sub(m.lastFetch) < m.minInterval {
+		return m.last, nil
+	}
+	lastErr := ErrNoProvider
+	for _, p := range m.providers {
+		if until, ok := m.blockedUntil[p.Name()]; ok && now.Before(until) {
+			continue
+		}
+		quote, err := p.Quote(ctx)
+		if err != nil {
+			var rateLimitErr *RateLimitError
+			if errors.As(err, &rateLimitErr) {
+				m.blockedUntil[p.Name()] = now.Add(rateLimitErr.RetryAfter)
+			}
+			m.logger.Warn("Cannot get quote from provider, fallback to next one", zap.String("provider", p.Name()), zap.Error(err))
+			lastErr = err
+			continue
+		}
+		quote.Source = p.Name()
+		if quote.UpdatedAt.IsZero() {
+			quote.UpdatedAt = now
+		}
+		m.last, m.lastFetch = quote, now
+		return quote, nil
+	}
+	return nil, lastErr
+}
+
+// Run fetch a quote every interval and pass it to handle, until ctx is done
+func (m *Market) Run(ctx context.Context, interval time.Duration, handle func(ctx context.Context, quote *Quote) error) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		quote, err := m.Quote(ctx)
+		if err != nil {
+			m.logger.Warn("Cannot refresh market data", zap.Error(err))
+		} else if err := handle(ctx, quote); err != nil {
+			m.logger.Warn("Cannot handle market quote", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// retryAfter read Retry-After header in seconds
+func retryAfter(header http.Header) time.Duration {
+	seconds, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return defaultRetryAfter
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,134 @@
+// Package external
+package external
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	cmcResponseOK = `{"status":{"error_code":0,"credit_count":1},"data":{"5453":{"id":5453,"name":"KardiaChain","symbol":"KAI",
+"circulating_supply":4000000000.5,"total_supply":5000000000,"quote":{"USD":{"price":0.05,"volume_24h":1000,"percent_change_1h":1,
+"percent_change_24h":2,"percent_change_7d":3,"market_cap":200000000,"last_updated":"2021-05-01T10:00:00.000Z"}}}}}`
+	cmcResponseRateLimited = `{"status":{"error_code":1008,"error_message":"You've exceeded your API Key's HTTP request rate limit."}}`
+	coinGeckoResponseOK    = `[{"id":"kardiachain","symbol":"kai","name":"KardiaChain","current_price":0.06,"market_cap":240000000,
+"total_volume":2000,"circulating_supply":4000000000,"total_supply":5000000000,"price_change_percentage_1h_in_currency":-1,
+"price_change_percentage_24h_in_currency":-2,"price_change_percentage_7d_in_currency":-3,"last_updated":"2021-05-01T10:05:00.000Z"}]`
+)
+
+func newStandIn(t *testing.T, status int, body string, header map[string]string, hits *int32, check func(r *http.Request)) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		if check != nil {
+			check(r)
+		}
+		for k, v := range header {
+			w.Header().Set(k, v)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCoinMarketCap_Quote(t *testing.T) {
+	var hits int32
+	srv := newStandIn(t, http.StatusOK, cmcResponseOK, nil, &hits, func(r *http.Request) {
+		assert.Equal(t, "/v1/cryptocurrency/quotes/latest", r.URL.Path)
+		assert.Equal(t, "5453", r.URL.Query().Get("id"))
+		assert.Equal(t, "key", r.Header.Get("X-CMC_PRO_API_KEY"))
+	})
+	quote, err := NewCoinMarketCap(CoinMarketCapConfig{APIKey: "key", BaseURL: srv.URL}).Quote(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "KAI", quote.Symbol)
+	assert.Equal(t, 0.05, quote.Price)
+	assert.Equal(t, 3.0, quote.Change7d)
+	assert.Equal(t, int64(4000000000), quote.CirculatingSupply)
+	assert.Equal(t, time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC), quote.UpdatedAt)
+}
+
+func TestCoinMarketCap_RateLimit(t *testing.T) {
+	var hits int32
+	srv := newStandIn(t, http.StatusTooManyRequests, cmcResponseRateLimited, map[string]string{"Retry-After": "30"}, &hits, nil)
+	_, err := NewCoinMarketCap(CoinMarketCapConfig{BaseURL: srv.URL}).Quote(context.Background())
+	rateLimitErr, ok := err.(*RateLimitError)
+	require.True(t, ok)
+	assert.Equal(t, 30*time.Second, rateLimitErr.RetryAfter)
+}
+
+func TestCoinGecko_Quote(t *testing.T) {
+	var hits int32
+	srv := newStandIn(t, http.StatusOK, coinGeckoResponseOK, nil, &hits, func(r *http.Request) {
+		assert.Equal(t, "/api/v3/coins/markets", r.URL.Path)
+		assert.Equal(t, "kardiachain", r.URL.Query().Get("ids"))
+	})
+	quote, err := NewCoinGecko(CoinGeckoConfig{BaseURL: srv.URL}).Quote(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "KAI", quote.Symbol)
+	assert.Equal(t, 0.06, quote.Price)
+	assert.Equal(t, 2000.0, quote.Volume24h)
+	assert.Equal(t, -2.0, quote.Change24h)
+}
+
+func TestMarket_Fallback(t *testing.T) {
+	var cmcHits, geckoHits int32
+	cmc := newStandIn(t, http.StatusTooManyRequests, cmcResponseRateLimited, map[string]string{"Retry-After": "60"}, &cmcHits, nil)
+	gecko := newStandIn(t, http.StatusOK, coinGeckoResponseOK, nil, &geckoHits, nil)
+	now := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+	market := NewMarket(MarketConfig{
+		Providers: []Provider{
+			NewCoinMarketCap(CoinMarketCapConfig{BaseURL: cmc.URL}),
+			NewCoinGecko(CoinGeckoConfig{BaseURL: gecko.URL}),
+		},
+		MinInterval: 10 * time.Second,
+		Logger:      zap.NewNop(),
+	})
+	market.now = func() time.Time { return now }
+
+	quote, err := market.Quote(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "coingecko", quote.Source)
+	assert.Equal(t, int32(1), cmcHits)
+	assert.Equal(t, int32(1), geckoHits)
+
+	// cached quote is returned within min interval
+	now = now.Add(5 * time.Second)
+	_, err = market.Quote(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), geckoHits)
+
+	// rate limited provider is skipped until retry time
+	now = now.Add(20 * time.Second)
+	_, err = market.Quote(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), cmcHits)
+	assert.Equal(t, int32(2), geckoHits)
+
+	now = now.Add(time.Minute)
+	_, err = market.Quote(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), cmcHits)
+}
+
+func TestMarket_AllProvidersFail(t *testing.T) {
+	var hits int32
+	srv := newStandIn(t, http.StatusInternalServerError, "", nil, &hits, nil)
+	market := NewMarket(MarketConfig{
+		Providers: []Provider{NewCoinGecko(CoinGeckoConfig{BaseURL: srv.URL})},
+		Logger:    zap.NewNop(),
+	})
+	_, err := market.Quote(context.Background())
+	assert.Error(t, err)
+
+	_, err = NewMarket(MarketConfig{Logger: zap.NewNop()}).Quote(context.Background())
+	assert.Equal(t, ErrNoProvider, err)
+}
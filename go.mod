@@ -5,9 +5,11 @@ go 1.14
 require (
 	github.com/aws/aws-sdk-go v1.34.28
 	github.com/chai2010/webp v1.1.0
+	github.com/dgrijalva/jwt-go v3.2.0+incompatible
 	github.com/ethereum/go-ethereum v1.9.18 // indirect
 	github.com/go-redis/redis/v8 v8.2.3
 	github.com/google/go-cmp v0.5.6 // indirect
+	github.com/graphql-go/graphql v0.7.9
 	github.com/joho/godotenv v1.3.0
 	github.com/kardiachain/go-kaiclient v1.0.2 // indirect
 	github.com/kardiachain/go-kardia v1.2.3-0.20210525082104-2913103edf92
@@ -16,11 +18,17 @@ require (
 	github.com/labstack/gommon v0.3.0 // indirect
 	github.com/mattn/go-colorable v0.1.8 // indirect
 	github.com/panjf2000/ants/v2 v2.4.3
+	github.com/prometheus/client_golang v1.8.0
 	github.com/stretchr/testify v1.7.0
 	github.com/tidwall/pretty v1.0.1 // indirect
 	github.com/xdg/stringprep v1.0.1-0.20180714160509-73f8eece6fdc // indirect
 	go.mongodb.org/mongo-driver v1.4.4
+	go.opentelemetry.io/otel v0.11.0
+	go.opentelemetry.io/otel/exporters/otlp v0.11.0
+	go.opentelemetry.io/otel/sdk v0.11.0
 	go.uber.org/zap v1.16.0
+	golang.org/x/crypto v0.0.0-20201117144127-c1f2f97bffc9
 	golang.org/x/sys v0.0.0-20220520151302-bc2c85ada10a // indirect
 	gopkg.in/mgo.v2 v2.0.0-20180705113604-9856a29383ce
+	gopkg.in/yaml.v2 v2.3.0
 )
@@ -227,14 +227,18 @@ func (h *handler) reloadValidator(ctx context.Context, validatorSMCAddress strin
 	if v.StakedAmount == "0" {
 		if err := h.db.RemoveValidator(ctx, v.SmcAddress); err != nil {
 			lgr.Error("cannot remove validator", zap.Error(err))
+			return
 		}
-		return
 	} else {
 		if err := h.db.UpsertValidator(ctx, v); err != nil {
 			lgr.Error("cannot upsert validator", zap.Error(err))
 			return
 		}
 	}
+	event := types.NewStreamEvent(types.StreamValidators, v, v.SmcAddress, v.Address)
+	if err := h.cache.PublishStreamEvent(ctx, event); err != nil {
+		lgr.Warn("cannot publish validator event", zap.Error(err))
+	}
 }
 
 func (h *handler) reloadDelegator(ctx context.Context, validatorSMCAddress, delegatorAddress string) {
@@ -35,14 +35,21 @@ type ClientInterface interface {
 	LatestBlockNumber(ctx context.Context) (uint64, error)
 	BlockByHash(ctx context.Context, hash string) (*types.Block, error)
 	BlockByHeight(ctx context.Context, height uint64) (*types.Block, error)
+	BlockHeaderByNumber(ctx context.Context, number uint64) (*types.Header, error)
 	GetTransaction(ctx context.Context, hash string) (*types.Transaction, error)
 	GetTransactionReceipt(ctx context.Context, txHash string) (*types.Receipt, error)
+	PendingTransactions(ctx context.Context) ([]*types.Transaction, error)
 	GetBalance(ctx context.Context, account string) (string, error)
 	GetCode(ctx context.Context, account string) (common.Bytes, error)
+	GetStorageAt(ctx context.Context, account string, key string) (common.Bytes, error)
+	DetectProxy(ctx context.Context, address string) (*types.ProxyInfo, error)
+	PairReserves(ctx context.Context, pair string) (*types.PairReserves, error)
 	NodesInfo(ctx context.Context) ([]*types.NodeInfo, error)
+	NodesHealth(ctx context.Context) []*types.NodeHealth
 	Validator(ctx context.Context, address string) (*types.Validator, error)
 	Validators(ctx context.Context) ([]*types.Validator, error)
 	TraceTransaction(ctx context.Context, hash string) (*types.TxTraceResult, error)
+	TraceCalls(ctx context.Context, hash string) (*types.CallFrame, error)
 
 	// staking related methods
 	GetValidatorsByDelegator(ctx context.Context, delAddr common.Address) ([]*types.ValidatorsByDelegator, error)
@@ -64,6 +71,8 @@ type ClientInterface interface {
 	KardiaCall(ctx context.Context, args types.CallArgsJSON) (common.Bytes, error)
 	DecodeInputWithABI(to string, input string, smcABI *abi.ABI) (*types.FunctionCall, error)
 	UnpackLog(log *types.Log, a *abi.ABI) (*types.Log, error)
+	DecodeInputWithSignatures(to string, input string, sigs []*types.Signature) (*types.FunctionCall, error)
+	UnpackLogWithSignatures(log *types.Log, sigs []*types.Signature) (*types.Log, error)
 
 	// KRC-related methods
 	GetKRC20TokenInfo(ctx context.Context, a *abi.ABI, krcTokenAddr common.Address) (*types.KRCTokenInfo, error)
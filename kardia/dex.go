@@ -0,0 +1,60 @@
+// Package kardia
+package kardia
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+// Selectors of Uniswap V2 like pair methods
+var (
+	pairToken0Selector      = []byte{0x0d, 0xfe, 0x16, 0x81} // token0()
+	pairToken1Selector      = []byte{0xd2, 0x12, 0x20, 0xa7} // token1()
+	pairGetReservesSelector = []byte{0x09, 0x02, 0xf1, 0xac} // getReserves()
+)
+
+var ErrNotPair = errors.New("not a pair contract")
+
+// PairReader call contract methods at latest block
+type PairReader interface {
+	Call(ctx context.Context, address string, data []byte) ([]byte, error)
+}
+
+// PairReserves read tokens and reserves of an Uniswap V2 like pair
+func PairReserves(ctx context.Context, r PairReader, pair string) (*types.PairReserves, error) {
+	result, err := r.Call(ctx, pair, pairToken0Selector)
+	if err != nil {
+		return nil, err
+	}
+	token0 := slotAddress(result)
+	result, err = r.Call(ctx, pair, pairToken1Selector)
+	if err != nil {
+		return nil, err
+	}
+	token1 := slotAddress(result)
+	if token0 == "" || token1 == "" {
+		return nil, ErrNotPair
+	}
+	// getReserves return (uint112 reserve0, uint112 reserve1, uint32 blockTimestampLast)
+	result, err = r.Call(ctx, pair, pairGetReservesSelector)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) < 64 {
+		return nil, ErrNotPair
+	}
+	return &types.PairReserves{
+		Token0:   token0,
+		Token1:   token1,
+		Reserve0: new(big.Int).SetBytes(result[:32]),
+		Reserve1: new(big.Int).SetBytes(result[32:64]),
+	}, nil
+}
+
+// PairReserves read tokens and reserves of the pair
+func (ec *Client) PairReserves(ctx context.Context, pair string) (*types.PairReserves, error) {
+	return PairReserves(ctx, &proxyReader{ec: ec}, pair)
+}
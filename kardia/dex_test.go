@@ -0,0 +1,54 @@
+// Package kardia
+package kardia
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pairReader serve call results keyed by method selector
+type pairReader map[string][]byte
+
+func (r pairReader) Call(ctx context.Context, address string, data []byte) ([]byte, error) {
+	result, ok := r[common.Bytes(data).String()]
+	if !ok {
+		return nil, errors.New("execution reverted")
+	}
+	return result, nil
+}
+
+func TestPairReserves(t *testing.T) {
+	var (
+		pair   = "0x00000000000000000000000000000000000000D1"
+		token0 = common.HexToAddress("0x00000000000000000000000000000000000000A1").Hex()
+		token1 = common.HexToAddress("0x00000000000000000000000000000000000000B1").Hex()
+		ctx    = context.Background()
+	)
+	reserves := append(common.LeftPadBytes(big.NewInt(1000).Bytes(), 32), common.LeftPadBytes(big.NewInt(4000).Bytes(), 32)...)
+	reserves = append(reserves, common.LeftPadBytes(big.NewInt(1620000000).Bytes(), 32)...)
+	r := pairReader{
+		common.Bytes(pairToken0Selector).String():      word(token0),
+		common.Bytes(pairToken1Selector).String():      word(token1),
+		common.Bytes(pairGetReservesSelector).String(): reserves,
+	}
+	info, err := PairReserves(ctx, r, pair)
+	require.NoError(t, err)
+	assert.Equal(t, token0, info.Token0)
+	assert.Equal(t, token1, info.Token1)
+	assert.Equal(t, big.NewInt(1000), info.Reserve0)
+	assert.Equal(t, big.NewInt(4000), info.Reserve1)
+
+	delete(r, common.Bytes(pairToken1Selector).String())
+	_, err = PairReserves(ctx, r, pair)
+	assert.Error(t, err)
+
+	r[common.Bytes(pairToken1Selector).String()] = make([]byte, 32)
+	_, err = PairReserves(ctx, r, pair)
+	assert.Equal(t, ErrNotPair, err)
+}
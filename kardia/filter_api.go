@@ -32,7 +32,7 @@ func (ec *Client) GetLogs(ctx context.Context, query kai.FilterQuery) ([]*types.
 	if err != nil {
 		return nil, err
 	}
-	err = ec.defaultClient.c.CallContext(ctx, &result, "kai_getLogs", arg)
+	err = ec.defaultClient.CallContext(ctx, &result, "kai_getLogs", arg)
 	return result, err
 }
 
@@ -0,0 +1,108 @@
+// Package kardia
+package kardia
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+// Standard storage slots where proxies keep their implementation
+const (
+	// bytes32(uint256(keccak256("eip1967.proxy.implementation")) - 1)
+	EIP1967ImplementationSlot = "0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc"
+	// bytes32(uint256(keccak256("eip1967.proxy.beacon")) - 1)
+	EIP1967BeaconSlot = "0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50"
+	// keccak256("PROXIABLE")
+	EIP1822ProxiableSlot = "0xc5f16f0fcc639fa48a6947836d9850f504798523bf8c9a3a87d5876cf622bcf7"
+)
+
+// implementation() selector of beacon contracts
+var beaconImplementationSelector = []byte{0x5c, 0x60, 0xda, 0x1b}
+
+var ErrNotProxy = errors.New("not a proxy contract")
+
+// ProxyReader read contract storage and call contract methods at latest block
+type ProxyReader interface {
+	StorageAt(ctx context.Context, address, slot string) ([]byte, error)
+	Call(ctx context.Context, address string, data []byte) ([]byte, error)
+}
+
+// DetectProxy look for an implementation address in EIP-1967 (implementation then beacon)
+// and EIP-1822 slots, return ErrNotProxy when every slot is empty
+func DetectProxy(ctx context.Context, r ProxyReader, address string) (*types.ProxyInfo, error) {
+	value, err := r.StorageAt(ctx, address, EIP1967ImplementationSlot)
+	if err != nil {
+		return nil, err
+	}
+	if impl := slotAddress(value); impl != "" {
+		return &types.ProxyInfo{Type: types.ProxyTypeEIP1967, Implementation: impl}, nil
+	}
+
+	value, err = r.StorageAt(ctx, address, EIP1967BeaconSlot)
+	if err != nil {
+		return nil, err
+	}
+	if beacon := slotAddress(value); beacon != "" {
+		impl, err := BeaconImplementation(ctx, r, beacon)
+		if err != nil {
+			return nil, err
+		}
+		return &types.ProxyInfo{Type: types.ProxyTypeBeacon, Implementation: impl, Beacon: beacon}, nil
+	}
+
+	value, err = r.StorageAt(ctx, address, EIP1822ProxiableSlot)
+	if err != nil {
+		return nil, err
+	}
+	if impl := slotAddress(value); impl != "" {
+		return &types.ProxyInfo{Type: types.ProxyTypeEIP1822, Implementation: impl}, nil
+	}
+	return nil, ErrNotProxy
+}
+
+// BeaconImplementation call implementation() of a beacon contract
+func BeaconImplementation(ctx context.Context, r ProxyReader, beacon string) (string, error) {
+	result, err := r.Call(ctx, beacon, beaconImplementationSelector)
+	if err != nil {
+		return "", err
+	}
+	impl := slotAddress(result)
+	if impl == "" {
+		return "", ErrNotProxy
+	}
+	return impl, nil
+}
+
+// slotAddress read the address right aligned in a 32 bytes word, empty when word is zero
+func slotAddress(value []byte) string {
+	if len(value) < common.AddressLength {
+		return ""
+	}
+	addr := common.BytesToAddress(value[len(value)-common.AddressLength:])
+	if addr == (common.Address{}) {
+		return ""
+	}
+	return addr.Hex()
+}
+
+// proxyReader adapt Client to ProxyReader
+type proxyReader struct {
+	ec *Client
+}
+
+func (r *proxyReader) StorageAt(ctx context.Context, address, slot string) ([]byte, error) {
+	return r.ec.GetStorageAt(ctx, address, slot)
+}
+
+func (r *proxyReader) Call(ctx context.Context, address string, data []byte) ([]byte, error) {
+	return r.ec.KardiaCall(ctx, constructCallArgs(address, data))
+}
+
+// DetectProxy read standard proxy slots of the contract
+func (ec *Client) DetectProxy(ctx context.Context, address string) (*types.ProxyInfo, error) {
+	return DetectProxy(ctx, &proxyReader{ec: ec}, address)
+}
@@ -0,0 +1,90 @@
+// Package kardia
+package kardia
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+// slotsReader serve storage from a map keyed by "address/slot"
+type slotsReader struct {
+	slots map[string][]byte
+	calls map[string][]byte
+}
+
+func (r *slotsReader) StorageAt(ctx context.Context, address, slot string) ([]byte, error) {
+	return r.slots[address+"/"+slot], nil
+}
+
+func (r *slotsReader) Call(ctx context.Context, address string, data []byte) ([]byte, error) {
+	result, ok := r.calls[address]
+	if !ok {
+		return nil, errors.New("execution reverted")
+	}
+	return result, nil
+}
+
+func word(address string) []byte {
+	return common.LeftPadBytes(common.HexToAddress(address).Bytes(), 32)
+}
+
+func TestDetectProxy(t *testing.T) {
+	var (
+		proxy  = "0x00000000000000000000000000000000000000A1"
+		beacon = common.HexToAddress("0x00000000000000000000000000000000000000B1").Hex()
+		impl   = common.HexToAddress("0x00000000000000000000000000000000000000C1").Hex()
+		ctx    = context.Background()
+	)
+	type testCase struct {
+		name   string
+		reader *slotsReader
+		info   *types.ProxyInfo
+		err    error
+	}
+	cases := []testCase{
+		{
+			name:   "eip1967 implementation slot",
+			reader: &slotsReader{slots: map[string][]byte{proxy + "/" + EIP1967ImplementationSlot: word(impl)}},
+			info:   &types.ProxyInfo{Type: types.ProxyTypeEIP1967, Implementation: impl},
+		},
+		{
+			name: "beacon slot resolve implementation from beacon",
+			reader: &slotsReader{
+				slots: map[string][]byte{proxy + "/" + EIP1967BeaconSlot: word(beacon)},
+				calls: map[string][]byte{beacon: word(impl)},
+			},
+			info: &types.ProxyInfo{Type: types.ProxyTypeBeacon, Implementation: impl, Beacon: beacon},
+		},
+		{
+			name:   "eip1822 proxiable slot",
+			reader: &slotsReader{slots: map[string][]byte{proxy + "/" + EIP1822ProxiableSlot: word(impl)}},
+			info:   &types.ProxyInfo{Type: types.ProxyTypeEIP1822, Implementation: impl},
+		},
+		{
+			name:   "zero slots",
+			reader: &slotsReader{slots: map[string][]byte{proxy + "/" + EIP1967ImplementationSlot: make([]byte, 32)}},
+			err:    ErrNotProxy,
+		},
+		{
+			name: "beacon without implementation",
+			reader: &slotsReader{
+				slots: map[string][]byte{proxy + "/" + EIP1967BeaconSlot: word(beacon)},
+				calls: map[string][]byte{beacon: make([]byte, 32)},
+			},
+			err: ErrNotProxy,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			info, err := DetectProxy(ctx, c.reader, proxy)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.info, info)
+		})
+	}
+}
@@ -21,11 +21,15 @@ package kardia
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
 	"os"
 	"path"
+	"sync"
+	"time"
 
 	"github.com/kardiachain/go-kardia/lib/abi/bind"
+	"go.opentelemetry.io/otel/label"
 	"go.uber.org/zap"
 
 	"github.com/kardiachain/go-kardia"
@@ -34,6 +38,8 @@ import (
 	"github.com/kardiachain/go-kardia/rpc"
 
 	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/metrics"
+	"github.com/kardiachain/kardia-explorer-backend/tracing"
 	"github.com/kardiachain/kardia-explorer-backend/types"
 )
 
@@ -48,6 +54,20 @@ type RPCClient struct {
 	ip     string
 }
 
+// CallContext performs a JSON-RPC call, records its latency by method and traces it as a child of ctx
+func (c *RPCClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) (err error) {
+	ctx, span := tracing.Start(ctx, "rpc."+method,
+		label.String("rpc.system", "jsonrpc"),
+		label.String("rpc.method", method),
+		label.String("rpc.node", c.ip),
+	)
+	defer func(start time.Time) {
+		metrics.ObserveRPC(method, start, err)
+		tracing.End(ctx, span, err)
+	}(time.Now())
+	return c.c.CallContext(ctx, result, method, args...)
+}
+
 type SmcUtil struct {
 	Abi             *abi.ABI
 	ContractAddress common.Address
@@ -169,7 +189,7 @@ func (ec *Client) chooseClient() *RPCClient {
 // LatestBlockNumber gets latest block number
 func (ec *Client) LatestBlockNumber(ctx context.Context) (uint64, error) {
 	var result uint64
-	err := ec.defaultClient.c.CallContext(ctx, &result, "kai_blockNumber")
+	err := ec.defaultClient.CallContext(ctx, &result, "kai_blockNumber")
 	return result, err
 }
 
@@ -200,7 +220,7 @@ func (ec *Client) BlockHeaderByHash(ctx context.Context, hash string) (*types.He
 // GetTransaction returns the transaction with the given hash.
 func (ec *Client) GetTransaction(ctx context.Context, hash string) (*types.Transaction, error) {
 	var raw *types.Transaction
-	err := ec.chooseClient().c.CallContext(ctx, &raw, "tx_getTransaction", common.HexToHash(hash))
+	err := ec.chooseClient().CallContext(ctx, &raw, "tx_getTransaction", common.HexToHash(hash))
 	if err != nil {
 		return nil, err
 	} else if raw == nil {
@@ -213,7 +233,7 @@ func (ec *Client) GetTransaction(ctx context.Context, hash string) (*types.Trans
 // Note that the receipt is not available for pending transactions.
 func (ec *Client) GetTransactionReceipt(ctx context.Context, txHash string) (*types.Receipt, error) {
 	var r *types.Receipt
-	err := ec.chooseClient().c.CallContext(ctx, &r, "tx_getTransactionReceipt", common.HexToHash(txHash))
+	err := ec.chooseClient().CallContext(ctx, &r, "tx_getTransactionReceipt", common.HexToHash(txHash))
 	if err == nil {
 		if r == nil {
 			return nil, kardia.NotFound
@@ -222,6 +242,15 @@ func (ec *Client) GetTransactionReceipt(ctx context.Context, txHash string) (*ty
 	return r, err
 }
 
+// PendingTransactions returns transactions waiting in mempool of the node.
+func (ec *Client) PendingTransactions(ctx context.Context) ([]*types.Transaction, error) {
+	var txs []*types.Transaction
+	if err := ec.chooseClient().CallContext(ctx, &txs, "tx_pendingTransactions"); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
 // BalanceAt returns balance (in HYDRO) of the given account.
 // The block number can be nil, in which case the balance is taken from the latest known block.
 func (ec *Client) GetBalance(ctx context.Context, account string) (string, error) {
@@ -229,7 +258,7 @@ func (ec *Client) GetBalance(ctx context.Context, account string) (string, error
 		result string
 		err    error
 	)
-	err = ec.chooseClient().c.CallContext(ctx, &result, "account_balance", common.HexToAddress(account), "latest")
+	err = ec.chooseClient().CallContext(ctx, &result, "account_balance", common.HexToAddress(account), "latest")
 	return result, err
 }
 
@@ -237,7 +266,7 @@ func (ec *Client) GetBalance(ctx context.Context, account string) (string, error
 // The block number can be nil, in which case the value is taken from the latest known block.
 func (ec *Client) GetStorageAt(ctx context.Context, account string, key string) (common.Bytes, error) {
 	var result common.Bytes
-	err := ec.chooseClient().c.CallContext(ctx, &result, "account_getStorageAt", common.HexToAddress(account), key, "latest")
+	err := ec.chooseClient().CallContext(ctx, &result, "account_getStorageAt", common.HexToAddress(account), key, "latest")
 	return result, err
 }
 
@@ -245,14 +274,14 @@ func (ec *Client) GetStorageAt(ctx context.Context, account string, key string)
 // The block number can be nil, in which case the code is taken from the latest known block.
 func (ec *Client) GetCode(ctx context.Context, account string) (common.Bytes, error) {
 	var result common.Bytes
-	err := ec.chooseClient().c.CallContext(ctx, &result, "account_getCode", common.HexToAddress(account), "latest")
+	err := ec.chooseClient().CallContext(ctx, &result, "account_getCode", common.HexToAddress(account), "latest")
 	return result, err
 }
 
 // NonceAt returns the account nonce of the given account.
 func (ec *Client) NonceAt(ctx context.Context, account string) (uint64, error) {
 	var result uint64
-	err := ec.chooseClient().c.CallContext(ctx, &result, "account_nonce", common.HexToAddress(account))
+	err := ec.chooseClient().CallContext(ctx, &result, "account_nonce", common.HexToAddress(account))
 	return result, err
 }
 
@@ -261,12 +290,12 @@ func (ec *Client) NonceAt(ctx context.Context, account string) (uint64, error) {
 // If the transaction was a contract creation use the GetTransactionReceipt method to get the
 // contract address after the transaction has been mined.
 func (ec *Client) SendRawTransaction(ctx context.Context, tx string) error {
-	return ec.chooseClient().c.CallContext(ctx, nil, "tx_sendRawTransaction", tx)
+	return ec.chooseClient().CallContext(ctx, nil, "tx_sendRawTransaction", tx)
 }
 
 func (ec *Client) KardiaCall(ctx context.Context, args types.CallArgsJSON) (common.Bytes, error) {
 	var result common.Bytes
-	err := ec.chooseClient().c.CallContext(ctx, &result, "kai_kardiaCall", args, "latest")
+	err := ec.chooseClient().CallContext(ctx, &result, "kai_kardiaCall", args, "latest")
 	if err != nil {
 		return nil, err
 	}
@@ -287,11 +316,11 @@ func (ec *Client) NodesInfo(ctx context.Context) ([]*types.NodeInfo, error) {
 			peers []*types.RPCPeerInfo
 		)
 		// get current node info then get it's peers
-		err = client.c.CallContext(ctx, &node, "node_nodeInfo")
+		err = client.CallContext(ctx, &node, "node_nodeInfo")
 		if err != nil {
 			continue
 		}
-		err := client.c.CallContext(ctx, &peers, "node_peers")
+		err := client.CallContext(ctx, &peers, "node_peers")
 		if err != nil {
 			continue
 		}
@@ -330,9 +359,61 @@ func (ec *Client) NodesInfo(ctx context.Context) ([]*types.NodeInfo, error) {
 	return nodes, nil
 }
 
+// NodesHealth query latest block of every configured node concurrently, unreachable nodes are reported with their error
+func (ec *Client) NodesHealth(ctx context.Context) []*types.NodeHealth {
+	var (
+		clients []*RPCClient
+		names   []string
+	)
+	for i, client := range ec.clientList {
+		clients = append(clients, client)
+		names = append(names, fmt.Sprintf("public-%d", i))
+	}
+	for i, client := range ec.trustedClientList {
+		clients = append(clients, client)
+		names = append(names, fmt.Sprintf("trusted-%d", i))
+	}
+
+	result := make([]*types.NodeHealth, len(clients))
+	var wg sync.WaitGroup
+	for i, client := range clients {
+		wg.Add(1)
+		go func(i int, client *RPCClient) {
+			defer wg.Done()
+			var (
+				height uint64
+				start  = time.Now()
+			)
+			health := &types.NodeHealth{Name: names[i]}
+			err := client.CallContext(ctx, &height, "kai_blockNumber")
+			health.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+			if err != nil {
+				ec.lgr.Warn("RPC node is unreachable", zap.String("node", client.ip), zap.Error(err))
+				health.Error = err.Error()
+			} else {
+				health.Reachable = true
+				health.LatestBlock = height
+			}
+			result[i] = health
+		}(i, client)
+	}
+	wg.Wait()
+	return result
+}
+
 func (ec *Client) TraceTransaction(ctx context.Context, hash string) (*types.TxTraceResult, error) {
 	var result *types.TxTraceResult
-	err := ec.chooseClient().c.CallContext(ctx, &result, "debug_traceTransaction", common.HexToHash(hash))
+	err := ec.chooseClient().CallContext(ctx, &result, "debug_traceTransaction", common.HexToHash(hash))
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TraceCalls return call tree of the tx from callTracer
+func (ec *Client) TraceCalls(ctx context.Context, hash string) (*types.CallFrame, error) {
+	var result *types.CallFrame
+	err := ec.chooseClient().CallContext(ctx, &result, "debug_traceTransaction", common.HexToHash(hash), map[string]string{"tracer": "callTracer"})
 	if err != nil {
 		return nil, err
 	}
@@ -344,7 +425,7 @@ func (ec *Client) TraceTransaction(ctx context.Context, hash string) (*types.TxT
 //		proposersStakedAmount = big.NewInt(0)
 //		validators            []*types.Validator
 //	)
-//	err := ec.defaultClient.c.CallContext(ctx, &validators, "kai_validators", true)
+//	err := ec.defaultClient.CallContext(ctx, &validators, "kai_validators", true)
 //	if err != nil {
 //		return nil, err
 //	}
@@ -430,14 +511,14 @@ func (ec *Client) TraceTransaction(ctx context.Context, hash string) (*types.TxT
 //// NonceAt returns the account nonce of the given account.
 //func (ec *Client) NonceAt(ctx context.Context, account string) (uint64, error) {
 //	var result uint64
-//	err := ec.defaultClient.c.CallContext(ctx, &result, "account_nonce", common.HexToAddress(account))
+//	err := ec.defaultClient.CallContext(ctx, &result, "account_nonce", common.HexToAddress(account))
 //	return result, err
 //}
 //
 
 func (ec *Client) getBlock(ctx context.Context, method string, args ...interface{}) (*types.Block, error) {
 	var raw types.Block
-	err := ec.defaultClient.c.CallContext(ctx, &raw, method, args...)
+	err := ec.defaultClient.CallContext(ctx, &raw, method, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -446,7 +527,7 @@ func (ec *Client) getBlock(ctx context.Context, method string, args ...interface
 
 func (ec *Client) getBlockHeader(ctx context.Context, method string, args ...interface{}) (*types.Header, error) {
 	var raw types.Header
-	err := ec.defaultClient.c.CallContext(ctx, &raw, method, args...)
+	err := ec.defaultClient.CallContext(ctx, &raw, method, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,366 @@
+// Package kardia
+package kardia
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kardiachain/go-kardia/lib/abi"
+	"github.com/kardiachain/go-kardia/lib/crypto"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// tupleSuffixPattern match array dimensions following a tuple type
+var tupleSuffixPattern = regexp.MustCompile(`^(\[[0-9]*\])*$`)
+
+// abiArgument is an argument of JSON ABI entry
+type abiArgument struct {
+	Name       string        `json:"name"`
+	Type       string        `json:"type"`
+	Indexed    bool          `json:"indexed,omitempty"`
+	Components []abiArgument `json:"components,omitempty"`
+}
+
+type abiEntry struct {
+	Type   string        `json:"type"`
+	Name   string        `json:"name"`
+	Inputs []abiArgument `json:"inputs"`
+}
+
+// NewSignature build a signature from its text, e.g. transfer(address,uint256)
+func NewSignature(sigType, text, source string) (*types.Signature, error) {
+	if sigType != types.SignatureTypeFunction && sigType != types.SignatureTypeEvent {
+		return nil, ErrInvalidSignature
+	}
+	text = strings.Join(strings.Fields(text), "")
+	if _, err := parseSignatureText(text); err != nil {
+		return nil, err
+	}
+	hash := crypto.Keccak256([]byte(text))
+	if sigType == types.SignatureTypeFunction {
+		hash = hash[:4]
+	}
+	return &types.Signature{
+		Hash:      "0x" + hex.EncodeToString(hash),
+		Type:      sigType,
+		Text:      text,
+		Source:    source,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// ParseSignatures read signature file, each line is "function name(types)" or "event Name(types)".
+// Empty lines and lines start with # are skipped.
+func ParseSignatures(r io.Reader, source string) ([]*types.Signature, error) {
+	var sigs []*types.Signature
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, " ", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("line %d: %v", lineNum, ErrInvalidSignature)
+		}
+		sig, err := NewSignature(parts[0], parts[1], source)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNum, err)
+		}
+		sigs = append(sigs, sig)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return sigs, nil
+}
+
+// SignaturesFromABI return signatures of every function and event declared in a JSON ABI,
+// each keep its ABI entry so argument names and indexed flags are used when decoding
+func SignaturesFromABI(abiJSON []byte, source string) ([]*types.Signature, error) {
+	var entries []json.RawMessage
+	if err := json.Unmarshal(abiJSON, &entries); err != nil {
+		return nil, err
+	}
+	var sigs []*types.Signature
+	for _, raw := range entries {
+		var entry struct {
+			Type      string `json:"type"`
+			Anonymous bool   `json:"anonymous"`
+		}
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return nil, err
+		}
+		if entry.Type != types.SignatureTypeFunction && (entry.Type != types.SignatureTypeEvent || entry.Anonymous) {
+			continue
+		}
+		a, err := abi.JSON(bytes.NewReader(append(append([]byte("["), raw...), ']')))
+		if err != nil {
+			return nil, err
+		}
+		sig := &types.Signature{
+			Type:      entry.Type,
+			ABI:       string(raw),
+			Source:    source,
+			CreatedAt: time.Now(),
+		}
+		for _, m := range a.Methods {
+			sig.Hash, sig.Text = "0x"+hex.EncodeToString(m.ID), m.Sig
+		}
+		for _, e := range a.Events {
+			sig.Hash, sig.Text = e.ID.Hex(), e.Sig
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs, nil
+}
+
+// DecodeInputWithSignatures decode tx input with every signature match its selector. When several
+// signatures decode the input, the first one is used and the call is marked ambiguous.
+func (ec *Client) DecodeInputWithSignatures(to string, input string, sigs []*types.Signature) (*types.FunctionCall, error) {
+	var (
+		result     *types.FunctionCall
+		candidates []string
+	)
+	for _, sig := range sortSignatures(sigs) {
+		a, err := signatureABI(sig, 0)
+		if err != nil {
+			continue
+		}
+		decoded, err := ec.DecodeInputWithABI(to, input, a)
+		if err != nil || decoded == nil || !isCanonicalInput(a, input) {
+			continue
+		}
+		if result == nil {
+			result = decoded
+		}
+		candidates = append(candidates, sig.Text)
+	}
+	if result == nil {
+		return nil, ErrMethodNotFound
+	}
+	if len(candidates) > 1 {
+		result.Ambiguous = true
+		result.Candidates = candidates
+	}
+	return result, nil
+}
+
+// UnpackLogWithSignatures unpack log with every signature match its topic. Signatures without ABI
+// entry assume leading arguments are indexed, as many as log topics.
+func (ec *Client) UnpackLogWithSignatures(log *types.Log, sigs []*types.Signature) (*types.Log, error) {
+	if len(log.Topics) == 0 {
+		return nil, ErrInvalidSignature
+	}
+	var (
+		result     *types.Log
+		candidates []string
+	)
+	for _, sig := range sortSignatures(sigs) {
+		a, err := signatureABI(sig, len(log.Topics)-1)
+		if err != nil {
+			continue
+		}
+		logCopy := *log
+		unpacked, err := ec.UnpackLog(&logCopy, a)
+		if err != nil || !isCanonicalLog(a, log) {
+			continue
+		}
+		if result == nil {
+			result = unpacked
+		}
+		candidates = append(candidates, sig.Text)
+	}
+	if result == nil {
+		return nil, ErrInvalidSignature
+	}
+	if len(candidates) > 1 {
+		result.Ambiguous = true
+		result.Candidates = candidates
+	}
+	return result, nil
+}
+
+// sortSignatures put signatures from verified contracts first, then sort by text
+func sortSignatures(sigs []*types.Signature) []*types.Signature {
+	sorted := append([]*types.Signature{}, sigs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		iVerified, jVerified := sorted[i].Source == types.SignatureSourceVerified, sorted[j].Source == types.SignatureSourceVerified
+		if iVerified != jVerified {
+			return iVerified
+		}
+		return sorted[i].Text < sorted[j].Text
+	})
+	return sorted
+}
+
+// signatureABI build a single entry ABI from the signature. indexed is the number of indexed
+// arguments of an event, an ABI entry which does not declare as many is rejected.
+func signatureABI(sig *types.Signature, indexed int) (*abi.ABI, error) {
+	entryJSON := []byte(sig.ABI)
+	if sig.ABI == "" {
+		entry, err := parseSignatureText(sig.Text)
+		if err != nil {
+			return nil, err
+		}
+		entry.Type = sig.Type
+		if sig.Type == types.SignatureTypeEvent {
+			if indexed > len(entry.Inputs) {
+				return nil, ErrInvalidSignature
+			}
+			for i := 0; i < indexed; i++ {
+				entry.Inputs[i].Indexed = true
+			}
+		}
+		if entryJSON, err = json.Marshal(entry); err != nil {
+			return nil, err
+		}
+	}
+	a, err := abi.JSON(bytes.NewReader(append(append([]byte("["), entryJSON...), ']')))
+	if err != nil {
+		return nil, err
+	}
+	if sig.Type == types.SignatureTypeEvent {
+		for _, e := range a.Events {
+			count := 0
+			for _, arg := range e.Inputs {
+				if arg.Indexed {
+					count++
+				}
+			}
+			if count != indexed {
+				return nil, ErrInvalidSignature
+			}
+		}
+	}
+	return &a, nil
+}
+
+// isCanonicalInput check input arguments re-encode to the same bytes, so a selector collision
+// which happen to unpack is not reported as a candidate
+func isCanonicalInput(a *abi.ABI, input string) bool {
+	data, err := hex.DecodeString(strings.TrimPrefix(input, "0x"))
+	if err != nil || len(data) < 4 {
+		return false
+	}
+	for _, m := range a.Methods {
+		return isCanonical(m.Inputs, data[4:])
+	}
+	return false
+}
+
+func isCanonicalLog(a *abi.ABI, log *types.Log) bool {
+	data, err := hex.DecodeString(strings.TrimPrefix(log.Data, "0x"))
+	if err != nil {
+		return false
+	}
+	for _, e := range a.Events {
+		return isCanonical(e.Inputs.NonIndexed(), data)
+	}
+	return false
+}
+
+func isCanonical(args abi.Arguments, data []byte) bool {
+	values, err := args.UnpackValues(data)
+	if err != nil {
+		return false
+	}
+	packed, err := args.Pack(values...)
+	if err != nil {
+		// cannot re-encode, trust unpacking
+		return true
+	}
+	return bytes.Equal(packed, data)
+}
+
+// parseSignatureText parse name(type1,type2) into an ABI entry, tuples are written (type1,type2)
+func parseSignatureText(text string) (*abiEntry, error) {
+	open := strings.Index(text, "(")
+	if open <= 0 || !strings.HasSuffix(text, ")") {
+		return nil, ErrInvalidSignature
+	}
+	args, err := parseArgumentTypes(text[open+1 : len(text)-1])
+	if err != nil {
+		return nil, err
+	}
+	return &abiEntry{Name: text[:open], Inputs: args}, nil
+}
+
+func parseArgumentTypes(list string) ([]abiArgument, error) {
+	if list == "" {
+		return []abiArgument{}, nil
+	}
+	var (
+		args  []abiArgument
+		depth int
+		start int
+	)
+	for i := 0; i <= len(list); i++ {
+		if i < len(list) {
+			switch list[i] {
+			case '(':
+				depth++
+				continue
+			case ')':
+				depth--
+				if depth < 0 {
+					return nil, ErrInvalidSignature
+				}
+				continue
+			case ',':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		arg, err := parseArgumentType(list[start:i])
+		if err != nil {
+			return nil, err
+		}
+		arg.Name = fmt.Sprintf("arg%d", len(args))
+		args = append(args, arg)
+		start = i + 1
+	}
+	if depth != 0 {
+		return nil, ErrInvalidSignature
+	}
+	return args, nil
+}
+
+func parseArgumentType(t string) (abiArgument, error) {
+	if t == "" {
+		return abiArgument{}, ErrInvalidSignature
+	}
+	if !strings.HasPrefix(t, "(") {
+		if _, err := abi.NewType(t, "", nil); err != nil {
+			return abiArgument{}, ErrInvalidSignature
+		}
+		return abiArgument{Type: t}, nil
+	}
+	end := strings.LastIndex(t, ")")
+	if end < 1 || !tupleSuffixPattern.MatchString(t[end+1:]) {
+		return abiArgument{}, ErrInvalidSignature
+	}
+	components, err := parseArgumentTypes(t[1:end])
+	if err != nil {
+		return abiArgument{}, err
+	}
+	// suffix hold array dimensions of tuple, e.g. (address,bytes)[]
+	return abiArgument{Type: "tuple" + t[end+1:], Components: components}, nil
+}
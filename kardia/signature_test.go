@@ -0,0 +1,118 @@
+// Package kardia
+package kardia
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+func mustSignature(t *testing.T, sigType, text, source string) *types.Signature {
+	sig, err := NewSignature(sigType, text, source)
+	assert.Nil(t, err)
+	return sig
+}
+
+func TestNewSignature(t *testing.T) {
+	sig := mustSignature(t, types.SignatureTypeFunction, "transfer(address, uint256)", types.SignatureSourceBundled)
+	assert.Equal(t, "0xa9059cbb", sig.Hash)
+	assert.Equal(t, "transfer(address,uint256)", sig.Text)
+
+	sig = mustSignature(t, types.SignatureTypeEvent, "Transfer(address,address,uint256)", types.SignatureSourceBundled)
+	assert.Equal(t, "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", sig.Hash)
+
+	sig = mustSignature(t, types.SignatureTypeFunction, "aggregate((address,bytes)[])", types.SignatureSourceBundled)
+	assert.Equal(t, "0x252dba42", sig.Hash)
+
+	for _, invalid := range []string{"transfer", "transfer(address", "transfer(adress)", "(uint256)", "f(uint256,)",
+		"foo((address)", "foo((address,(uint256))", "foo((address)x)"} {
+		_, err := NewSignature(types.SignatureTypeFunction, invalid, types.SignatureSourceBundled)
+		assert.Equal(t, ErrInvalidSignature, err, invalid)
+	}
+}
+
+func TestParseSignatures(t *testing.T) {
+	f, err := os.Open("../abi/signatures.txt")
+	assert.Nil(t, err)
+	defer f.Close()
+	sigs, err := ParseSignatures(f, types.SignatureSourceBundled)
+	assert.Nil(t, err)
+	assert.True(t, len(sigs) > 100)
+
+	_, err = ParseSignatures(strings.NewReader("function transfer(address,uint256)\nmethod foo()"), types.SignatureSourceBundled)
+	assert.NotNil(t, err)
+}
+
+func TestSignaturesFromABI(t *testing.T) {
+	abiJSON := `[
+		{"type":"constructor","inputs":[]},
+		{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+		{"type":"event","name":"Transfer","anonymous":false,"inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+	]`
+	sigs, err := SignaturesFromABI([]byte(abiJSON), types.SignatureSourceVerified)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(sigs))
+	assert.Equal(t, "0xa9059cbb", sigs[0].Hash)
+	assert.Equal(t, "transfer(address,uint256)", sigs[0].Text)
+	assert.Equal(t, "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", sigs[1].Hash)
+	assert.True(t, strings.Contains(sigs[1].ABI, `"indexed":true`))
+}
+
+func TestDecodeWithSignatures(t *testing.T) {
+	ec := &Client{}
+	input := "0xa9059cbb" +
+		"000000000000000000000000000000000000000000000000000000000000dead" +
+		"000000000000000000000000000000000000000000000000000000000000002a"
+
+	call, err := ec.DecodeInputWithSignatures("", input, []*types.Signature{
+		mustSignature(t, types.SignatureTypeFunction, "transfer(address,uint256)", types.SignatureSourceBundled),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "transfer", call.MethodName)
+	assert.Equal(t, "42", call.Arguments["arg1"])
+	assert.False(t, call.Ambiguous)
+
+	// burn(uint256) and collate_propagate_storage(bytes16) share selector 0x42966c68,
+	// a word with trailing zeros is valid for both
+	burn := mustSignature(t, types.SignatureTypeFunction, "burn(uint256)", types.SignatureSourceBundled)
+	collision := mustSignature(t, types.SignatureTypeFunction, "collate_propagate_storage(bytes16)", types.SignatureSourceBundled)
+	assert.Equal(t, burn.Hash, collision.Hash)
+	call, err = ec.DecodeInputWithSignatures("", burn.Hash+"0000000000000000000000000000002a00000000000000000000000000000000", []*types.Signature{burn, collision})
+	assert.Nil(t, err)
+	assert.True(t, call.Ambiguous)
+	assert.Equal(t, "burn", call.MethodName)
+	assert.Equal(t, []string{"burn(uint256)", "collate_propagate_storage(bytes16)"}, call.Candidates)
+	// only burn(uint256) decode a word without trailing zeros
+	call, err = ec.DecodeInputWithSignatures("", burn.Hash+"000000000000000000000000000000000000000000000000000000000000002a", []*types.Signature{collision, burn})
+	assert.Nil(t, err)
+	assert.False(t, call.Ambiguous)
+	assert.Equal(t, "burn", call.MethodName)
+
+	// input which does not fit the arguments is not decoded
+	_, err = ec.DecodeInputWithSignatures("", input[:len(input)-64], []*types.Signature{
+		mustSignature(t, types.SignatureTypeFunction, "transfer(address,uint256)", types.SignatureSourceBundled),
+	})
+	assert.NotNil(t, err)
+
+	log := &types.Log{
+		Topics: []string{
+			"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+			"0x000000000000000000000000000000000000000000000000000000000000beef",
+			"0x000000000000000000000000000000000000000000000000000000000000dead",
+		},
+		Data: "0x000000000000000000000000000000000000000000000000000000000000002a",
+	}
+	unpacked, err := ec.UnpackLogWithSignatures(log, []*types.Signature{
+		mustSignature(t, types.SignatureTypeEvent, "Transfer(address,address,uint256)", types.SignatureSourceBundled),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "Transfer", unpacked.MethodName)
+	assert.Equal(t, "42", unpacked.Arguments["arg2"])
+	assert.Equal(t, "index_topic_1 address arg0, index_topic_2 address arg1, uint256 arg2", unpacked.ArgumentsName)
+	// original log is kept untouched
+	assert.Nil(t, log.Arguments)
+}
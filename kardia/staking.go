@@ -304,7 +304,7 @@ func GetParamsSMCAddress(stakingUtil *SmcUtil, client *RPCClient) (common.Addres
 		res common.Bytes
 		ctx = context.Background()
 	)
-	err = client.c.CallContext(ctx, &res, "kai_kardiaCall", constructCallArgs(stakingUtil.ContractAddress.Hex(), payload), "latest")
+	err = client.CallContext(ctx, &res, "kai_kardiaCall", constructCallArgs(stakingUtil.ContractAddress.Hex(), payload), "latest")
 	if err != nil {
 		return common.Address{}, err
 	}
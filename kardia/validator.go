@@ -31,7 +31,7 @@ import (
 
 func (ec *Client) Validator(ctx context.Context, address string) (*types.Validator, error) {
 	var validator *types.Validator
-	err := ec.defaultClient.c.CallContext(ctx, &validator, "kai_validator", address, true)
+	err := ec.defaultClient.CallContext(ctx, &validator, "kai_validator", address, true)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +54,7 @@ func (ec *Client) Validators(ctx context.Context) ([]*types.Validator, error) {
 		proposersStakedAmount = big.NewInt(0)
 		validators            []*types.Validator
 	)
-	err := ec.defaultClient.c.CallContext(ctx, &validators, "kai_validators", true)
+	err := ec.defaultClient.CallContext(ctx, &validators, "kai_validators", true)
 	if err != nil {
 		return nil, err
 	}
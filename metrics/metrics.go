@@ -54,6 +54,8 @@ func (p *Provider) Reset() {
 }
 
 func (p *Provider) RecordInsertBlockTime(duration time.Duration) {
+	observeImportStage(StageInsertBlock, duration)
+
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -61,6 +63,8 @@ func (p *Provider) RecordInsertBlockTime(duration time.Duration) {
 }
 
 func (p *Provider) RecordScrapingTime(duration time.Duration) {
+	observeImportStage(StageFetchBlock, duration)
+
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -68,6 +72,8 @@ func (p *Provider) RecordScrapingTime(duration time.Duration) {
 }
 
 func (p *Provider) RecordInsertTxsTime(duration time.Duration) {
+	observeImportStage(StageInsertTxs, duration)
+
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -75,6 +81,8 @@ func (p *Provider) RecordInsertTxsTime(duration time.Duration) {
 }
 
 func (p *Provider) RecordInsertActiveAddressTime(duration time.Duration) {
+	observeImportStage(StageInsertActiveAddress, duration)
+
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -82,6 +90,8 @@ func (p *Provider) RecordInsertActiveAddressTime(duration time.Duration) {
 }
 
 func (p *Provider) RecordUpsertBlockTime(duration time.Duration) {
+	observeImportStage(StageUpsertBlock, duration)
+
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
@@ -107,6 +117,7 @@ func (p *Provider) RecordReorgedBlock() {
 	defer p.mu.Unlock()
 
 	p.reorgedBlocks++
+	reorgedBlocks.Inc()
 }
 
 func (p *Provider) RecordInvalidBlock() {
@@ -114,4 +125,5 @@ func (p *Provider) RecordInvalidBlock() {
 	defer p.mu.Unlock()
 
 	p.invalidBlocks++
+	invalidBlocks.Inc()
 }
@@ -0,0 +1,198 @@
+/*
+ *  Copyright 2018 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+const (
+	namespace = "explorer"
+
+	// queueLengthsTimeout bound the time spent reading queue lengths during a scrape
+	queueLengthsTimeout = 3 * time.Second
+)
+
+// Import stages recorded by Provider
+const (
+	StageFetchBlock          = "fetch_block"
+	StageInsertBlock         = "insert_block"
+	StageUpsertBlock         = "upsert_block"
+	StageInsertTxs           = "insert_txs"
+	StageInsertActiveAddress = "insert_active_address"
+)
+
+var (
+	rpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "rpc",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of Kardia RPC calls by method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "status"})
+	mongoDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "mongo",
+		Name:      "operation_duration_seconds",
+		Help:      "Latency of Mongo operations by collection.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"collection", "operation"})
+	importStageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "import",
+		Name:      "stage_duration_seconds",
+		Help:      "Time spent in each stage of block import.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"stage"})
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of API handlers by route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "path", "status"})
+
+	latestBlock = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "latest_block",
+		Help:      "Latest block height of the network and of the indexer.",
+	}, []string{"source"})
+	chainHeadLag = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "chain_head_lag_blocks",
+		Help:      "Number of blocks the indexer is behind the network head.",
+	})
+	reorgedBlocks = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reorged_blocks_total",
+		Help:      "Number of orphaned blocks rolled back after a chain reorg.",
+	})
+	invalidBlocks = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "invalid_blocks_total",
+		Help:      "Number of imported blocks which failed verification.",
+	})
+
+	queues = &queueCollector{
+		desc: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "queue_length"),
+			"Length of Redis work queues.", []string{"queue"}, nil),
+	}
+)
+
+func init() {
+	prometheus.MustRegister(rpcDuration, mongoDuration, importStageDuration, httpDuration,
+		latestBlock, chainHeadLag, reorgedBlocks, invalidBlocks, queues)
+}
+
+// QueueLengthsFunc return current length of queues, keyed by queue name
+type QueueLengthsFunc func(ctx context.Context) map[string]int64
+
+// queueCollector read queue lengths at scrape time so values are never stale
+type queueCollector struct {
+	mu   sync.RWMutex
+	fn   QueueLengthsFunc
+	desc *prometheus.Desc
+}
+
+func (q *queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- q.desc
+}
+
+func (q *queueCollector) Collect(ch chan<- prometheus.Metric) {
+	q.mu.RLock()
+	fn := q.fn
+	q.mu.RUnlock()
+	if fn == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), queueLengthsTimeout)
+	defer cancel()
+	for queue, length := range fn(ctx) {
+		ch <- prometheus.MustNewConstMetric(q.desc, prometheus.GaugeValue, float64(length), queue)
+	}
+}
+
+// RegisterQueueLengths set the source of queue_length gauges, the latest registration wins
+func RegisterQueueLengths(fn QueueLengthsFunc) {
+	queues.mu.Lock()
+	defer queues.mu.Unlock()
+	queues.fn = fn
+}
+
+// ObserveRPC record latency of a RPC call started at start, meant to be deferred
+func ObserveRPC(method string, start time.Time, err error) {
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	rpcDuration.WithLabelValues(method, status).Observe(time.Since(start).Seconds())
+}
+
+// ObserveMongo record latency of a Mongo operation started at start, meant to be deferred
+func ObserveMongo(collection, operation string, start time.Time) {
+	mongoDuration.WithLabelValues(collection, operation).Observe(time.Since(start).Seconds())
+}
+
+// ObserveHTTP record latency of an API handler. path is the route pattern, not the requested URL,
+// to keep cardinality bounded.
+func ObserveHTTP(method, path string, status int, duration time.Duration) {
+	httpDuration.WithLabelValues(method, path, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+func observeImportStage(stage string, duration time.Duration) {
+	importStageDuration.WithLabelValues(stage).Observe(duration.Seconds())
+}
+
+// SetChainHead record network head and the highest block imported by the indexer
+func SetChainHead(network, indexed uint64) {
+	latestBlock.WithLabelValues("network").Set(float64(network))
+	latestBlock.WithLabelValues("indexer").Set(float64(indexed))
+	lag := float64(0)
+	if network > indexed {
+		lag = float64(network - indexed)
+	}
+	chainHeadLag.Set(lag)
+}
+
+// Handler serve metrics of the default registry in Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Serve expose /metrics on addr in background, do nothing when addr is empty
+func Serve(addr string, logger *zap.Logger) {
+	if addr == "" {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	go func() {
+		logger.Info("Serving metrics", zap.String("addr", addr))
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			logger.Error("cannot serve metrics", zap.Error(err))
+		}
+	}()
+}
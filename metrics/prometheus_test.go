@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	RegisterQueueLengths(func(ctx context.Context) map[string]int64 {
+		return map[string]int64{"error_blocks": 3, "unverified_blocks": 0}
+	})
+	defer RegisterQueueLengths(nil)
+
+	p := New()
+	p.RecordInsertBlockTime(20 * time.Millisecond)
+	p.RecordReorgedBlock()
+	p.RecordReorgedBlock()
+	p.RecordInvalidBlock()
+	SetChainHead(120, 100)
+	ObserveRPC("kai_blockNumber", time.Now(), nil)
+	ObserveMongo("Blocks", "find", time.Now())
+	ObserveHTTP("GET", "/api/v1/blocks/:block", 200, time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, 200, rec.Code)
+	body := rec.Body.String()
+
+	for _, line := range []string{
+		`explorer_queue_length{queue="error_blocks"} 3`,
+		`explorer_queue_length{queue="unverified_blocks"} 0`,
+		`explorer_reorged_blocks_total 2`,
+		`explorer_invalid_blocks_total 1`,
+		`explorer_chain_head_lag_blocks 20`,
+		`explorer_latest_block{source="network"} 120`,
+		`explorer_import_stage_duration_seconds_count{stage="insert_block"} 1`,
+		`explorer_rpc_request_duration_seconds_count{method="kai_blockNumber",status="ok"} 1`,
+		`explorer_mongo_operation_duration_seconds_count{collection="Blocks",operation="find"} 1`,
+		`explorer_http_request_duration_seconds_count{method="GET",path="/api/v1/blocks/:block",status="200"} 1`,
+	} {
+		assert.Contains(t, body, line)
+	}
+}
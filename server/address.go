@@ -6,11 +6,15 @@ import (
 	"time"
 
 	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/tracing"
 	"github.com/kardiachain/kardia-explorer-backend/types"
+	"go.opentelemetry.io/otel/label"
 	"go.uber.org/zap"
 )
 
-func (s *infoServer) ProcessActiveAddress(ctx context.Context, txs []*types.Transaction) error {
+func (s *infoServer) ProcessActiveAddress(ctx context.Context, txs []*types.Transaction) (err error) {
+	ctx, span := tracing.Start(ctx, "ProcessActiveAddress", label.Int("block.txs", len(txs)))
+	defer func() { tracing.End(ctx, span, err) }()
 	lgr := s.logger.With(zap.String("method", "ProcessActiveAddress"))
 	// update active addresses
 	startTime := time.Now()
@@ -27,6 +31,7 @@ func (s *infoServer) ProcessActiveAddress(ctx context.Context, txs []*types.Tran
 	}
 	lgr.Debug("UpdateAddressTime", zap.Duration("TotalTime", time.Since(updateAddressTime)))
 	endTime := time.Since(startTime)
+	s.metrics.RecordInsertActiveAddressTime(endTime)
 	s.logger.Info("Total time for update addresses", zap.Duration("TimeConsumed", endTime), zap.String("Avg", s.metrics.GetInsertActiveAddressTime()))
 	startTime = time.Now()
 	totalAddresses, err := s.dbClient.CountAddresses(ctx)
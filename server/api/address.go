@@ -3,6 +3,7 @@ package api
 
 import (
 	"context"
+	"sort"
 	"strconv"
 
 	kClient "github.com/kardiachain/go-kaiclient/kardia"
@@ -20,18 +21,30 @@ type IAddress interface {
 }
 
 func (s *Server) Addresses(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	pagination, page, limit := getPagingOption(c)
 	sortDirectionStr := c.QueryParam("sort")
 	sortDirection, err := strconv.Atoi(sortDirectionStr)
 	if err != nil || (sortDirection != 1 && sortDirection != -1) {
 		sortDirection = -1 // DESC
 	}
-	addrs, err := s.dbClient.GetListAddresses(ctx, sortDirection, pagination)
-	if err != nil {
-		return Invalid.Build(c)
+	var (
+		addrs []*types.Address
+		total uint64
+	)
+	if filter := labelFilter(c); filter != nil {
+		addrs, total, err = s.labeledAddressesByBalance(ctx, filter, sortDirection, pagination)
+		if err != nil {
+			return Invalid.Build(c)
+		}
+	} else {
+		addrs, err = s.dbClient.GetListAddresses(ctx, sortDirection, pagination)
+		if err != nil {
+			return Invalid.Build(c)
+		}
+		totalHolders, totalContracts := s.cacheClient.TotalHolders(ctx)
+		total = totalHolders + totalContracts
 	}
-	totalHolders, totalContracts := s.cacheClient.TotalHolders(ctx)
 	smcAddress := s.getValidatorsAddressAndRole(ctx)
 	var result Addresses
 	for _, addr := range addrs {
@@ -57,16 +70,63 @@ func (s *Server) Addresses(c echo.Context) error {
 		}
 		result = append(result, addrInfo)
 	}
+	labels := s.addressLabels(ctx, addressesOf(result)...)
+	for i := range result {
+		result[i].Labels = labels[result[i].Address]
+	}
 	return OK.SetData(PagingResponse{
 		Page:  page,
 		Limit: limit,
-		Total: totalHolders + totalContracts,
+		Total: total,
 		Data:  result,
 	}).Build(c)
 }
 
+// labeledAddressesByBalance return the page of addresses carrying a label matching filter, ranked by balance
+func (s *Server) labeledAddressesByBalance(ctx context.Context, filter *types.AddressLabelsFilter, sortDirection int, pagination *types.Pagination) ([]*types.Address, uint64, error) {
+	labeled, err := s.labeledAddresses(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(labeled) == 0 {
+		return nil, 0, nil
+	}
+	addrs, err := s.dbClient.AddressesByHashes(ctx, labeled)
+	if err != nil {
+		return nil, 0, err
+	}
+	sort.SliceStable(addrs, func(i, j int) bool {
+		if sortDirection == 1 {
+			return addrs[i].BalanceFloat < addrs[j].BalanceFloat
+		}
+		return addrs[i].BalanceFloat > addrs[j].BalanceFloat
+	})
+	for i := range addrs {
+		addrs[i].Rank = uint64(i + 1)
+	}
+	total := uint64(len(addrs))
+	if pagination != nil {
+		if pagination.Skip >= len(addrs) {
+			return nil, total, nil
+		}
+		addrs = addrs[pagination.Skip:]
+		if len(addrs) > pagination.Limit {
+			addrs = addrs[:pagination.Limit]
+		}
+	}
+	return addrs, total, nil
+}
+
+func addressesOf(addrs Addresses) []string {
+	addresses := make([]string, len(addrs))
+	for i := range addrs {
+		addresses[i] = addrs[i].Address
+	}
+	return addresses
+}
+
 func (s *Server) AddressInfo(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	// Convert to addr and get back string to avoid wrong checksum
 	address := common.HexToAddress(c.Param("address")).String()
 	smcAddress := s.getValidatorsAddressAndRole(ctx)
@@ -98,6 +158,7 @@ func (s *Server) AddressInfo(c echo.Context) error {
 			result.IsInValidatorsList = true
 			result.Role = smcAddress[result.Address].Role
 		}
+		result.Labels = s.addressLabels(ctx, result.Address)[result.Address]
 		return OK.SetData(result).Build(c)
 	}
 	s.logger.Warn("address not found in db, getting from RPC instead...", zap.Error(err))
@@ -117,11 +178,12 @@ func (s *Server) AddressInfo(c echo.Context) error {
 		result.Role = smcAddress[result.Address].Role
 		result.Name = smcAddress[result.Address].Name
 	}
+	result.Labels = s.addressLabels(ctx, result.Address)[result.Address]
 	return OK.SetData(result).Build(c)
 }
 
 func (s *Server) AddressTxs(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	var err error
 	address := c.Param("address")
 	pagination, page, limit := getPagingOption(c)
@@ -161,6 +223,7 @@ func (s *Server) AddressTxs(c echo.Context) error {
 		}
 		result = append(result, t)
 	}
+	s.labelTxs(ctx, result)
 
 	return OK.SetData(PagingResponse{
 		Page:  page,
@@ -172,7 +235,7 @@ func (s *Server) AddressTxs(c echo.Context) error {
 
 func (s *Server) AddressHolders(c echo.Context) error {
 	lgr := s.logger
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	var (
 		page, limit int
 		err         error
@@ -219,7 +282,7 @@ func (s *Server) AddressHolders(c echo.Context) error {
 
 func (s *Server) SearchAddressByName(c echo.Context) error {
 	var (
-		ctx     = context.Background()
+		ctx     = c.Request().Context()
 		name    = c.QueryParam("name")
 		addrMap = make(map[string]*SimpleKRCTokenInfo)
 	)
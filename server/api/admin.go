@@ -0,0 +1,471 @@
+// Package api
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/labstack/echo"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+const (
+	// ContextAdmin hold admin user of the request after admin auth middleware
+	ContextAdmin = "admin"
+
+	contextAudit = "audit"
+
+	minAdminPasswordLength = 10
+)
+
+var (
+	ErrAdminAuthDisabled  = errors.New("admin token secret is not configured")
+	ErrInvalidAdminToken  = errors.New("invalid admin token")
+	ErrInvalidAdminRole   = errors.New("invalid admin role")
+	ErrWeakAdminPassword  = errors.New("admin password is too short")
+	ErrAdminUserExisted   = errors.New("admin user existed")
+	ErrUpdateOwnAdminUser = errors.New("cannot change role or disable own admin user")
+)
+
+type IAdmin interface {
+	AdminLogin(c echo.Context) error
+	CreateAdminUser(c echo.Context) error
+	AdminUsers(c echo.Context) error
+	UpdateAdminUser(c echo.Context) error
+	AuditLogs(c echo.Context) error
+
+	// AdminAuth authenticate bearer token of an admin user
+	AdminAuth() echo.MiddlewareFunc
+	// Audit write every request changing data to audit logs
+	Audit() echo.MiddlewareFunc
+}
+
+func bindAdminAPIs(gr *echo.Group, srv RestServer) {
+	apis := []restDefinition{
+		{
+			method:      echo.POST,
+			path:        "/users",
+			fn:          srv.CreateAdminUser,
+			middlewares: []echo.MiddlewareFunc{requireRole(types.AdminRoleSuperAdmin)},
+		},
+		{
+			method:      echo.GET,
+			path:        "/users",
+			fn:          srv.AdminUsers,
+			middlewares: []echo.MiddlewareFunc{requireRole(types.AdminRoleSuperAdmin)},
+		},
+		{
+			method:      echo.PUT,
+			path:        "/users/:username",
+			fn:          srv.UpdateAdminUser,
+			middlewares: []echo.MiddlewareFunc{requireRole(types.AdminRoleSuperAdmin)},
+		},
+		{
+			method: echo.GET,
+			// Query params: ?page=0&limit=10&actor=&resource=&startTime=&endTime=
+			path:        "/audit-logs",
+			fn:          srv.AuditLogs,
+			middlewares: []echo.MiddlewareFunc{checkPagination(), requireRole(types.AdminRoleSuperAdmin)},
+		},
+	}
+	for _, api := range apis {
+		gr.Add(api.method, api.path, api.fn, api.middlewares...)
+	}
+}
+
+type adminClaims struct {
+	Role string `json:"role"`
+	jwt.StandardClaims
+}
+
+type adminLoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type adminToken struct {
+	Token     string    `json:"token"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type adminUserRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+	Disabled *bool  `json:"disabled"`
+}
+
+// AdminLogin exchange username and password for a signed token, valid for the configured TTL
+func (s *Server) AdminLogin(c echo.Context) error {
+	ctx := c.Request().Context()
+	if s.adminTokenSecret == "" {
+		return Unauthorized.Build(c)
+	}
+	var req adminLoginRequest
+	if err := c.Bind(&req); err != nil || req.Username == "" || req.Password == "" {
+		return Invalid.Build(c)
+	}
+	user, err := s.dbClient.AdminUser(ctx, req.Username)
+	if err != nil {
+		s.logger.Warn("Cannot get admin user", zap.Error(err))
+		return InternalServer.Build(c)
+	}
+	if user == nil || user.Disabled || bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) != nil {
+		s.logger.Warn("Admin login failed", zap.String("username", req.Username), zap.String("ip", c.RealIP()))
+		return Unauthorized.Build(c)
+	}
+	token, expiresAt, err := signAdminToken(s.adminTokenSecret, user, s.adminTokenTTL, time.Now())
+	if err != nil {
+		s.logger.Warn("Cannot sign admin token", zap.Error(err))
+		return InternalServer.Build(c)
+	}
+	return OK.SetData(&adminToken{Token: token, Role: user.Role, ExpiresAt: expiresAt}).Build(c)
+}
+
+func (s *Server) CreateAdminUser(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req adminUserRequest
+	if err := c.Bind(&req); err != nil || req.Username == "" {
+		return Invalid.Build(c)
+	}
+	if !types.AdminRoles[req.Role] {
+		resp := Invalid
+		return resp.SetData(ErrInvalidAdminRole.Error()).Build(c)
+	}
+	existed, err := s.dbClient.AdminUser(ctx, req.Username)
+	if err != nil {
+		return InternalServer.Build(c)
+	}
+	if existed != nil {
+		resp := Invalid
+		return resp.SetData(ErrAdminUserExisted.Error()).Build(c)
+	}
+	user, err := newAdminUser(req.Username, req.Password, req.Role, time.Now())
+	if err != nil {
+		resp := Invalid
+		return resp.SetData(err.Error()).Build(c)
+	}
+	if err := s.dbClient.InsertAdminUser(ctx, user); err != nil {
+		s.logger.Warn("Cannot insert admin user", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	setAudit(c, "admin:"+user.Username, nil, user)
+	return OK.SetData(user).Build(c)
+}
+
+func (s *Server) AdminUsers(c echo.Context) error {
+	ctx := c.Request().Context()
+	users, err := s.dbClient.AdminUsers(ctx)
+	if err != nil {
+		s.logger.Warn("Cannot get admin users from db", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	return OK.SetData(users).Build(c)
+}
+
+// UpdateAdminUser change password, role or disabled flag of an admin user
+func (s *Server) UpdateAdminUser(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req adminUserRequest
+	if err := c.Bind(&req); err != nil {
+		return Invalid.Build(c)
+	}
+	user, err := s.dbClient.AdminUser(ctx, c.Param("username"))
+	if err != nil || user == nil {
+		return Invalid.Build(c)
+	}
+	before := *user
+	if req.Role != "" {
+		if !types.AdminRoles[req.Role] {
+			resp := Invalid
+			return resp.SetData(ErrInvalidAdminRole.Error()).Build(c)
+		}
+		user.Role = req.Role
+	}
+	if req.Disabled != nil {
+		user.Disabled = *req.Disabled
+	}
+	// superadmin must not lock themselves out
+	if admin := requestAdmin(c); admin != nil && admin.Username == user.Username &&
+		(user.Role != before.Role || user.Disabled) {
+		resp := Invalid
+		return resp.SetData(ErrUpdateOwnAdminUser.Error()).Build(c)
+	}
+	if req.Password != "" {
+		if user.PasswordHash, err = hashAdminPassword(req.Password); err != nil {
+			resp := Invalid
+			return resp.SetData(err.Error()).Build(c)
+		}
+	}
+	user.UpdatedAt = time.Now()
+	if err := s.dbClient.UpdateAdminUser(ctx, user); err != nil {
+		s.logger.Warn("Cannot update admin user", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	setAudit(c, "admin:"+user.Username, &before, user)
+	return OK.SetData(user).Build(c)
+}
+
+func (s *Server) AuditLogs(c echo.Context) error {
+	ctx := c.Request().Context()
+	pagination, page, limit := getPagingOption(c)
+	filter := &types.AuditLogsFilter{
+		Pagination: pagination,
+		Actor:      c.QueryParam("actor"),
+		Resource:   c.QueryParam("resource"),
+	}
+	var err error
+	if v := c.QueryParam("startTime"); v != "" {
+		if filter.StartTime, err = time.Parse(time.RFC3339, v); err != nil {
+			return Invalid.Build(c)
+		}
+	}
+	if v := c.QueryParam("endTime"); v != "" {
+		if filter.EndTime, err = time.Parse(time.RFC3339, v); err != nil {
+			return Invalid.Build(c)
+		}
+	}
+	logs, total, err := s.dbClient.AuditLogs(ctx, filter)
+	if err != nil {
+		s.logger.Warn("Cannot get audit logs from db", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	return OK.SetData(PagingResponse{
+		Page:  page,
+		Limit: limit,
+		Total: total,
+		Data:  logs,
+	}).Build(c)
+}
+
+// EnsureSuperAdmin create a superadmin when username does not exist, used to bootstrap a fresh database
+func (s *Server) EnsureSuperAdmin(ctx context.Context, username, password string) error {
+	if username == "" {
+		return nil
+	}
+	user, err := s.dbClient.AdminUser(ctx, username)
+	if err != nil || user != nil {
+		return err
+	}
+	if user, err = newAdminUser(username, password, types.AdminRoleSuperAdmin, time.Now()); err != nil {
+		return err
+	}
+	return s.dbClient.InsertAdminUser(ctx, user)
+}
+
+func (s *Server) AdminAuth() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			lgr := s.logger.With(zap.String("path", c.Path()), zap.String("ip", c.RealIP()))
+			if s.adminTokenSecret == "" {
+				lgr.Warn("Reject admin request", zap.Error(ErrAdminAuthDisabled))
+				return Unauthorized.Build(c)
+			}
+			auth := c.Request().Header.Get(echo.HeaderAuthorization)
+			if !strings.HasPrefix(auth, "Bearer ") {
+				return Unauthorized.Build(c)
+			}
+			claims, err := parseAdminToken(s.adminTokenSecret, strings.TrimPrefix(auth, "Bearer "))
+			if err != nil {
+				lgr.Warn("Reject admin token", zap.Error(err))
+				return Unauthorized.Build(c)
+			}
+			// role and status are read from db, so disabling a user revoke its tokens right away
+			user, err := s.dbClient.AdminUser(c.Request().Context(), claims.Subject)
+			if err != nil {
+				lgr.Warn("Cannot get admin user", zap.Error(err))
+				return InternalServer.Build(c)
+			}
+			if user == nil || user.Disabled {
+				return Unauthorized.Build(c)
+			}
+			c.Set(ContextAdmin, user)
+			return next(c)
+		}
+	}
+}
+
+type auditState struct {
+	resource string
+	before   interface{}
+	after    interface{}
+}
+
+// setAudit attach resource and its state before and after the change to audit log of request
+func setAudit(c echo.Context, resource string, before, after interface{}) {
+	c.Set(contextAudit, &auditState{resource: resource, before: before, after: after})
+}
+
+func (s *Server) Audit() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			method := c.Request().Method
+			if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+				return next(c)
+			}
+			now := time.Now()
+			err := next(c)
+			log := &types.AuditLog{
+				Method: method,
+				Path:   c.Path(),
+				Query:  c.QueryString(),
+				Status: c.Response().Status,
+				IP:     c.RealIP(),
+				Time:   now,
+			}
+			if admin := requestAdmin(c); admin != nil {
+				log.Actor, log.Role = admin.Username, admin.Role
+			}
+			if names := c.ParamNames(); len(names) > 0 {
+				log.Params = make(map[string]string, len(names))
+				for i, name := range names {
+					log.Params[name] = c.ParamValues()[i]
+				}
+			}
+			if state, ok := c.Get(contextAudit).(*auditState); ok {
+				log.Resource = state.resource
+				log.Before, log.After = auditDocument(state.before), auditDocument(state.after)
+				log.Changes = auditChanges(log.Before, log.After)
+			}
+			if err := s.dbClient.InsertAuditLog(context.Background(), log); err != nil {
+				s.logger.Error("Cannot insert audit log", zap.Any("log", log), zap.Error(err))
+			}
+			return err
+		}
+	}
+}
+
+// requireRole allow admin users with one of roles, superadmin is always allowed
+func requireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			admin := requestAdmin(c)
+			if admin == nil {
+				return Unauthorized.Build(c)
+			}
+			if admin.Role == types.AdminRoleSuperAdmin {
+				return next(c)
+			}
+			for _, role := range roles {
+				if admin.Role == role {
+					return next(c)
+				}
+			}
+			return Forbidden.Build(c)
+		}
+	}
+}
+
+func requestAdmin(c echo.Context) *types.AdminUser {
+	admin, _ := c.Get(ContextAdmin).(*types.AdminUser)
+	return admin
+}
+
+func newAdminUser(username, password, role string, now time.Time) (*types.AdminUser, error) {
+	passwordHash, err := hashAdminPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	return &types.AdminUser{
+		Username:     username,
+		PasswordHash: passwordHash,
+		Role:         role,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+func hashAdminPassword(password string) (string, error) {
+	if len(password) < minAdminPasswordLength {
+		return "", ErrWeakAdminPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func signAdminToken(secret string, user *types.AdminUser, ttl time.Duration, now time.Time) (string, time.Time, error) {
+	expiresAt := now.Add(ttl)
+	claims := &adminClaims{
+		Role: user.Role,
+		StandardClaims: jwt.StandardClaims{
+			Subject:   user.Username,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		return "", expiresAt, err
+	}
+	return token, expiresAt, nil
+}
+
+func parseAdminToken(secret, token string) (*adminClaims, error) {
+	claims := &adminClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if t.Method != jwt.SigningMethodHS256 {
+			return nil, ErrInvalidAdminToken
+		}
+		return []byte(secret), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !parsed.Valid || claims.Subject == "" || claims.ExpiresAt == 0 {
+		return nil, ErrInvalidAdminToken
+	}
+	return claims, nil
+}
+
+// auditDocument convert a resource to its JSON fields, so hidden fields such as secrets are never logged
+func auditDocument(v interface{}) map[string]interface{} {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	doc := make(map[string]interface{})
+	if err := json.Unmarshal(data, &doc); err != nil {
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil
+		}
+		return map[string]interface{}{"value": value}
+	}
+	return doc
+}
+
+// auditChanges return top level fields differ between before and after, sorted by field
+func auditChanges(before, after map[string]interface{}) []*types.AuditChange {
+	var changes []*types.AuditChange
+	for field, value := range after {
+		if old, ok := before[field]; !ok || !reflect.DeepEqual(old, value) {
+			changes = append(changes, &types.AuditChange{Field: field, Before: old, After: value})
+		}
+	}
+	for field, old := range before {
+		if _, ok := after[field]; !ok {
+			changes = append(changes, &types.AuditChange{Field: field, Before: old})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes
+}
@@ -0,0 +1,147 @@
+// Package api
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+const testAdminSecret = "admin-secret"
+
+func adminBearer(t *testing.T, user *types.AdminUser, ttl time.Duration) string {
+	token, _, err := signAdminToken(testAdminSecret, user, ttl, time.Now())
+	require.NoError(t, err)
+	return "Bearer " + token
+}
+
+// serveAdmin route a request through admin middlewares to a handler which audit an address update
+func serveAdmin(f *fakeServer, method, target, authorization string, role string) *httptest.ResponseRecorder {
+	e := echo.New()
+	admin := e.Group("/api/v1/admin", f.AdminAuth(), f.Audit())
+	admin.Add(method, "/addresses/:address", func(c echo.Context) error {
+		setAudit(c, "address:"+c.Param("address"),
+			&types.Address{Address: c.Param("address"), Name: "old"},
+			&types.Address{Address: c.Param("address"), Name: "new"})
+		return OK.Build(c)
+	}, requireRole(role))
+	var header map[string]string
+	if authorization != "" {
+		header = map[string]string{echo.HeaderAuthorization: authorization}
+	}
+	return serveRequest(e, newRequest(method, target, nil, header))
+}
+
+func TestAdminToken(t *testing.T) {
+	user := &types.AdminUser{Username: "alice", Role: types.AdminRoleOperator}
+	now := time.Now()
+	token, expiresAt, err := signAdminToken(testAdminSecret, user, time.Hour, now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Hour), expiresAt)
+
+	claims, err := parseAdminToken(testAdminSecret, token)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", claims.Subject)
+	assert.Equal(t, types.AdminRoleOperator, claims.Role)
+
+	_, err = parseAdminToken("other-secret", token)
+	assert.Error(t, err)
+
+	expired, _, err := signAdminToken(testAdminSecret, user, time.Hour, now.Add(-2*time.Hour))
+	require.NoError(t, err)
+	_, err = parseAdminToken(testAdminSecret, expired)
+	assert.Error(t, err)
+}
+
+func TestAdminAuth_Roles(t *testing.T) {
+	editor := &types.AdminUser{Username: "editor", Role: types.AdminRoleContentEditor}
+	operator := &types.AdminUser{Username: "operator", Role: types.AdminRoleOperator}
+	superAdmin := &types.AdminUser{Username: "root", Role: types.AdminRoleSuperAdmin}
+	disabled := &types.AdminUser{Username: "gone", Role: types.AdminRoleSuperAdmin, Disabled: true}
+	f := newFakeServer()
+	f.db.adminUsers = []*types.AdminUser{editor, operator, superAdmin, disabled}
+	f.SetAdminAuth(testAdminSecret, time.Hour)
+	target := "/api/v1/admin/addresses/0xabc"
+
+	assert.Equal(t, http.StatusOK, serveAdmin(f, echo.PUT, target, adminBearer(t, editor, time.Hour), types.AdminRoleContentEditor).Code)
+	assert.Equal(t, http.StatusForbidden, serveAdmin(f, echo.PUT, target, adminBearer(t, operator, time.Hour), types.AdminRoleContentEditor).Code)
+	assert.Equal(t, http.StatusOK, serveAdmin(f, echo.PUT, target, adminBearer(t, superAdmin, time.Hour), types.AdminRoleContentEditor).Code)
+
+	assert.Equal(t, http.StatusUnauthorized, serveAdmin(f, echo.PUT, target, "", types.AdminRoleContentEditor).Code)
+	// the former shared secret is not accepted anymore
+	assert.Equal(t, http.StatusUnauthorized, serveAdmin(f, echo.PUT, target, testAdminSecret, types.AdminRoleContentEditor).Code)
+	assert.Equal(t, http.StatusUnauthorized, serveAdmin(f, echo.PUT, target, adminBearer(t, disabled, time.Hour), types.AdminRoleContentEditor).Code)
+	assert.Equal(t, http.StatusUnauthorized, serveAdmin(f, echo.PUT, target, adminBearer(t, editor, -time.Minute), types.AdminRoleContentEditor).Code)
+
+	// admin APIs are closed without secret
+	f.SetAdminAuth("", time.Hour)
+	assert.Equal(t, http.StatusUnauthorized, serveAdmin(f, echo.PUT, target, adminBearer(t, superAdmin, time.Hour), types.AdminRoleContentEditor).Code)
+}
+
+func TestAudit(t *testing.T) {
+	editor := &types.AdminUser{Username: "editor", Role: types.AdminRoleContentEditor}
+	operator := &types.AdminUser{Username: "operator", Role: types.AdminRoleOperator}
+	f := newFakeServer()
+	f.db.adminUsers = []*types.AdminUser{editor, operator}
+	f.SetAdminAuth(testAdminSecret, time.Hour)
+
+	rec := serveAdmin(f, echo.PUT, "/api/v1/admin/addresses/0xabc?force=true", adminBearer(t, editor, time.Hour), types.AdminRoleContentEditor)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, f.db.auditLogs, 1)
+	log := f.db.auditLogs[0]
+	assert.Equal(t, "editor", log.Actor)
+	assert.Equal(t, types.AdminRoleContentEditor, log.Role)
+	assert.Equal(t, "/api/v1/admin/addresses/:address", log.Path)
+	assert.Equal(t, map[string]string{"address": "0xabc"}, log.Params)
+	assert.Equal(t, "force=true", log.Query)
+	assert.Equal(t, http.StatusOK, log.Status)
+	assert.Equal(t, "address:0xabc", log.Resource)
+	require.Len(t, log.Changes, 1)
+	assert.Equal(t, &types.AuditChange{Field: "name", Before: "old", After: "new"}, log.Changes[0])
+
+	// rejected attempts are logged too, reads are not
+	serveAdmin(f, echo.PUT, "/api/v1/admin/addresses/0xabc", adminBearer(t, operator, time.Hour), types.AdminRoleContentEditor)
+	require.Len(t, f.db.auditLogs, 2)
+	assert.Equal(t, http.StatusForbidden, f.db.auditLogs[1].Status)
+	assert.Empty(t, f.db.auditLogs[1].Resource)
+	serveAdmin(f, echo.GET, "/api/v1/admin/addresses/0xabc", adminBearer(t, editor, time.Hour), types.AdminRoleContentEditor)
+	assert.Len(t, f.db.auditLogs, 2)
+}
+
+func TestAuditChanges(t *testing.T) {
+	before := auditDocument(&types.APIKey{ID: "k1", Name: "old", KeyHash: "hash", Tier: types.RateLimitTierFree})
+	after := auditDocument(&types.APIKey{ID: "k1", Name: "new", KeyHash: "hash", Tier: types.RateLimitTierPro, Disabled: true})
+	// hidden fields are never written
+	assert.NotContains(t, before, "keyHash")
+
+	changes := auditChanges(before, after)
+	require.Len(t, changes, 3)
+	assert.Equal(t, "disabled", changes[0].Field)
+	assert.Equal(t, "name", changes[1].Field)
+	assert.Equal(t, "tier", changes[2].Field)
+	assert.Equal(t, types.RateLimitTierFree, changes[2].Before)
+
+	var missing *types.APIKey
+	assert.Nil(t, auditDocument(missing))
+	changes = auditChanges(nil, after)
+	assert.Len(t, changes, len(after))
+	changes = auditChanges(before, nil)
+	assert.Nil(t, changes[0].After)
+}
+
+func TestHashAdminPassword(t *testing.T) {
+	_, err := hashAdminPassword("short")
+	assert.Equal(t, ErrWeakAdminPassword, err)
+
+	user, err := newAdminUser("root", "correct horse battery", types.AdminRoleSuperAdmin, time.Now())
+	require.NoError(t, err)
+	assert.NotEqual(t, "correct horse battery", user.PasswordHash)
+	assert.Empty(t, auditDocument(user)["passwordHash"])
+}
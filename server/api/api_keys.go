@@ -0,0 +1,328 @@
+// Package api
+package api
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+const (
+	HeaderAPIKey = "X-API-Key"
+	// QueryAPIKey is accepted too, Etherscan clients send their key in query
+	QueryAPIKey = "apikey"
+
+	// ContextAPIKey hold key of the request after rate limit middleware
+	ContextAPIKey = "apiKey"
+
+	maxUsageDays = 31
+)
+
+var (
+	ErrInvalidAPIKey      = errors.New("invalid or disabled api key")
+	ErrInvalidAPIKeyTier  = errors.New("invalid api key tier")
+	ErrInvalidUsageParams = errors.New("invalid usage range")
+)
+
+type IAPIKey interface {
+	CreateAPIKey(c echo.Context) error
+	APIKeys(c echo.Context) error
+	UpdateAPIKey(c echo.Context) error
+	APIKeyUsage(c echo.Context) error
+	Usage(c echo.Context) error
+
+	// RateLimit limit requests per key by its tier, and requests without key per IP by anonymous tier
+	RateLimit(anonymous *types.RateLimitTier) echo.MiddlewareFunc
+}
+
+func bindAPIKeyAPIs(gr *echo.Group, srv RestServer) {
+	apis := []restDefinition{
+		{
+			method: echo.GET,
+			// Query params: same as /admin/api-keys/:id/usage, usage of key sent with request
+			path:        "/usage",
+			fn:          srv.Usage,
+			middlewares: nil,
+		},
+	}
+	for _, api := range apis {
+		gr.Add(api.method, api.path, api.fn, api.middlewares...)
+	}
+}
+
+type apiKeyRequest struct {
+	Name     string `json:"name"`
+	Owner    string `json:"owner"`
+	Tier     string `json:"tier"`
+	Disabled *bool  `json:"disabled"`
+}
+
+type createdAPIKey struct {
+	*types.APIKey
+	// Key is only returned at creation
+	Key string `json:"key"`
+}
+
+// CreateAPIKey issue a new key, free tier by default
+func (s *Server) CreateAPIKey(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req apiKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return Invalid.Build(c)
+	}
+	if req.Tier == "" {
+		req.Tier = types.RateLimitTierFree
+	}
+	if _, ok := types.RateLimitTiers[req.Tier]; !ok {
+		resp := Invalid
+		return resp.SetData(ErrInvalidAPIKeyTier.Error()).Build(c)
+	}
+	id, key, err := types.NewAPIKey()
+	if err != nil {
+		return InternalServer.Build(c)
+	}
+	now := time.Now()
+	apiKey := &types.APIKey{
+		ID:        id,
+		Name:      req.Name,
+		Owner:     req.Owner,
+		Prefix:    key[:types.APIKeyPrefixLength],
+		KeyHash:   types.HashAPIKey(key),
+		Tier:      req.Tier,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.dbClient.InsertAPIKey(ctx, apiKey); err != nil {
+		s.logger.Warn("Cannot insert api key", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	// drop unknown key marker cached for this hash, if any
+	_ = s.cacheClient.DeleteAPIKey(ctx, apiKey.KeyHash)
+	setAudit(c, "apiKey:"+apiKey.ID, nil, apiKey)
+	return OK.SetData(&createdAPIKey{APIKey: apiKey, Key: key}).Build(c)
+}
+
+func (s *Server) APIKeys(c echo.Context) error {
+	ctx := c.Request().Context()
+	pagination, page, limit := getPagingOption(c)
+	keys, total, err := s.dbClient.APIKeys(ctx, pagination)
+	if err != nil {
+		s.logger.Warn("Cannot get api keys from db", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	return OK.SetData(PagingResponse{
+		Page:  page,
+		Limit: limit,
+		Total: total,
+		Data:  keys,
+	}).Build(c)
+}
+
+// UpdateAPIKey change name, owner, tier or disabled flag of a key
+func (s *Server) UpdateAPIKey(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req apiKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return Invalid.Build(c)
+	}
+	apiKey, err := s.dbClient.APIKeyByID(ctx, c.Param("id"))
+	if err != nil || apiKey == nil {
+		return Invalid.Build(c)
+	}
+	before := *apiKey
+	if req.Tier != "" {
+		if _, ok := types.RateLimitTiers[req.Tier]; !ok {
+			resp := Invalid
+			return resp.SetData(ErrInvalidAPIKeyTier.Error()).Build(c)
+		}
+		apiKey.Tier = req.Tier
+	}
+	if req.Name != "" {
+		apiKey.Name = req.Name
+	}
+	if req.Owner != "" {
+		apiKey.Owner = req.Owner
+	}
+	if req.Disabled != nil {
+		apiKey.Disabled = *req.Disabled
+	}
+	apiKey.UpdatedAt = time.Now()
+	if err := s.dbClient.UpdateAPIKey(ctx, apiKey); err != nil {
+		s.logger.Warn("Cannot update api key", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	if err := s.cacheClient.DeleteAPIKey(ctx, apiKey.KeyHash); err != nil {
+		s.logger.Warn("Cannot invalidate cached api key", zap.Error(err))
+	}
+	setAudit(c, "apiKey:"+apiKey.ID, &before, apiKey)
+	return OK.SetData(apiKey).Build(c)
+}
+
+// APIKeyUsage return requests per endpoint and day of a key, id "anonymous" return usage of requests without key
+func (s *Server) APIKeyUsage(c echo.Context) error {
+	return s.usage(c, c.Param("id"))
+}
+
+// Usage return usage of the key sent with request
+func (s *Server) Usage(c echo.Context) error {
+	apiKey, ok := c.Get(ContextAPIKey).(*types.APIKey)
+	if !ok || apiKey == nil {
+		ctx := c.Request().Context()
+		var err error
+		if apiKey, err = s.requestAPIKey(ctx, c); err != nil || apiKey == nil {
+			return Unauthorized.Build(c)
+		}
+	}
+	return s.usage(c, apiKey.ID)
+}
+
+func (s *Server) usage(c echo.Context, subject string) error {
+	ctx := c.Request().Context()
+	from, to, err := parseUsageRange(c, time.Now())
+	if err != nil {
+		return Invalid.Build(c)
+	}
+	usage := make([]*types.EndpointUsage, 0)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		dayUsage, err := s.cacheClient.Usage(ctx, subject, day)
+		if err != nil {
+			s.logger.Warn("Cannot get usage from cache", zap.String("subject", subject), zap.Error(err))
+			return Invalid.Build(c)
+		}
+		usage = append(usage, dayUsage...)
+	}
+	return OK.SetData(usage).Build(c)
+}
+
+// parseUsageRange read from and to days, at most maxUsageDays days
+func parseUsageRange(c echo.Context, now time.Time) (time.Time, time.Time, error) {
+	to := types.RollupStart(types.RollupIntervalDay, now)
+	from := to
+	var err error
+	if v := c.QueryParam("from"); v != "" {
+		if from, err = time.Parse(chartDateLayout, v); err != nil {
+			return from, to, ErrInvalidUsageParams
+		}
+	}
+	if v := c.QueryParam("to"); v != "" {
+		if to, err = time.Parse(chartDateLayout, v); err != nil {
+			return from, to, ErrInvalidUsageParams
+		}
+	} else if from.After(to) {
+		return from, to, ErrInvalidUsageParams
+	}
+	if to.Before(from) || to.Sub(from) >= maxUsageDays*24*time.Hour {
+		return from, to, ErrInvalidUsageParams
+	}
+	return from, to, nil
+}
+
+// requestAPIKey return key sent with request, nil when no key is sent
+func (s *Server) requestAPIKey(ctx context.Context, c echo.Context) (*types.APIKey, error) {
+	key := c.Request().Header.Get(HeaderAPIKey)
+	if key == "" {
+		key = c.QueryParam(QueryAPIKey)
+	}
+	if key == "" {
+		return nil, nil
+	}
+	keyHash := types.HashAPIKey(key)
+	apiKey, cached, err := s.cacheClient.APIKey(ctx, keyHash)
+	if err != nil {
+		s.logger.Warn("Cannot get api key from cache", zap.Error(err))
+	}
+	if !cached {
+		if apiKey, err = s.dbClient.APIKeyByHash(ctx, keyHash); err != nil {
+			return nil, err
+		}
+		if err := s.cacheClient.SetAPIKey(ctx, keyHash, apiKey); err != nil {
+			s.logger.Warn("Cannot cache api key", zap.Error(err))
+		}
+	}
+	if apiKey == nil || apiKey.Disabled {
+		return nil, ErrInvalidAPIKey
+	}
+	return apiKey, nil
+}
+
+func (s *Server) RateLimit(anonymous *types.RateLimitTier) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			// load balancer probes must not be throttled nor spend a quota
+			if strings.HasPrefix(c.Path(), "/api/v1/health/") {
+				return next(c)
+			}
+			ctx := c.Request().Context()
+			apiKey, err := s.requestAPIKey(ctx, c)
+			if err == ErrInvalidAPIKey {
+				return Unauthorized.Build(c)
+			}
+			if err != nil {
+				// database errors must not block traffic, request is limited as anonymous
+				s.logger.Warn("Cannot get api key", zap.Error(err))
+			}
+			subject, bucket, tier := types.RateLimitTierAnonymous, "ip:"+c.RealIP(), anonymous
+			if apiKey != nil {
+				subject, bucket = apiKey.ID, "key:"+apiKey.ID
+				if tier = types.RateLimitTiers[apiKey.Tier]; tier == nil {
+					tier = types.RateLimitTiers[types.RateLimitTierFree]
+				}
+				c.Set(ContextAPIKey, apiKey)
+			}
+
+			now := time.Now()
+			header := c.Response().Header()
+			// unlimited tiers are only metered
+			if tier.RequestsPerMinute > 0 {
+				result, err := s.cacheClient.RateLimit(ctx, bucket, tier, now)
+				if err != nil {
+					// limiter is unavailable, let request pass rather than failing every replica
+					s.logger.Warn("Cannot check rate limit", zap.Error(err))
+					return next(c)
+				}
+				header.Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+				header.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+				if !result.Allowed {
+					header.Set("Retry-After", strconv.Itoa(retryAfterSeconds(result.RetryAfter)))
+					return TooManyRequest.Build(c)
+				}
+			}
+
+			total, err := s.cacheClient.IncrUsage(ctx, subject, usageEndpoint(c), now)
+			if err != nil {
+				s.logger.Warn("Cannot count api usage", zap.Error(err))
+			} else if tier.DailyQuota > 0 && total > tier.DailyQuota {
+				nextDay := types.RollupStart(types.RollupIntervalDay, now).AddDate(0, 0, 1)
+				header.Set("Retry-After", strconv.Itoa(retryAfterSeconds(nextDay.Sub(now))))
+				return TooManyRequest.Build(c)
+			}
+			return next(c)
+		}
+	}
+}
+
+// usageEndpoint name endpoint of request in usage counters, requests no route match share one
+// endpoint so scanned URLs don't create a counter each
+func usageEndpoint(c echo.Context) string {
+	switch reflect.ValueOf(c.Handler()).Pointer() {
+	case reflect.ValueOf(echo.NotFoundHandler).Pointer(), reflect.ValueOf(echo.MethodNotAllowedHandler).Pointer():
+		return unmatchedPath
+	}
+	return c.Path()
+}
+
+func retryAfterSeconds(d time.Duration) int {
+	seconds := int((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
@@ -0,0 +1,138 @@
+// Package api
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+// serveRateLimited route a request from 10.0.0.1 through the rate limiter
+func serveRateLimited(f *fakeServer, anonymous *types.RateLimitTier, header map[string]string, target string) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.Use(f.RateLimit(anonymous))
+	e.GET("/api/v1/blocks", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	req := newRequest(http.MethodGet, target, nil, header)
+	req.Header.Set(echo.HeaderXRealIP, "10.0.0.1")
+	return serveRequest(e, req)
+}
+
+func TestRateLimit_Anonymous(t *testing.T) {
+	f := newFakeServer()
+	f.authorizationSecret = "secret"
+	anonymous := &types.RateLimitTier{Name: types.RateLimitTierAnonymous, RequestsPerMinute: 60, Burst: 2}
+
+	for i := 0; i < 2; i++ {
+		rec := serveRateLimited(f, anonymous, nil, "/api/v1/blocks")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "60", rec.Header().Get("X-RateLimit-Limit"))
+	}
+	rec := serveRateLimited(f, anonymous, nil, "/api/v1/blocks")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+	assert.Equal(t, "ip:10.0.0.1", f.cache.buckets[0])
+	assert.Equal(t, int64(2), f.cache.usage[types.RateLimitTierAnonymous])
+
+	// authorization secret does not bypass the limit
+	rec = serveRateLimited(f, anonymous, map[string]string{"Authorization": "secret"}, "/api/v1/blocks")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+}
+
+func TestRateLimit_APIKey(t *testing.T) {
+	const rawKey = "kai_0123456789abcdef"
+	key := &types.APIKey{ID: "k1", KeyHash: types.HashAPIKey(rawKey), Tier: types.RateLimitTierStandard}
+	disabled := &types.APIKey{ID: "k2", KeyHash: types.HashAPIKey("kai_disabled"), Disabled: true}
+	f := newFakeServer()
+	f.db.apiKeys = []*types.APIKey{key, disabled}
+	anonymous := &types.RateLimitTier{Name: types.RateLimitTierAnonymous, RequestsPerMinute: 60}
+
+	rec := serveRateLimited(f, anonymous, map[string]string{HeaderAPIKey: rawKey}, "/api/v1/blocks")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "600", rec.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "key:k1", f.cache.buckets[0])
+	assert.Equal(t, int64(1), f.cache.usage["k1"])
+	// key is cached after the first lookup
+	assert.Equal(t, key, f.cache.apiKeys[key.KeyHash])
+
+	rec = serveRateLimited(f, anonymous, nil, "/api/v1/blocks?apikey="+rawKey)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, int64(2), f.cache.usage["k1"])
+
+	rec = serveRateLimited(f, anonymous, map[string]string{HeaderAPIKey: "kai_unknown"}, "/api/v1/blocks")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = serveRateLimited(f, anonymous, map[string]string{HeaderAPIKey: "kai_disabled"}, "/api/v1/blocks")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRateLimit_InternalKey(t *testing.T) {
+	const rawKey = "kai_internal0123456"
+	key := &types.APIKey{ID: "frontend", KeyHash: types.HashAPIKey(rawKey), Tier: types.RateLimitTierInternal}
+	f := newFakeServer()
+	f.db.apiKeys = []*types.APIKey{key}
+	anonymous := &types.RateLimitTier{Name: types.RateLimitTierAnonymous, RequestsPerMinute: 60, Burst: 1, DailyQuota: 1}
+
+	for i := 0; i < 5; i++ {
+		rec := serveRateLimited(f, anonymous, map[string]string{HeaderAPIKey: rawKey}, "/api/v1/blocks")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("X-RateLimit-Limit"))
+	}
+	// internal keys never reach the limiter but their usage is still metered
+	assert.Empty(t, f.cache.buckets)
+	assert.Equal(t, int64(5), f.cache.usage["frontend"])
+}
+
+func TestRateLimit_DailyQuota(t *testing.T) {
+	f := newFakeServer()
+	anonymous := &types.RateLimitTier{Name: types.RateLimitTierAnonymous, RequestsPerMinute: 60, Burst: 10, DailyQuota: 1}
+
+	assert.Equal(t, http.StatusOK, serveRateLimited(f, anonymous, nil, "/api/v1/blocks").Code)
+	rec := serveRateLimited(f, anonymous, nil, "/api/v1/blocks")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	// limiter errors let requests pass
+	f.cache.err = errors.New("connection refused")
+	assert.Equal(t, http.StatusOK, serveRateLimited(f, anonymous, nil, "/api/v1/blocks").Code)
+}
+
+func TestRateLimit_UsageEndpoint(t *testing.T) {
+	f := newFakeServer()
+	anonymous := &types.RateLimitTier{Name: types.RateLimitTierAnonymous, RequestsPerMinute: 60, Burst: 10}
+
+	serveRateLimited(f, anonymous, nil, "/api/v1/blocks?page=2")
+	serveRateLimited(f, anonymous, nil, "/wp-login.php")
+	serveRateLimited(f, anonymous, nil, "/.env")
+	assert.Equal(t, []string{"/api/v1/blocks", unmatchedPath, unmatchedPath}, f.cache.endpoints)
+}
+
+func TestParseUsageRange(t *testing.T) {
+	now := time.Date(2021, 5, 20, 10, 0, 0, 0, time.UTC)
+	parse := func(query string) (time.Time, time.Time, error) {
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/usage?"+query, nil), httptest.NewRecorder())
+		return parseUsageRange(c, now)
+	}
+	from, to, err := parse("")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 5, 20, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, from, to)
+
+	from, to, err = parse("from=2021-05-01&to=2021-05-31")
+	assert.NoError(t, err)
+	assert.Equal(t, 30*24*time.Hour, to.Sub(from))
+
+	_, _, err = parse("from=2021-05-01&to=2021-06-01")
+	assert.Equal(t, ErrInvalidUsageParams, err)
+	_, _, err = parse("from=2021-05-21")
+	assert.Equal(t, ErrInvalidUsageParams, err)
+	_, _, err = parse("from=May")
+	assert.Equal(t, ErrInvalidUsageParams, err)
+}
@@ -0,0 +1,132 @@
+// Package api
+package api
+
+import (
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/labstack/echo"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+type IBalance interface {
+	AddressBalanceAt(c echo.Context) error
+	AddressBalanceHistory(c echo.Context) error
+}
+
+func bindBalanceAPIs(gr *echo.Group, srv RestServer) {
+	apis := []restDefinition{
+		{
+			method: echo.GET,
+			// Query params: ?blockHeight=100 or ?timestamp=1620000000, latest block by default
+			path:        "/addresses/:address/balance",
+			fn:          srv.AddressBalanceAt,
+			middlewares: nil,
+		},
+		{
+			method: echo.GET,
+			// Query params: ?startTime=1620000000&endTime=1630000000&page=0&limit=10
+			path:        "/addresses/:address/balance/history",
+			fn:          srv.AddressBalanceHistory,
+			middlewares: []echo.MiddlewareFunc{checkPagination()},
+		},
+	}
+	for _, api := range apis {
+		gr.Add(api.method, api.path, api.fn, api.middlewares...)
+	}
+}
+
+// AddressBalanceAt return KAI balance of address after a block, computed from balance ledger
+func (s *Server) AddressBalanceAt(c echo.Context) error {
+	ctx := c.Request().Context()
+	address := c.Param("address")
+	if !common.IsHexAddress(address) {
+		return Invalid.Build(c)
+	}
+	address = common.HexToAddress(address).Hex()
+
+	var (
+		blockHeight uint64
+		err         error
+	)
+	switch {
+	case c.QueryParam("blockHeight") != "":
+		blockHeight, err = strconv.ParseUint(c.QueryParam("blockHeight"), 10, 64)
+	case c.QueryParam("timestamp") != "":
+		var timestamp int64
+		if timestamp, err = strconv.ParseInt(c.QueryParam("timestamp"), 10, 64); err == nil {
+			blockHeight, err = s.dbClient.BlockHeightByTime(ctx, time.Unix(timestamp, 0), true)
+		}
+	default:
+		blockHeight = s.cacheClient.LatestBlockHeight(ctx)
+	}
+	if err != nil {
+		return Invalid.Build(c)
+	}
+
+	balance, err := s.dbClient.BalanceAt(ctx, address, blockHeight)
+	if err != nil {
+		s.logger.Warn("Cannot get balance from ledger", zap.Error(err), zap.String("address", address))
+		return Invalid.Build(c)
+	}
+	return OK.SetData(AddressBalance{
+		Address:     address,
+		BlockHeight: blockHeight,
+		Balance:     balance.String(),
+	}).Build(c)
+}
+
+// AddressBalanceHistory return balance change of address per block in time range, with balance
+// after each block, oldest first
+func (s *Server) AddressBalanceHistory(c echo.Context) error {
+	ctx := c.Request().Context()
+	address := c.Param("address")
+	if !common.IsHexAddress(address) {
+		return Invalid.Build(c)
+	}
+	pagination, page, limit := getPagingOption(c)
+	filter := &types.BalanceChangesFilter{
+		Pagination: pagination,
+		Address:    common.HexToAddress(address).Hex(),
+	}
+	for param, t := range map[string]*time.Time{"startTime": &filter.StartTime, "endTime": &filter.EndTime} {
+		if c.QueryParam(param) == "" {
+			continue
+		}
+		timestamp, err := strconv.ParseInt(c.QueryParam(param), 10, 64)
+		if err != nil {
+			return Invalid.Build(c)
+		}
+		*t = time.Unix(timestamp, 0)
+	}
+
+	changes, total, err := s.dbClient.BalanceChanges(ctx, filter)
+	if err != nil {
+		s.logger.Warn("Cannot get balance changes from ledger", zap.Error(err), zap.String("address", filter.Address))
+		return Invalid.Build(c)
+	}
+	if len(changes) > 0 {
+		// opening balance is balance before the first block of page
+		balance := big.NewInt(0)
+		if changes[0].BlockHeight > 0 {
+			if balance, err = s.dbClient.BalanceAt(ctx, filter.Address, changes[0].BlockHeight-1); err != nil {
+				return Invalid.Build(c)
+			}
+		}
+		for _, change := range changes {
+			delta, _ := new(big.Int).SetString(change.Change, 10)
+			balance.Add(balance, delta)
+			change.Balance = balance.String()
+		}
+	}
+	return OK.SetData(PagingResponse{
+		Page:  page,
+		Limit: limit,
+		Total: total,
+		Data:  changes,
+	}).Build(c)
+}
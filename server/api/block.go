@@ -2,7 +2,6 @@
 package api
 
 import (
-	"context"
 	"strconv"
 	"strings"
 
@@ -55,7 +54,7 @@ func bindBlocksAPIs(gr *echo.Group, srv RestServer) {
 }
 
 func (s *Server) Blocks(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	var (
 		err    error
 		blocks []*types.Block
@@ -113,7 +112,7 @@ func (s *Server) Blocks(c echo.Context) error {
 }
 
 func (s *Server) Block(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	blockHashOrHeightStr := c.Param("block")
 	var (
 		block *types.Block
@@ -183,7 +182,7 @@ func (s *Server) Block(c echo.Context) error {
 }
 
 func (s *Server) BlockTxs(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	block := c.Param("block")
 	pagination, page, limit := getPagingOption(c)
 
@@ -279,6 +278,7 @@ func (s *Server) BlockTxs(c echo.Context) error {
 		}
 		result = append(result, t)
 	}
+	s.labelTxs(ctx, result)
 
 	return OK.SetData(PagingResponse{
 		Page:  page,
@@ -289,7 +289,7 @@ func (s *Server) BlockTxs(c echo.Context) error {
 }
 
 func (s *Server) BlocksByProposer(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	pagination, page, limit := getPagingOption(c)
 	blocks, total, err := s.dbClient.BlocksByProposer(ctx, c.Param("address"), pagination)
 	if err != nil {
@@ -0,0 +1,145 @@
+// Package api
+package api
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+const (
+	chartDateLayout = "2006-01-02"
+	maxChartDays    = 366
+	maxChartHours   = 24 * 31
+)
+
+var ErrInvalidChartParams = errors.New("invalid interval or range")
+
+// chartMetrics map metric of /charts/:metric to its rollup value
+var chartMetrics = map[string]func(r *types.Rollup) interface{}{
+	"txs":                  func(r *types.Rollup) interface{} { return r.Txs },
+	"active-addresses":     func(r *types.Rollup) interface{} { return r.ActiveAddresses },
+	"new-addresses":        func(r *types.Rollup) interface{} { return r.NewAddresses },
+	"contract-deployments": func(r *types.Rollup) interface{} { return r.ContractDeployments },
+	"gas-used":             func(r *types.Rollup) interface{} { return r.GasUsed },
+	"gas-price":            func(r *types.Rollup) interface{} { return r.AvgGasPrice },
+	"fees":                 func(r *types.Rollup) interface{} { return r.Fees },
+	"block-time":           func(r *types.Rollup) interface{} { return r.AvgBlockTime },
+	"blocks":               func(r *types.Rollup) interface{} { return r.Blocks },
+}
+
+type IChart interface {
+	Charts(c echo.Context) error
+	Chart(c echo.Context) error
+}
+
+func bindChartAPIs(gr *echo.Group, srv RestServer) {
+	apis := []restDefinition{
+		{
+			method: echo.GET,
+			// Query params: ?interval=day&from=2021-05-01&to=2021-05-31, from and to also accept unix time
+			path:        "/charts",
+			fn:          srv.Charts,
+			middlewares: nil,
+		},
+		{
+			method: echo.GET,
+			// Query params: same as /charts
+			path:        "/charts/:metric",
+			fn:          srv.Chart,
+			middlewares: nil,
+		},
+	}
+	for _, api := range apis {
+		gr.Add(api.method, api.path, api.fn, api.middlewares...)
+	}
+}
+
+// Charts return every metric of rollups in range
+func (s *Server) Charts(c echo.Context) error {
+	rollups, err := s.chartRollups(c)
+	if err != nil {
+		return Invalid.Build(c)
+	}
+	return OK.SetData(rollups).Build(c)
+}
+
+// Chart return series of a metric in range
+func (s *Server) Chart(c echo.Context) error {
+	value, ok := chartMetrics[c.Param("metric")]
+	if !ok {
+		return Invalid.Build(c)
+	}
+	rollups, err := s.chartRollups(c)
+	if err != nil {
+		return Invalid.Build(c)
+	}
+	points := make([]*ChartPoint, len(rollups))
+	for i, r := range rollups {
+		points[i] = &ChartPoint{Time: r.Time, Value: value(r)}
+	}
+	return OK.SetData(points).Build(c)
+}
+
+func (s *Server) chartRollups(c echo.Context) ([]*types.Rollup, error) {
+	ctx := c.Request().Context()
+	filter, err := parseRollupsFilter(c, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	rollups, err := s.dbClient.Rollups(ctx, filter)
+	if err != nil {
+		s.logger.Warn("Cannot get rollups from db", zap.Error(err))
+		return nil, err
+	}
+	return rollups, nil
+}
+
+// parseRollupsFilter read interval and range of charts, default range is last 30 days or last 24 hours
+func parseRollupsFilter(c echo.Context, now time.Time) (*types.RollupsFilter, error) {
+	filter := &types.RollupsFilter{Interval: c.QueryParam("interval")}
+	maxRange := maxChartDays * 24 * time.Hour
+	switch filter.Interval {
+	case "", types.RollupIntervalDay:
+		filter.Interval = types.RollupIntervalDay
+		filter.StartTime = now.AddDate(0, 0, -30)
+	case types.RollupIntervalHour:
+		filter.StartTime = now.Add(-24 * time.Hour)
+		maxRange = maxChartHours * time.Hour
+	default:
+		return nil, ErrInvalidChartParams
+	}
+	filter.EndTime = now
+	var err error
+	if from := c.QueryParam("from"); from != "" {
+		if filter.StartTime, err = parseChartTime(from); err != nil {
+			return nil, err
+		}
+	}
+	if to := c.QueryParam("to"); to != "" {
+		if filter.EndTime, err = parseChartTime(to); err != nil {
+			return nil, err
+		}
+	}
+	filter.StartTime = types.RollupStart(filter.Interval, filter.StartTime)
+	if filter.EndTime.Before(filter.StartTime) || filter.EndTime.Sub(filter.StartTime) > maxRange {
+		return nil, ErrInvalidChartParams
+	}
+	return filter, nil
+}
+
+func parseChartTime(value string) (time.Time, error) {
+	if t, err := time.Parse(chartDateLayout, value); err == nil {
+		return t, nil
+	}
+	timestamp, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrInvalidChartParams
+	}
+	return time.Unix(timestamp, 0).UTC(), nil
+}
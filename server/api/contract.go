@@ -3,11 +3,20 @@ package api
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"sort"
+	"strings"
 
 	kClient "github.com/kardiachain/go-kaiclient/kardia"
 	"github.com/kardiachain/go-kardia/lib/common"
 
 	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/driver/solc"
 	"github.com/kardiachain/kardia-explorer-backend/types"
 	"github.com/labstack/echo"
 	"go.uber.org/zap"
@@ -19,6 +28,9 @@ type IContract interface {
 	UpdateContract(c echo.Context) error
 	UpdateSMCABIByType(c echo.Context) error
 	ContractEvents(c echo.Context) error
+	VerifyContract(c echo.Context) error
+	ContractUpgrades(c echo.Context) error
+	DetectProxy(c echo.Context) error
 }
 
 func bindContractAPIs(gr *echo.Group, srv RestServer) {
@@ -32,6 +44,13 @@ func bindContractAPIs(gr *echo.Group, srv RestServer) {
 			fn:          srv.Contracts,
 			middlewares: nil,
 		},
+		{
+			method: echo.POST,
+			// Body: {contractAddress, contractName, sourceCode, compilerVersion, optimization, runs, constructorArguments}
+			path:        "/contracts/verify",
+			fn:          srv.VerifyContract,
+			middlewares: nil,
+		},
 		{
 			method:      echo.GET,
 			path:        "/contracts/:contractAddress",
@@ -45,6 +64,19 @@ func bindContractAPIs(gr *echo.Group, srv RestServer) {
 			fn:          srv.ContractEvents,
 			middlewares: nil,
 		},
+		{
+			method: echo.GET,
+			// Query params: ?page=0&limit=10
+			path:        "/contracts/:contractAddress/upgrades",
+			fn:          srv.ContractUpgrades,
+			middlewares: nil,
+		},
+		{
+			method:      echo.POST,
+			path:        "/contracts/:contractAddress/proxy",
+			fn:          srv.DetectProxy,
+			middlewares: nil,
+		},
 	}
 	for _, api := range apis {
 		gr.Add(api.method, api.path, api.fn, api.middlewares...)
@@ -52,7 +84,7 @@ func bindContractAPIs(gr *echo.Group, srv RestServer) {
 }
 
 func (s *Server) ContractEvents(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	var (
 		page, limit  int
 		err          error
@@ -80,16 +112,17 @@ func (s *Server) ContractEvents(c echo.Context) error {
 		}
 		for i := range receipt.Logs {
 			smcABI, err := s.getSMCAbi(ctx, &receipt.Logs[i])
-			if err != nil {
-				events = append(events, &receipt.Logs[i])
-				continue
+			if err == nil {
+				if unpackedLog, err := s.kaiClient.UnpackLog(&receipt.Logs[i], smcABI); err == nil {
+					events = append(events, unpackedLog)
+					continue
+				}
 			}
-			unpackedLog, err := s.kaiClient.UnpackLog(&receipt.Logs[i], smcABI)
-			if err != nil {
-				events = append(events, &receipt.Logs[i])
+			if unpackedLog := s.unpackLogWithSignatures(ctx, &receipt.Logs[i]); unpackedLog != nil {
+				events = append(events, unpackedLog)
 				continue
 			}
-			events = append(events, unpackedLog)
+			events = append(events, &receipt.Logs[i])
 		}
 	}
 	result := make([]*InternalTransaction, len(events))
@@ -112,7 +145,7 @@ func (s *Server) ContractEvents(c echo.Context) error {
 }
 
 func (s *Server) Contracts(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	pagination, page, limit := getPagingOption(c)
 	// default filter
 	filterCrit := &types.ContractsFilter{
@@ -168,7 +201,7 @@ func (s *Server) Contracts(c echo.Context) error {
 }
 
 func (s *Server) Contract(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	contractAddress := c.Param("contractAddress")
 
 	smc, addrInfo, err := s.dbClient.Contract(ctx, contractAddress)
@@ -192,6 +225,19 @@ func (s *Server) Contract(c echo.Context) error {
 		TotalSupply:   smc.TotalSupply,
 		Status:        int64(smc.Status),
 		CreatedAt:     smc.CreatedAt,
+
+		ProxyType:      smc.ProxyType,
+		Implementation: smc.Implementation,
+		Beacon:         smc.Beacon,
+	}
+	if smc.IsVerified {
+		result.SourceFiles = contractSourceFiles(smc)
+		result.CompilerVersion = smc.CompilerVersion
+		result.IsOptimize = smc.IsOptimize
+		result.OptimizeRuns = smc.OptimizeRuns
+		result.EVMVersion = smc.EVMVersion
+		result.Remappings = smc.Remappings
+		result.Libraries = smc.Libraries
 	}
 
 	if smc.Type == cfg.SMCTypeKRC20 {
@@ -208,23 +254,272 @@ func (s *Server) Contract(c echo.Context) error {
 	return OK.SetData(result).Build(c)
 }
 
-func (s *Server) VerifyContract(ctx context.Context) error {
-	lgr := s.logger.With(zap.String("method", "VerifyContract"))
-	lgr.Debug("Start verify contract data")
-	type verifyRequest struct {
-		SMCAddress string `json:"smc_address"`
-		Code       string `json:"code"`
+const (
+	codeFormatSingleFile   = "solidity-single-file"
+	codeFormatMultiFile    = "solidity-multi-file"
+	codeFormatStandardJSON = "solidity-standard-json-input"
+)
+
+type verifyContractRequest struct {
+	ContractAddress string `json:"contractAddress" form:"contractAddress"`
+	// ContractName may be qualified by source file, e.g. contracts/Token.sol:Token
+	ContractName string `json:"contractName" form:"contractName"`
+	// CodeFormat is solidity-single-file (default), solidity-multi-file or solidity-standard-json-input
+	CodeFormat string `json:"codeFormat" form:"codeFormat"`
+	// SourceCode hold the single file source or the standard JSON input
+	SourceCode      string                   `json:"sourceCode" form:"sourceCode"`
+	SourceFiles     []*types.ContractSource  `json:"sourceFiles"`
+	CompilerVersion string                   `json:"compilerVersion" form:"compilerVersion"`
+	Optimization    bool                     `json:"optimization" form:"optimization"`
+	Runs            int                      `json:"runs" form:"runs"`
+	EVMVersion      string                   `json:"evmVersion" form:"evmVersion"`
+	Remappings      []string                 `json:"remappings" form:"remappings"`
+	Libraries       []*types.ContractLibrary `json:"libraries"`
+	ConstructorArgs string                   `json:"constructorArguments" form:"constructorArguments"`
+}
+
+type verifyContractError struct {
+	Error         string   `json:"error"`
+	CompileErrors []string `json:"compileErrors,omitempty"`
+}
+
+// VerifyContract accept a JSON body or a multipart form, where every uploaded "files" part
+// is a source file of a multi-file contract and the "paths" value at the same position is its path,
+// as multipart parsing keep only the base name of uploaded files
+func (s *Server) VerifyContract(c echo.Context) error {
+	ctx := c.Request().Context()
+	var req verifyContractRequest
+	if err := c.Bind(&req); err != nil {
+		return Invalid.Build(c)
+	}
+	if form, err := c.MultipartForm(); err == nil {
+		files, err := readSourceFiles(form.File["files"], form.Value["paths"])
+		if err != nil {
+			resp := Invalid
+			return resp.SetData(&verifyContractError{Error: err.Error()}).Build(c)
+		}
+		req.SourceFiles = append(req.SourceFiles, files...)
 	}
-	var req verifyRequest
+	smc, result, err := s.verifyContract(ctx, &req)
+	if err != nil {
+		resp := Invalid
+		verifyErr := &verifyContractError{Error: err.Error()}
+		if result != nil {
+			verifyErr.CompileErrors = result.CompileErrors
+		}
+		return resp.SetData(verifyErr).Build(c)
+	}
+	return OK.SetData(struct {
+		*types.Contract
+		ConstructorArgs string `json:"constructorArguments"`
+	}{
+		Contract:        smc,
+		ConstructorArgs: result.ConstructorArgs,
+	}).Build(c)
+}
 
-	code, err := s.kaiClient.GetCode(ctx, req.SMCAddress)
+// verifyContract compile submitted sources and mark the contract verified when its
+// runtime bytecode match the deployed code
+func (s *Server) verifyContract(ctx context.Context, req *verifyContractRequest) (*types.Contract, *solc.VerifyResult, error) {
+	lgr := s.logger.With(zap.String("method", "verifyContract"), zap.String("address", req.ContractAddress))
+	if s.compiler == nil {
+		return nil, nil, errors.New("contract verification is not enabled")
+	}
+	if !common.IsHexAddress(req.ContractAddress) || req.CompilerVersion == "" {
+		return nil, nil, errors.New("missing contract address or compiler version")
+	}
+	input, err := buildCompilerInput(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	address := common.HexToAddress(req.ContractAddress).Hex()
+	code, err := s.kaiClient.GetCode(ctx, address)
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+	if len(code) == 0 {
+		return nil, nil, errors.New("no contract code at " + address)
+	}
+	smc, _, err := s.dbClient.Contract(ctx, address)
+	if err != nil || smc == nil {
+		smc = &types.Contract{Address: address, Type: cfg.SMCTypeNormal}
 	}
 
-	if req.Code != string(code) {
-		return err
+	verifyReq := &solc.VerifyRequest{
+		CompilerVersion: req.CompilerVersion,
+		Input:           input,
+		ContractName:    req.ContractName,
+		ConstructorArgs: req.ConstructorArgs,
+		DeployedCode:    code,
+	}
+	if smc.TxHash != "" {
+		if tx, err := s.dbClient.TxByHash(ctx, smc.TxHash); err == nil && tx != nil {
+			verifyReq.CreationInput, _ = hex.DecodeString(strings.TrimPrefix(tx.InputData, "0x"))
+		}
+	}
+	result, err := solc.Verify(ctx, s.compiler, verifyReq)
+	if err != nil {
+		lgr.Info("Contract verification failed", zap.Error(err))
+		return nil, result, err
 	}
 
-	return nil
+	applyCompilerInput(smc, input, result.File)
+	smc.ABI = base64.StdEncoding.EncodeToString([]byte(result.ABI))
+	smc.CompilerVersion = req.CompilerVersion
+	smc.IsVerified = true
+	smc.Status = types.ContractStatusVerified
+	if smc.Name == "" {
+		smc.Name = result.ContractName
+	}
+	if err := s.dbClient.UpdateContract(ctx, smc, nil); err != nil {
+		lgr.Error("cannot store verified contract", zap.Error(err))
+		return nil, result, err
+	}
+	// proxies are decoded with merged ABI, drop cached ABI instead of caching the proxy's own
+	if err := s.refreshProxy(ctx, smc); err == nil && smc.Implementation != "" {
+		if err := s.cacheClient.RemoveSMCAbi(ctx, address); err != nil {
+			lgr.Warn("cannot remove cached proxy ABI", zap.Error(err))
+		}
+	} else if err := s.cacheClient.UpdateSMCAbi(ctx, address, smc.ABI); err != nil {
+		lgr.Warn("cannot cache verified contract ABI", zap.Error(err))
+	}
+	s.dropProxiesABI(ctx, address)
+	s.storeABISignatures(ctx, smc.ABI)
+	return smc, result, nil
+}
+
+func buildCompilerInput(req *verifyContractRequest) (*solc.Input, error) {
+	opts := solc.Options{
+		Optimize:   req.Optimization,
+		Runs:       req.Runs,
+		EVMVersion: req.EVMVersion,
+		Remappings: req.Remappings,
+		Libraries:  compilerLibraries(req.Libraries),
+	}
+
+	codeFormat := req.CodeFormat
+	if codeFormat == "" && len(req.SourceFiles) > 0 {
+		codeFormat = codeFormatMultiFile
+	}
+	switch codeFormat {
+	case codeFormatStandardJSON:
+		return solc.ParseStandardJSON([]byte(req.SourceCode))
+	case codeFormatMultiFile:
+		if len(req.SourceFiles) == 0 {
+			return nil, errors.New("missing source files")
+		}
+		sources := make(map[string]string, len(req.SourceFiles))
+		for _, f := range req.SourceFiles {
+			if f.Name == "" || f.Content == "" {
+				return nil, errors.New("source file must have name and content")
+			}
+			if _, ok := sources[f.Name]; ok {
+				return nil, fmt.Errorf("duplicated source file %s", f.Name)
+			}
+			sources[f.Name] = f.Content
+		}
+		return solc.NewInput(sources, opts), nil
+	case "", codeFormatSingleFile:
+		if req.SourceCode == "" {
+			return nil, errors.New("missing source code")
+		}
+		return solc.NewInput(map[string]string{contractFileName(req.ContractName): req.SourceCode}, opts), nil
+	}
+	return nil, errors.New("unsupported code format " + codeFormat)
+}
+
+// compilerLibraries group libraries by source file as solc expects, unqualified names are global
+func compilerLibraries(libs []*types.ContractLibrary) map[string]map[string]string {
+	if len(libs) == 0 {
+		return nil
+	}
+	libraries := make(map[string]map[string]string)
+	for _, lib := range libs {
+		file, name := "", lib.Name
+		if i := strings.LastIndex(lib.Name, ":"); i >= 0 {
+			file, name = lib.Name[:i], lib.Name[i+1:]
+		}
+		if libraries[file] == nil {
+			libraries[file] = make(map[string]string)
+		}
+		libraries[file][name] = lib.Address
+	}
+	return libraries
+}
+
+// applyCompilerInput store every source file and compiler settings which produced the contract.
+// Source keep the file declaring the contract for clients reading a single source.
+func applyCompilerInput(smc *types.Contract, input *solc.Input, mainFile string) {
+	smc.SourceFiles = nil
+	for _, name := range input.FileNames() {
+		smc.SourceFiles = append(smc.SourceFiles, &types.ContractSource{Name: name, Content: input.Sources[name].Content})
+	}
+	if main, ok := input.Sources[mainFile]; ok {
+		smc.Source = main.Content
+	}
+	settings := input.Settings
+	smc.IsOptimize, smc.OptimizeRuns = false, 0
+	if settings.Optimizer != nil {
+		smc.IsOptimize = settings.Optimizer.Enabled
+		smc.OptimizeRuns = settings.Optimizer.Runs
+	}
+	smc.EVMVersion = settings.EVMVersion
+	smc.Remappings = settings.Remappings
+	smc.Libraries = nil
+	for file, libs := range settings.Libraries {
+		for name, address := range libs {
+			if file != "" {
+				name = file + ":" + name
+			}
+			smc.Libraries = append(smc.Libraries, &types.ContractLibrary{Name: name, Address: address})
+		}
+	}
+	sort.Slice(smc.Libraries, func(i, j int) bool {
+		return smc.Libraries[i].Name < smc.Libraries[j].Name
+	})
+}
+
+// readSourceFiles name uploaded files by paths, files are named by their base name when paths are missing
+func readSourceFiles(headers []*multipart.FileHeader, paths []string) ([]*types.ContractSource, error) {
+	if len(paths) > 0 && len(paths) != len(headers) {
+		return nil, errors.New("paths must have one path per source file")
+	}
+	var files []*types.ContractSource
+	for i, h := range headers {
+		name := h.Filename
+		if len(paths) > 0 {
+			name = strings.TrimSpace(paths[i])
+		}
+		f, err := h.Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := ioutil.ReadAll(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &types.ContractSource{Name: name, Content: string(content)})
+	}
+	return files, nil
+}
+
+// contractSourceFiles return source files of a verified contract, contracts verified
+// before multi-file support only have a single Source
+func contractSourceFiles(smc *types.Contract) []*types.ContractSource {
+	if len(smc.SourceFiles) > 0 || smc.Source == "" {
+		return smc.SourceFiles
+	}
+	return []*types.ContractSource{{Name: contractFileName(smc.Name), Content: smc.Source}}
+}
+
+// contractFileName name the single source file after the contract, as solc messages refer to it
+func contractFileName(contractName string) string {
+	if i := strings.LastIndex(contractName, ":"); i >= 0 {
+		return contractName[:i]
+	}
+	if contractName == "" {
+		return "Contract.sol"
+	}
+	return contractName + ".sol"
 }
@@ -0,0 +1,98 @@
+// Package api
+package api
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+func TestBuildCompilerInput(t *testing.T) {
+	req := &verifyContractRequest{
+		ContractName: "contracts/Token.sol:Token",
+		SourceFiles: []*types.ContractSource{
+			{Name: "contracts/Token.sol", Content: "contract Token {}"},
+			{Name: "contracts/lib/Math.sol", Content: "library Math {}"},
+		},
+		Optimization: true,
+		Runs:         500,
+		EVMVersion:   "berlin",
+		Remappings:   []string{"@lib/=contracts/lib/"},
+		Libraries: []*types.ContractLibrary{
+			{Name: "contracts/lib/Math.sol:Math", Address: "0x1"},
+			{Name: "Strings", Address: "0x2"},
+		},
+	}
+	input, err := buildCompilerInput(req)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"contracts/Token.sol", "contracts/lib/Math.sol"}, input.FileNames())
+	assert.Equal(t, map[string]map[string]string{
+		"contracts/lib/Math.sol": {"Math": "0x1"},
+		"":                       {"Strings": "0x2"},
+	}, input.Settings.Libraries)
+
+	smc := &types.Contract{}
+	applyCompilerInput(smc, input, "contracts/Token.sol")
+	assert.Equal(t, "contract Token {}", smc.Source)
+	assert.Equal(t, req.SourceFiles, smc.SourceFiles)
+	assert.True(t, smc.IsOptimize)
+	assert.Equal(t, 500, smc.OptimizeRuns)
+	assert.Equal(t, "berlin", smc.EVMVersion)
+	assert.Equal(t, req.Remappings, smc.Remappings)
+	assert.Equal(t, []*types.ContractLibrary{
+		{Name: "Strings", Address: "0x2"},
+		{Name: "contracts/lib/Math.sol:Math", Address: "0x1"},
+	}, smc.Libraries)
+
+	_, err = buildCompilerInput(&verifyContractRequest{CodeFormat: codeFormatMultiFile})
+	assert.NotNil(t, err)
+	_, err = buildCompilerInput(&verifyContractRequest{CodeFormat: "vyper"})
+	assert.NotNil(t, err)
+	_, err = buildCompilerInput(&verifyContractRequest{CodeFormat: codeFormatMultiFile, SourceFiles: []*types.ContractSource{
+		{Name: "Token.sol", Content: "contract Token {}"},
+		{Name: "Token.sol", Content: "contract Other {}"},
+	}})
+	assert.NotNil(t, err)
+
+	// contracts verified before multi-file support expose their single source as a file
+	legacy := &types.Contract{Name: "Token", Source: "contract Token {}"}
+	assert.Equal(t, []*types.ContractSource{{Name: "Token.sol", Content: "contract Token {}"}}, contractSourceFiles(legacy))
+}
+
+func TestReadSourceFiles(t *testing.T) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for _, f := range []struct{ path, content string }{
+		{"contracts/a/Token.sol", "contract A {}"},
+		{"contracts/b/Token.sol", "contract B {}"},
+	} {
+		part, err := w.CreateFormFile("files", f.path)
+		require.NoError(t, err)
+		_, _ = part.Write([]byte(f.content))
+		require.NoError(t, w.WriteField("paths", f.path))
+	}
+	require.NoError(t, w.Close())
+	req := httptest.NewRequest("POST", "/api/v1/contracts/verify", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	require.NoError(t, req.ParseMultipartForm(1<<20))
+
+	// multipart parsing drop the directories of uploaded file names
+	headers := req.MultipartForm.File["files"]
+	assert.Equal(t, "Token.sol", headers[0].Filename)
+
+	files, err := readSourceFiles(headers, req.MultipartForm.Value["paths"])
+	require.NoError(t, err)
+	assert.Equal(t, []*types.ContractSource{
+		{Name: "contracts/a/Token.sol", Content: "contract A {}"},
+		{Name: "contracts/b/Token.sol", Content: "contract B {}"},
+	}, files)
+
+	_, err = readSourceFiles(headers, []string{"contracts/a/Token.sol"})
+	assert.NotNil(t, err)
+}
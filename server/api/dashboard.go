@@ -2,13 +2,11 @@
 package api
 
 import (
-	"context"
-
 	"github.com/labstack/echo"
 )
 
 func (s *Server) Stats(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	totalContracts, err := s.cacheClient.TotalContracts(ctx)
 	if err != nil {
 		return err
@@ -28,7 +26,7 @@ func (s *Server) Stats(c echo.Context) error {
 }
 
 func (s *Server) TotalHolders(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	totalHolders, totalContracts := s.cacheClient.TotalHolders(ctx)
 	return OK.SetData(struct {
 		TotalHolders   uint64 `json:"totalHolders"`
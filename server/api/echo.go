@@ -20,11 +20,13 @@ package api
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/labstack/echo"
 	"github.com/labstack/echo/middleware"
 
 	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/types"
 )
 
 type restDefinition struct {
@@ -43,15 +45,19 @@ func bind(gr *echo.Group, srv RestServer) {
 			middlewares: nil,
 		},
 		{
-			method:      echo.GET,
-			path:        "/status",
-			fn:          srv.ServerStatus,
-			middlewares: nil,
+			method: echo.GET,
+			path:   "/health/live",
+			fn:     srv.Liveness,
 		},
 		{
-			method:      echo.PUT,
+			method: echo.GET,
+			path:   "/health/ready",
+			fn:     srv.Readiness,
+		},
+		{
+			method:      echo.GET,
 			path:        "/status",
-			fn:          srv.UpdateServerStatus,
+			fn:          srv.ServerStatus,
 			middlewares: nil,
 		},
 		{
@@ -69,21 +75,6 @@ func bind(gr *echo.Group, srv RestServer) {
 			path:   "/dashboard/token",
 			fn:     srv.TokenInfo,
 		},
-		{
-			method: echo.PUT,
-			path:   "/dashboard/token/supplies",
-			fn:     srv.UpdateSupplyAmounts,
-		},
-		{
-			method: echo.PUT,
-			path:   "/nodes",
-			fn:     srv.UpsertNetworkNodes,
-		},
-		{
-			method: echo.DELETE,
-			path:   "/nodes/:nodeID",
-			fn:     srv.RemoveNetworkNodes,
-		},
 		// Blocks
 
 		// blocks
@@ -111,11 +102,6 @@ func bind(gr *echo.Group, srv RestServer) {
 			path:   "/addresses/:address",
 			fn:     srv.AddressInfo,
 		},
-		{
-			method: echo.POST,
-			path:   "/addresses/reload",
-			fn:     srv.ReloadAddressesBalance,
-		},
 		// Tokens
 		{
 			method:      echo.GET,
@@ -156,22 +142,10 @@ func bind(gr *echo.Group, srv RestServer) {
 			fn:          srv.GetParams,
 			middlewares: nil,
 		},
-		{
-			method:      echo.PUT,
-			path:        "/addresses",
-			fn:          srv.UpdateAddressName,
-			middlewares: nil,
-		},
-		{
-			method:      echo.POST,
-			path:        "/validators/reload",
-			fn:          srv.ReloadValidators,
-			middlewares: nil,
-		},
 		{
 			method:      echo.GET,
 			path:        "/search",
-			fn:          srv.SearchAddressByName,
+			fn:          srv.Search,
 			middlewares: nil,
 		},
 		{
@@ -181,34 +155,26 @@ func bind(gr *echo.Group, srv RestServer) {
 			fn:          srv.GetInternalTxs,
 			middlewares: nil,
 		},
-		{
-			method:      echo.PUT,
-			path:        "/token/txs",
-			fn:          srv.UpdateInternalTxs,
-			middlewares: nil,
-		},
 	}
 	bindKRC721APIs(gr, srv)
 	bindKRC20APIs(gr, srv)
 	bindBlocksAPIs(gr, srv)
 	bindContractAPIs(gr, srv)
-	bindEventAPIs(gr, srv)
 	bindStakingAPIs(gr, srv)
 	bindPrivateAPIs(gr, srv)
-	for _, api := range apis {
-		gr.Add(api.method, api.path, api.fn, api.middlewares...)
-	}
-}
-
-func bindEventAPIs(gr *echo.Group, srv RestServer) {
-	apis := []restDefinition{
-		{
-			method:      echo.DELETE,
-			path:        "/event/duplicate",
-			fn:          srv.RemoveDuplicateEvents,
-			middlewares: nil,
-		},
-	}
+	bindStreamAPIs(gr, srv)
+	bindSignatureAPIs(gr, srv)
+	bindInternalCallAPIs(gr, srv)
+	bindBalanceAPIs(gr, srv)
+	bindChartAPIs(gr, srv)
+	bindGasAPIs(gr, srv)
+	bindMarketAPIs(gr, srv)
+	bindLabelAPIs(gr, srv)
+	bindPortfolioAPIs(gr, srv)
+	bindExportAPIs(gr, srv)
+	bindAPIKeyAPIs(gr, srv)
+	bindOpenAPIs(gr, srv)
+	bindGraphQLAPIs(gr, srv)
 	for _, api := range apis {
 		gr.Add(api.method, api.path, api.fn, api.middlewares...)
 	}
@@ -217,12 +183,30 @@ func bindEventAPIs(gr *echo.Group, srv RestServer) {
 func Start(srv RestServer, cfg cfg.ExplorerConfig) {
 	e := echo.New()
 
+	e.Use(traceRequest())
+	e.Use(recordMetrics())
 	e.Use(middleware.CORS())
 	e.Use(middleware.Logger())
-	e.Use(middleware.Gzip())
+	e.Use(middleware.GzipWithConfig(middleware.GzipConfig{
+		// stream responses must be flushed as soon as events come
+		Skipper: func(c echo.Context) bool {
+			return strings.HasSuffix(c.Path(), "/stream")
+		},
+	}))
+	if cfg.RateLimitEnabled {
+		e.Use(srv.RateLimit(&types.RateLimitTier{
+			Name:              types.RateLimitTierAnonymous,
+			RequestsPerMinute: cfg.RateLimitAnonymousRPM,
+			Burst:             cfg.RateLimitAnonymousBurst,
+			DailyQuota:        cfg.RateLimitAnonymousDailyQuota,
+		}))
+	}
+	e.Use(validateRequest())
 
 	v1Gr := e.Group("/api/v1")
 	bind(v1Gr, srv)
+	// Etherscan-compatible endpoint, served at /api like Etherscan does
+	bindEtherscanAPIs(e.Group("/api"), srv)
 	if err := e.Start(cfg.Port); err != nil {
 		fmt.Println("cannot start echo server", err.Error())
 		panic(err)
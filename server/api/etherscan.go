@@ -0,0 +1,853 @@
+// Package api
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kardiachain/go-kardia"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/labstack/echo"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/driver/solc"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+const (
+	etherscanStatusOK    = "1"
+	etherscanStatusNotOK = "0"
+
+	// etherscanMaxLogs is the number of logs returned by a getLogs request, same as Etherscan
+	etherscanMaxLogs = 1000
+	// etherscanMaxLogsRange is the number of blocks a getLogs request can span, each FilterLogsInterval blocks cost an RPC call
+	etherscanMaxLogsRange = 10 * cfg.FilterLogsInterval
+	// etherscanMaxBalanceAddresses is the number of addresses of a balancemulti request, same as Etherscan
+	etherscanMaxBalanceAddresses = 20
+)
+
+var (
+	errEtherscanUnknownAction  = errors.New("Error! Missing Or invalid Module name or Action name")
+	errEtherscanInvalidAddress = errors.New("Error! Invalid address format")
+	errEtherscanInvalidTxHash  = errors.New("Error! Invalid transaction hash")
+	errEtherscanNotVerified    = errors.New("Contract source code not verified")
+	errEtherscanMissingRange   = errors.New("Error! Missing fromBlock or toBlock")
+	errEtherscanInvalidRange   = errors.New("Error! Invalid block range")
+	errEtherscanRangeTooLarge  = fmt.Errorf("Error! Block range is too large, maximum is %d blocks", etherscanMaxLogsRange)
+	errEtherscanTooManyAddrs   = fmt.Errorf("Error! Maximum %d addresses per request", etherscanMaxBalanceAddresses)
+	errEtherscanMissingAPIKey  = errors.New("Missing/Invalid API Key")
+)
+
+// IEtherscan expose an Etherscan-compatible "module/action" API, so existing tooling
+// (hardhat-etherscan, web3 providers, block explorers SDK...) can query the explorer unchanged.
+type IEtherscan interface {
+	Etherscan(c echo.Context) error
+}
+
+func bindEtherscanAPIs(gr *echo.Group, srv RestServer) {
+	apis := []restDefinition{
+		{
+			method: echo.GET,
+			// Query params: ?module=account&action=txlist&address=0x&startblock=0&endblock=0&page=1&offset=10&sort=asc
+			path:        "",
+			fn:          srv.Etherscan,
+			middlewares: nil,
+		},
+		{
+			// verifysourcecode is submitted as form data
+			method:      echo.POST,
+			path:        "",
+			fn:          srv.Etherscan,
+			middlewares: nil,
+		},
+	}
+	for _, api := range apis {
+		gr.Add(api.method, api.path, api.fn, api.middlewares...)
+	}
+}
+
+// EtherscanResponse is the status/message/result envelope of Etherscan APIs
+type EtherscanResponse struct {
+	Status  string      `json:"status"`
+	Message string      `json:"message"`
+	Result  interface{} `json:"result"`
+}
+
+// etherscanNoRecords is returned by list actions when nothing matches, Etherscan answers
+// these requests with status "0", a descriptive message and an empty result
+type etherscanNoRecords struct {
+	message string
+}
+
+func (e *etherscanNoRecords) Error() string {
+	return e.message
+}
+
+type etherscanAction func(ctx context.Context, c echo.Context) (interface{}, error)
+
+func (s *Server) etherscanActions() map[string]etherscanAction {
+	return map[string]etherscanAction{
+		"account.balance":                 s.etherscanBalance,
+		"account.balancemulti":            s.etherscanBalanceMulti,
+		"account.txlist":                  s.etherscanTxList,
+		"account.tokentx":                 s.etherscanTokenTx(cfg.SMCTypeKRC20),
+		"account.tokennfttx":              s.etherscanTokenTx(cfg.SMCTypeKRC721),
+		"contract.getabi":                 s.etherscanGetABI,
+		"contract.getsourcecode":          s.etherscanGetSourceCode,
+		"contract.verifysourcecode":       s.etherscanVerifySourceCode,
+		"contract.checkverifystatus":      s.etherscanCheckVerifyStatus,
+		"contract.verifyproxycontract":    s.etherscanVerifyProxyContract,
+		"contract.checkproxyverification": s.etherscanCheckProxyVerification,
+		"transaction.getstatus":           s.etherscanTxStatus,
+		"transaction.gettxreceiptstatus":  s.etherscanTxReceiptStatus,
+		"block.getblockreward":            s.etherscanBlockReward,
+		"block.getblocknobytime":          s.etherscanBlockNoByTime,
+		"logs.getLogs":                    s.etherscanGetLogs,
+		"stats.kaisupply":                 s.etherscanKaiSupply,
+		"stats.kaiprice":                  s.etherscanKaiPrice,
+	}
+}
+
+func (s *Server) Etherscan(c echo.Context) error {
+	ctx := c.Request().Context()
+	action, ok := s.etherscanActions()[c.FormValue("module")+"."+c.FormValue("action")]
+	if !ok {
+		return etherscanError(c, errEtherscanUnknownAction)
+	}
+	result, err := action(ctx, c)
+	if err != nil {
+		return etherscanError(c, err)
+	}
+	return c.JSON(http.StatusOK, &EtherscanResponse{
+		Status:  etherscanStatusOK,
+		Message: "OK",
+		Result:  result,
+	})
+}
+
+func etherscanError(c echo.Context, err error) error {
+	var noRecords *etherscanNoRecords
+	if errors.As(err, &noRecords) {
+		return c.JSON(http.StatusOK, &EtherscanResponse{
+			Status:  etherscanStatusNotOK,
+			Message: noRecords.message,
+			Result:  []interface{}{},
+		})
+	}
+	return c.JSON(http.StatusOK, &EtherscanResponse{
+		Status:  etherscanStatusNotOK,
+		Message: "NOTOK",
+		Result:  err.Error(),
+	})
+}
+
+func (s *Server) etherscanBalance(ctx context.Context, c echo.Context) (interface{}, error) {
+	address, err := etherscanAddress(c.FormValue("address"))
+	if err != nil {
+		return nil, err
+	}
+	return s.kaiClient.GetBalance(ctx, address)
+}
+
+func (s *Server) etherscanBalanceMulti(ctx context.Context, c echo.Context) (interface{}, error) {
+	type accountBalance struct {
+		Account string `json:"account"`
+		Balance string `json:"balance"`
+	}
+	addresses := strings.Split(c.FormValue("address"), ",")
+	if len(addresses) > etherscanMaxBalanceAddresses {
+		return nil, errEtherscanTooManyAddrs
+	}
+	var result []*accountBalance
+	for _, addr := range addresses {
+		address, err := etherscanAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+		balance, err := s.kaiClient.GetBalance(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &accountBalance{Account: address, Balance: balance})
+	}
+	return result, nil
+}
+
+func (s *Server) etherscanTxList(ctx context.Context, c echo.Context) (interface{}, error) {
+	type etherscanTx struct {
+		BlockNumber      string `json:"blockNumber"`
+		TimeStamp        string `json:"timeStamp"`
+		Hash             string `json:"hash"`
+		Nonce            string `json:"nonce"`
+		BlockHash        string `json:"blockHash"`
+		TransactionIndex string `json:"transactionIndex"`
+		From             string `json:"from"`
+		To               string `json:"to"`
+		Value            string `json:"value"`
+		Gas              string `json:"gas"`
+		GasPrice         string `json:"gasPrice"`
+		IsError          string `json:"isError"`
+		TxReceiptStatus  string `json:"txreceipt_status"`
+		Input            string `json:"input"`
+		ContractAddress  string `json:"contractAddress"`
+		GasUsed          string `json:"gasUsed"`
+		Confirmations    string `json:"confirmations"`
+	}
+	address, err := etherscanAddress(c.FormValue("address"))
+	if err != nil {
+		return nil, err
+	}
+	txs, _, err := s.dbClient.TxsByAddressInRange(ctx, address, etherscanBlockRange(c), etherscanPaging(c))
+	if err != nil {
+		return nil, err
+	}
+	if len(txs) == 0 {
+		return nil, &etherscanNoRecords{message: "No transactions found"}
+	}
+	latest := s.cacheClient.LatestBlockHeight(ctx)
+	result := make([]*etherscanTx, len(txs))
+	for i, tx := range txs {
+		result[i] = &etherscanTx{
+			BlockNumber:      strconv.FormatUint(tx.BlockNumber, 10),
+			TimeStamp:        strconv.FormatInt(tx.Time.Unix(), 10),
+			Hash:             tx.Hash,
+			Nonce:            strconv.FormatUint(tx.Nonce, 10),
+			BlockHash:        tx.BlockHash,
+			TransactionIndex: strconv.FormatUint(uint64(tx.TransactionIndex), 10),
+			From:             tx.From,
+			To:               tx.To,
+			Value:            tx.Value,
+			Gas:              strconv.FormatUint(tx.GasLimit, 10),
+			GasPrice:         strconv.FormatUint(tx.GasPrice, 10),
+			IsError:          "0",
+			TxReceiptStatus:  strconv.FormatUint(uint64(tx.Status), 10),
+			Input:            tx.InputData,
+			GasUsed:          strconv.FormatUint(tx.GasUsed, 10),
+			Confirmations:    etherscanConfirmations(latest, tx.BlockNumber),
+		}
+		if tx.Status == types.TransactionStatusFailed {
+			result[i].IsError = "1"
+		}
+		if tx.ContractAddress != "" && tx.ContractAddress != "0x" {
+			result[i].ContractAddress = tx.ContractAddress
+		}
+	}
+	return result, nil
+}
+
+func (s *Server) etherscanTokenTx(tokenType string) etherscanAction {
+	type etherscanTokenTransfer struct {
+		BlockNumber     string `json:"blockNumber"`
+		TimeStamp       string `json:"timeStamp"`
+		Hash            string `json:"hash"`
+		From            string `json:"from"`
+		ContractAddress string `json:"contractAddress"`
+		To              string `json:"to"`
+		Value           string `json:"value,omitempty"`
+		TokenID         string `json:"tokenID,omitempty"`
+		TokenName       string `json:"tokenName"`
+		TokenSymbol     string `json:"tokenSymbol"`
+		TokenDecimal    string `json:"tokenDecimal"`
+		LogIndex        string `json:"logIndex"`
+		Confirmations   string `json:"confirmations"`
+	}
+	return func(ctx context.Context, c echo.Context) (interface{}, error) {
+		filter := &types.InternalTxsFilter{
+			Pagination: etherscanPaging(c),
+			BlockRange: etherscanBlockRange(c),
+			TokenType:  tokenType,
+		}
+		var err error
+		if addr := c.FormValue("address"); addr != "" {
+			if filter.Address, err = etherscanAddress(addr); err != nil {
+				return nil, err
+			}
+		}
+		if contract := c.FormValue("contractaddress"); contract != "" {
+			if filter.Contract, err = etherscanAddress(contract); err != nil {
+				return nil, err
+			}
+		}
+		if filter.Address == "" && filter.Contract == "" {
+			return nil, errEtherscanInvalidAddress
+		}
+		transfers, _, err := s.dbClient.GetListInternalTxs(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if len(transfers) == 0 {
+			return nil, &etherscanNoRecords{message: "No transactions found"}
+		}
+		latest := s.cacheClient.LatestBlockHeight(ctx)
+		result := make([]*etherscanTokenTransfer, len(transfers))
+		for i, t := range transfers {
+			result[i] = &etherscanTokenTransfer{
+				BlockNumber:     strconv.FormatUint(t.BlockHeight, 10),
+				TimeStamp:       strconv.FormatInt(t.Time.Unix(), 10),
+				Hash:            t.TransactionHash,
+				From:            t.From,
+				ContractAddress: t.Contract,
+				To:              t.To,
+				LogIndex:        fmt.Sprint(t.LogIndex),
+				Confirmations:   etherscanConfirmations(latest, t.BlockHeight),
+			}
+			if tokenType == cfg.SMCTypeKRC721 {
+				result[i].TokenID = t.TokenID
+			} else {
+				result[i].Value = t.Value
+			}
+			if tokenInfo, err := s.getTokenInfo(ctx, t.Contract); err == nil && tokenInfo != nil {
+				result[i].TokenName = tokenInfo.TokenName
+				result[i].TokenSymbol = tokenInfo.TokenSymbol
+				result[i].TokenDecimal = strconv.FormatInt(tokenInfo.Decimals, 10)
+			}
+		}
+		return result, nil
+	}
+}
+
+func (s *Server) etherscanGetABI(ctx context.Context, c echo.Context) (interface{}, error) {
+	address, err := etherscanAddress(c.FormValue("address"))
+	if err != nil {
+		return nil, err
+	}
+	smc, _, err := s.dbClient.Contract(ctx, address)
+	if err != nil || smc.ABI == "" {
+		return nil, errEtherscanNotVerified
+	}
+	abiData, err := base64.StdEncoding.DecodeString(smc.ABI)
+	if err != nil {
+		s.logger.Warn("Cannot decode smc abi", zap.String("address", address), zap.Error(err))
+		return nil, errEtherscanNotVerified
+	}
+	return string(abiData), nil
+}
+
+func (s *Server) etherscanGetSourceCode(ctx context.Context, c echo.Context) (interface{}, error) {
+	type etherscanSourceCode struct {
+		SourceCode           string `json:"SourceCode"`
+		ABI                  string `json:"ABI"`
+		ContractName         string `json:"ContractName"`
+		CompilerVersion      string `json:"CompilerVersion"`
+		OptimizationUsed     string `json:"OptimizationUsed"`
+		Runs                 string `json:"Runs"`
+		ConstructorArguments string `json:"ConstructorArguments"`
+		EVMVersion           string `json:"EVMVersion"`
+		Library              string `json:"Library"`
+		LicenseType          string `json:"LicenseType"`
+		Proxy                string `json:"Proxy"`
+		Implementation       string `json:"Implementation"`
+		SwarmSource          string `json:"SwarmSource"`
+	}
+	address, err := etherscanAddress(c.FormValue("address"))
+	if err != nil {
+		return nil, err
+	}
+	result := &etherscanSourceCode{
+		ABI:        errEtherscanNotVerified.Error(),
+		EVMVersion: "Default",
+		Proxy:      "0",
+	}
+	smc, _, err := s.dbClient.Contract(ctx, address)
+	if err != nil || !smc.IsVerified {
+		return []*etherscanSourceCode{result}, nil
+	}
+	result.SourceCode = smc.Source
+	if len(smc.SourceFiles) > 1 {
+		result.SourceCode = etherscanStandardJSONSource(smc)
+	}
+	result.ContractName = smc.Name
+	result.CompilerVersion = smc.CompilerVersion
+	result.OptimizationUsed = "0"
+	if smc.IsOptimize {
+		result.OptimizationUsed = "1"
+	}
+	if smc.OptimizeRuns > 0 {
+		result.Runs = strconv.Itoa(smc.OptimizeRuns)
+	}
+	if smc.EVMVersion != "" {
+		result.EVMVersion = smc.EVMVersion
+	}
+	var libs []string
+	for _, lib := range smc.Libraries {
+		libs = append(libs, lib.Name+":"+lib.Address)
+	}
+	result.Library = strings.Join(libs, ";")
+	if smc.Implementation != "" {
+		result.Proxy = "1"
+		result.Implementation = smc.Implementation
+	}
+	if abiData, err := base64.StdEncoding.DecodeString(smc.ABI); err == nil {
+		result.ABI = string(abiData)
+	}
+	return []*etherscanSourceCode{result}, nil
+}
+
+// etherscanStandardJSONSource encode multi-file sources the way Etherscan does, a standard JSON
+// input wrapped in an extra pair of braces
+func etherscanStandardJSONSource(smc *types.Contract) string {
+	sources := make(map[string]string, len(smc.SourceFiles))
+	for _, f := range smc.SourceFiles {
+		sources[f.Name] = f.Content
+	}
+	input := solc.NewInput(sources, solc.Options{
+		Optimize:   smc.IsOptimize,
+		Runs:       smc.OptimizeRuns,
+		EVMVersion: smc.EVMVersion,
+		Remappings: smc.Remappings,
+		Libraries:  compilerLibraries(smc.Libraries),
+	})
+	data, err := json.Marshal(input)
+	if err != nil {
+		return smc.Source
+	}
+	return "{" + string(data) + "}"
+}
+
+// etherscanVerifySourceCode verify submitted source right away. Like Etherscan, the contract
+// address is returned as GUID so clients can poll checkverifystatus.
+// Compiling is expensive, so like Etherscan an API key is required.
+func (s *Server) etherscanVerifySourceCode(ctx context.Context, c echo.Context) (interface{}, error) {
+	if _, ok := c.Get(ContextAPIKey).(*types.APIKey); !ok {
+		return nil, errEtherscanMissingAPIKey
+	}
+	address, err := etherscanAddress(c.FormValue("contractaddress"))
+	if err != nil {
+		return nil, err
+	}
+	if smc, _, err := s.dbClient.Contract(ctx, address); err == nil && smc.IsVerified {
+		return nil, errors.New("Contract source code already verified")
+	}
+	runs, _ := strconv.Atoi(c.FormValue("runs"))
+	req := &verifyContractRequest{
+		ContractAddress: address,
+		ContractName:    c.FormValue("contractname"),
+		SourceCode:      c.FormValue("sourceCode"),
+		CompilerVersion: c.FormValue("compilerversion"),
+		Optimization:    c.FormValue("optimizationUsed") == "1",
+		Runs:            runs,
+		CodeFormat:      c.FormValue("codeformat"),
+		EVMVersion:      c.FormValue("evmversion"),
+		// Etherscan API spell it this way
+		ConstructorArgs: c.FormValue("constructorArguements"),
+	}
+	if req.EVMVersion == "default" {
+		req.EVMVersion = ""
+	}
+	for i := 1; i <= 10; i++ {
+		name, address := c.FormValue(fmt.Sprintf("libraryname%d", i)), c.FormValue(fmt.Sprintf("libraryaddress%d", i))
+		if name != "" && address != "" {
+			req.Libraries = append(req.Libraries, &types.ContractLibrary{Name: name, Address: address})
+		}
+	}
+	if _, _, err := s.verifyContract(ctx, req); err != nil {
+		return nil, errors.New("Fail - Unable to verify. " + err.Error())
+	}
+	return address, nil
+}
+
+func (s *Server) etherscanCheckVerifyStatus(ctx context.Context, c echo.Context) (interface{}, error) {
+	address, err := etherscanAddress(c.FormValue("guid"))
+	if err != nil {
+		return nil, errors.New("Unknown UID")
+	}
+	smc, _, err := s.dbClient.Contract(ctx, address)
+	if err != nil {
+		return nil, errors.New("Unknown UID")
+	}
+	switch {
+	case smc.IsVerified:
+		return "Pass - Verified", nil
+	case smc.Status == types.ContractStatusSourceUploaded:
+		return "Pending in queue", nil
+	default:
+		return nil, errors.New("Fail - Unable to verify")
+	}
+}
+
+// etherscanVerifyProxyContract detect implementation of a proxy right away, the address is returned
+// as GUID for checkproxyverification
+func (s *Server) etherscanVerifyProxyContract(ctx context.Context, c echo.Context) (interface{}, error) {
+	address, err := etherscanAddress(c.FormValue("address"))
+	if err != nil {
+		return nil, err
+	}
+	smc, _, err := s.dbClient.Contract(ctx, address)
+	if err != nil {
+		return nil, errors.New("Contract not found")
+	}
+	if err := s.refreshProxy(ctx, smc); err != nil {
+		return nil, errors.New("A corresponding implementation contract was unfortunately not detected for the proxy address.")
+	}
+	return address, nil
+}
+
+func (s *Server) etherscanCheckProxyVerification(ctx context.Context, c echo.Context) (interface{}, error) {
+	address, err := etherscanAddress(c.FormValue("guid"))
+	if err != nil {
+		return nil, errors.New("Unknown UID")
+	}
+	smc, _, err := s.dbClient.Contract(ctx, address)
+	if err != nil || smc.Implementation == "" {
+		return nil, errors.New("A corresponding implementation contract was unfortunately not detected for the proxy address.")
+	}
+	return fmt.Sprintf("The proxy's (%s) implementation contract is found at %s and is successfully updated.", smc.Address, smc.Implementation), nil
+}
+
+func (s *Server) etherscanTxStatus(ctx context.Context, c echo.Context) (interface{}, error) {
+	type txStatus struct {
+		IsError        string `json:"isError"`
+		ErrDescription string `json:"errDescription"`
+	}
+	tx, err := s.etherscanTx(ctx, c.FormValue("txhash"))
+	if err != nil {
+		return nil, err
+	}
+	if tx.Status == types.TransactionStatusFailed {
+		return &txStatus{IsError: "1", ErrDescription: "Reverted"}, nil
+	}
+	return &txStatus{IsError: "0"}, nil
+}
+
+func (s *Server) etherscanTxReceiptStatus(ctx context.Context, c echo.Context) (interface{}, error) {
+	type receiptStatus struct {
+		Status string `json:"status"`
+	}
+	tx, err := s.etherscanTx(ctx, c.FormValue("txhash"))
+	if err != nil {
+		return nil, err
+	}
+	return &receiptStatus{Status: strconv.FormatUint(uint64(tx.Status), 10)}, nil
+}
+
+func (s *Server) etherscanTx(ctx context.Context, txHash string) (*types.Transaction, error) {
+	if len(txHash) != 66 || !strings.HasPrefix(txHash, "0x") {
+		return nil, errEtherscanInvalidTxHash
+	}
+	tx, err := s.dbClient.TxByHash(ctx, txHash)
+	if err == nil && tx != nil {
+		return tx, nil
+	}
+	receipt, err := s.kaiClient.GetTransactionReceipt(ctx, txHash)
+	if err != nil {
+		return nil, errEtherscanInvalidTxHash
+	}
+	return &types.Transaction{Hash: txHash, Status: receipt.Status}, nil
+}
+
+func (s *Server) etherscanBlockReward(ctx context.Context, c echo.Context) (interface{}, error) {
+	type blockReward struct {
+		BlockNumber          string        `json:"blockNumber"`
+		TimeStamp            string        `json:"timeStamp"`
+		BlockMiner           string        `json:"blockMiner"`
+		BlockReward          string        `json:"blockReward"`
+		Uncles               []interface{} `json:"uncles"`
+		UncleInclusionReward string        `json:"uncleInclusionReward"`
+	}
+	height, err := strconv.ParseUint(c.FormValue("blockno"), 10, 64)
+	if err != nil {
+		return nil, errors.New("Error! Invalid block number")
+	}
+	block, err := s.dbClient.BlockByHeight(ctx, height)
+	if err != nil {
+		block, err = s.kaiClient.BlockByHeight(ctx, height)
+		if err != nil {
+			return nil, errors.New("Error! Block number not found")
+		}
+	}
+	return &blockReward{
+		BlockNumber:          strconv.FormatUint(block.Height, 10),
+		TimeStamp:            strconv.FormatInt(block.Time.Unix(), 10),
+		BlockMiner:           block.ProposerAddress,
+		BlockReward:          block.Rewards,
+		Uncles:               []interface{}{},
+		UncleInclusionReward: "0",
+	}, nil
+}
+
+func (s *Server) etherscanBlockNoByTime(ctx context.Context, c echo.Context) (interface{}, error) {
+	timestamp, err := strconv.ParseInt(c.FormValue("timestamp"), 10, 64)
+	if err != nil {
+		return nil, errors.New("Error! Invalid timestamp")
+	}
+	closest := c.FormValue("closest")
+	if closest != "before" && closest != "after" {
+		return nil, errors.New("Error! Invalid closest parameter, must be before or after")
+	}
+	height, err := s.dbClient.BlockHeightByTime(ctx, time.Unix(timestamp, 0), closest == "before")
+	if err != nil {
+		return nil, errors.New("Error! No closest block found")
+	}
+	return strconv.FormatUint(height, 10), nil
+}
+
+func (s *Server) etherscanGetLogs(ctx context.Context, c echo.Context) (interface{}, error) {
+	type etherscanLog struct {
+		Address          string   `json:"address"`
+		Topics           []string `json:"topics"`
+		Data             string   `json:"data"`
+		BlockNumber      string   `json:"blockNumber"`
+		TimeStamp        string   `json:"timeStamp"`
+		LogIndex         string   `json:"logIndex"`
+		TransactionHash  string   `json:"transactionHash"`
+		TransactionIndex string   `json:"transactionIndex"`
+	}
+	topicFilter, err := parseLogTopicFilter(c.FormValue)
+	if err != nil {
+		return nil, err
+	}
+	fromBlock, toBlock, err := etherscanLogsRange(c.FormValue("fromBlock"), c.FormValue("toBlock"), s.cacheClient.LatestBlockHeight(ctx))
+	if err != nil {
+		return nil, err
+	}
+	query := kardia.FilterQuery{Topics: topicFilter.query()}
+	if addr := c.FormValue("address"); addr != "" {
+		address, err := etherscanAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+		query.Addresses = []common.Address{common.HexToAddress(address)}
+	}
+
+	var logs []*types.Log
+	for from := fromBlock; from <= toBlock && len(logs) < etherscanMaxLogs; from += cfg.FilterLogsInterval {
+		query.FromBlock = from
+		query.ToBlock = from + cfg.FilterLogsInterval - 1
+		if query.ToBlock > toBlock {
+			query.ToBlock = toBlock
+		}
+		partLogs, err := s.kaiClient.GetLogs(ctx, query)
+		if err != nil {
+			s.logger.Warn("Cannot get logs from RPC", zap.Any("query", query), zap.Error(err))
+			return nil, err
+		}
+		for _, l := range partLogs {
+			if topicFilter.match(l.Topics) {
+				logs = append(logs, l)
+			}
+		}
+	}
+	if len(logs) == 0 {
+		return nil, &etherscanNoRecords{message: "No records found"}
+	}
+	if len(logs) > etherscanMaxLogs {
+		logs = logs[:etherscanMaxLogs]
+	}
+
+	blockTimes := make(map[uint64]time.Time)
+	result := make([]*etherscanLog, len(logs))
+	for i, l := range logs {
+		blockTime, ok := blockTimes[l.BlockHeight]
+		if !ok {
+			if block, err := s.dbClient.BlockByHeight(ctx, l.BlockHeight); err == nil {
+				blockTime = block.Time
+			}
+			blockTimes[l.BlockHeight] = blockTime
+		}
+		result[i] = &etherscanLog{
+			Address:          l.Address,
+			Topics:           l.Topics,
+			Data:             l.Data,
+			BlockNumber:      "0x" + strconv.FormatUint(l.BlockHeight, 16),
+			TimeStamp:        "0x" + strconv.FormatInt(blockTime.Unix(), 16),
+			LogIndex:         "0x" + strconv.FormatUint(uint64(l.Index), 16),
+			TransactionHash:  l.TxHash,
+			TransactionIndex: "0x" + strconv.FormatUint(uint64(l.TxIndex), 16),
+		}
+	}
+	return result, nil
+}
+
+// etherscanKaiSupply return the circulating supply of KAI on mainnet, in Hydro
+func (s *Server) etherscanKaiSupply(ctx context.Context, c echo.Context) (interface{}, error) {
+	supply, err := s.kaiClient.GetCirculatingSupply(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return supply.String(), nil
+}
+
+func (s *Server) etherscanKaiPrice(ctx context.Context, c echo.Context) (interface{}, error) {
+	// keys follow Etherscan ethprice response so existing clients can parse it
+	type kaiPrice struct {
+		KaiUSD          string `json:"ethusd"`
+		KaiUSDTimestamp string `json:"ethusd_timestamp"`
+	}
+	tokenInfo, err := s.cacheClient.TokenInfo(ctx)
+	if err != nil {
+		if tokenInfo, err = s.fetchTokenInfo(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return &kaiPrice{
+		KaiUSD:          strconv.FormatFloat(tokenInfo.Price, 'f', -1, 64),
+		KaiUSDTimestamp: strconv.FormatInt(time.Now().Unix(), 10),
+	}, nil
+}
+
+func etherscanAddress(address string) (string, error) {
+	address = strings.TrimSpace(address)
+	if !common.IsHexAddress(address) {
+		return "", errEtherscanInvalidAddress
+	}
+	return common.HexToAddress(address).Hex(), nil
+}
+
+// etherscanPaging convert page/offset params, offset is capped by types.MaximumLimit
+func etherscanPaging(c echo.Context) *types.Pagination {
+	page, err := strconv.Atoi(c.FormValue("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	offset, err := strconv.Atoi(c.FormValue("offset"))
+	if err != nil || offset <= 0 || offset > types.MaximumLimit {
+		offset = types.MaximumLimit
+	}
+	return &types.Pagination{
+		Skip:  (page - 1) * offset,
+		Limit: offset,
+	}
+}
+
+func etherscanBlockRange(c echo.Context) *types.BlockRangeFilter {
+	r := &types.BlockRangeFilter{Asc: c.FormValue("sort") != "desc"}
+	if from, err := strconv.ParseUint(c.FormValue("startblock"), 10, 64); err == nil {
+		r.FromBlock = from
+	}
+	if to, err := strconv.ParseUint(c.FormValue("endblock"), 10, 64); err == nil {
+		r.ToBlock = to
+	}
+	return r
+}
+
+// etherscanBlockNumber parse block number in decimal, hex or "latest" form
+func etherscanBlockNumber(value string, defaultValue, latest uint64) (uint64, error) {
+	switch {
+	case value == "":
+		return defaultValue, nil
+	case value == "latest":
+		return latest, nil
+	case strings.HasPrefix(value, "0x"):
+		n, ok := new(big.Int).SetString(value[2:], 16)
+		if !ok || !n.IsUint64() {
+			return 0, errors.New("Error! Invalid block number")
+		}
+		return n.Uint64(), nil
+	}
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, errors.New("Error! Invalid block number")
+	}
+	return n, nil
+}
+
+// etherscanLogsRange parse the block range of a getLogs request, both ends are required and the range is capped
+// so a single request cannot walk the chain
+func etherscanLogsRange(fromValue, toValue string, latest uint64) (uint64, uint64, error) {
+	if fromValue == "" || toValue == "" {
+		return 0, 0, errEtherscanMissingRange
+	}
+	fromBlock, err := etherscanBlockNumber(fromValue, 0, latest)
+	if err != nil {
+		return 0, 0, err
+	}
+	toBlock, err := etherscanBlockNumber(toValue, latest, latest)
+	if err != nil {
+		return 0, 0, err
+	}
+	if fromBlock > toBlock {
+		return 0, 0, errEtherscanInvalidRange
+	}
+	if toBlock-fromBlock >= etherscanMaxLogsRange {
+		return 0, 0, errEtherscanRangeTooLarge
+	}
+	return fromBlock, toBlock, nil
+}
+
+func etherscanConfirmations(latest, height uint64) string {
+	if latest < height {
+		return "0"
+	}
+	return strconv.FormatUint(latest-height+1, 10)
+}
+
+// logTopicFilter match log topics against topic0..topic3 params combined by
+// topicX_Y_opr operators ("and" by default), evaluated from left to right.
+type logTopicFilter struct {
+	topics [4]string
+	oprs   map[[2]int]string
+}
+
+func parseLogTopicFilter(param func(name string) string) (*logTopicFilter, error) {
+	f := &logTopicFilter{oprs: make(map[[2]int]string)}
+	for i := range f.topics {
+		f.topics[i] = strings.ToLower(param(fmt.Sprintf("topic%d", i)))
+		for j := i + 1; j < len(f.topics); j++ {
+			opr := strings.ToLower(param(fmt.Sprintf("topic%d_%d_opr", i, j)))
+			switch opr {
+			case "":
+				opr = "and"
+			case "and", "or":
+			default:
+				return nil, errors.New("Error! Invalid topic operator, must be and/or")
+			}
+			f.oprs[[2]int{i, j}] = opr
+		}
+	}
+	return f, nil
+}
+
+// query return positional topics to narrow down node filtering, only possible when
+// every used topic is combined with "and"
+func (f *logTopicFilter) query() [][]common.Hash {
+	var (
+		topics [][]common.Hash
+		prev   = -1
+	)
+	for i, t := range f.topics {
+		if t == "" {
+			continue
+		}
+		if prev >= 0 && f.oprs[[2]int{prev, i}] != "and" {
+			return nil
+		}
+		for len(topics) < i {
+			topics = append(topics, nil)
+		}
+		topics = append(topics, []common.Hash{common.HexToHash(t)})
+		prev = i
+	}
+	return topics
+}
+
+func (f *logTopicFilter) match(topics []string) bool {
+	var (
+		matched bool
+		prev    = -1
+	)
+	for i, t := range f.topics {
+		if t == "" {
+			continue
+		}
+		m := i < len(topics) && strings.ToLower(topics[i]) == t
+		switch {
+		case prev < 0:
+			matched = m
+		case f.oprs[[2]int{prev, i}] == "or":
+			matched = matched || m
+		default:
+			matched = matched && m
+		}
+		prev = i
+	}
+	return prev < 0 || matched
+}
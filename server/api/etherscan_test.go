@@ -0,0 +1,138 @@
+// Package api
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+func TestLogTopicFilter(t *testing.T) {
+	const (
+		transfer = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+		approval = "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"
+		alice    = "0x000000000000000000000000c1fe56e3f58d3244f606306611a5d10c8333f1f6"
+		bob      = "0x0000000000000000000000004f36a53dc32272b97ae5ff511387e2741d727bdb"
+	)
+	logs := map[string][]string{
+		"transfer alice->bob": {transfer, alice, bob},
+		"transfer bob->alice": {transfer, bob, alice},
+		"approval alice->bob": {approval, alice, bob},
+		"anonymous":           nil,
+	}
+	type testCase struct {
+		name     string
+		params   map[string]string
+		expected []string
+		query    int
+	}
+	cases := []testCase{
+		{
+			name:     "no topics match every log",
+			params:   map[string]string{},
+			expected: []string{"anonymous", "approval alice->bob", "transfer alice->bob", "transfer bob->alice"},
+		},
+		{
+			name:     "and by default",
+			params:   map[string]string{"topic0": transfer, "topic1": alice},
+			expected: []string{"transfer alice->bob"},
+			query:    2,
+		},
+		{
+			name:     "or operator",
+			params:   map[string]string{"topic0": approval, "topic2": alice, "topic0_2_opr": "or"},
+			expected: []string{"approval alice->bob", "transfer bob->alice"},
+		},
+		{
+			name:     "left to right evaluation",
+			params:   map[string]string{"topic0": approval, "topic1": bob, "topic2": bob, "topic0_1_opr": "or", "topic1_2_opr": "and"},
+			expected: []string{"approval alice->bob"},
+		},
+		{
+			name:     "case insensitive",
+			params:   map[string]string{"topic0": "0xDDF252AD1BE2C89B69C2B068FC378DAA952BA7F163C4A11628F55A4DF523B3EF", "topic2": alice},
+			expected: []string{"transfer bob->alice"},
+			query:    3,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f, err := parseLogTopicFilter(func(name string) string { return c.params[name] })
+			assert.Nil(t, err)
+			var matched []string
+			for _, name := range []string{"anonymous", "approval alice->bob", "transfer alice->bob", "transfer bob->alice"} {
+				if f.match(logs[name]) {
+					matched = append(matched, name)
+				}
+			}
+			assert.Equal(t, c.expected, matched)
+			assert.Equal(t, c.query, len(f.query()))
+		})
+	}
+
+	_, err := parseLogTopicFilter(func(name string) string {
+		if name == "topic0_1_opr" {
+			return "xor"
+		}
+		return ""
+	})
+	assert.NotNil(t, err)
+}
+
+func TestEtherscanLogsRange(t *testing.T) {
+	from, to, err := etherscanLogsRange("100", "latest", 5000)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), from)
+	assert.Equal(t, uint64(5000), to)
+
+	from, to, err = etherscanLogsRange("0x10", "0x20", 5000)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(16), from)
+	assert.Equal(t, uint64(32), to)
+
+	_, _, err = etherscanLogsRange("", "latest", 5000)
+	assert.Equal(t, errEtherscanMissingRange, err)
+	_, _, err = etherscanLogsRange("100", "", 5000)
+	assert.Equal(t, errEtherscanMissingRange, err)
+	_, _, err = etherscanLogsRange("200", "100", 5000)
+	assert.Equal(t, errEtherscanInvalidRange, err)
+	_, _, err = etherscanLogsRange("0", "latest", etherscanMaxLogsRange)
+	assert.Equal(t, errEtherscanRangeTooLarge, err)
+	_, _, err = etherscanLogsRange("1", "latest", etherscanMaxLogsRange)
+	assert.NoError(t, err)
+}
+
+func TestEtherscanRejectedRequests(t *testing.T) {
+	s := newFakeServer()
+	call := func(query url.Values, apiKey *types.APIKey) *EtherscanResponse {
+		_, rec := callHandler(t, func(c echo.Context) error {
+			if apiKey != nil {
+				c.Set(ContextAPIKey, apiKey)
+			}
+			return s.Etherscan(c)
+		}, newRequest(http.MethodGet, "/api?"+query.Encode(), nil, nil))
+		var resp EtherscanResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return &resp
+	}
+
+	addresses := strings.TrimSuffix(strings.Repeat("0xc1fe56E3F58D3244F606306611a5d10c8333f1f6,", etherscanMaxBalanceAddresses+1), ",")
+	resp := call(url.Values{"module": {"account"}, "action": {"balancemulti"}, "address": {addresses}}, nil)
+	assert.Equal(t, etherscanStatusNotOK, resp.Status)
+	assert.Equal(t, errEtherscanTooManyAddrs.Error(), resp.Result)
+
+	verify := url.Values{"module": {"contract"}, "action": {"verifysourcecode"}, "contractaddress": {"0xinvalid"}}
+	resp = call(verify, nil)
+	assert.Equal(t, errEtherscanMissingAPIKey.Error(), resp.Result)
+	// with a key the request goes on to verification
+	resp = call(verify, &types.APIKey{ID: "k1"})
+	assert.Equal(t, errEtherscanInvalidAddress.Error(), resp.Result)
+}
@@ -0,0 +1,468 @@
+// Package api
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/labstack/echo"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+const (
+	// exportFlushRows is the number of rows written between two flushes of the response
+	exportFlushRows = 500
+	// exportStakingBatchRows is the number of staking rows whose internal calls are loaded by one query
+	exportStakingBatchRows = 100
+
+	// headerExportError is sent as trailer when the export stops before its last row, as status is already sent
+	headerExportError = "X-Export-Error"
+)
+
+var ErrInvalidExportParams = errors.New("invalid export params")
+
+type IExport interface {
+	ExportAddressTxs(c echo.Context) error
+	ExportAddressTokenTransfers(c echo.Context) error
+	ExportAddressStaking(c echo.Context) error
+}
+
+func bindExportAPIs(gr *echo.Group, srv RestServer) {
+	apis := []restDefinition{
+		{
+			method: echo.GET,
+			// Query params: ?format=csv|ndjson&startTime=1620000000&endTime=1630000000&fromBlock=1&toBlock=100&limit=1000
+			// startTime and endTime also accept YYYY-MM-DD
+			path:        "/addresses/:address/export/txs",
+			fn:          srv.ExportAddressTxs,
+			middlewares: nil,
+		},
+		{
+			method: echo.GET,
+			// Query params: same as /export/txs and ?type=KRC20|KRC721
+			path:        "/addresses/:address/export/token-transfers",
+			fn:          srv.ExportAddressTokenTransfers,
+			middlewares: nil,
+		},
+		{
+			method: echo.GET,
+			// Query params: same as /export/txs
+			path:        "/addresses/:address/export/staking",
+			fn:          srv.ExportAddressStaking,
+			middlewares: nil,
+		},
+	}
+	for _, api := range apis {
+		gr.Add(api.method, api.path, api.fn, api.middlewares...)
+	}
+}
+
+type exportTxRow struct {
+	Hash            string `json:"hash"`
+	BlockNumber     uint64 `json:"blockNumber"`
+	Time            int64  `json:"time"`
+	From            string `json:"from"`
+	To              string `json:"to"`
+	ContractAddress string `json:"contractAddress,omitempty"`
+	Method          string `json:"method"`
+	Value           string `json:"value"`
+	Status          uint   `json:"status"`
+	GasPrice        uint64 `json:"gasPrice"`
+	GasUsed         uint64 `json:"gasUsed"`
+	TxFee           string `json:"txFee"`
+}
+
+var exportTxHeader = []string{"hash", "blockNumber", "time", "from", "to", "contractAddress", "method", "value",
+	"status", "gasPrice", "gasUsed", "txFee"}
+
+func (r *exportTxRow) csvRecord() []string {
+	return []string{r.Hash, strconv.FormatUint(r.BlockNumber, 10), strconv.FormatInt(r.Time, 10), r.From, r.To,
+		r.ContractAddress, r.Method, r.Value, strconv.FormatUint(uint64(r.Status), 10),
+		strconv.FormatUint(r.GasPrice, 10), strconv.FormatUint(r.GasUsed, 10), r.TxFee}
+}
+
+type exportTokenTransferRow struct {
+	TxHash          string `json:"txHash"`
+	BlockHeight     uint64 `json:"blockHeight"`
+	Time            int64  `json:"time"`
+	ContractAddress string `json:"contractAddress"`
+	TokenType       string `json:"tokenType"`
+	TokenSymbol     string `json:"tokenSymbol"`
+	TokenDecimals   int64  `json:"tokenDecimals"`
+	From            string `json:"from"`
+	To              string `json:"to"`
+	Value           string `json:"value"`
+	TokenID         string `json:"tokenID,omitempty"`
+}
+
+var exportTokenTransferHeader = []string{"txHash", "blockHeight", "time", "contractAddress", "tokenType", "tokenSymbol",
+	"tokenDecimals", "from", "to", "value", "tokenID"}
+
+func (r *exportTokenTransferRow) csvRecord() []string {
+	return []string{r.TxHash, strconv.FormatUint(r.BlockHeight, 10), strconv.FormatInt(r.Time, 10), r.ContractAddress,
+		r.TokenType, r.TokenSymbol, strconv.FormatInt(r.TokenDecimals, 10), r.From, r.To, r.Value, r.TokenID}
+}
+
+type exportStakingRow struct {
+	Hash          string `json:"hash"`
+	BlockNumber   uint64 `json:"blockNumber"`
+	Time          int64  `json:"time"`
+	Validator     string `json:"validator"`
+	ValidatorName string `json:"validatorName"`
+	Method        string `json:"method"`
+	Amount        string `json:"amount"`
+	Status        uint   `json:"status"`
+	TxFee         string `json:"txFee"`
+}
+
+var exportStakingHeader = []string{"hash", "blockNumber", "time", "validator", "validatorName", "method", "amount",
+	"status", "txFee"}
+
+func (r *exportStakingRow) csvRecord() []string {
+	return []string{r.Hash, strconv.FormatUint(r.BlockNumber, 10), strconv.FormatInt(r.Time, 10), r.Validator,
+		r.ValidatorName, r.Method, r.Amount, strconv.FormatUint(uint64(r.Status), 10), r.TxFee}
+}
+
+type exportRow interface {
+	csvRecord() []string
+}
+
+// exportWriter write rows to response as CSV with header or as one JSON object per line
+type exportWriter struct {
+	w    *echo.Response
+	csv  *csv.Writer
+	json *json.Encoder
+	rows int
+}
+
+func newExportWriter(c echo.Context, format, name string, header []string) (*exportWriter, error) {
+	w := c.Response()
+	ew := &exportWriter{w: w}
+	switch format {
+	case types.ExportFormatCSV:
+		w.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+		ew.csv = csv.NewWriter(w)
+	case types.ExportFormatNDJSON:
+		w.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+		ew.json = json.NewEncoder(w)
+	default:
+		return nil, ErrInvalidExportParams
+	}
+	w.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", name+"."+format))
+	w.Header().Set("X-Export-Limit", strconv.Itoa(types.MaxExportRows))
+	w.Header().Set("Trailer", headerExportError)
+	w.WriteHeader(http.StatusOK)
+	if ew.csv != nil {
+		if err := ew.csv.Write(header); err != nil {
+			return nil, err
+		}
+	}
+	return ew, nil
+}
+
+func (ew *exportWriter) write(row exportRow) error {
+	var err error
+	if ew.csv != nil {
+		err = ew.csv.Write(row.csvRecord())
+	} else {
+		err = ew.json.Encode(row)
+	}
+	if err != nil {
+		return err
+	}
+	ew.rows++
+	if ew.rows%exportFlushRows == 0 {
+		return ew.flush()
+	}
+	return nil
+}
+
+func (ew *exportWriter) flush() error {
+	if ew.csv != nil {
+		ew.csv.Flush()
+		if err := ew.csv.Error(); err != nil {
+			return err
+		}
+	}
+	ew.w.Flush()
+	return nil
+}
+
+// parseExportFilter read address, format, time and block range of an export request
+func parseExportFilter(c echo.Context) (*types.ExportFilter, string, error) {
+	address := c.Param("address")
+	if !common.IsHexAddress(address) {
+		return nil, "", ErrInvalidExportParams
+	}
+	filter := &types.ExportFilter{Address: common.HexToAddress(address).Hex()}
+	format := c.QueryParam("format")
+	if format == "" {
+		format = types.ExportFormatCSV
+	}
+	if format != types.ExportFormatCSV && format != types.ExportFormatNDJSON {
+		return nil, "", ErrInvalidExportParams
+	}
+	var err error
+	if v := c.QueryParam("startTime"); v != "" {
+		if filter.StartTime, err = parseChartTime(v); err != nil {
+			return nil, "", ErrInvalidExportParams
+		}
+	}
+	if v := c.QueryParam("endTime"); v != "" {
+		if filter.EndTime, err = parseChartTime(v); err != nil {
+			return nil, "", ErrInvalidExportParams
+		}
+	}
+	if v := c.QueryParam("fromBlock"); v != "" {
+		if filter.FromBlock, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return nil, "", ErrInvalidExportParams
+		}
+	}
+	if v := c.QueryParam("toBlock"); v != "" {
+		if filter.ToBlock, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return nil, "", ErrInvalidExportParams
+		}
+	}
+	if v := c.QueryParam("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			return nil, "", ErrInvalidExportParams
+		}
+	}
+	if (!filter.EndTime.IsZero() && filter.EndTime.Before(filter.StartTime)) ||
+		(filter.ToBlock != 0 && filter.ToBlock < filter.FromBlock) {
+		return nil, "", ErrInvalidExportParams
+	}
+	filter.Sanitize()
+	return filter, format, nil
+}
+
+// exportMethod return decoded method name of tx, or its method id when input cannot be decoded
+func exportMethod(tx *types.Transaction) string {
+	if tx.DecodedInputData != nil && tx.DecodedInputData.MethodName != "" {
+		return tx.DecodedInputData.MethodName
+	}
+	if len(tx.InputData) >= 10 {
+		return tx.InputData[:10]
+	}
+	return ""
+}
+
+// ExportAddressTxs stream normal txs of address, oldest first
+func (s *Server) ExportAddressTxs(c echo.Context) error {
+	ctx := c.Request().Context()
+	filter, format, err := parseExportFilter(c)
+	if err != nil {
+		return Invalid.Build(c)
+	}
+	ew, err := newExportWriter(c, format, filter.Address+"-txs", exportTxHeader)
+	if err != nil {
+		return Invalid.Build(c)
+	}
+	err = s.dbClient.ExportTxs(ctx, filter, func(tx *types.Transaction) error {
+		return ew.write(&exportTxRow{
+			Hash:            tx.Hash,
+			BlockNumber:     tx.BlockNumber,
+			Time:            tx.Time.Unix(),
+			From:            tx.From,
+			To:              tx.To,
+			ContractAddress: tx.ContractAddress,
+			Method:          exportMethod(tx),
+			Value:           tx.Value,
+			Status:          tx.Status,
+			GasPrice:        tx.GasPrice,
+			GasUsed:         tx.GasUsed,
+			TxFee:           tx.TxFee,
+		})
+	})
+	return s.finishExport(ew, filter, err)
+}
+
+// ExportAddressTokenTransfers stream KRC20 and KRC721 transfers from or to address, oldest first
+func (s *Server) ExportAddressTokenTransfers(c echo.Context) error {
+	ctx := c.Request().Context()
+	filter, format, err := parseExportFilter(c)
+	if err != nil {
+		return Invalid.Build(c)
+	}
+	switch filter.TokenType = c.QueryParam("type"); filter.TokenType {
+	case "", cfg.SMCTypeKRC20, cfg.SMCTypeKRC721:
+	default:
+		return Invalid.Build(c)
+	}
+	ew, err := newExportWriter(c, format, filter.Address+"-token-transfers", exportTokenTransferHeader)
+	if err != nil {
+		return Invalid.Build(c)
+	}
+	tokens := make(map[string]*types.KRCTokenInfo)
+	err = s.dbClient.ExportTokenTransfers(ctx, filter, func(transfer *types.TokenTransfer) error {
+		row := &exportTokenTransferRow{
+			TxHash:          transfer.TransactionHash,
+			BlockHeight:     transfer.BlockHeight,
+			Time:            transfer.Time.Unix(),
+			ContractAddress: transfer.Contract,
+			From:            transfer.From,
+			To:              transfer.To,
+			Value:           transfer.Value,
+			TokenID:         transfer.TokenID,
+		}
+		if tokenInfo := s.exportTokenInfo(ctx, tokens, transfer.Contract); tokenInfo != nil {
+			row.TokenType = tokenInfo.TokenType
+			row.TokenSymbol = tokenInfo.TokenSymbol
+			row.TokenDecimals = tokenInfo.Decimals
+		}
+		return ew.write(row)
+	})
+	return s.finishExport(ew, filter, err)
+}
+
+// ExportAddressStaking stream staking actions sent by address to staking and validator contracts, oldest first
+func (s *Server) ExportAddressStaking(c echo.Context) error {
+	ctx := c.Request().Context()
+	filter, format, err := parseExportFilter(c)
+	if err != nil {
+		return Invalid.Build(c)
+	}
+	validators := s.getValidatorsAddressAndRole(ctx)
+	filter.ToAddresses = []string{cfg.StakingContractAddr}
+	for smcAddress := range validators {
+		filter.ToAddresses = append(filter.ToAddresses, smcAddress)
+	}
+	ew, err := newExportWriter(c, format, filter.Address+"-staking", exportStakingHeader)
+	if err != nil {
+		return Invalid.Build(c)
+	}
+	// txs are buffered so internal calls of withdrawals are loaded once per batch
+	batch := make([]*types.Transaction, 0, exportStakingBatchRows)
+	writeBatch := func() error {
+		calls, err := s.exportStakingCredits(ctx, batch)
+		if err != nil {
+			return err
+		}
+		for _, tx := range batch {
+			row := &exportStakingRow{
+				Hash:        tx.Hash,
+				BlockNumber: tx.BlockNumber,
+				Time:        tx.Time.Unix(),
+				Validator:   tx.To,
+				Method:      exportMethod(tx),
+				Amount:      stakingAmount(tx, calls[tx.Hash]),
+				Status:      tx.Status,
+				TxFee:       tx.TxFee,
+			}
+			if v, ok := validators[tx.To]; ok {
+				row.ValidatorName = v.Name
+			}
+			if err := ew.write(row); err != nil {
+				return err
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+	err = s.dbClient.ExportTxs(ctx, filter, func(tx *types.Transaction) error {
+		batch = append(batch, tx)
+		if len(batch) < exportStakingBatchRows {
+			return nil
+		}
+		return writeBatch()
+	})
+	if err == nil {
+		err = writeBatch()
+	}
+	return s.finishExport(ew, filter, err)
+}
+
+// exportStakingCredits return internal calls of withdrawal txs by tx hash, other staking actions do not need them
+func (s *Server) exportStakingCredits(ctx context.Context, txs []*types.Transaction) (map[string][]*types.InternalCall, error) {
+	var hashes []string
+	for _, tx := range txs {
+		if tx.Status == types.TransactionStatusSuccess && stakingWithdrawals[exportMethod(tx)] {
+			hashes = append(hashes, tx.Hash)
+		}
+	}
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+	return s.dbClient.InternalCallsByTxHashes(ctx, hashes)
+}
+
+// stakingWithdrawals are methods which pay the sender through internal calls
+var stakingWithdrawals = map[string]bool{
+	"withdraw":           true,
+	"withdrawRewards":    true,
+	"withdrawCommission": true,
+}
+
+// stakingAmount return KAI moved by a staking action in hydro. Delegations carry the amount in value,
+// undelegations in input or Undelegate event, withdrawals are credits of calls to the sender.
+func stakingAmount(tx *types.Transaction, calls []*types.InternalCall) string {
+	if tx.Status != types.TransactionStatusSuccess {
+		return "0"
+	}
+	method := exportMethod(tx)
+	switch {
+	case method == "undelegate" || method == "undelegateWithAmount":
+		if tx.DecodedInputData != nil {
+			if amount, ok := tx.DecodedInputData.Arguments["_amount"].(string); ok {
+				return amount
+			}
+		}
+		// undelegate without amount withdraws the whole stake
+		for _, log := range tx.Logs {
+			if log.MethodName != "Undelegate" {
+				continue
+			}
+			if amount, ok := log.Arguments["_amount"].(string); ok {
+				return amount
+			}
+		}
+		return "0"
+	case stakingWithdrawals[method]:
+		total := new(big.Int)
+		for _, call := range calls {
+			if call.To != tx.From || call.Error != "" {
+				continue
+			}
+			if value, ok := new(big.Int).SetString(call.Value, 10); ok {
+				total.Add(total, value)
+			}
+		}
+		return total.String()
+	}
+	return tx.Value
+}
+
+// exportTokenInfo get token info once per contract of an export
+func (s *Server) exportTokenInfo(ctx context.Context, tokens map[string]*types.KRCTokenInfo, contract string) *types.KRCTokenInfo {
+	tokenInfo, ok := tokens[contract]
+	if !ok {
+		tokenInfo, _ = s.getTokenInfo(ctx, contract)
+		tokens[contract] = tokenInfo
+	}
+	return tokenInfo
+}
+
+// finishExport flush remaining rows. Response status is already sent, so an export which stops early
+// is reported by the X-Export-Error trailer, clients continue from the block of the last row.
+func (s *Server) finishExport(ew *exportWriter, filter *types.ExportFilter, err error) error {
+	// rows written before an error are still sent
+	if flushErr := ew.flush(); err == nil {
+		err = flushErr
+	}
+	if err == nil || err == context.Canceled {
+		return nil
+	}
+	s.logger.Warn("Cannot complete export", zap.String("address", filter.Address), zap.Int("rows", ew.rows), zap.Error(err))
+	ew.w.Header().Set(headerExportError, fmt.Sprintf("incomplete export, stopped after %d rows", ew.rows))
+	return nil
+}
@@ -0,0 +1,142 @@
+// Package api
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+func newExportContext(query string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("address")
+	c.SetParamValues("0xc1fe56e3f58d3244f606306611a5d10c8333f1f6")
+	return c, rec
+}
+
+func TestParseExportFilter(t *testing.T) {
+	c, _ := newExportContext("format=ndjson&startTime=2021-05-01&fromBlock=10&toBlock=20&limit=100000")
+	filter, format, err := parseExportFilter(c)
+	require.NoError(t, err)
+	assert.Equal(t, types.ExportFormatNDJSON, format)
+	assert.Equal(t, "0xc1fe56E3F58D3244F606306611a5d10c8333f1f6", filter.Address)
+	assert.Equal(t, int64(1619827200), filter.StartTime.Unix())
+	assert.Equal(t, uint64(10), filter.FromBlock)
+	assert.Equal(t, uint64(20), filter.ToBlock)
+	assert.Equal(t, types.MaxExportRows, filter.Limit)
+
+	for _, query := range []string{"format=xml", "fromBlock=20&toBlock=10", "startTime=abc", "limit=ten"} {
+		c, _ := newExportContext(query)
+		_, _, err := parseExportFilter(c)
+		assert.Equal(t, ErrInvalidExportParams, err, query)
+	}
+}
+
+func TestExportWriter(t *testing.T) {
+	row := &exportTxRow{Hash: "0x01", BlockNumber: 1, Time: 1620000000, From: "0xa", To: "0xb", Method: "transfer",
+		Value: "10", Status: 1, GasPrice: 1000000000, GasUsed: 21000, TxFee: "21000000000000"}
+
+	c, rec := newExportContext("")
+	ew, err := newExportWriter(c, types.ExportFormatCSV, "txs", exportTxHeader)
+	require.NoError(t, err)
+	require.NoError(t, ew.write(row))
+	require.NoError(t, ew.flush())
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, `attachment; filename="txs.csv"`, rec.Header().Get(echo.HeaderContentDisposition))
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, strings.Join(exportTxHeader, ","), lines[0])
+	assert.Equal(t, "0x01,1,1620000000,0xa,0xb,,transfer,10,1,1000000000,21000,21000000000000", lines[1])
+
+	c, rec = newExportContext("")
+	ew, err = newExportWriter(c, types.ExportFormatNDJSON, "txs", exportTxHeader)
+	require.NoError(t, err)
+	require.NoError(t, ew.write(row))
+	require.NoError(t, ew.write(row))
+	require.NoError(t, ew.flush())
+	lines = strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"method":"transfer"`)
+	assert.Contains(t, lines[0], `"txFee":"21000000000000"`)
+}
+
+func TestExportMethod(t *testing.T) {
+	assert.Equal(t, "delegate", exportMethod(&types.Transaction{InputData: "0x5c19a95c", DecodedInputData: &types.FunctionCall{MethodName: "delegate"}}))
+	assert.Equal(t, "0x5c19a95c", exportMethod(&types.Transaction{InputData: "0x5c19a95c0000"}))
+	assert.Equal(t, "", exportMethod(&types.Transaction{InputData: "0x"}))
+}
+
+func TestStakingAmount(t *testing.T) {
+	delegator := "0xc1fe56E3F58D3244F606306611a5d10c8333f1f6"
+	success := func(method string, args map[string]interface{}) *types.Transaction {
+		return &types.Transaction{From: delegator, Value: "0", Status: types.TransactionStatusSuccess,
+			DecodedInputData: &types.FunctionCall{MethodName: method, Arguments: args}}
+	}
+
+	delegate := success("delegate", nil)
+	delegate.Value = "1000"
+	assert.Equal(t, "1000", stakingAmount(delegate, nil))
+	assert.Equal(t, "300", stakingAmount(success("undelegateWithAmount", map[string]interface{}{"_amount": "300"}), nil))
+
+	undelegateAll := success("undelegate", map[string]interface{}{})
+	undelegateAll.Logs = []types.Log{{MethodName: "Delegate"}, {MethodName: "Undelegate", Arguments: map[string]interface{}{"_amount": "700"}}}
+	assert.Equal(t, "700", stakingAmount(undelegateAll, nil))
+
+	calls := []*types.InternalCall{
+		{From: "0xValSMC", To: "0x0000000000000000000000000000000000001337", Value: "5"},
+		{From: "0x0000000000000000000000000000000000001337", To: delegator, Value: "40"},
+		{From: "0x0000000000000000000000000000000000001337", To: delegator, Value: "2"},
+		{From: "0x0000000000000000000000000000000000001337", To: delegator, Value: "100", Error: "execution reverted"},
+	}
+	assert.Equal(t, "42", stakingAmount(success("withdrawRewards", nil), calls))
+	assert.Equal(t, "0", stakingAmount(success("withdraw", nil), nil))
+
+	failed := success("undelegateWithAmount", map[string]interface{}{"_amount": "300"})
+	failed.Status = types.TransactionStatusFailed
+	assert.Equal(t, "0", stakingAmount(failed, nil))
+}
+
+func TestExportAddressStaking_BatchCredits(t *testing.T) {
+	delegator := "0xc1fe56E3F58D3244F606306611a5d10c8333f1f6"
+	f := newFakeServer()
+	for i := 0; i < exportStakingBatchRows+10; i++ {
+		hash := "0x" + strconv.Itoa(i)
+		f.db.txs = append(f.db.txs, &types.Transaction{Hash: hash, From: delegator, Status: types.TransactionStatusSuccess,
+			DecodedInputData: &types.FunctionCall{MethodName: "withdrawRewards"}})
+		f.db.internalCalls = append(f.db.internalCalls, &types.InternalCall{TxHash: hash, To: delegator, Value: strconv.Itoa(i)})
+	}
+
+	c, rec := newExportContext("format=ndjson")
+	require.NoError(t, f.ExportAddressStaking(c))
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	require.Len(t, lines, exportStakingBatchRows+10)
+	assert.Contains(t, lines[7], `"amount":"7"`)
+	assert.Contains(t, lines[exportStakingBatchRows+5], `"amount":"105"`)
+	assert.Equal(t, 2, f.db.calls["InternalCallsByTxHashes"])
+	assert.Empty(t, rec.Result().Trailer.Get(headerExportError))
+}
+
+func TestExport_IncompleteTrailer(t *testing.T) {
+	f := newFakeServer()
+	f.db.txs = []*types.Transaction{{Hash: "0x01"}, {Hash: "0x02"}}
+	f.db.exportErr = errors.New("cursor id not found")
+
+	c, rec := newExportContext("format=csv")
+	require.NoError(t, f.ExportAddressTxs(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	// rows read before the error are sent
+	assert.Len(t, strings.Split(strings.TrimSpace(rec.Body.String()), "\n"), 3)
+	assert.Equal(t, "incomplete export, stopped after 2 rows", rec.Result().Trailer.Get(headerExportError))
+}
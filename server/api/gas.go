@@ -0,0 +1,54 @@
+// Package api
+package api
+
+import (
+	"github.com/labstack/echo"
+	"go.uber.org/zap"
+)
+
+type IGas interface {
+	GasOracle(c echo.Context) error
+	GasHistory(c echo.Context) error
+}
+
+func bindGasAPIs(gr *echo.Group, srv RestServer) {
+	apis := []restDefinition{
+		{
+			method:      echo.GET,
+			path:        "/gas/oracle",
+			fn:          srv.GasOracle,
+			middlewares: nil,
+		},
+		{
+			method:      echo.GET,
+			path:        "/gas/history",
+			fn:          srv.GasHistory,
+			middlewares: nil,
+		},
+	}
+	for _, api := range apis {
+		gr.Add(api.method, api.path, api.fn, api.middlewares...)
+	}
+}
+
+// GasOracle return safe, standard and fast gas price suggestion, which is refreshed by grabber
+func (s *Server) GasOracle(c echo.Context) error {
+	ctx := c.Request().Context()
+	oracle, err := s.cacheClient.GasOracle(ctx)
+	if err != nil {
+		s.logger.Warn("Cannot get gas oracle from cache", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	return OK.SetData(oracle).Build(c)
+}
+
+// GasHistory return gas price percentiles and fullness of blocks in oracle window, latest first
+func (s *Server) GasHistory(c echo.Context) error {
+	ctx := c.Request().Context()
+	history, err := s.cacheClient.BlockGasHistory(ctx)
+	if err != nil {
+		s.logger.Warn("Cannot get gas history from cache", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	return OK.SetData(history).Build(c)
+}
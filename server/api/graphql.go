@@ -0,0 +1,255 @@
+// Package api
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/labstack/echo"
+	"go.uber.org/zap"
+)
+
+// graphQLUnboundedListSize is the estimated size of list fields without limit argument, e.g. logs of a tx
+const graphQLUnboundedListSize = 20
+
+var (
+	ErrGraphQLQueryTooDeep    = errors.New("query is too deep")
+	ErrGraphQLQueryTooComplex = errors.New("query is too complex")
+)
+
+type IGraphQL interface {
+	GraphQL(c echo.Context) error
+}
+
+func bindGraphQLAPIs(gr *echo.Group, srv RestServer) {
+	apis := []restDefinition{
+		{
+			method: echo.GET,
+			// Query params: ?query={...}&operationName=&variables={...}
+			path:        "/graphql",
+			fn:          srv.GraphQL,
+			middlewares: nil,
+		},
+		{
+			method:      echo.POST,
+			path:        "/graphql",
+			fn:          srv.GraphQL,
+			middlewares: nil,
+		},
+	}
+	for _, api := range apis {
+		gr.Add(api.method, api.path, api.fn, api.middlewares...)
+	}
+}
+
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQL execute a query over blocks, txs, addresses, tokens and validators.
+// Response follow the GraphQL convention {data, errors} instead of the REST envelope.
+func (s *Server) GraphQL(c echo.Context) error {
+	var req graphQLRequest
+	if c.Request().Method == echo.GET {
+		req.Query = c.QueryParam("query")
+		req.OperationName = c.QueryParam("operationName")
+		if variables := c.QueryParam("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				return graphQLError(c, err)
+			}
+		}
+	} else if err := c.Bind(&req); err != nil {
+		return graphQLError(c, err)
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		return graphQLError(c, errors.New("query is required"))
+	}
+
+	schema, err := s.graphQL()
+	if err != nil {
+		s.logger.Error("Cannot build graphql schema", zap.Error(err))
+		return InternalServer.Build(c)
+	}
+	if err := checkGraphQLLimits(&schema, &req, s.graphQLMaxDepth, s.graphQLMaxComplexity); err != nil {
+		return graphQLError(c, err)
+	}
+
+	ctx := context.WithValue(c.Request().Context(), graphQLLoadersKey{}, s.newGraphQLLoaders())
+	result := graphql.Do(graphql.Params{
+		Schema:         schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        ctx,
+	})
+	return c.JSON(http.StatusOK, result)
+}
+
+func (s *Server) graphQL() (graphql.Schema, error) {
+	var err error
+	s.graphQLOnce.Do(func() {
+		s.graphQLSchema, err = s.newGraphQLSchema()
+	})
+	return s.graphQLSchema, err
+}
+
+func graphQLError(c echo.Context, err error) error {
+	return c.JSON(http.StatusBadRequest, &graphql.Result{
+		Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(err.Error())},
+	})
+}
+
+// checkGraphQLLimits reject queries deeper or more complex than allowed before they are executed.
+// Every field cost 1, list fields multiply the cost of their selection by the requested limit.
+// Introspection fields are free so tools can load the schema. Invalid queries are left to the executor.
+func checkGraphQLLimits(schema *graphql.Schema, req *graphQLRequest, maxDepth, maxComplexity int) error {
+	doc, err := parser.Parse(parser.ParseParams{Source: req.Query})
+	if err != nil {
+		return nil
+	}
+	w := &graphQLWalker{
+		schema:    schema,
+		variables: req.Variables,
+		fragments: make(map[string]*ast.FragmentDefinition),
+		visiting:  make(map[string]bool),
+	}
+	var op *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.FragmentDefinition:
+			w.fragments[def.Name.Value] = def
+		case *ast.OperationDefinition:
+			if req.OperationName == "" || (def.Name != nil && def.Name.Value == req.OperationName) {
+				op = def
+			}
+		}
+	}
+	if op == nil || op.Operation != ast.OperationTypeQuery {
+		return nil
+	}
+	complexity, depth := w.selectionSet(schema.QueryType(), op.SelectionSet, 1)
+	if maxDepth > 0 && depth > maxDepth {
+		return fmt.Errorf("%v: depth %d exceeds %d", ErrGraphQLQueryTooDeep, depth, maxDepth)
+	}
+	if maxComplexity > 0 && complexity > maxComplexity {
+		return fmt.Errorf("%v: complexity %d exceeds %d", ErrGraphQLQueryTooComplex, complexity, maxComplexity)
+	}
+	return nil
+}
+
+type graphQLWalker struct {
+	schema    *graphql.Schema
+	variables map[string]interface{}
+	fragments map[string]*ast.FragmentDefinition
+	visiting  map[string]bool
+}
+
+// selectionSet return the cost and the depth of a selection on parent type
+func (w *graphQLWalker) selectionSet(parent graphql.Type, set *ast.SelectionSet, depth int) (int, int) {
+	if set == nil {
+		return 0, depth - 1
+	}
+	var (
+		cost     int
+		maxDepth = depth
+	)
+	add := func(c, d int) {
+		cost += c
+		if d > maxDepth {
+			maxDepth = d
+		}
+	}
+	for _, selection := range set.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			add(w.field(parent, selection, depth))
+		case *ast.InlineFragment:
+			add(w.selectionSet(w.typeCondition(parent, selection.TypeCondition), selection.SelectionSet, depth))
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			fragment, ok := w.fragments[name]
+			if !ok || w.visiting[name] {
+				continue
+			}
+			w.visiting[name] = true
+			add(w.selectionSet(w.typeCondition(parent, fragment.TypeCondition), fragment.SelectionSet, depth))
+			w.visiting[name] = false
+		}
+	}
+	return cost, maxDepth
+}
+
+func (w *graphQLWalker) field(parent graphql.Type, field *ast.Field, depth int) (int, int) {
+	if strings.HasPrefix(field.Name.Value, "__") {
+		return 0, 0
+	}
+	object, ok := parent.(*graphql.Object)
+	if !ok {
+		return 1, depth
+	}
+	def, ok := object.Fields()[field.Name.Value]
+	if !ok {
+		return 1, depth
+	}
+	childCost, childDepth := w.selectionSet(graphql.GetNamed(def.Type).(graphql.Type), field.SelectionSet, depth+1)
+	if _, isList := graphql.GetNullable(def.Type).(*graphql.List); isList {
+		childCost *= w.listSize(def, field)
+	}
+	return 1 + childCost, childDepth
+}
+
+// listSize is the limit argument of the field, its default value, or graphQLUnboundedListSize
+func (w *graphQLWalker) listSize(def *graphql.FieldDefinition, field *ast.Field) int {
+	size := graphQLUnboundedListSize
+	for _, arg := range def.Args {
+		if arg.Name() == "limit" {
+			if v, ok := arg.DefaultValue.(int); ok {
+				size = v
+			}
+		}
+	}
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "limit" {
+			continue
+		}
+		switch v := arg.Value.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.Atoi(v.Value); err == nil {
+				size = n
+			}
+		case *ast.Variable:
+			// variables decoded from JSON are float64
+			switch n := w.variables[v.Name.Value].(type) {
+			case float64:
+				size = int(n)
+			case int:
+				size = n
+			}
+		}
+	}
+	if size < 1 {
+		size = 1
+	}
+	return size
+}
+
+func (w *graphQLWalker) typeCondition(parent graphql.Type, named *ast.Named) graphql.Type {
+	if named == nil {
+		return parent
+	}
+	if t := w.schema.Type(named.Name.Value); t != nil {
+		return t
+	}
+	return parent
+}
@@ -0,0 +1,230 @@
+// Package api
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/kardiachain/kardia-explorer-backend/db"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+type graphQLLoadersKey struct{}
+
+// batchFn fetch values of keys in one query, keys without value are missing from result
+type batchFn func(ctx context.Context, keys []string) (map[string]interface{}, error)
+
+// loader batch lookups of a GraphQL request. Resolvers call load and return the thunk, the executor
+// resolve every field of a level before calling thunks, so the first call fetch all queued keys at once.
+// Values are cached for the request lifetime.
+type loader struct {
+	mu     sync.Mutex
+	fetch  batchFn
+	queue  []string
+	values map[string]interface{}
+	errs   map[string]error
+}
+
+func newLoader(fetch batchFn) *loader {
+	return &loader{
+		fetch:  fetch,
+		values: make(map[string]interface{}),
+		errs:   make(map[string]error),
+	}
+}
+
+func (l *loader) load(ctx context.Context, key string) func() (interface{}, error) {
+	l.mu.Lock()
+	if _, ok := l.values[key]; !ok && key != "" {
+		// mark key as queued with nil value, it stay nil when not found
+		l.values[key] = nil
+		l.queue = append(l.queue, key)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if len(l.queue) > 0 {
+			keys := l.queue
+			l.queue = nil
+			values, err := l.fetch(ctx, keys)
+			if err != nil {
+				for _, k := range keys {
+					l.errs[k] = err
+				}
+			}
+			// fetch may return more than asked, keep everything for next lookups
+			for k, v := range values {
+				l.values[k] = v
+			}
+		}
+		return l.values[key], l.errs[key]
+	}
+}
+
+// graphQLLoaders is created for every GraphQL request so cached values never leak across requests
+type graphQLLoaders struct {
+	addresses  *loader
+	contracts  *loader
+	blocks     *loader
+	txs        *loader
+	blockTxs   *loader
+	labels     *loader
+	validators *loader
+}
+
+func (s *Server) newGraphQLLoaders() *graphQLLoaders {
+	return &graphQLLoaders{
+		addresses: newLoader(func(ctx context.Context, keys []string) (map[string]interface{}, error) {
+			addresses, err := s.dbClient.AddressesByHashes(ctx, keys)
+			if err != nil {
+				return nil, err
+			}
+			values := make(map[string]interface{}, len(addresses))
+			for _, address := range addresses {
+				values[address.Address] = address
+			}
+			return values, nil
+		}),
+		contracts: newLoader(func(ctx context.Context, keys []string) (map[string]interface{}, error) {
+			contracts, err := s.dbClient.ContractsByAddresses(ctx, keys)
+			if err != nil {
+				return nil, err
+			}
+			values := make(map[string]interface{}, len(contracts))
+			for _, contract := range contracts {
+				values[contract.Address] = contract
+			}
+			return values, nil
+		}),
+		blocks: newLoader(func(ctx context.Context, keys []string) (map[string]interface{}, error) {
+			heights := make([]uint64, 0, len(keys))
+			for _, key := range keys {
+				if height, err := strconv.ParseUint(key, 10, 64); err == nil {
+					heights = append(heights, height)
+				}
+			}
+			blocks, err := s.dbClient.BlocksByHeights(ctx, heights)
+			if err != nil {
+				return nil, err
+			}
+			values := make(map[string]interface{}, len(blocks))
+			for _, block := range blocks {
+				values[strconv.FormatUint(block.Height, 10)] = block
+			}
+			return values, nil
+		}),
+		txs: newLoader(func(ctx context.Context, keys []string) (map[string]interface{}, error) {
+			txs, err := s.dbClient.TxsByHashes(ctx, keys)
+			if err != nil {
+				return nil, err
+			}
+			values := make(map[string]interface{}, len(txs))
+			for _, tx := range txs {
+				values[tx.Hash] = tx
+			}
+			return values, nil
+		}),
+		// keys are "height/skip/limit", blocks sharing a page are fetched in one query
+		blockTxs: newLoader(func(ctx context.Context, keys []string) (map[string]interface{}, error) {
+			heights := make(map[types.Pagination][]uint64)
+			for _, key := range keys {
+				height, pagination, err := parseBlockTxsKey(key)
+				if err != nil {
+					continue
+				}
+				heights[*pagination] = append(heights[*pagination], height)
+			}
+			values := make(map[string]interface{}, len(keys))
+			for pagination, page := range heights {
+				pagination := pagination
+				txs, err := s.dbClient.TxsByBlockHeights(ctx, page, &pagination)
+				if err != nil {
+					return nil, err
+				}
+				for _, height := range page {
+					values[blockTxsKey(height, &pagination)] = txs[height]
+				}
+			}
+			return values, nil
+		}),
+		labels: newLoader(func(ctx context.Context, keys []string) (map[string]interface{}, error) {
+			labels, err := s.dbClient.LabelsOfAddresses(ctx, keys)
+			if err != nil {
+				return nil, err
+			}
+			values := make(map[string]interface{}, len(labels))
+			for address, addressLabels := range labels {
+				values[address] = addressLabels
+			}
+			return values, nil
+		}),
+		// validators are few, the whole set is loaded once and looked up by address or staking contract
+		validators: newLoader(func(ctx context.Context, keys []string) (map[string]interface{}, error) {
+			validators, err := s.dbClient.Validators(ctx, db.ValidatorsFilter{})
+			if err != nil {
+				return nil, err
+			}
+			values := make(map[string]interface{}, 2*len(validators))
+			for _, validator := range validators {
+				values[validator.Address] = validator
+				values[validator.SmcAddress] = validator
+			}
+			return values, nil
+		}),
+	}
+}
+
+func graphQLLoadersFrom(ctx context.Context) *graphQLLoaders {
+	loaders, _ := ctx.Value(graphQLLoadersKey{}).(*graphQLLoaders)
+	return loaders
+}
+
+// loadAddress return thunk of address info, nil when address is unknown
+func loadAddress(ctx context.Context, address string) func() (interface{}, error) {
+	return graphQLLoadersFrom(ctx).addresses.load(ctx, address)
+}
+
+func loadContract(ctx context.Context, address string) func() (interface{}, error) {
+	return graphQLLoadersFrom(ctx).contracts.load(ctx, address)
+}
+
+func loadBlock(ctx context.Context, height uint64) func() (interface{}, error) {
+	return graphQLLoadersFrom(ctx).blocks.load(ctx, strconv.FormatUint(height, 10))
+}
+
+func loadTx(ctx context.Context, hash string) func() (interface{}, error) {
+	return graphQLLoadersFrom(ctx).txs.load(ctx, hash)
+}
+
+func blockTxsKey(height uint64, pagination *types.Pagination) string {
+	return fmt.Sprintf("%d/%d/%d", height, pagination.Skip, pagination.Limit)
+}
+
+func parseBlockTxsKey(key string) (uint64, *types.Pagination, error) {
+	var (
+		height     uint64
+		pagination types.Pagination
+	)
+	if _, err := fmt.Sscanf(key, "%d/%d/%d", &height, &pagination.Skip, &pagination.Limit); err != nil {
+		return 0, nil, err
+	}
+	return height, &pagination, nil
+}
+
+// loadBlockTxs return thunk of a page of block txs
+func loadBlockTxs(ctx context.Context, height uint64, pagination *types.Pagination) func() (interface{}, error) {
+	return graphQLLoadersFrom(ctx).blockTxs.load(ctx, blockTxsKey(height, pagination))
+}
+
+// loadLabels return thunk of labels of address, nil when address is unlabeled
+func loadLabels(ctx context.Context, address string) func() (interface{}, error) {
+	return graphQLLoadersFrom(ctx).labels.load(ctx, address)
+}
+
+func loadValidator(ctx context.Context, address string) func() (interface{}, error) {
+	return graphQLLoadersFrom(ctx).validators.load(ctx, address)
+}
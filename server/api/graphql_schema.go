@@ -0,0 +1,592 @@
+// Package api
+package api
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/kardiachain/kardia-explorer-backend/db"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+const (
+	graphQLDefaultLimit = 25
+)
+
+var (
+	ErrGraphQLPagination    = errors.New("page must be positive and limit between 1 and 100")
+	ErrGraphQLBlockSelector = errors.New("block height or hash is required")
+)
+
+// graphQLUint64 serialize 64 bits integers such as heights and gas, GraphQL Int is 32 bits only
+var graphQLUint64 = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Uint64",
+	Description: "Unsigned 64 bits integer",
+	Serialize: func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case uint64, uint, uint32, uint8, int64, int, int32:
+			return v
+		}
+		return nil
+	},
+	ParseValue: func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case int:
+			if v >= 0 {
+				return uint64(v)
+			}
+		case float64:
+			if v >= 0 {
+				return uint64(v)
+			}
+		case string:
+			if n, err := strconv.ParseUint(v, 10, 64); err == nil {
+				return n
+			}
+		}
+		return nil
+	},
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		switch v := valueAST.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.ParseUint(v.Value, 10, 64); err == nil {
+				return n
+			}
+		case *ast.StringValue:
+			if n, err := strconv.ParseUint(v.Value, 10, 64); err == nil {
+				return n
+			}
+		}
+		return nil
+	},
+})
+
+// graphQLJSON is arbitrary JSON such as decoded arguments of calls and events
+var graphQLJSON = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "JSON",
+	Description: "Arbitrary JSON value",
+	Serialize: func(value interface{}) interface{} {
+		return value
+	},
+	ParseValue: func(value interface{}) interface{} {
+		return value
+	},
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		return valueAST.GetValue()
+	},
+})
+
+var pagingArgs = graphql.FieldConfigArgument{
+	"page":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1},
+	"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: graphQLDefaultLimit},
+}
+
+func graphQLPagination(p graphql.ResolveParams) (*types.Pagination, error) {
+	page, _ := p.Args["page"].(int)
+	limit, _ := p.Args["limit"].(int)
+	if page < 1 || limit < 1 || limit > types.MaximumLimit {
+		return nil, ErrGraphQLPagination
+	}
+	return &types.Pagination{Skip: (page - 1) * limit, Limit: limit}, nil
+}
+
+func graphQLLog(source interface{}) *types.Log {
+	switch l := source.(type) {
+	case types.Log:
+		return &l
+	case *types.Log:
+		return l
+	}
+	return nil
+}
+
+// newGraphQLSchema build the GraphQL schema, relations are resolved with the request loaders
+func (s *Server) newGraphQLSchema() (graphql.Schema, error) {
+	var (
+		blockType, txType, logType, addressType, tokenType, tokenBalanceType, validatorType, delegatorType *graphql.Object
+	)
+
+	functionCallType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "FunctionCall",
+		Fields: graphql.Fields{
+			"function":   &graphql.Field{Type: graphql.String},
+			"methodID":   &graphql.Field{Type: graphql.String},
+			"methodName": &graphql.Field{Type: graphql.String},
+			"arguments":  &graphql.Field{Type: graphQLJSON},
+			"ambiguous":  &graphql.Field{Type: graphql.Boolean},
+			"candidates": &graphql.Field{Type: graphql.NewList(graphql.String)},
+		},
+	})
+	labelType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Label",
+		Fields: graphql.Fields{
+			"name":       &graphql.Field{Type: graphql.String},
+			"category":   &graphql.Field{Type: graphql.String},
+			"source":     &graphql.Field{Type: graphql.String},
+			"confidence": &graphql.Field{Type: graphql.Float},
+		},
+	})
+	eventType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Event",
+		Description: "Log decoded with the contract ABI, or with the signatures registry when the ABI is unknown",
+		Fields: graphql.Fields{
+			"methodName":    &graphql.Field{Type: graphql.String},
+			"argumentsName": &graphql.Field{Type: graphql.String},
+			"arguments":     &graphql.Field{Type: graphQLJSON},
+			"ambiguous":     &graphql.Field{Type: graphql.Boolean},
+			"candidates":    &graphql.Field{Type: graphql.NewList(graphql.String)},
+		},
+	})
+
+	blockType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Block",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"hash":            &graphql.Field{Type: graphql.String},
+				"height":          &graphql.Field{Type: graphQLUint64},
+				"time":            &graphql.Field{Type: graphql.DateTime},
+				"numTxs":          &graphql.Field{Type: graphQLUint64},
+				"gasUsed":         &graphql.Field{Type: graphQLUint64},
+				"gasLimit":        &graphql.Field{Type: graphQLUint64},
+				"rewards":         &graphql.Field{Type: graphql.String},
+				"proposerAddress": &graphql.Field{Type: graphql.String},
+				"proposer": &graphql.Field{
+					Type: validatorType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadValidator(p.Context, p.Source.(*types.Block).ProposerAddress), nil
+					},
+				},
+				"txs": &graphql.Field{
+					Type: graphql.NewList(txType),
+					Args: pagingArgs,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						pagination, err := graphQLPagination(p)
+						if err != nil {
+							return nil, err
+						}
+						return loadBlockTxs(p.Context, p.Source.(*types.Block).Height, pagination), nil
+					},
+				},
+			}
+		}),
+	})
+
+	txType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Transaction",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"hash":        &graphql.Field{Type: graphql.String},
+				"blockHash":   &graphql.Field{Type: graphql.String},
+				"blockNumber": &graphql.Field{Type: graphQLUint64},
+				"block": &graphql.Field{
+					Type: blockType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadBlock(p.Context, p.Source.(*types.Transaction).BlockNumber), nil
+					},
+				},
+				"from": &graphql.Field{Type: graphql.String},
+				"fromAddress": &graphql.Field{
+					Type: addressType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadAddress(p.Context, p.Source.(*types.Transaction).From), nil
+					},
+				},
+				"to": &graphql.Field{Type: graphql.String},
+				"toAddress": &graphql.Field{
+					Type: addressType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadAddress(p.Context, p.Source.(*types.Transaction).To), nil
+					},
+				},
+				"contractAddress":  &graphql.Field{Type: graphql.String},
+				"status":           &graphql.Field{Type: graphql.Int},
+				"value":            &graphql.Field{Type: graphql.String},
+				"gasPrice":         &graphql.Field{Type: graphQLUint64},
+				"gas":              &graphql.Field{Type: graphQLUint64},
+				"gasUsed":          &graphql.Field{Type: graphQLUint64},
+				"txFee":            &graphql.Field{Type: graphql.String},
+				"nonce":            &graphql.Field{Type: graphQLUint64},
+				"time":             &graphql.Field{Type: graphql.DateTime},
+				"transactionIndex": &graphql.Field{Type: graphql.Int},
+				"input":            &graphql.Field{Type: graphql.String},
+				"decodedInput": &graphql.Field{
+					Type: functionCallType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						tx := p.Source.(*types.Transaction)
+						if tx.To == "" {
+							return nil, nil
+						}
+						contract := loadContract(p.Context, tx.To)
+						return func() (interface{}, error) {
+							v, err := contract()
+							if err != nil {
+								return nil, err
+							}
+							contractInfo, _ := v.(*types.Contract)
+							if decoded := s.decodeFunctionCall(p.Context, tx, contractInfo); decoded != nil {
+								return decoded, nil
+							}
+							return nil, nil
+						}, nil
+					},
+				},
+				"logs": &graphql.Field{Type: graphql.NewList(logType)},
+			}
+		}),
+	})
+
+	logType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Log",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"address": &graphql.Field{Type: graphql.String},
+				"contract": &graphql.Field{
+					Type: tokenType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadContract(p.Context, graphQLLog(p.Source).Address), nil
+					},
+				},
+				"topics":          &graphql.Field{Type: graphql.NewList(graphql.String)},
+				"data":            &graphql.Field{Type: graphql.String},
+				"blockHeight":     &graphql.Field{Type: graphQLUint64},
+				"transactionHash": &graphql.Field{Type: graphql.String},
+				"logIndex":        &graphql.Field{Type: graphql.Int},
+				"event": &graphql.Field{
+					Type: eventType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						l := graphQLLog(p.Source)
+						contract := loadContract(p.Context, l.Address)
+						return func() (interface{}, error) {
+							v, err := contract()
+							if err != nil {
+								return nil, err
+							}
+							contractInfo, _ := v.(*types.Contract)
+							if decoded := s.decodeLog(p.Context, l, contractInfo); decoded != nil {
+								return decoded, nil
+							}
+							return nil, nil
+						}, nil
+					},
+				},
+			}
+		}),
+	})
+
+	addressType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Address",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"address":         &graphql.Field{Type: graphql.String},
+				"name":            &graphql.Field{Type: graphql.String},
+				"balance":         &graphql.Field{Type: graphql.String},
+				"isContract":      &graphql.Field{Type: graphql.Boolean},
+				"type":            &graphql.Field{Type: graphql.String},
+				"logo":            &graphql.Field{Type: graphql.String},
+				"txCount":         &graphql.Field{Type: graphql.Int},
+				"tokenTxCount":    &graphql.Field{Type: graphql.Int},
+				"internalTxCount": &graphql.Field{Type: graphql.Int},
+				"labels": &graphql.Field{
+					Type: graphql.NewList(labelType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadLabels(p.Context, p.Source.(*types.Address).Address), nil
+					},
+				},
+				"contract": &graphql.Field{
+					Type: tokenType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						address := p.Source.(*types.Address)
+						if !address.IsContract {
+							return nil, nil
+						}
+						return loadContract(p.Context, address.Address), nil
+					},
+				},
+				"txs": &graphql.Field{
+					Type: graphql.NewList(txType),
+					Args: pagingArgs,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						pagination, err := graphQLPagination(p)
+						if err != nil {
+							return nil, err
+						}
+						txs, _, err := s.dbClient.TxsByAddress(p.Context, p.Source.(*types.Address).Address, pagination)
+						return txs, err
+					},
+				},
+				"tokens": &graphql.Field{
+					Type:        graphql.NewList(tokenBalanceType),
+					Description: "KRC20 balances",
+					Args:        pagingArgs,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						pagination, err := graphQLPagination(p)
+						if err != nil {
+							return nil, err
+						}
+						holders, _, err := s.dbClient.KRC20Holders(p.Context, &types.KRC20HolderFilter{
+							Pagination:    pagination,
+							HolderAddress: p.Source.(*types.Address).Address,
+						})
+						return holders, err
+					},
+				},
+			}
+		}),
+	})
+
+	tokenType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Token",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"address":      &graphql.Field{Type: graphql.String},
+				"name":         &graphql.Field{Type: graphql.String},
+				"symbol":       &graphql.Field{Type: graphql.String},
+				"decimals":     &graphql.Field{Type: graphql.Int},
+				"totalSupply":  &graphql.Field{Type: graphql.String},
+				"type":         &graphql.Field{Type: graphql.String},
+				"logo":         &graphql.Field{Type: graphql.String},
+				"ownerAddress": &graphql.Field{Type: graphql.String},
+				"isVerified":   &graphql.Field{Type: graphql.Boolean},
+				"status":       &graphql.Field{Type: graphql.Int},
+				"holders": &graphql.Field{
+					Type:        graphql.NewList(tokenBalanceType),
+					Description: "KRC20 holders",
+					Args:        pagingArgs,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						pagination, err := graphQLPagination(p)
+						if err != nil {
+							return nil, err
+						}
+						holders, _, err := s.dbClient.KRC20Holders(p.Context, &types.KRC20HolderFilter{
+							Pagination:      pagination,
+							ContractAddress: p.Source.(*types.Contract).Address,
+						})
+						return holders, err
+					},
+				},
+			}
+		}),
+	})
+
+	tokenBalanceType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "TokenBalance",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"contractAddress": &graphql.Field{Type: graphql.String},
+				"holderAddress":   &graphql.Field{Type: graphql.String},
+				"balance":         &graphql.Field{Type: graphql.String},
+				"tokenName":       &graphql.Field{Type: graphql.String},
+				"tokenSymbol":     &graphql.Field{Type: graphql.String},
+				"tokenDecimals":   &graphql.Field{Type: graphql.Int},
+				"updatedAt":       &graphql.Field{Type: graphQLUint64},
+				"token": &graphql.Field{
+					Type: tokenType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadContract(p.Context, p.Source.(*types.KRC20Holder).ContractAddress), nil
+					},
+				},
+				"holder": &graphql.Field{
+					Type: addressType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadAddress(p.Context, p.Source.(*types.KRC20Holder).HolderAddress), nil
+					},
+				},
+			}
+		}),
+	})
+
+	validatorType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Validator",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"address":               &graphql.Field{Type: graphql.String},
+				"smcAddress":            &graphql.Field{Type: graphql.String},
+				"name":                  &graphql.Field{Type: graphql.String},
+				"status":                &graphql.Field{Type: graphql.Int},
+				"role":                  &graphql.Field{Type: graphql.Int},
+				"jailed":                &graphql.Field{Type: graphql.Boolean},
+				"votingPowerPercentage": &graphql.Field{Type: graphql.String},
+				"stakedAmount":          &graphql.Field{Type: graphql.String},
+				"accumulatedCommission": &graphql.Field{Type: graphql.String},
+				"commissionRate":        &graphql.Field{Type: graphql.String},
+				"totalDelegators":       &graphql.Field{Type: graphql.Int},
+				"account": &graphql.Field{
+					Type: addressType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadAddress(p.Context, p.Source.(*types.Validator).Address), nil
+					},
+				},
+				"delegators": &graphql.Field{
+					Type: graphql.NewList(delegatorType),
+					Args: pagingArgs,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						pagination, err := graphQLPagination(p)
+						if err != nil {
+							return nil, err
+						}
+						return s.dbClient.Delegators(p.Context, db.DelegatorFilter{
+							ValidatorSMCAddress: p.Source.(*types.Validator).SmcAddress,
+							Skip:                int64(pagination.Skip),
+							Limit:               int64(pagination.Limit),
+						})
+					},
+				},
+			}
+		}),
+	})
+
+	delegatorType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Delegator",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"address":             &graphql.Field{Type: graphql.String},
+				"validatorSMCAddress": &graphql.Field{Type: graphql.String},
+				"stakedAmount":        &graphql.Field{Type: graphql.String},
+				"reward":              &graphql.Field{Type: graphql.String},
+				"account": &graphql.Field{
+					Type: addressType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadAddress(p.Context, p.Source.(*types.Delegator).Address), nil
+					},
+				},
+				"validator": &graphql.Field{
+					Type: validatorType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadValidator(p.Context, p.Source.(*types.Delegator).ValidatorSMCAddress), nil
+					},
+				},
+			}
+		}),
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"block": &graphql.Field{
+				Type:        blockType,
+				Description: "Block by height or hash",
+				Args: graphql.FieldConfigArgument{
+					"height": &graphql.ArgumentConfig{Type: graphQLUint64},
+					"hash":   &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if hash, ok := p.Args["hash"].(string); ok && hash != "" {
+						block, err := s.dbClient.BlockByHash(p.Context, hash)
+						if err == mongo.ErrNoDocuments {
+							return nil, nil
+						}
+						return block, err
+					}
+					height, ok := p.Args["height"].(uint64)
+					if !ok {
+						return nil, ErrGraphQLBlockSelector
+					}
+					return loadBlock(p.Context, height), nil
+				},
+			},
+			"blocks": &graphql.Field{
+				Type:        graphql.NewList(blockType),
+				Description: "Latest blocks",
+				Args:        pagingArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					pagination, err := graphQLPagination(p)
+					if err != nil {
+						return nil, err
+					}
+					return s.dbClient.Blocks(p.Context, pagination)
+				},
+			},
+			"tx": &graphql.Field{
+				Type: txType,
+				Args: graphql.FieldConfigArgument{
+					"hash": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadTx(p.Context, p.Args["hash"].(string)), nil
+				},
+			},
+			"txs": &graphql.Field{
+				Type:        graphql.NewList(txType),
+				Description: "Latest transactions",
+				Args:        pagingArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					pagination, err := graphQLPagination(p)
+					if err != nil {
+						return nil, err
+					}
+					return s.dbClient.LatestTxs(p.Context, pagination)
+				},
+			},
+			"address": &graphql.Field{
+				Type:        addressType,
+				Description: "Address info, unknown addresses only have their relations",
+				Args: graphql.FieldConfigArgument{
+					"address": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					address := common.HexToAddress(p.Args["address"].(string)).String()
+					return thenLoadOr(loadAddress(p.Context, address), &types.Address{Address: address}), nil
+				},
+			},
+			"token": &graphql.Field{
+				Type: tokenType,
+				Args: graphql.FieldConfigArgument{
+					"address": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadContract(p.Context, common.HexToAddress(p.Args["address"].(string)).String()), nil
+				},
+			},
+			"validators": &graphql.Field{
+				Type:        graphql.NewList(validatorType),
+				Description: "Validators and candidates",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return s.dbClient.Validators(p.Context, db.ValidatorsFilter{})
+				},
+			},
+			"validator": &graphql.Field{
+				Type:        validatorType,
+				Description: "Validator by address or staking contract address",
+				Args: graphql.FieldConfigArgument{
+					"address": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadValidator(p.Context, common.HexToAddress(p.Args["address"].(string)).String()), nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+// thenLoadOr return fallback when loader found nothing
+func thenLoadOr(thunk func() (interface{}, error), fallback interface{}) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		v, err := thunk()
+		if err != nil || v != nil {
+			return v, err
+		}
+		return fallback, nil
+	}
+}
+
+// decodeLog unpack log with the ABI of its contract, or the signatures registry when contract is unknown
+func (s *Server) decodeLog(ctx context.Context, l *types.Log, contractInfo *types.Contract) *types.Log {
+	var unpacked *types.Log
+	if contractInfo != nil {
+		if contractABI, err := s.contractABI(ctx, contractInfo); err == nil && contractABI != nil {
+			unpacked, _ = s.kaiClient.UnpackLog(l, contractABI)
+		}
+	}
+	if unpacked == nil {
+		unpacked = s.unpackLogWithSignatures(ctx, l)
+	}
+	return unpacked
+}
@@ -0,0 +1,147 @@
+// Package api
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+// seedGraphQL index blocks 100 to 1 proposed by one validator, each with 3 txs logging a token
+func seedGraphQL(d *fakeDB) {
+	d.validators = []*types.Validator{{Address: "0xVal", SmcAddress: "0xValSMC", Name: "val"}}
+	d.contracts = []*types.Contract{{Address: "0xToken", Symbol: "TKN"}}
+	for height := uint64(100); height > 0; height-- {
+		d.blocks = append(d.blocks, &types.Block{Height: height, ProposerAddress: "0xVal"})
+		for i := 0; i < 3; i++ {
+			d.txs = append(d.txs, &types.Transaction{
+				Hash:        fmt.Sprintf("0x%d-%d", height, i),
+				BlockNumber: height,
+				From:        fmt.Sprintf("0xFrom%d", i),
+				Logs:        []types.Log{{Address: "0xToken"}},
+			})
+		}
+	}
+	for i := 0; i < 3; i++ {
+		hash := fmt.Sprintf("0xFrom%d", i)
+		d.addresses = append(d.addresses, &types.Address{Address: hash, Name: "name of " + hash})
+	}
+	d.labels = []*types.AddressLabel{{Address: "0xFrom0", Label: types.Label{Name: "Hot Wallet", Category: types.LabelCategoryExchange}}}
+}
+
+func queryGraphQL(t *testing.T, f *fakeServer, req *graphQLRequest) (int, map[string]interface{}) {
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	_, rec := callHandler(t, f.GraphQL, newRequest(echo.POST, "/graphql", bytes.NewReader(body),
+		map[string]string{echo.HeaderContentType: echo.MIMEApplicationJSON}))
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	return rec.Code, result
+}
+
+func TestGraphQL_Batching(t *testing.T) {
+	f := newFakeServer()
+	f.SetGraphQLLimits(8, 5000)
+	seedGraphQL(f.db)
+	code, result := queryGraphQL(t, f, &graphQLRequest{Query: `{
+		blocks(limit: 3) {
+			height
+			proposer { name }
+			txs(limit: 2) {
+				hash
+				fromAddress { name labels { name category } }
+				block { height }
+				logs { contract { symbol } }
+			}
+		}
+	}`})
+	require.Equal(t, http.StatusOK, code)
+	require.Nil(t, result["errors"])
+
+	blocks := result["data"].(map[string]interface{})["blocks"].([]interface{})
+	require.Len(t, blocks, 3)
+	block := blocks[0].(map[string]interface{})
+	assert.Equal(t, "val", block["proposer"].(map[string]interface{})["name"])
+	tx := block["txs"].([]interface{})[1].(map[string]interface{})
+	assert.Equal(t, "name of 0xFrom1", tx["fromAddress"].(map[string]interface{})["name"])
+	assert.Nil(t, tx["fromAddress"].(map[string]interface{})["labels"])
+	labels := block["txs"].([]interface{})[0].(map[string]interface{})["fromAddress"].(map[string]interface{})["labels"].([]interface{})
+	assert.Equal(t, map[string]interface{}{"name": "Hot Wallet", "category": types.LabelCategoryExchange}, labels[0])
+	assert.EqualValues(t, 100, tx["block"].(map[string]interface{})["height"])
+	log := tx["logs"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "TKN", log["contract"].(map[string]interface{})["symbol"])
+
+	// relations and lookups are batched once per level
+	assert.Equal(t, 1, f.db.calls["TxsByBlockHeights"])
+	assert.Equal(t, 1, f.db.calls["Validators"])
+	assert.Equal(t, 1, f.db.calls["AddressesByHashes"])
+	assert.ElementsMatch(t, []string{"0xFrom0", "0xFrom1"}, f.db.keys["AddressesByHashes"])
+	assert.Equal(t, 1, f.db.calls["LabelsOfAddresses"])
+	assert.ElementsMatch(t, []string{"0xFrom0", "0xFrom1"}, f.db.keys["LabelsOfAddresses"])
+	assert.Equal(t, 1, f.db.calls["BlocksByHeights"])
+	assert.Equal(t, 1, f.db.calls["ContractsByAddresses"])
+	assert.Equal(t, []string{"0xToken"}, f.db.keys["ContractsByAddresses"])
+}
+
+func TestGraphQL_BlockTxsPages(t *testing.T) {
+	f := newFakeServer()
+	f.SetGraphQLLimits(8, 5000)
+	seedGraphQL(f.db)
+	code, result := queryGraphQL(t, f, &graphQLRequest{Query: `{ blocks(limit: 2) { first: txs(limit: 1) { hash } more: txs(limit: 3) { hash } } }`})
+	require.Equal(t, http.StatusOK, code)
+	require.Nil(t, result["errors"])
+
+	block := result["data"].(map[string]interface{})["blocks"].([]interface{})[1].(map[string]interface{})
+	assert.Len(t, block["first"], 1)
+	assert.Len(t, block["more"], 3)
+	assert.Equal(t, "0x99-2", block["more"].([]interface{})[2].(map[string]interface{})["hash"])
+	// one query per distinct page
+	assert.Equal(t, 2, f.db.calls["TxsByBlockHeights"])
+}
+
+func TestGraphQL_Limits(t *testing.T) {
+	f := newFakeServer()
+	f.SetGraphQLLimits(4, 500)
+	seedGraphQL(f.db)
+
+	// depth 5
+	code, result := queryGraphQL(t, f, &graphQLRequest{Query: `{ blocks { txs { block { txs { hash } } } } }`})
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, fmt.Sprint(result["errors"]), ErrGraphQLQueryTooDeep.Error())
+
+	// 1 + 100 * (1 + 10 * 1)
+	code, result = queryGraphQL(t, f, &graphQLRequest{Query: `{ blocks(limit: 100) { txs(limit: 10) { hash } } }`})
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, fmt.Sprint(result["errors"]), ErrGraphQLQueryTooComplex.Error())
+
+	// limit given as variable and through fragments
+	code, _ = queryGraphQL(t, f, &graphQLRequest{
+		Query:     `query Q($limit: Int) { blocks(limit: $limit) { ...B } } fragment B on Block { txs(limit: 10) { hash } }`,
+		Variables: map[string]interface{}{"limit": 100},
+	})
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Empty(t, f.db.calls, "rejected queries must not reach the database")
+
+	code, result = queryGraphQL(t, f, &graphQLRequest{Query: `{ blocks(limit: 5) { txs(limit: 10) { hash } } }`})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Nil(t, result["errors"])
+
+	// introspection is free
+	code, result = queryGraphQL(t, f, &graphQLRequest{Query: `{ __schema { types { name fields { name type { name ofType { name ofType { name } } } } } } }`})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Nil(t, result["errors"])
+
+	code, _ = queryGraphQL(t, f, &graphQLRequest{Query: `{ blocks(limit: 1000) { height } }`})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, result = queryGraphQL(t, f, &graphQLRequest{Query: `{ blocks(limit: 0) { height } }`})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, fmt.Sprint(result["errors"]), ErrGraphQLPagination.Error())
+}
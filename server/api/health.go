@@ -3,8 +3,12 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"math/big"
+	"sync"
+	"time"
 
+	"github.com/kardiachain/kardia-explorer-backend/cache"
 	"github.com/kardiachain/kardia-explorer-backend/cfg"
 	"github.com/kardiachain/kardia-explorer-backend/types"
 	"github.com/labstack/echo"
@@ -19,9 +23,128 @@ func (s *Server) Ping(c echo.Context) error {
 	return OK.SetData(stats).Build(c)
 }
 
+// healthCheckTimeout bound the readiness checks, a hung dependency is reported unreachable instead of hanging the probe
+const healthCheckTimeout = 3 * time.Second
+
+// Liveness answer as long as the process serve requests, dependencies are not checked
+// so a broken Mongo or Redis does not get every replica restarted
+func (s *Server) Liveness(c echo.Context) error {
+	type liveness struct {
+		Status  string `json:"status"`
+		Version string `json:"version"`
+	}
+	return OK.SetData(&liveness{Status: types.HealthStatusOK, Version: cfg.ServerVersion}).Build(c)
+}
+
+// Readiness report the indexer lag and dependencies health, respond 503 when the replica should not receive traffic
+func (s *Server) Readiness(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), healthCheckTimeout)
+	defer cancel()
+	report := s.healthReport(ctx, time.Now())
+	if report.Status != types.HealthStatusOK {
+		s.logger.Warn("Replica is not ready", zap.Strings("reasons", report.Reasons))
+		resp := Unavailable
+		return resp.SetData(report).Build(c)
+	}
+	return OK.SetData(report).Build(c)
+}
+
+func (s *Server) healthReport(ctx context.Context, now time.Time) *types.HealthReport {
+	var (
+		wg             sync.WaitGroup
+		mongo, redis   *types.DependencyHealth
+		latestBlocks   []*types.Block
+		latestBlockErr error
+		nodes          []*types.NodeHealth
+		queues         map[string]int64
+	)
+	wg.Add(5)
+	go func() {
+		defer wg.Done()
+		mongo = checkDependency(ctx, s.dbClient.Ping)
+	}()
+	go func() {
+		defer wg.Done()
+		redis = checkDependency(ctx, s.cacheClient.Ping)
+	}()
+	go func() {
+		defer wg.Done()
+		latestBlocks, latestBlockErr = s.dbClient.Blocks(ctx, &types.Pagination{Skip: 0, Limit: 1})
+	}()
+	go func() {
+		defer wg.Done()
+		nodes = s.kaiClient.NodesHealth(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		queues = cache.QueueLengths(ctx, s.cacheClient)
+	}()
+	wg.Wait()
+
+	report := &types.HealthReport{
+		Status:       types.HealthStatusOK,
+		Queues:       queues,
+		Dependencies: map[string]*types.DependencyHealth{"mongo": mongo, "redis": redis},
+		Nodes:        nodes,
+	}
+	if !mongo.Reachable {
+		report.Reasons = append(report.Reasons, "mongo is unreachable")
+	}
+	if !redis.Reachable {
+		report.Reasons = append(report.Reasons, "redis is unreachable")
+	}
+	// unreachable nodes are reported but do not fail the probe, they are shared by every replica
+	// and the block age still catch an indexer stuck on them
+	for _, node := range nodes {
+		if node.Reachable && node.LatestBlock > report.ChainHeight {
+			report.ChainHeight = node.LatestBlock
+		}
+	}
+
+	switch {
+	case latestBlockErr != nil:
+		if mongo.Reachable {
+			report.Reasons = append(report.Reasons, "cannot read latest indexed block")
+		}
+	case len(latestBlocks) == 0:
+		report.Reasons = append(report.Reasons, "no block indexed yet")
+	default:
+		latest := latestBlocks[0]
+		report.IndexedHeight = latest.Height
+		report.LatestBlockAge = now.Sub(latest.Time).Seconds()
+		if report.ChainHeight > latest.Height {
+			report.Lag = report.ChainHeight - latest.Height
+		}
+		if s.healthMaxBlockLag > 0 && report.Lag > s.healthMaxBlockLag {
+			report.Reasons = append(report.Reasons, fmt.Sprintf("indexer is %d blocks behind the chain head, threshold is %d", report.Lag, s.healthMaxBlockLag))
+		}
+		if s.healthMaxBlockAge > 0 && now.Sub(latest.Time) > s.healthMaxBlockAge {
+			report.Reasons = append(report.Reasons, fmt.Sprintf("latest indexed block is %s old, threshold is %s", now.Sub(latest.Time).Truncate(time.Second), s.healthMaxBlockAge))
+		}
+	}
+
+	if len(report.Reasons) > 0 {
+		report.Status = types.HealthStatusUnavailable
+	}
+	return report
+}
+
+func checkDependency(ctx context.Context, ping func(ctx context.Context) error) *types.DependencyHealth {
+	start := time.Now()
+	err := ping(ctx)
+	health := &types.DependencyHealth{
+		Reachable: err == nil,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		health.Error = err.Error()
+	}
+	return health
+}
+
 func (s *Server) ServerStatus(c echo.Context) error {
 	lgr := s.logger
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	//var status *types.ServerStatus
 	//var err error
 	status, err := s.cacheClient.ServerStatus(ctx)
@@ -40,27 +163,25 @@ func (s *Server) ServerStatus(c echo.Context) error {
 
 func (s *Server) UpdateServerStatus(c echo.Context) error {
 	lgr := s.logger.With(zap.String("method", "UpdateServerStatus"))
-	if c.Request().Header.Get("Authorization") != s.authorizationSecret {
-		lgr.Warn("Cannot authorization request")
-		return Unauthorized.Build(c)
-	}
 	var serverStatus *types.ServerStatus
 	if err := c.Bind(&serverStatus); err != nil {
 		lgr.Error("cannot bind server status", zap.Error(err))
 		return Invalid.Build(c)
 	}
-	ctx := context.Background()
+	ctx := c.Request().Context()
+	before, _ := s.cacheClient.ServerStatus(ctx)
 	if err := s.cacheClient.UpdateServerStatus(ctx, serverStatus); err != nil {
 		lgr.Error("cannot update server status", zap.Error(err))
 		return Invalid.Build(c)
 	}
+	setAudit(c, "status", before, serverStatus)
 
 	return OK.SetData(nil).Build(c)
 
 }
 
 func (s *Server) Nodes(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	nodes, err := s.kaiClient.NodesInfo(ctx)
 	if err != nil {
 		s.logger.Warn("cannot get nodes info from RPC", zap.Error(err))
@@ -93,7 +214,7 @@ func (s *Server) Nodes(c echo.Context) error {
 }
 
 func (s *Server) TokenInfo(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	if !s.cacheClient.IsRequestToCoinMarket(ctx) {
 		tokenInfo, err := s.cacheClient.TokenInfo(ctx)
 		if err != nil {
@@ -125,10 +246,7 @@ func (s *Server) TokenInfo(c echo.Context) error {
 }
 
 func (s *Server) UpdateSupplyAmounts(c echo.Context) error {
-	ctx := context.Background()
-	if c.Request().Header.Get("Authorization") != s.authorizationSecret {
-		return Unauthorized.Build(c)
-	}
+	ctx := c.Request().Context()
 	var supplyInfo *types.SupplyInfo
 	if err := c.Bind(&supplyInfo); err != nil {
 		return Invalid.Build(c)
@@ -136,5 +254,6 @@ func (s *Server) UpdateSupplyAmounts(c echo.Context) error {
 	if err := s.cacheClient.UpdateSupplyAmounts(ctx, supplyInfo); err != nil {
 		return Invalid.Build(c)
 	}
+	setAudit(c, "supplies", nil, supplyInfo)
 	return OK.SetData(nil).Build(c)
 }
@@ -0,0 +1,65 @@
+// Package api
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+func readiness(t *testing.T, latest *types.Block, pingErr error, nodes ...*types.NodeHealth) (int, *types.HealthReport) {
+	f := newFakeServer()
+	f.healthMaxBlockLag = 30
+	f.healthMaxBlockAge = 5 * time.Minute
+	f.db.pingErr = pingErr
+	if latest != nil {
+		f.db.blocks = []*types.Block{latest}
+	}
+	f.cache.listSize = 2
+	f.kai.nodes = nodes
+
+	var report *types.HealthReport
+	code := callData(t, f.Readiness, newRequest(echo.GET, "/api/v1/health/ready", nil, nil), &report)
+	return code, report
+}
+
+func TestReadiness(t *testing.T) {
+	now := time.Now()
+	nodes := []*types.NodeHealth{
+		{Name: "public-0", Reachable: true, LatestBlock: 1010},
+		{Name: "trusted-0", Error: "connection refused"},
+	}
+
+	code, report := readiness(t, &types.Block{Height: 1000, Time: now.Add(-10 * time.Second)}, nil, nodes...)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, types.HealthStatusOK, report.Status)
+	assert.EqualValues(t, 1000, report.IndexedHeight)
+	assert.EqualValues(t, 1010, report.ChainHeight)
+	assert.EqualValues(t, 10, report.Lag)
+	assert.EqualValues(t, 2, report.Queues["error_blocks"])
+	assert.True(t, report.Dependencies["mongo"].Reachable)
+	assert.Len(t, report.Nodes, 2)
+
+	// indexer lagging behind the chain head
+	code, report = readiness(t, &types.Block{Height: 900, Time: now.Add(-10 * time.Second)}, nil, nodes...)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, types.HealthStatusUnavailable, report.Status)
+	assert.EqualValues(t, 110, report.Lag)
+	assert.Len(t, report.Reasons, 1)
+
+	// indexer stuck while RPC nodes are down too
+	code, report = readiness(t, &types.Block{Height: 1000, Time: now.Add(-time.Hour)}, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Len(t, report.Reasons, 1)
+
+	code, report = readiness(t, nil, errors.New("server selection timeout"), nodes...)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.False(t, report.Dependencies["mongo"].Reachable)
+	assert.Equal(t, []string{"mongo is unreachable"}, report.Reasons)
+}
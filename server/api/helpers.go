@@ -5,12 +5,8 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
-	"encoding/json"
 	"errors"
-	"io/ioutil"
 	"math/big"
-	"net"
-	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -19,6 +15,7 @@ import (
 	"github.com/kardiachain/go-kardia/lib/common"
 	"github.com/kardiachain/kardia-explorer-backend/cfg"
 	"github.com/kardiachain/kardia-explorer-backend/db"
+	"github.com/kardiachain/kardia-explorer-backend/external"
 	"github.com/kardiachain/kardia-explorer-backend/types"
 	"github.com/labstack/echo"
 	"go.uber.org/zap"
@@ -55,6 +52,14 @@ func (s *Server) getSMCAbi(ctx context.Context, log *types.Log) (*abi.ABI, error
 			s.logger.Debug("Cannot get smc info from db", zap.Error(err), zap.String("smcAddr", log.Address))
 			return nil, err
 		}
+		if smc.Implementation != "" {
+			// decode calls and events of proxy with its implementation ABI
+			proxyABIStr, err := s.proxyABI(ctx, smc)
+			if err == nil {
+				return s.decodeSMCABIFromBase64(ctx, proxyABIStr, log.Address)
+			}
+			s.logger.Debug("Cannot get implementation abi of proxy", zap.Error(err), zap.String("smcAddr", log.Address))
+		}
 		if smc.Type != "" {
 			err = s.cacheClient.UpdateSMCAbi(ctx, log.Address, cfg.SMCTypePrefix+smc.Type)
 			if err != nil {
@@ -413,101 +418,18 @@ func (s *Server) getInternalTxs(ctx context.Context, log *types.Log) *types.Toke
 	}
 }
 
+// fetchTokenInfo get latest quote from market providers and refresh token info in cache
 func (s *Server) fetchTokenInfo(ctx context.Context) (*types.TokenInfo, error) {
-	type CMQuote struct {
-		Price            float64 `json:"price"`
-		Volume24h        float64 `json:"volume_24h"`
-		PercentChange1h  float64 `json:"percent_change_1h"`
-		PercentChange24h float64 `json:"percent_change_24h"`
-		PercentChange7d  float64 `json:"percent_change_7d"`
-		MarketCap        float64 `json:"market_cap"`
-		LastUpdated      string  `json:"last_updated"`
-	}
-	type CMTokenInfo struct {
-		ID                int                `json:"id"`
-		Name              string             `json:"name"`
-		Symbol            string             `json:"symbol"`
-		Slug              string             `json:"slug"`
-		NumMarketPairs    int                `json:"num_market_pairs"`
-		DateAdded         string             `json:"date_added"`
-		Tags              []string           `json:"tags"`
-		MaxSupply         int64              `json:"max_supply"`
-		CirculatingSupply int64              `json:"circulating_supply"`
-		TotalSupply       int64              `json:"total_supply"`
-		IsActive          int                `json:"is_active"`
-		Platform          interface{}        `json:"platform"`
-		CmcRank           int                `json:"cmc_rank"`
-		IsFiat            int                `json:"is_fiat"`
-		LastUpdated       string             `json:"last_updated"`
-		Quote             map[string]CMQuote `json:"quote"`
-	}
-
-	type CMResponseStatus struct {
-		Timestamp    string `json:"timestamp"`
-		ErrorCode    int    `json:"error_code"`
-		ErrorMessage string `json:"error_message"`
-		Elapsed      int    `json:"elapsed"`
-		CreditCount  int    `json:"credit_count"`
-		Notice       string `json:"notice"`
-	}
-
-	type CMResponse struct {
-		Status CMResponseStatus       `json:"status"`
-		Data   map[string]CMTokenInfo `json:"data"`
-	}
-
-	coinMarketUrl := "https://pro-api.coinmarketcap.com/v1/cryptocurrency/quotes/latest?id=5453"
-	var netTransport = &http.Transport{
-		Dial: (&net.Dialer{
-			Timeout: 5 * time.Second,
-		}).Dial,
-		TLSHandshakeTimeout: 5 * time.Second,
+	if s.market == nil {
+		return nil, external.ErrNoProvider
 	}
-	var netClient = &http.Client{
-		Timeout:   time.Second * 10,
-		Transport: netTransport,
-	}
-
-	req, _ := http.NewRequest(http.MethodGet, coinMarketUrl, nil)
-	req.Header.Set("X-CMC_PRO_API_KEY", "a9aaf65c-1d6f-4daf-8e2e-df30bd405b66")
-
-	response, _ := netClient.Do(req)
-	defer response.Body.Close()
-	body, err := ioutil.ReadAll(response.Body)
+	quote, err := s.market.Quote(ctx)
 	if err != nil {
 		return nil, err
 	}
-
-	var cmResponse CMResponse
-
-	if err := json.Unmarshal(body, &cmResponse); err != nil {
-		return nil, err
-	}
-
-	if cmResponse.Status.ErrorCode != 0 {
-		return nil, errors.New("api failed")
-	}
-	cmData := cmResponse.Data["5453"]
-	cmQuote := cmData.Quote["USD"]
-
-	// Cast to internal
-	tokenInfo := &types.TokenInfo{
-		Name:                     cmData.Name,
-		Symbol:                   cmData.Symbol,
-		Decimal:                  18,
-		TotalSupply:              cmData.TotalSupply,
-		ERC20CirculatingSupply:   cmData.CirculatingSupply,
-		MainnetCirculatingSupply: 0,
-		Price:                    cmQuote.Price,
-		Volume24h:                cmQuote.Volume24h,
-		Change1h:                 cmQuote.PercentChange1h,
-		Change24h:                cmQuote.PercentChange24h,
-		Change7d:                 cmQuote.PercentChange7d,
-		MarketCap:                cmQuote.MarketCap,
-	}
+	tokenInfo := quote.TokenInfo()
 	if err := s.cacheClient.UpdateTokenInfo(ctx, tokenInfo); err != nil {
 		return nil, err
 	}
-
 	return tokenInfo, nil
 }
@@ -0,0 +1,75 @@
+// Package api
+package api
+
+import (
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/labstack/echo"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+type IInternalCall interface {
+	TxInternalCalls(c echo.Context) error
+	AddressInternalCalls(c echo.Context) error
+}
+
+func bindInternalCallAPIs(gr *echo.Group, srv RestServer) {
+	apis := []restDefinition{
+		{
+			method: echo.GET,
+			// Query params: ?page=0&limit=10
+			path:        "/txs/:txHash/internal",
+			fn:          srv.TxInternalCalls,
+			middlewares: []echo.MiddlewareFunc{checkPagination()},
+		},
+		{
+			method: echo.GET,
+			// Query params: ?page=0&limit=10
+			path:        "/addresses/:address/internal-txs",
+			fn:          srv.AddressInternalCalls,
+			middlewares: []echo.MiddlewareFunc{checkPagination()},
+		},
+	}
+	for _, api := range apis {
+		gr.Add(api.method, api.path, api.fn, api.middlewares...)
+	}
+}
+
+// TxInternalCalls return KAI transfers, contract creations and self destructs made inside a tx,
+// in execution order
+func (s *Server) TxInternalCalls(c echo.Context) error {
+	pagination, page, limit := getPagingOption(c)
+	return s.internalCalls(c, &types.InternalCallsFilter{
+		Pagination: pagination,
+		TxHash:     c.Param("txHash"),
+	}, page, limit)
+}
+
+// AddressInternalCalls return internal calls sent or received by address, latest first
+func (s *Server) AddressInternalCalls(c echo.Context) error {
+	address := c.Param("address")
+	if !common.IsHexAddress(address) {
+		return Invalid.Build(c)
+	}
+	pagination, page, limit := getPagingOption(c)
+	return s.internalCalls(c, &types.InternalCallsFilter{
+		Pagination: pagination,
+		Address:    common.HexToAddress(address).Hex(),
+	}, page, limit)
+}
+
+func (s *Server) internalCalls(c echo.Context, filter *types.InternalCallsFilter, page, limit int) error {
+	ctx := c.Request().Context()
+	calls, total, err := s.dbClient.InternalCalls(ctx, filter)
+	if err != nil {
+		s.logger.Warn("Cannot get internal calls from db", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	return OK.SetData(PagingResponse{
+		Page:  page,
+		Limit: limit,
+		Total: total,
+		Data:  calls,
+	}).Build(c)
+}
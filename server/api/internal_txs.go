@@ -22,9 +22,39 @@ func (s *Server) GetInternalTxs(c echo.Context) error {
 	address := c.QueryParam("address")
 	transactionHash := c.QueryParam("txHash")
 
+	if filter := labelFilter(c); filter != nil {
+		// transfers from or to labeled addresses, narrowed by the other params
+		var (
+			result      []*InternalTransaction
+			totalRecord uint64
+		)
+		addresses, err := s.labeledAddresses(c.Request().Context(), filter)
+		if err != nil {
+			return Invalid.Build(c)
+		}
+		if len(addresses) > 0 {
+			result, totalRecord, err = s.internalTxsByFilter(c.Request().Context(), &types.InternalTxsFilter{
+				Pagination:      pagination,
+				TransactionHash: transactionHash,
+				Contract:        contractAddress,
+				Address:         address,
+				Addresses:       addresses,
+			})
+			if err != nil {
+				return Invalid.Build(c)
+			}
+		}
+		return OK.SetData(PagingResponse{
+			Page:  page,
+			Limit: limit,
+			Total: totalRecord,
+			Data:  result,
+		}).Build(c)
+	}
+
 	//
 	if contractAddress != "" && address != "" && transactionHash != "" {
-		result, totalRecord, err := s.internalTxsOfAddressByTokenInTx(contractAddress, address, transactionHash, pagination)
+		result, totalRecord, err := s.internalTxsOfAddressByTokenInTx(c.Request().Context(), contractAddress, address, transactionHash, pagination)
 		if err != nil {
 			return Invalid.Build(c)
 		}
@@ -37,7 +67,7 @@ func (s *Server) GetInternalTxs(c echo.Context) error {
 	}
 
 	if contractAddress != "" && address != "" {
-		result, totalRecord, err := s.internalTxsOfAddressByToken(contractAddress, address, pagination)
+		result, totalRecord, err := s.internalTxsOfAddressByToken(c.Request().Context(), contractAddress, address, pagination)
 		if err != nil {
 			return Invalid.Build(c)
 		}
@@ -50,7 +80,7 @@ func (s *Server) GetInternalTxs(c echo.Context) error {
 	}
 
 	if contractAddress != "" {
-		result, totalRecord, err := s.internalTxsOfContract(contractAddress, pagination)
+		result, totalRecord, err := s.internalTxsOfContract(c.Request().Context(), contractAddress, pagination)
 		if err != nil {
 			return Invalid.Build(c)
 		}
@@ -63,7 +93,7 @@ func (s *Server) GetInternalTxs(c echo.Context) error {
 	}
 
 	if address != "" {
-		result, totalRecord, err := s.internalTxsOfAddress(address, pagination)
+		result, totalRecord, err := s.internalTxsOfAddress(c.Request().Context(), address, pagination)
 		if err != nil {
 			return Invalid.Build(c)
 		}
@@ -76,7 +106,7 @@ func (s *Server) GetInternalTxs(c echo.Context) error {
 	}
 
 	if transactionHash != "" {
-		result, totalRecord, err := s.internalTxsOfTransaction(transactionHash, pagination)
+		result, totalRecord, err := s.internalTxsOfTransaction(c.Request().Context(), transactionHash, pagination)
 		if err != nil {
 			return Invalid.Build(c)
 		}
@@ -92,8 +122,7 @@ func (s *Server) GetInternalTxs(c echo.Context) error {
 
 }
 
-func (s *Server) internalTxsOfAddress(address string, pagination *types.Pagination) ([]*InternalTransaction, uint64, error) {
-	ctx := context.Background()
+func (s *Server) internalTxsOfAddress(ctx context.Context, address string, pagination *types.Pagination) ([]*InternalTransaction, uint64, error) {
 	filterCrit := &types.InternalTxsFilter{
 		Pagination: pagination,
 		Address:    address,
@@ -130,11 +159,50 @@ func (s *Server) internalTxsOfAddress(address string, pagination *types.Paginati
 			result[i].KRCTokenInfo = krcTokenInfo
 		}
 	}
+	s.labelInternalTxs(ctx, result)
 	return result, total, nil
 }
 
-func (s *Server) internalTxsOfTransaction(txHash string, pagination *types.Pagination) ([]*InternalTransaction, uint64, error) {
-	ctx := context.Background()
+func (s *Server) internalTxsByFilter(ctx context.Context, filterCrit *types.InternalTxsFilter) ([]*InternalTransaction, uint64, error) {
+	iTxs, total, err := s.dbClient.GetListInternalTxs(ctx, filterCrit)
+	if err != nil {
+		s.logger.Warn("Cannot get internal txs from db", zap.Error(err))
+		return nil, 0, err
+	}
+	var (
+		result           = make([]*InternalTransaction, len(iTxs))
+		fromInfo, toInfo *types.Address
+	)
+	for i := range iTxs {
+		result[i] = &InternalTransaction{
+			Log: &types.Log{
+				Address: iTxs[i].Contract,
+				Time:    iTxs[i].Time,
+				TxHash:  iTxs[i].TransactionHash,
+			},
+			From:    iTxs[i].From,
+			To:      iTxs[i].To,
+			Value:   iTxs[i].Value,
+			TokenID: iTxs[i].TokenID,
+		}
+		fromInfo, _ = s.getAddressDetail(ctx, iTxs[i].From)
+		if fromInfo != nil {
+			result[i].FromName = fromInfo.Name
+		}
+		toInfo, _ = s.getAddressDetail(ctx, iTxs[i].To)
+		if toInfo != nil {
+			result[i].ToName = toInfo.Name
+		}
+		krcTokenInfo, _ := s.getTokenInfo(ctx, iTxs[i].Contract)
+		if krcTokenInfo != nil {
+			result[i].KRCTokenInfo = krcTokenInfo
+		}
+	}
+	s.labelInternalTxs(ctx, result)
+	return result, total, nil
+}
+
+func (s *Server) internalTxsOfTransaction(ctx context.Context, txHash string, pagination *types.Pagination) ([]*InternalTransaction, uint64, error) {
 	filterCrit := &types.InternalTxsFilter{
 		Pagination:      pagination,
 		TransactionHash: txHash,
@@ -171,11 +239,11 @@ func (s *Server) internalTxsOfTransaction(txHash string, pagination *types.Pagin
 			result[i].KRCTokenInfo = krcTokenInfo
 		}
 	}
+	s.labelInternalTxs(ctx, result)
 	return result, total, nil
 }
 
-func (s *Server) internalTxsOfContract(contractAddress string, pagination *types.Pagination) ([]*InternalTransaction, uint64, error) {
-	ctx := context.Background()
+func (s *Server) internalTxsOfContract(ctx context.Context, contractAddress string, pagination *types.Pagination) ([]*InternalTransaction, uint64, error) {
 	filterCrit := &types.InternalTxsFilter{
 		Pagination: pagination,
 		Contract:   contractAddress,
@@ -224,11 +292,11 @@ func (s *Server) internalTxsOfContract(contractAddress string, pagination *types
 		}
 		result[i].KRCTokenInfo = tokenInfo
 	}
+	s.labelInternalTxs(ctx, result)
 	return result, total, nil
 }
 
-func (s *Server) internalTxsOfAddressByTokenInTx(contractAddress, address, txHash string, pagination *types.Pagination) ([]*InternalTransaction, uint64, error) {
-	ctx := context.Background()
+func (s *Server) internalTxsOfAddressByTokenInTx(ctx context.Context, contractAddress, address, txHash string, pagination *types.Pagination) ([]*InternalTransaction, uint64, error) {
 	filterCrit := &types.InternalTxsFilter{
 		Pagination:      pagination,
 		Contract:        contractAddress,
@@ -279,11 +347,11 @@ func (s *Server) internalTxsOfAddressByTokenInTx(contractAddress, address, txHas
 		}
 		result[i].KRCTokenInfo = tokenInfo
 	}
+	s.labelInternalTxs(ctx, result)
 	return result, total, nil
 }
 
-func (s *Server) internalTxsOfAddressByToken(contractAddress, address string, pagination *types.Pagination) ([]*InternalTransaction, uint64, error) {
-	ctx := context.Background()
+func (s *Server) internalTxsOfAddressByToken(ctx context.Context, contractAddress, address string, pagination *types.Pagination) ([]*InternalTransaction, uint64, error) {
 	filterCrit := &types.InternalTxsFilter{
 		Pagination: pagination,
 		Contract:   contractAddress,
@@ -333,21 +401,18 @@ func (s *Server) internalTxsOfAddressByToken(contractAddress, address string, pa
 		}
 		result[i].KRCTokenInfo = tokenInfo
 	}
+	s.labelInternalTxs(ctx, result)
 	return result, total, nil
 }
 
 func (s *Server) UpdateInternalTxs(c echo.Context) error {
 	var (
-		ctx             = context.Background()
+		ctx             = c.Request().Context()
 		crit            *types.TxsFilter
 		internalTxsCrit *types.InternalTxsFilter
 		lgr             = s.logger.With(zap.String("api", "UpdateInternalTxs"))
 		bodyBytes, _    = ioutil.ReadAll(c.Request().Body)
 	)
-	if c.Request().Header.Get("Authorization") != s.authorizationSecret {
-		lgr.Warn("Cannot authorization request")
-		return Unauthorized.Build(c)
-	}
 	c.Request().Body = ioutil.NopCloser(bytes.NewBuffer(bodyBytes))
 	if err := c.Bind(&crit); err != nil {
 		lgr.Error("cannot bind txs filter", zap.Error(err))
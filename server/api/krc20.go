@@ -2,8 +2,6 @@
 package api
 
 import (
-	"context"
-
 	"github.com/kardiachain/kardia-explorer-backend/types"
 	"github.com/labstack/echo"
 	"go.uber.org/zap"
@@ -30,7 +28,7 @@ func bindKRC20APIs(gr *echo.Group, srv RestServer) {
 }
 
 func (s *Server) KRC20Holders(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	var (
 		page, limit int
 		err         error
@@ -61,6 +59,7 @@ func (s *Server) KRC20Holders(c echo.Context) error {
 			}
 		}
 	}
+	s.labelKRC20Holders(ctx, holders)
 	return OK.SetData(PagingResponse{
 		Page:  page,
 		Limit: limit,
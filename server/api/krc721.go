@@ -2,8 +2,6 @@
 package api
 
 import (
-	"context"
-
 	"github.com/kardiachain/kardia-explorer-backend/types"
 	"github.com/labstack/echo"
 )
@@ -29,7 +27,7 @@ func bindKRC721APIs(gr *echo.Group, srv RestServer) {
 }
 
 func (s *Server) KRC721Holders(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	var (
 		page, limit int
 		err         error
@@ -43,6 +41,7 @@ func (s *Server) KRC721Holders(c echo.Context) error {
 	if err != nil {
 		return Invalid.Build(c)
 	}
+	s.labelKRC721Holders(ctx, holders)
 	return OK.SetData(PagingResponse{
 		Page:  page,
 		Limit: limit,
@@ -0,0 +1,425 @@
+// Package api
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/labstack/echo"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+const (
+	// maxLabeledAddresses cap addresses a label filter of a list resolve to
+	maxLabeledAddresses = 1000
+	// maxLabelImportRows cap rows of an imported label file
+	maxLabelImportRows = 10000
+	labelImportBatch   = 1000
+	maxLabelNameLength = 64
+)
+
+var (
+	ErrInvalidLabel     = errors.New("invalid address label")
+	ErrInvalidLabelFile = errors.New("invalid label file")
+)
+
+type ILabel interface {
+	AddressLabels(c echo.Context) error
+	LabelCategories(c echo.Context) error
+	UpsertAddressLabel(c echo.Context) error
+	RemoveAddressLabel(c echo.Context) error
+	ImportAddressLabels(c echo.Context) error
+}
+
+func bindLabelAPIs(gr *echo.Group, srv RestServer) {
+	apis := []restDefinition{
+		{
+			method: echo.GET,
+			// Query params: ?address=0x&category=exchange&label=&source=&page=1&limit=25
+			path:        "/labels",
+			fn:          srv.AddressLabels,
+			middlewares: nil,
+		},
+		{
+			method:      echo.GET,
+			path:        "/labels/categories",
+			fn:          srv.LabelCategories,
+			middlewares: nil,
+		},
+	}
+	for _, api := range apis {
+		gr.Add(api.method, api.path, api.fn, api.middlewares...)
+	}
+}
+
+// AddressLabels list labels matching the query, ordered by address
+func (s *Server) AddressLabels(c echo.Context) error {
+	ctx := c.Request().Context()
+	pagination, page, limit := getPagingOption(c)
+	if pagination == nil {
+		pagination = &types.Pagination{Skip: 0, Limit: 25}
+		pagination.Sanitize()
+		page, limit = 1, pagination.Limit
+	}
+	filter := &types.AddressLabelsFilter{
+		Pagination: pagination,
+		Category:   c.QueryParam("category"),
+		Name:       c.QueryParam("label"),
+		Source:     c.QueryParam("source"),
+	}
+	if address := c.QueryParam("address"); address != "" {
+		if !common.IsHexAddress(address) {
+			return Invalid.Build(c)
+		}
+		filter.Address = common.HexToAddress(address).String()
+	}
+	labels, total, err := s.dbClient.AddressLabels(ctx, filter)
+	if err != nil {
+		s.logger.Warn("Cannot get address labels", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	return OK.SetData(PagingResponse{
+		Page:  page,
+		Limit: limit,
+		Total: total,
+		Data:  labels,
+	}).Build(c)
+}
+
+func (s *Server) LabelCategories(c echo.Context) error {
+	return OK.SetData(sortedKeys(types.LabelCategories)).Build(c)
+}
+
+func (s *Server) UpsertAddressLabel(c echo.Context) error {
+	ctx := c.Request().Context()
+	var label *types.AddressLabel
+	if err := c.Bind(&label); err != nil || label == nil {
+		return Invalid.Build(c)
+	}
+	if err := sanitizeAddressLabel(label, types.LabelSourceManual); err != nil {
+		resp := Invalid
+		return resp.SetData(err.Error()).Build(c)
+	}
+	label.UpdatedAt = time.Now()
+	if err := s.dbClient.UpsertAddressLabels(ctx, []*types.AddressLabel{label}); err != nil {
+		s.logger.Warn("Cannot upsert address label", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	setAudit(c, "label:"+label.Address, nil, label)
+	return OK.SetData(label).Build(c)
+}
+
+func (s *Server) RemoveAddressLabel(c echo.Context) error {
+	ctx := c.Request().Context()
+	var (
+		address  = c.QueryParam("address")
+		category = strings.ToLower(c.QueryParam("category"))
+		name     = c.QueryParam("label")
+	)
+	if !common.IsHexAddress(address) || category == "" || name == "" {
+		return Invalid.Build(c)
+	}
+	address = common.HexToAddress(address).String()
+	// audit the stored label, source and confidence are not part of the request
+	labels, _, err := s.dbClient.AddressLabels(ctx, &types.AddressLabelsFilter{Address: address, Category: category, Name: name})
+	if err != nil {
+		s.logger.Warn("Cannot get address label", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	if len(labels) == 0 {
+		return Invalid.Build(c)
+	}
+	removed, err := s.dbClient.RemoveAddressLabel(ctx, address, category, name)
+	if err != nil {
+		s.logger.Warn("Cannot remove address label", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	if !removed {
+		return Invalid.Build(c)
+	}
+	setAudit(c, "label:"+address, labels[0], nil)
+	return OK.SetData(nil).Build(c)
+}
+
+// ImportAddressLabels upsert labels of a JSON array or a CSV file with a header row, depending on Content-Type.
+// Rows without source get the source query param. Invalid rows are reported and skipped, valid rows are imported.
+func (s *Server) ImportAddressLabels(c echo.Context) error {
+	ctx := c.Request().Context()
+	source := strings.TrimSpace(c.QueryParam("source"))
+	if source == "" {
+		source = types.LabelSourceManual
+	}
+	var (
+		labels []*types.AddressLabel
+		err    error
+	)
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), "text/csv") {
+		labels, err = parseLabelsCSV(c.Request().Body)
+	} else {
+		labels, err = parseLabelsJSON(c.Request().Body)
+	}
+	if err != nil {
+		resp := Invalid
+		return resp.SetData(err.Error()).Build(c)
+	}
+
+	var (
+		result = &types.LabelImportResult{Errors: make(map[int]string)}
+		valid  []*types.AddressLabel
+		now    = time.Now()
+	)
+	for i, label := range labels {
+		if err := sanitizeAddressLabel(label, source); err != nil {
+			result.Errors[i+1] = err.Error()
+			continue
+		}
+		label.UpdatedAt = now
+		valid = append(valid, label)
+	}
+	for start := 0; start < len(valid); start += labelImportBatch {
+		end := start + labelImportBatch
+		if end > len(valid) {
+			end = len(valid)
+		}
+		if err := s.dbClient.UpsertAddressLabels(ctx, valid[start:end]); err != nil {
+			s.logger.Warn("Cannot import address labels", zap.Error(err))
+			return InternalServer.Build(c)
+		}
+		result.Imported = end
+	}
+	result.Rejected = len(result.Errors)
+	setAudit(c, "labels:"+source, nil, result)
+	return OK.SetData(result).Build(c)
+}
+
+func parseLabelsJSON(r io.Reader) ([]*types.AddressLabel, error) {
+	var labels []*types.AddressLabel
+	if err := json.NewDecoder(r).Decode(&labels); err != nil {
+		return nil, ErrInvalidLabelFile
+	}
+	if len(labels) > maxLabelImportRows {
+		return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidLabelFile, maxLabelImportRows)
+	}
+	return labels, nil
+}
+
+// parseLabelsCSV read rows of address, name and category columns, plus optional source and confidence columns,
+// in the order of the header row
+func parseLabelsCSV(r io.Reader) ([]*types.AddressLabel, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, ErrInvalidLabelFile
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"address", "name", "category"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: missing %s column", ErrInvalidLabelFile, required)
+		}
+	}
+	cell := func(row []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+
+	var labels []*types.AddressLabel
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidLabelFile, err)
+		}
+		if len(labels) == maxLabelImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidLabelFile, maxLabelImportRows)
+		}
+		label := &types.AddressLabel{
+			Address: cell(row, "address"),
+			Label: types.Label{
+				Name:     cell(row, "name"),
+				Category: cell(row, "category"),
+				Source:   cell(row, "source"),
+			},
+		}
+		if confidence := strings.TrimSpace(cell(row, "confidence")); confidence != "" {
+			// an unparsable confidence is out of range so the row is rejected
+			label.Confidence, err = strconv.ParseFloat(confidence, 64)
+			if err != nil {
+				label.Confidence = -1
+			}
+		}
+		labels = append(labels, label)
+	}
+	return labels, nil
+}
+
+// sanitizeAddressLabel checksum the address and normalize the category, a label without source get defaultSource
+// and one without confidence is certain
+func sanitizeAddressLabel(label *types.AddressLabel, defaultSource string) error {
+	label.Address = strings.TrimSpace(label.Address)
+	if !common.IsHexAddress(label.Address) {
+		return fmt.Errorf("%w: bad address %q", ErrInvalidLabel, label.Address)
+	}
+	label.Address = common.HexToAddress(label.Address).String()
+	label.Name = strings.TrimSpace(label.Name)
+	if label.Name == "" || len(label.Name) > maxLabelNameLength {
+		return fmt.Errorf("%w: name must have 1 to %d characters", ErrInvalidLabel, maxLabelNameLength)
+	}
+	label.Category = strings.ToLower(strings.TrimSpace(label.Category))
+	if !types.LabelCategories[label.Category] {
+		return fmt.Errorf("%w: unknown category %q", ErrInvalidLabel, label.Category)
+	}
+	label.Source = strings.TrimSpace(label.Source)
+	if label.Source == "" {
+		label.Source = defaultSource
+	}
+	if label.Confidence == 0 {
+		label.Confidence = 1
+	}
+	if label.Confidence < 0 || label.Confidence > 1 {
+		return fmt.Errorf("%w: confidence must be in (0, 1]", ErrInvalidLabel)
+	}
+	return nil
+}
+
+// labelFilter return the label filter of a list, nil when the list is not filtered by label
+func labelFilter(c echo.Context) *types.AddressLabelsFilter {
+	category, name := c.QueryParam("labelCategory"), c.QueryParam("label")
+	if category == "" && name == "" {
+		return nil
+	}
+	return &types.AddressLabelsFilter{Category: category, Name: name}
+}
+
+// labeledAddresses return addresses carrying a label matching filter
+func (s *Server) labeledAddresses(ctx context.Context, filter *types.AddressLabelsFilter) ([]string, error) {
+	addresses, err := s.dbClient.LabeledAddresses(ctx, filter, maxLabeledAddresses)
+	if err != nil {
+		s.logger.Warn("Cannot get labeled addresses", zap.Error(err))
+		return nil, err
+	}
+	return addresses, nil
+}
+
+// addressLabels return labels of addresses, responses are rendered without labels when they cannot be read
+func (s *Server) addressLabels(ctx context.Context, addresses ...string) map[string][]*types.Label {
+	var (
+		unique []string
+		seen   = make(map[string]bool)
+	)
+	for _, address := range addresses {
+		if address != "" && !seen[address] {
+			seen[address] = true
+			unique = append(unique, address)
+		}
+	}
+	sort.Strings(unique)
+	labels, err := s.dbClient.LabelsOfAddresses(ctx, unique)
+	if err != nil {
+		s.logger.Warn("Cannot get labels of addresses", zap.Error(err))
+		return nil
+	}
+	return labels
+}
+
+// labelTxs set labels of senders and receivers of txs
+func (s *Server) labelTxs(ctx context.Context, txs Transactions) {
+	addresses := make([]string, 0, 2*len(txs))
+	for _, tx := range txs {
+		addresses = append(addresses, tx.From, tx.To)
+	}
+	labels := s.addressLabels(ctx, addresses...)
+	for i := range txs {
+		txs[i].FromLabels = labels[txs[i].From]
+		txs[i].ToLabels = labels[txs[i].To]
+	}
+}
+
+// labelInternalTxs set labels of senders and receivers of token transfers
+func (s *Server) labelInternalTxs(ctx context.Context, txs []*InternalTransaction) {
+	addresses := make([]string, 0, 2*len(txs))
+	for _, tx := range txs {
+		addresses = append(addresses, tx.From, tx.To)
+	}
+	labels := s.addressLabels(ctx, addresses...)
+	for _, tx := range txs {
+		tx.FromLabels = labels[tx.From]
+		tx.ToLabels = labels[tx.To]
+	}
+}
+
+// labelValidators set labels of validators and of their delegators
+func (s *Server) labelValidators(ctx context.Context, validators []*types.Validator) {
+	var addresses []string
+	for _, v := range validators {
+		addresses = append(addresses, v.Address, v.SmcAddress)
+		for _, d := range v.Delegators {
+			addresses = append(addresses, d.Address)
+		}
+	}
+	labels := s.addressLabels(ctx, addresses...)
+	for _, v := range validators {
+		v.Labels = append(append([]*types.Label(nil), labels[v.Address]...), labels[v.SmcAddress]...)
+		for _, d := range v.Delegators {
+			d.Labels = labels[d.Address]
+		}
+	}
+}
+
+// labelKRC20Holders set labels of token holders
+func (s *Server) labelKRC20Holders(ctx context.Context, holders []*types.KRC20Holder) {
+	addresses := make([]string, len(holders))
+	for i, holder := range holders {
+		addresses[i] = holder.HolderAddress
+	}
+	labels := s.addressLabels(ctx, addresses...)
+	for _, holder := range holders {
+		holder.HolderLabels = labels[holder.HolderAddress]
+	}
+}
+
+func (s *Server) labelKRC721Holders(ctx context.Context, holders []*types.KRC721Holder) {
+	addresses := make([]string, len(holders))
+	for i, holder := range holders {
+		addresses[i] = holder.Address
+	}
+	labels := s.addressLabels(ctx, addresses...)
+	for _, holder := range holders {
+		holder.Labels = labels[holder.Address]
+	}
+}
+
+// labelSearchResults set labels of hits identified by an address
+func (s *Server) labelSearchResults(ctx context.Context, results []*types.SearchResult) {
+	var addresses []string
+	for _, result := range results {
+		if common.IsHexAddress(result.ID) {
+			addresses = append(addresses, result.ID)
+		}
+	}
+	if len(addresses) == 0 {
+		return
+	}
+	labels := s.addressLabels(ctx, addresses...)
+	for _, result := range results {
+		result.Labels = labels[result.ID]
+	}
+}
@@ -0,0 +1,132 @@
+// Package api
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+func importLabels(t *testing.T, f *fakeServer, target, contentType, body string) (int, *types.LabelImportResult) {
+	_, rec := callHandler(t, f.ImportAddressLabels, newRequest(echo.POST, target, strings.NewReader(body),
+		map[string]string{echo.HeaderContentType: contentType}))
+	if rec.Code != http.StatusOK {
+		return rec.Code, nil
+	}
+	var result *types.LabelImportResult
+	decodeData(t, rec, &result)
+	return rec.Code, result
+}
+
+func TestImportAddressLabels_CSV(t *testing.T) {
+	f := newFakeServer()
+	code, result := importLabels(t, f, "/api/v1/admin/labels/import?source=kaiscan", "text/csv", strings.Join([]string{
+		"category,address,name,confidence",
+		"exchange,0xc1fe56e3f58d3244f606306611a5d10c8333f1f6,Hot Wallet,0.9",
+		"Bridge, 0x14191195F9BB6e54465a341CeC6cce4491599ccC ,KAI Bridge,",
+		"casino,0x14191195F9BB6e54465a341CeC6cce4491599ccC,Lucky,",
+		"scam,0x1234,Phishing,",
+		"scam,0xc1fe56e3f58d3244f606306611a5d10c8333f1f6,Drainer,high",
+	}, "\n"))
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 2, result.Imported)
+	assert.Equal(t, 3, result.Rejected)
+	assert.Contains(t, result.Errors[3], "unknown category")
+	assert.Contains(t, result.Errors[4], "bad address")
+	assert.Contains(t, result.Errors[5], "confidence")
+
+	require.Len(t, f.db.labels, 2)
+	assert.Equal(t, "0xc1fe56E3F58D3244F606306611a5d10c8333f1f6", f.db.labels[0].Address)
+	assert.Equal(t, types.Label{Name: "Hot Wallet", Category: types.LabelCategoryExchange, Source: "kaiscan", Confidence: 0.9}, f.db.labels[0].Label)
+	assert.Equal(t, types.Label{Name: "KAI Bridge", Category: types.LabelCategoryBridge, Source: "kaiscan", Confidence: 1}, f.db.labels[1].Label)
+}
+
+func TestImportAddressLabels_JSON(t *testing.T) {
+	f := newFakeServer()
+	code, result := importLabels(t, f, "/api/v1/admin/labels/import", echo.MIMEApplicationJSON,
+		`[{"address":"0x3a6a1E6bE6aC2F0b6D8b8A0c35e1D7B5E0a1C2D3","name":"Kaiswap Router","category":"dex_router","source":"kaiswap"},
+		  {"address":"0x3a6a1E6bE6aC2F0b6D8b8A0c35e1D7B5E0a1C2D3","name":"  ","category":"dex_router"}]`)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, 1, result.Rejected)
+	assert.Equal(t, "kaiswap", f.db.labels[0].Source)
+
+	code, _ = importLabels(t, f, "/api/v1/admin/labels/import", "text/csv", "address,name\n0x3a6a1E6bE6aC2F0b6D8b8A0c35e1D7B5E0a1C2D3,Router\n")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestRemoveAddressLabel(t *testing.T) {
+	stored := &types.AddressLabel{Address: "0xc1fe56E3F58D3244F606306611a5d10c8333f1f6",
+		Label: types.Label{Name: "Hot Wallet", Category: types.LabelCategoryExchange, Source: "kaiscan", Confidence: 0.9}}
+	f := newFakeServer()
+	f.db.labels = []*types.AddressLabel{stored}
+	remove := func() (echo.Context, *httptest.ResponseRecorder) {
+		return callHandler(t, f.RemoveAddressLabel, newRequest(echo.DELETE,
+			"/api/v1/admin/labels?address=0xc1fe56e3f58d3244f606306611a5d10c8333f1f6&category=Exchange&label=Hot+Wallet", nil, nil))
+	}
+
+	c, rec := remove()
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, f.db.labels)
+	// the audit log keep source and confidence of the removed label
+	audit := c.Get(contextAudit).(*auditState)
+	assert.Equal(t, stored, audit.before)
+	assert.Nil(t, audit.after)
+
+	c, rec = remove()
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Nil(t, c.Get(contextAudit))
+}
+
+func TestLabelTxs(t *testing.T) {
+	f := newFakeServer()
+	f.db.labels = []*types.AddressLabel{
+		{Address: "0xc1fe56E3F58D3244F606306611a5d10c8333f1f6", Label: types.Label{Name: "Hot Wallet", Category: types.LabelCategoryExchange}},
+		{Address: "0xc1fe56E3F58D3244F606306611a5d10c8333f1f6", Label: types.Label{Name: "Drainer", Category: types.LabelCategoryScam}},
+	}
+	txs := Transactions{
+		{From: "0xc1fe56E3F58D3244F606306611a5d10c8333f1f6", To: "0x14191195F9BB6e54465a341CeC6cce4491599ccC"},
+		{From: "0x14191195F9BB6e54465a341CeC6cce4491599ccC", To: "0xc1fe56E3F58D3244F606306611a5d10c8333f1f6"},
+	}
+	f.labelTxs(context.Background(), txs)
+	assert.Len(t, txs[0].FromLabels, 2)
+	assert.Empty(t, txs[0].ToLabels)
+	assert.Empty(t, txs[1].FromLabels)
+	assert.Equal(t, "Drainer", txs[1].ToLabels[1].Name)
+}
+
+func TestLabelValidatorsAndHolders(t *testing.T) {
+	f := newFakeServer()
+	f.db.labels = []*types.AddressLabel{
+		{Address: "0x7F0E7F2A3F4C6D4A9A0E5B7E9C3D2B1A0F9E8D7C", Label: types.Label{Name: "Kai Team", Category: types.LabelCategoryExchange}},
+		{Address: "0x0000000000000000000000000000000000000101", Label: types.Label{Name: "Genesis Validator", Category: types.LabelCategoryBridge}},
+		{Address: "0xc1fe56E3F58D3244F606306611a5d10c8333f1f6", Label: types.Label{Name: "Hot Wallet", Category: types.LabelCategoryExchange}},
+	}
+	validator := &types.Validator{
+		Address:    "0x7F0E7F2A3F4C6D4A9A0E5B7E9C3D2B1A0F9E8D7C",
+		SmcAddress: "0x0000000000000000000000000000000000000101",
+		Delegators: []*types.Delegator{
+			{Address: "0xc1fe56E3F58D3244F606306611a5d10c8333f1f6"},
+			{Address: "0x14191195F9BB6e54465a341CeC6cce4491599ccC"},
+		},
+	}
+	f.labelValidators(context.Background(), []*types.Validator{validator})
+	require.Len(t, validator.Labels, 2)
+	assert.Equal(t, "Kai Team", validator.Labels[0].Name)
+	assert.Equal(t, "Genesis Validator", validator.Labels[1].Name)
+	assert.Equal(t, "Hot Wallet", validator.Delegators[0].Labels[0].Name)
+	assert.Empty(t, validator.Delegators[1].Labels)
+
+	holders := []*types.KRC20Holder{{HolderAddress: "0xc1fe56E3F58D3244F606306611a5d10c8333f1f6"}, {HolderAddress: "0x14191195F9BB6e54465a341CeC6cce4491599ccC"}}
+	f.labelKRC20Holders(context.Background(), holders)
+	assert.Equal(t, "Hot Wallet", holders[0].HolderLabels[0].Name)
+	assert.Empty(t, holders[1].HolderLabels)
+}
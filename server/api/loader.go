@@ -82,6 +82,10 @@ func (s *Server) LoadBootData(ctx context.Context) error {
 		}
 	}
 
+	if err := s.loadSignatures(ctx, smcABIByType); err != nil {
+		s.logger.Warn("Cannot load signatures", zap.Error(err))
+	}
+
 	// insert boot smc and ABI(type) to db
 	validators, err := s.dbClient.Validators(ctx, db.ValidatorsFilter{})
 	if err != nil || len(validators) == 0 {
@@ -0,0 +1,181 @@
+// Package api
+package api
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/labstack/echo"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+var ErrInvalidPriceSource = errors.New("invalid token price source")
+
+type IMarket interface {
+	KaiPrices(c echo.Context) error
+	TokenPrices(c echo.Context) error
+	TokenPriceSources(c echo.Context) error
+	UpsertTokenPriceSource(c echo.Context) error
+	RemoveTokenPriceSource(c echo.Context) error
+}
+
+func bindMarketAPIs(gr *echo.Group, srv RestServer) {
+	apis := []restDefinition{
+		{
+			method: echo.GET,
+			// Query params: ?from=2021-05-01&to=2021-05-31, from and to also accept unix time
+			path:        "/market/prices",
+			fn:          srv.KaiPrices,
+			middlewares: nil,
+		},
+		{
+			method: echo.GET,
+			// Query params: same as /market/prices
+			path:        "/tokens/:contractAddress/prices",
+			fn:          srv.TokenPrices,
+			middlewares: nil,
+		},
+		{
+			method:      echo.GET,
+			path:        "/tokens/price-sources",
+			fn:          srv.TokenPriceSources,
+			middlewares: nil,
+		},
+	}
+	for _, api := range apis {
+		gr.Add(api.method, api.path, api.fn, api.middlewares...)
+	}
+}
+
+// KaiPrices return hourly KAI/USD prices in range, default range is last 30 days
+func (s *Server) KaiPrices(c echo.Context) error {
+	return s.tokenPrices(c, types.PriceTokenKAI)
+}
+
+// TokenPrices return hourly USD prices of a KRC20 token in range, default range is last 30 days
+func (s *Server) TokenPrices(c echo.Context) error {
+	address := c.Param("contractAddress")
+	if !common.IsHexAddress(address) {
+		return Invalid.Build(c)
+	}
+	return s.tokenPrices(c, common.HexToAddress(address).Hex())
+}
+
+func (s *Server) tokenPrices(c echo.Context, token string) error {
+	ctx := c.Request().Context()
+	now := time.Now()
+	filter := &types.TokenPricesFilter{
+		Token:     token,
+		StartTime: now.AddDate(0, 0, -30),
+		EndTime:   now,
+	}
+	var err error
+	if from := c.QueryParam("from"); from != "" {
+		if filter.StartTime, err = parseChartTime(from); err != nil {
+			return Invalid.Build(c)
+		}
+	}
+	if to := c.QueryParam("to"); to != "" {
+		if filter.EndTime, err = parseChartTime(to); err != nil {
+			return Invalid.Build(c)
+		}
+	}
+	if filter.EndTime.Before(filter.StartTime) || filter.EndTime.Sub(filter.StartTime) > maxChartDays*24*time.Hour {
+		return Invalid.Build(c)
+	}
+	prices, err := s.dbClient.TokenPrices(ctx, filter)
+	if err != nil {
+		s.logger.Warn("Cannot get token prices from db", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	return OK.SetData(prices).Build(c)
+}
+
+func (s *Server) TokenPriceSources(c echo.Context) error {
+	ctx := c.Request().Context()
+	sources, err := s.dbClient.TokenPriceSources(ctx)
+	if err != nil {
+		s.logger.Warn("Cannot get token price sources from db", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	return OK.SetData(sources).Build(c)
+}
+
+// UpsertTokenPriceSource set where grabber pull price of a KRC20 token from
+func (s *Server) UpsertTokenPriceSource(c echo.Context) error {
+	ctx := c.Request().Context()
+	var source *types.TokenPriceSource
+	if err := c.Bind(&source); err != nil || source == nil {
+		return Invalid.Build(c)
+	}
+	source.Token = c.Param("contractAddress")
+	if err := sanitizeTokenPriceSource(source); err != nil {
+		return Invalid.Build(c)
+	}
+	before := s.tokenPriceSource(ctx, source.Token)
+	source.UpdatedAt = time.Now()
+	if err := s.dbClient.UpsertTokenPriceSource(ctx, source); err != nil {
+		s.logger.Warn("Cannot upsert token price source", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	setAudit(c, "priceSource:"+source.Token, before, source)
+	return OK.SetData(source).Build(c)
+}
+
+func (s *Server) RemoveTokenPriceSource(c echo.Context) error {
+	ctx := c.Request().Context()
+	address := c.Param("contractAddress")
+	if !common.IsHexAddress(address) {
+		return Invalid.Build(c)
+	}
+	token := common.HexToAddress(address).Hex()
+	before := s.tokenPriceSource(ctx, token)
+	if err := s.dbClient.RemoveTokenPriceSource(ctx, token); err != nil {
+		s.logger.Warn("Cannot remove token price source", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	setAudit(c, "priceSource:"+token, before, nil)
+	return OK.SetData(nil).Build(c)
+}
+
+// tokenPriceSource return current price source of token, nil when it has none
+func (s *Server) tokenPriceSource(ctx context.Context, token string) *types.TokenPriceSource {
+	sources, err := s.dbClient.TokenPriceSources(ctx)
+	if err != nil {
+		return nil
+	}
+	for _, source := range sources {
+		if source.Token == token {
+			return source
+		}
+	}
+	return nil
+}
+
+// sanitizeTokenPriceSource checksum addresses and keep only fields of the source type
+func sanitizeTokenPriceSource(source *types.TokenPriceSource) error {
+	if !common.IsHexAddress(source.Token) {
+		return ErrInvalidPriceSource
+	}
+	source.Token = common.HexToAddress(source.Token).Hex()
+	switch source.Type {
+	case types.PriceSourceMarket:
+		if source.CoinGeckoID == "" && source.CoinMarketCapID == 0 {
+			return ErrInvalidPriceSource
+		}
+		source.PairAddress = ""
+	case types.PriceSourceDEX:
+		if !common.IsHexAddress(source.PairAddress) {
+			return ErrInvalidPriceSource
+		}
+		source.PairAddress = common.HexToAddress(source.PairAddress).Hex()
+		source.CoinGeckoID, source.CoinMarketCapID = "", 0
+	default:
+		return ErrInvalidPriceSource
+	}
+	return nil
+}
@@ -21,9 +21,14 @@ package api
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/semconv"
 
+	"github.com/kardiachain/kardia-explorer-backend/metrics"
+	"github.com/kardiachain/kardia-explorer-backend/tracing"
 	"github.com/kardiachain/kardia-explorer-backend/types"
 )
 
@@ -41,3 +46,80 @@ func checkPagination() echo.MiddlewareFunc {
 		}
 	}
 }
+
+// validateRequest reject requests whose path or query params do not match the OpenAPI spec,
+// routes without spec and unknown params are let through
+func validateRequest() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			op := apiSpec().operations[c.Request().Method+" "+c.Path()]
+			if op == nil {
+				return next(c)
+			}
+			if err := op.validateParams(c); err != nil {
+				resp := Invalid
+				return resp.SetData(err.Error()).Build(c)
+			}
+			return next(c)
+		}
+	}
+}
+
+// recordMetrics observe handler latency by route pattern, requests matching no route are grouped together
+// so unknown URLs cannot blow up the number of series
+// unmatchedPath label requests no route match, router keep their raw URL as path
+const unmatchedPath = "unmatched"
+
+func recordMetrics() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			status := c.Response().Status
+			if err != nil {
+				// error handler has not written the response yet
+				status = http.StatusInternalServerError
+				if he, ok := err.(*echo.HTTPError); ok {
+					status = he.Code
+				}
+			}
+			path := c.Path()
+			// router keep the requested URL as path when no route match
+			if err == echo.ErrNotFound || err == echo.ErrMethodNotAllowed {
+				path = unmatchedPath
+			}
+			metrics.ObserveHTTP(c.Request().Method, path, status, time.Since(start))
+			return err
+		}
+	}
+}
+
+// traceRequest start the server span of the request, continuing the trace of the caller when
+// trace headers are sent. Handlers get the span through c.Request().Context().
+func traceRequest() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := tracing.Extract(req.Context(), req.Header)
+			ctx, span := tracing.Tracer().Start(ctx, req.Method+" "+c.Path(),
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPMethodKey.String(req.Method),
+					semconv.HTTPRouteKey.String(c.Path()),
+					semconv.HTTPTargetKey.String(req.URL.RequestURI()),
+					semconv.HTTPClientIPKey.String(c.RealIP()),
+				),
+			)
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+			status := c.Response().Status
+			if he, ok := err.(*echo.HTTPError); ok {
+				status = he.Code
+			}
+			span.SetAttributes(semconv.HTTPStatusCodeKey.Int(status))
+			tracing.End(ctx, span, err)
+			return err
+		}
+	}
+}
@@ -42,8 +42,10 @@ type SimpleTransaction struct {
 	Time               time.Time           `json:"time"`
 	From               string              `json:"from"`
 	FromName           string              `json:"fromName,omitempty"`
+	FromLabels         []*types.Label      `json:"fromLabels,omitempty"`
 	To                 string              `json:"to"`
 	ToName             string              `json:"toName,omitempty"`
+	ToLabels           []*types.Label      `json:"toLabels,omitempty"`
 	IsInValidatorsList bool                `json:"isInValidatorsList"`
 	Role               int                 `json:"role"`
 	ContractAddress    string              `json:"contractAddress,omitempty"`
@@ -61,8 +63,10 @@ type Transaction struct {
 	Hash               string                 `json:"hash"`
 	From               string                 `json:"from"`
 	FromName           string                 `json:"fromName,omitempty"`
+	FromLabels         []*types.Label         `json:"fromLabels,omitempty"`
 	To                 string                 `json:"to"`
 	ToName             string                 `json:"toName,omitempty"`
+	ToLabels           []*types.Label         `json:"toLabels,omitempty"`
 	IsInValidatorsList bool                   `json:"isInValidatorsList"`
 	Role               int                    `json:"role"`
 	Status             uint                   `json:"status"`
@@ -99,6 +103,8 @@ type SimpleAddress struct {
 	IsInValidatorsList bool   `json:"isInValidatorsList"`
 	Role               int    `json:"role"`
 	Rank               uint64 `json:"rank"`
+
+	Labels []*types.Label `json:"labels,omitempty"`
 }
 
 type valInfoResponse struct {
@@ -122,6 +128,20 @@ type KRCTokenInfo struct {
 	// SMC
 	IsContract bool `json:"isContract,omitempty"`
 
+	// Verified source, each file is returned separately
+	SourceFiles     []*types.ContractSource  `json:"sourceFiles,omitempty"`
+	CompilerVersion string                   `json:"compilerVersion,omitempty"`
+	IsOptimize      bool                     `json:"isOptimize,omitempty"`
+	OptimizeRuns    int                      `json:"optimizeRuns,omitempty"`
+	EVMVersion      string                   `json:"evmVersion,omitempty"`
+	Remappings      []string                 `json:"remappings,omitempty"`
+	Libraries       []*types.ContractLibrary `json:"libraries,omitempty"`
+
+	// Proxy
+	ProxyType      string `json:"proxyType,omitempty"`
+	Implementation string `json:"implementation,omitempty"`
+	Beacon         string `json:"beacon,omitempty"`
+
 	// Token
 	TokenName   string `json:"tokenName,omitempty"`
 	TokenSymbol string `json:"tokenSymbol,omitempty"`
@@ -155,10 +175,51 @@ type SimpleKRCTokenInfo struct {
 type InternalTransaction struct {
 	*types.Log
 	*types.KRCTokenInfo
-	From     string `json:"from,omitempty"`
-	FromName string `json:"fromName,omitempty"`
-	To       string `json:"to,omitempty"`
-	ToName   string `json:"toName,omitempty"`
-	Value    string `json:"value,omitempty"`
-	TokenID  string `json:"tokenID,omitempty"`
+	From       string         `json:"from,omitempty"`
+	FromName   string         `json:"fromName,omitempty"`
+	FromLabels []*types.Label `json:"fromLabels,omitempty"`
+	To         string         `json:"to,omitempty"`
+	ToName     string         `json:"toName,omitempty"`
+	ToLabels   []*types.Label `json:"toLabels,omitempty"`
+	Value      string         `json:"value,omitempty"`
+	TokenID    string         `json:"tokenID,omitempty"`
+}
+
+type AddressBalance struct {
+	Address     string `json:"address"`
+	BlockHeight uint64 `json:"blockHeight"`
+	// Balance is in hydro
+	Balance string `json:"balance"`
+}
+
+type ChartPoint struct {
+	Time  time.Time   `json:"time"`
+	Value interface{} `json:"value"`
+}
+
+type PortfolioHolding struct {
+	// Token is "KAI" for native coin or the KRC20 contract address
+	Token    string `json:"token"`
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals int64  `json:"decimals"`
+	Logo     string `json:"logo,omitempty"`
+	Balance  string `json:"balance"`
+
+	// Price is zero and HasPrice false when token has no price source
+	HasPrice       bool      `json:"hasPrice"`
+	Price          float64   `json:"price"`
+	PriceUpdatedAt time.Time `json:"priceUpdatedAt,omitempty"`
+	Value          float64   `json:"value"`
+	// Change24h is price change in percent, ValueChange24h is in USD
+	Change24h      float64 `json:"change24h"`
+	ValueChange24h float64 `json:"valueChange24h"`
+}
+
+type Portfolio struct {
+	Address        string              `json:"address"`
+	TotalValue     float64             `json:"totalValue"`
+	Change24h      float64             `json:"change24h"`
+	ValueChange24h float64             `json:"valueChange24h"`
+	Holdings       []*PortfolioHolding `json:"holdings"`
 }
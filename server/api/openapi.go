@@ -0,0 +1,519 @@
+// Package api
+package api
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo"
+
+	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+const (
+	openAPIVersion = "3.0.3"
+	openAPIBaseURL = "/api/v1"
+
+	securityAdminToken = "adminToken"
+	securityAPIKey     = "apiKey"
+)
+
+var operationIDSeparator = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+type openAPISpec struct {
+	OpenAPI    string                     `json:"openapi"`
+	Info       *openAPIInfo               `json:"info"`
+	Servers    []*openAPIServer           `json:"servers"`
+	Paths      map[string]openAPIPathItem `json:"paths"`
+	Components *openAPIComponents         `json:"components"`
+
+	// operations index operation by method and echo route path
+	operations map[string]*openAPIOperation
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url"`
+}
+
+// openAPIPathItem map lower case method to operation
+type openAPIPathItem map[string]*openAPIOperation
+
+type openAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary,omitempty"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Parameters  []*openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+	Security    []map[string][]string       `json:"security,omitempty"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                         `json:"required,omitempty"`
+	Content  map[string]*openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema         `json:"schemas"`
+	SecuritySchemes map[string]*openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Pattern              string                    `json:"pattern,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Minimum              *float64                  `json:"minimum,omitempty"`
+	Maximum              *float64                  `json:"maximum,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// routeDoc describe a route registered in bind, path is the echo path under /api/v1
+type routeDoc struct {
+	method  string
+	path    string
+	summary string
+	query   []*openAPIParameter
+	// params override schema of path params named in pathParamSchemas
+	params map[string]*openAPISchema
+	body   interface{}
+	// data is the value of response data, nil for responses without data
+	data   interface{}
+	paging bool
+	// roles allowed to call an admin route, superadmin is always allowed
+	roles []string
+	// produces is content type of responses which are not JSON
+	produces []string
+}
+
+var (
+	addressPattern = `^(0x)?[0-9a-fA-F]{40}$`
+	hashPattern    = `^(0x)?[0-9a-fA-F]{64}$`
+
+	// pathParamSchemas is schema of path params by name, shared by every route
+	pathParamSchemas = map[string]*openAPISchema{
+		"address":         {Type: "string", Pattern: addressPattern},
+		"contractAddress": {Type: "string", Pattern: addressPattern},
+		"txHash":          {Type: "string", Pattern: hashPattern},
+		"block":           {Type: "string", Pattern: `^([0-9]+|0x[0-9a-fA-F]{64})$`, Description: "Block height or hash"},
+		"hash":            {Type: "string", Pattern: `^(0x)?([0-9a-fA-F]{8}|[0-9a-fA-F]{64})$`, Description: "4 bytes method selector or 32 bytes event topic"},
+	}
+
+	openAPIOnce sync.Once
+	openAPI     *openAPISpec
+)
+
+type IOpenAPI interface {
+	OpenAPI(c echo.Context) error
+}
+
+func bindOpenAPIs(gr *echo.Group, srv RestServer) {
+	apis := []restDefinition{
+		{
+			method:      echo.GET,
+			path:        "/openapi.json",
+			fn:          srv.OpenAPI,
+			middlewares: nil,
+		},
+	}
+	for _, api := range apis {
+		gr.Add(api.method, api.path, api.fn, api.middlewares...)
+	}
+}
+
+// OpenAPI serve the OpenAPI 3 specification of /api/v1
+func (s *Server) OpenAPI(c echo.Context) error {
+	return c.JSON(http.StatusOK, apiSpec())
+}
+
+// apiSpec return the specification built from routeDocs
+func apiSpec() *openAPISpec {
+	openAPIOnce.Do(func() {
+		openAPI = newOpenAPISpec(routeDocs)
+	})
+	return openAPI
+}
+
+func newOpenAPISpec(docs []*routeDoc) *openAPISpec {
+	spec := &openAPISpec{
+		OpenAPI: openAPIVersion,
+		Info: &openAPIInfo{
+			Title: "KardiaChain Explorer API",
+			Description: "Every JSON response is wrapped in {code, msg, data}. The Etherscan-compatible API served " +
+				"at /api follows the Etherscan documentation and is not described here.",
+			Version: cfg.ServerVersion,
+		},
+		Servers: []*openAPIServer{{URL: openAPIBaseURL}},
+		Paths:   make(map[string]openAPIPathItem),
+		Components: &openAPIComponents{
+			Schemas: make(map[string]*openAPISchema),
+			SecuritySchemes: map[string]*openAPISecurityScheme{
+				securityAdminToken: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+				securityAPIKey:     {Type: "apiKey", In: "header", Name: HeaderAPIKey},
+			},
+		},
+		operations: make(map[string]*openAPIOperation),
+	}
+	for _, doc := range docs {
+		op := spec.operation(doc)
+		specPath := openAPIPath(doc.path)
+		if spec.Paths[specPath] == nil {
+			spec.Paths[specPath] = make(openAPIPathItem)
+		}
+		spec.Paths[specPath][strings.ToLower(doc.method)] = op
+		spec.operations[doc.method+" "+openAPIBaseURL+doc.path] = op
+	}
+	return spec
+}
+
+func (spec *openAPISpec) operation(doc *routeDoc) *openAPIOperation {
+	op := &openAPIOperation{
+		OperationID: operationID(doc.method, doc.path),
+		Summary:     doc.summary,
+		Tags:        []string{strings.Split(strings.TrimPrefix(doc.path, "/"), "/")[0]},
+		Responses: map[string]*openAPIResponse{
+			"400": {Description: "Invalid request"},
+		},
+	}
+	for _, segment := range strings.Split(doc.path, "/") {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		name := strings.TrimPrefix(segment, ":")
+		schema, ok := doc.params[name]
+		if !ok {
+			if schema, ok = pathParamSchemas[name]; !ok {
+				schema = &openAPISchema{Type: "string"}
+			}
+		}
+		op.Parameters = append(op.Parameters, &openAPIParameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+	if doc.paging {
+		op.Parameters = append(op.Parameters, pagingParams()...)
+	}
+	op.Parameters = append(op.Parameters, doc.query...)
+	for _, param := range op.Parameters {
+		param.Schema.compile()
+	}
+
+	if doc.body != nil {
+		op.RequestBody = &openAPIRequestBody{
+			Required: true,
+			Content:  map[string]*openAPIMediaType{echo.MIMEApplicationJSON: {Schema: spec.schemaOf(reflect.TypeOf(doc.body))}},
+		}
+	}
+
+	ok := &openAPIResponse{Description: "Success"}
+	if len(doc.produces) > 0 {
+		ok.Content = make(map[string]*openAPIMediaType)
+		for _, contentType := range doc.produces {
+			ok.Content[contentType] = &openAPIMediaType{Schema: &openAPISchema{Type: "string"}}
+		}
+	} else {
+		ok.Content = map[string]*openAPIMediaType{echo.MIMEApplicationJSON: {Schema: spec.envelope(doc)}}
+	}
+	op.Responses["200"] = ok
+
+	if strings.HasPrefix(doc.path, "/admin") && doc.path != "/admin/login" {
+		op.Summary += fmt.Sprintf(" (roles: %s)", strings.Join(doc.roles, ", "))
+		op.Security = []map[string][]string{{securityAdminToken: {}}}
+		op.Responses["401"] = &openAPIResponse{Description: "Missing or invalid admin token"}
+		op.Responses["403"] = &openAPIResponse{Description: "Admin role is not allowed"}
+	} else {
+		// API key is optional, requests without key are limited as anonymous
+		op.Security = []map[string][]string{{}, {securityAPIKey: {}}}
+		op.Responses["429"] = &openAPIResponse{Description: "Rate limit or daily quota exceeded"}
+	}
+	return op
+}
+
+// envelope wrap response data of route in the {code, msg, data} response
+func (spec *openAPISpec) envelope(doc *routeDoc) *openAPISchema {
+	schema := &openAPISchema{
+		Type: "object",
+		Properties: map[string]*openAPISchema{
+			"code": {Type: "integer"},
+			"msg":  {Type: "string"},
+		},
+	}
+	if doc.data == nil {
+		return schema
+	}
+	data := spec.schemaOf(reflect.TypeOf(doc.data))
+	if doc.paging {
+		data = &openAPISchema{
+			Type: "object",
+			Properties: map[string]*openAPISchema{
+				"page":  {Type: "integer"},
+				"limit": {Type: "integer"},
+				"total": {Type: "integer"},
+				"data":  data,
+			},
+		}
+	}
+	schema.Properties["data"] = data
+	return schema
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	bigIntType        = reflect.TypeOf(big.Int{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// schemaOf describe how type is encoded to JSON, named structs are added to components
+func (spec *openAPISpec) schemaOf(t reflect.Type) *openAPISchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	case t == bigIntType:
+		return &openAPISchema{Type: "integer"}
+	case reflect.PtrTo(t).Implements(textMarshalerType) && !reflect.PtrTo(t).Implements(jsonMarshalerType):
+		return &openAPISchema{Type: "string"}
+	case reflect.PtrTo(t).Implements(jsonMarshalerType):
+		return &openAPISchema{}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &openAPISchema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number"}
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+		return &openAPISchema{Type: "array", Items: spec.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: spec.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return spec.structSchema(t)
+		}
+		name := path.Base(t.PkgPath()) + "." + t.Name()
+		if _, ok := spec.Components.Schemas[name]; !ok {
+			// placeholder stop recursion of self referencing types
+			spec.Components.Schemas[name] = &openAPISchema{}
+			spec.Components.Schemas[name] = spec.structSchema(t)
+		}
+		return &openAPISchema{Ref: "#/components/schemas/" + name}
+	}
+	// interface and other kinds may hold any value
+	return &openAPISchema{}
+}
+
+func (spec *openAPISpec) structSchema(t reflect.Type) *openAPISchema {
+	schema := &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if idx := strings.Index(tag, ","); idx >= 0 {
+			name, opts = tag[:idx], tag[idx+1:]
+		}
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			// fields of embedded struct are promoted
+			for k, v := range spec.structSchema(fieldType).Properties {
+				schema.Properties[k] = v
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(opts, "string") {
+			schema.Properties[name] = &openAPISchema{Type: "string"}
+			continue
+		}
+		schema.Properties[name] = spec.schemaOf(field.Type)
+	}
+	return schema
+}
+
+func (s *openAPISchema) compile() {
+	if s.Pattern != "" && s.pattern == nil {
+		s.pattern = regexp.MustCompile(s.Pattern)
+	}
+	if s.Items != nil {
+		s.Items.compile()
+	}
+}
+
+// validate check a path or query value against a parameter schema
+func (s *openAPISchema) validate(value string) error {
+	switch s.Type {
+	case "integer", "number":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil || (s.Type == "integer" && strings.ContainsAny(value, ".eE")) {
+			return fmt.Errorf("must be %s", map[string]string{"integer": "an integer", "number": "a number"}[s.Type])
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			return fmt.Errorf("must be at least %v", *s.Minimum)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			return fmt.Errorf("must be at most %v", *s.Maximum)
+		}
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("must be a boolean")
+		}
+	}
+	if len(s.Enum) > 0 {
+		for _, v := range s.Enum {
+			if v == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(s.Enum, ", "))
+	}
+	if s.pattern != nil && !s.pattern.MatchString(value) {
+		return fmt.Errorf("must match %s", s.Pattern)
+	}
+	return nil
+}
+
+// validateParams return the first path or query parameter of the request not valid against operation
+func (op *openAPIOperation) validateParams(c echo.Context) error {
+	query := c.QueryParams()
+	for _, param := range op.Parameters {
+		var values []string
+		if param.In == "path" {
+			values = []string{c.Param(param.Name)}
+		} else {
+			values = query[param.Name]
+		}
+		if len(values) == 0 || (len(values) == 1 && values[0] == "") {
+			if param.Required {
+				return fmt.Errorf("%s parameter %s is required", param.In, param.Name)
+			}
+			continue
+		}
+		schema := param.Schema
+		if schema.Type == "array" {
+			schema = schema.Items
+		} else if len(values) > 1 {
+			return fmt.Errorf("%s parameter %s must be set once", param.In, param.Name)
+		}
+		for _, value := range values {
+			if err := schema.validate(value); err != nil {
+				return fmt.Errorf("%s parameter %s %s", param.In, param.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// openAPIPath convert echo path params to OpenAPI templates, /blocks/:block become /blocks/{block}
+func openAPIPath(echoPath string) string {
+	segments := strings.Split(echoPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + strings.TrimPrefix(segment, ":") + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// operationID is method and path in camel case, e.g. GET /blocks/:block become getBlocksBlock
+func operationID(method, echoPath string) string {
+	id := strings.ToLower(method)
+	for _, word := range operationIDSeparator.Split(echoPath, -1) {
+		if word != "" {
+			id += strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return id
+}
+
+func pagingParams() []*openAPIParameter {
+	return []*openAPIParameter{
+		{Name: "page", In: "query", Description: "Page number, starting from 1", Schema: &openAPISchema{Type: "integer", Minimum: float(0)}},
+		{Name: "limit", In: "query", Description: "Page size", Schema: &openAPISchema{Type: "integer", Minimum: float(0), Maximum: float(types.MaximumLimit)}},
+	}
+}
+
+func queryParam(name, description string, schema *openAPISchema) *openAPIParameter {
+	return &openAPIParameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+func float(v float64) *float64 {
+	return &v
+}
+
+// sortedKeys return keys of a map with string keys, used to list enums
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
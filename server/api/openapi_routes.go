@@ -0,0 +1,237 @@
+// Package api
+package api
+
+import (
+	"github.com/labstack/echo"
+
+	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+var (
+	dayOrUnixPattern = `^([0-9]+|[0-9]{4}-[0-9]{2}-[0-9]{2})$`
+	dayPattern       = `^[0-9]{4}-[0-9]{2}-[0-9]{2}$`
+
+	exportParams = []*openAPIParameter{
+		queryParam("format", "Export format, csv by default", &openAPISchema{Type: "string", Enum: []string{types.ExportFormatCSV, types.ExportFormatNDJSON}}),
+		queryParam("startTime", "Unix time", &openAPISchema{Type: "integer", Minimum: float(0)}),
+		queryParam("endTime", "Unix time", &openAPISchema{Type: "integer", Minimum: float(0)}),
+		queryParam("fromBlock", "", &openAPISchema{Type: "integer", Minimum: float(0)}),
+		queryParam("toBlock", "", &openAPISchema{Type: "integer", Minimum: float(0)}),
+		queryParam("limit", "Maximum rows, capped by the server", &openAPISchema{Type: "integer", Minimum: float(0)}),
+	}
+	rangeParams = []*openAPIParameter{
+		queryParam("from", "Day as YYYY-MM-DD or unix time", &openAPISchema{Type: "string", Pattern: dayOrUnixPattern}),
+		queryParam("to", "Day as YYYY-MM-DD or unix time", &openAPISchema{Type: "string", Pattern: dayOrUnixPattern}),
+	}
+	usageParams = []*openAPIParameter{
+		queryParam("from", "Day as YYYY-MM-DD, today by default", &openAPISchema{Type: "string", Pattern: dayPattern}),
+		queryParam("to", "Day as YYYY-MM-DD, at most 31 days after from", &openAPISchema{Type: "string", Pattern: dayPattern}),
+	}
+	labelFilterParams = []*openAPIParameter{
+		queryParam("labelCategory", "Only addresses with a label of this category", &openAPISchema{Type: "string", Enum: sortedKeys(types.LabelCategories)}),
+		queryParam("label", "Only addresses with a label of this name", &openAPISchema{Type: "string"}),
+	}
+	mobileValidators = struct {
+		*types.StakingStats
+		Validators []*types.Validator `json:"validators"`
+	}{}
+	totalHolders = struct {
+		TotalHolders   uint64 `json:"totalHolders"`
+		TotalContracts uint64 `json:"totalContracts"`
+	}{}
+)
+
+// routeDocs document every route of /api/v1, TestOpenAPI_Routes fail when a registered route is missing here
+var routeDocs = []*routeDoc{
+	// Health
+	{method: echo.GET, path: "/ping", summary: "Server version", data: struct {
+		Version string `json:"version"`
+	}{}},
+	{method: echo.GET, path: "/health/live", summary: "Liveness probe, answer as long as the process serves requests", data: struct {
+		Status  string `json:"status"`
+		Version string `json:"version"`
+	}{}},
+	{method: echo.GET, path: "/health/ready", summary: "Readiness probe, respond 503 when dependencies are down or the indexer lag pass the thresholds", data: &types.HealthReport{}},
+	{method: echo.GET, path: "/status", summary: "Server status", data: &types.ServerStatus{}},
+	{method: echo.GET, path: "/openapi.json", summary: "This OpenAPI specification", data: nil, produces: []string{echo.MIMEApplicationJSON}},
+	{method: echo.GET, path: "/nodes", summary: "Network nodes", data: []*NodeInfo{}},
+	{method: echo.GET, path: "/graphql", summary: "GraphQL query, the response is {data, errors} instead of the REST envelope", query: []*openAPIParameter{
+		queryParam("query", "", &openAPISchema{Type: "string"}),
+		queryParam("operationName", "", &openAPISchema{Type: "string"}),
+		queryParam("variables", "JSON object", &openAPISchema{Type: "string"}),
+	}, produces: []string{echo.MIMEApplicationJSON}},
+	{method: echo.POST, path: "/graphql", summary: "GraphQL query, the response is {data, errors} instead of the REST envelope",
+		body: &graphQLRequest{}, produces: []string{echo.MIMEApplicationJSON}},
+	{method: echo.GET, path: "/usage", summary: "Usage of the API key sent with request, per endpoint and day", query: usageParams, data: []*types.EndpointUsage{}},
+
+	// Dashboard
+	{method: echo.GET, path: "/dashboard/stats", summary: "Total holders and contracts", data: totalHolders},
+	{method: echo.GET, path: "/dashboard/holders/total", summary: "Total holders and contracts", data: totalHolders},
+	{method: echo.GET, path: "/dashboard/token", summary: "KAI market and supply info", data: &types.TokenInfo{}},
+
+	// Blocks
+	{method: echo.GET, path: "/blocks", summary: "Latest blocks", paging: true, data: Blocks{}},
+	{method: echo.GET, path: "/blocks/:block", summary: "Block by height or hash", data: &Block{}},
+	{method: echo.GET, path: "/block/:block/txs", summary: "Transactions of block", paging: true, data: Transactions{}},
+	{method: echo.GET, path: "/blocks/proposer/:address", summary: "Blocks proposed by validator", paging: true, data: Blocks{}},
+
+	// Transactions
+	{method: echo.GET, path: "/txs", summary: "Latest transactions, from or to labeled addresses when filtered by label", paging: true,
+		query: labelFilterParams, data: Transactions{}},
+	{method: echo.GET, path: "/txs/:txHash", summary: "Transaction by hash", data: &Transaction{}},
+	{method: echo.GET, path: "/txs/:txHash/internal", summary: "Internal calls of transaction", paging: true, data: []*types.InternalCall{}},
+	{method: echo.GET, path: "/token/txs", summary: "Token transfers", paging: true, query: append([]*openAPIParameter{
+		queryParam("address", "Sender or receiver", &openAPISchema{Type: "string", Pattern: addressPattern}),
+		queryParam("contractAddress", "", &openAPISchema{Type: "string", Pattern: addressPattern}),
+		queryParam("txHash", "", &openAPISchema{Type: "string", Pattern: hashPattern}),
+	}, labelFilterParams...), data: []*InternalTransaction{}},
+
+	// Addresses
+	{method: echo.GET, path: "/addresses", summary: "Addresses sorted by balance", paging: true, query: append([]*openAPIParameter{
+		queryParam("sort", "1 for ascending, -1 for descending balance", &openAPISchema{Type: "string", Enum: []string{"1", "-1"}}),
+	}, labelFilterParams...), data: Addresses{}},
+	{method: echo.GET, path: "/addresses/:address", summary: "Address info", data: &SimpleAddress{}},
+	{method: echo.GET, path: "/addresses/:address/txs", summary: "Transactions of address", paging: true, data: Transactions{}},
+	{method: echo.GET, path: "/addresses/:address/tokens", summary: "KRC20 balances of address", paging: true, data: []*types.KRC20Holder{}},
+	{method: echo.GET, path: "/addresses/:address/internal-txs", summary: "Internal calls from or to address", paging: true, data: []*types.InternalCall{}},
+	{method: echo.GET, path: "/addresses/:address/balance", summary: "KAI balance at a block or time, latest by default", query: []*openAPIParameter{
+		queryParam("blockHeight", "", &openAPISchema{Type: "integer", Minimum: float(0)}),
+		queryParam("timestamp", "Unix time", &openAPISchema{Type: "integer", Minimum: float(0)}),
+	}, data: &AddressBalance{}},
+	{method: echo.GET, path: "/addresses/:address/balance/history", summary: "KAI balance changes of address", paging: true, query: []*openAPIParameter{
+		queryParam("startTime", "Unix time", &openAPISchema{Type: "integer", Minimum: float(0)}),
+		queryParam("endTime", "Unix time", &openAPISchema{Type: "integer", Minimum: float(0)}),
+	}, data: []*types.BalanceChange{}},
+	{method: echo.GET, path: "/addresses/:address/portfolio", summary: "Token holdings of address valued in USD", data: &Portfolio{}},
+	{method: echo.GET, path: "/addresses/:address/export/txs", summary: "Export transactions of address", query: exportParams,
+		produces: []string{"text/csv", "application/x-ndjson"}},
+	{method: echo.GET, path: "/addresses/:address/export/token-transfers", summary: "Export token transfers of address",
+		query: append([]*openAPIParameter{
+			queryParam("type", "Token type, every type by default", &openAPISchema{Type: "string", Enum: []string{cfg.SMCTypeKRC20, cfg.SMCTypeKRC721}}),
+		}, exportParams...), produces: []string{"text/csv", "application/x-ndjson"}},
+	{method: echo.GET, path: "/addresses/:address/export/staking", summary: "Export staking actions of address", query: exportParams,
+		produces: []string{"text/csv", "application/x-ndjson"}},
+	{method: echo.GET, path: "/search", summary: "Search blocks, txs, addresses, validators, tokens and contracts, best hits first", query: []*openAPIParameter{
+		queryParam("q", "Block height, block or tx hash, address, or the prefix or a word of a name or token symbol", &openAPISchema{Type: "string"}),
+		queryParam("limit", "Maximum number of hits", &openAPISchema{Type: "integer", Minimum: float(1), Maximum: float(searchMaxLimit)}),
+		queryParam("name", "Deprecated, name search returning tokens and addresses when q is missing", &openAPISchema{Type: "string"}),
+	}, data: []*types.SearchResult{}},
+	{method: echo.GET, path: "/labels", summary: "Address labels, ordered by address", paging: true, query: []*openAPIParameter{
+		queryParam("address", "", &openAPISchema{Type: "string", Pattern: addressPattern}),
+		queryParam("category", "", &openAPISchema{Type: "string", Enum: sortedKeys(types.LabelCategories)}),
+		queryParam("label", "Label name", &openAPISchema{Type: "string"}),
+		queryParam("source", "", &openAPISchema{Type: "string"}),
+	}, data: []*types.AddressLabel{}},
+	{method: echo.GET, path: "/labels/categories", summary: "Categories of address labels", data: []string{}},
+
+	// Contracts and tokens
+	{method: echo.GET, path: "/contracts", summary: "Contracts", paging: true, query: []*openAPIParameter{
+		queryParam("type", "Contract type, e.g. KRC20", &openAPISchema{Type: "string"}),
+		queryParam("status", "Verification status", &openAPISchema{Type: "string"}),
+	}, data: []*SimpleKRCTokenInfo{}},
+	{method: echo.GET, path: "/contracts/:contractAddress", summary: "Contract info", data: &KRCTokenInfo{}},
+	{method: echo.GET, path: "/contracts/:contractAddress/upgrades", summary: "Implementation upgrades of proxy", paging: true, data: []*types.ProxyUpgrade{}},
+	{method: echo.POST, path: "/contracts/:contractAddress/proxy", summary: "Detect proxy implementation of contract", data: &types.ProxyInfo{}},
+	{method: echo.POST, path: "/contracts/verify", summary: "Verify contract source code, also accept a multipart form of source files with their paths at the same position in paths",
+		body: &verifyContractRequest{}, data: &types.Contract{}},
+	{method: echo.GET, path: "/contracts/events", summary: "Decoded events of contract", paging: true, query: []*openAPIParameter{
+		queryParam("contractAddress", "", &openAPISchema{Type: "string", Pattern: addressPattern}),
+		queryParam("methodName", "", &openAPISchema{Type: "string"}),
+		queryParam("txHash", "", &openAPISchema{Type: "string", Pattern: hashPattern}),
+	}, data: []*InternalTransaction{}},
+	{method: echo.GET, path: "/krc20/:contractAddress/holders", summary: "Holders of KRC20 token", paging: true, data: []*types.KRC20Holder{}},
+	{method: echo.GET, path: "/krc721/:contractAddress/holders", summary: "Holders of KRC721 token", paging: true, data: []*types.KRC721Holder{}},
+	{method: echo.GET, path: "/signatures/:hash", summary: "Text signatures of method selector or event topic", data: []*types.Signature{}},
+
+	// Market
+	{method: echo.GET, path: "/market/prices", summary: "Hourly KAI prices, last 30 days by default", query: rangeParams, data: []*types.TokenPrice{}},
+	{method: echo.GET, path: "/tokens/:contractAddress/prices", summary: "Hourly prices of KRC20 token", query: rangeParams, data: []*types.TokenPrice{}},
+	{method: echo.GET, path: "/tokens/price-sources", summary: "Price sources of KRC20 tokens", data: []*types.TokenPriceSource{}},
+	{method: echo.GET, path: "/gas/oracle", summary: "Suggested gas prices", data: &types.GasOracle{}},
+	{method: echo.GET, path: "/gas/history", summary: "Gas usage of latest blocks", data: []*types.BlockGasStats{}},
+
+	// Charts
+	{method: echo.GET, path: "/charts", summary: "Chain rollups", query: append([]*openAPIParameter{
+		queryParam("interval", "", &openAPISchema{Type: "string", Enum: []string{types.RollupIntervalHour, types.RollupIntervalDay}}),
+	}, rangeParams...), data: []*types.Rollup{}},
+	{method: echo.GET, path: "/charts/:metric", summary: "Chart of one rollup metric", params: map[string]*openAPISchema{
+		"metric": {Type: "string", Enum: sortedKeys(chartMetrics)},
+	}, query: append([]*openAPIParameter{
+		queryParam("interval", "", &openAPISchema{Type: "string", Enum: []string{types.RollupIntervalHour, types.RollupIntervalDay}}),
+	}, rangeParams...), data: []*ChartPoint{}},
+
+	// Staking
+	{method: echo.GET, path: "/staking/stats", summary: "Staking stats", data: &types.StakingStats{}},
+	{method: echo.GET, path: "/staking/validators", summary: "Validators", data: []*types.Validator{}},
+	{method: echo.GET, path: "/staking/candidates", summary: "Candidates", data: []*types.Validator{}},
+	{method: echo.GET, path: "/validators", summary: "Staking stats and validators", data: mobileValidators},
+	{method: echo.GET, path: "/validators/candidates", summary: "Staking stats and candidates", data: mobileValidators},
+	{method: echo.GET, path: "/validators/:address", summary: "Validator and its delegators", paging: true, data: &types.Validator{}},
+	{method: echo.GET, path: "/delegators/:address/validators", summary: "Validators of delegator", data: []*types.ValidatorsByDelegator{}},
+
+	// Proposals
+	{method: echo.GET, path: "/proposal", summary: "Proposals", paging: true, data: []*types.ProposalDetail{}},
+	{method: echo.GET, path: "/proposal/:id", summary: "Proposal detail", params: map[string]*openAPISchema{
+		"id": {Type: "integer", Minimum: float(0)},
+	}, data: &types.ProposalDetail{}},
+	{method: echo.GET, path: "/proposal/params", summary: "Network params", data: map[string]interface{}{}},
+
+	// Stream
+	{method: echo.GET, path: "/stream", summary: "Server-sent events of new blocks, transactions, transfers and validators", query: []*openAPIParameter{
+		queryParam("subscribe", "blocks, txs:<address>, transfers:<address> or validators, at most 20 topics", &openAPISchema{Type: "array", Items: &openAPISchema{Type: "string"}}),
+	}, produces: []string{"text/event-stream"}},
+
+	// Admin
+	{method: echo.POST, path: "/admin/login", summary: "Exchange admin credentials for a token", body: &adminLoginRequest{}, data: &adminToken{}},
+	{method: echo.PUT, path: "/admin/addresses", summary: "Update address name", roles: []string{types.AdminRoleContentEditor}, body: &types.UpdateAddress{}},
+	{method: echo.PUT, path: "/admin/contracts", summary: "Update contract info", roles: []string{types.AdminRoleContentEditor}, body: &types.Contract{}, data: &types.Address{}},
+	{method: echo.PUT, path: "/admin/contracts/abi", summary: "Update ABI of a contract type", roles: []string{types.AdminRoleContentEditor}, body: &types.ContractABI{}},
+	{method: echo.PUT, path: "/admin/tokens/:contractAddress/price-source", summary: "Set price source of KRC20 token", roles: []string{types.AdminRoleContentEditor},
+		body: &types.TokenPriceSource{}, data: &types.TokenPriceSource{}},
+	{method: echo.DELETE, path: "/admin/tokens/:contractAddress/price-source", summary: "Remove price source of KRC20 token", roles: []string{types.AdminRoleContentEditor}},
+	{method: echo.PUT, path: "/admin/labels", summary: "Upsert address label", roles: []string{types.AdminRoleContentEditor},
+		body: &types.AddressLabel{}, data: &types.AddressLabel{}},
+	{method: echo.DELETE, path: "/admin/labels", summary: "Remove address label", roles: []string{types.AdminRoleContentEditor}, query: []*openAPIParameter{
+		queryParam("address", "", &openAPISchema{Type: "string", Pattern: addressPattern}),
+		queryParam("category", "", &openAPISchema{Type: "string", Enum: sortedKeys(types.LabelCategories)}),
+		queryParam("label", "Label name", &openAPISchema{Type: "string"}),
+	}},
+	{method: echo.POST, path: "/admin/labels/import", summary: "Import address labels of a JSON array or a CSV file with address, name, category, source and confidence columns",
+		roles: []string{types.AdminRoleContentEditor}, query: []*openAPIParameter{
+			queryParam("source", "Source of rows without one, manual by default", &openAPISchema{Type: "string"}),
+		}, body: []*types.AddressLabel{}, data: &types.LabelImportResult{}},
+	{method: echo.PUT, path: "/admin/status", summary: "Update server status", roles: []string{types.AdminRoleOperator}, body: &types.ServerStatus{}},
+	{method: echo.PUT, path: "/admin/dashboard/token/supplies", summary: "Update KAI supplies", roles: []string{types.AdminRoleOperator}, body: &types.SupplyInfo{}},
+	{method: echo.PUT, path: "/admin/nodes", summary: "Upsert network node", roles: []string{types.AdminRoleOperator}, body: &types.NodeInfo{}},
+	{method: echo.DELETE, path: "/admin/nodes/:nodeID", summary: "Remove network node", roles: []string{types.AdminRoleOperator}},
+	{method: echo.POST, path: "/admin/addresses/reload", summary: "Reload balances of every address", roles: []string{types.AdminRoleOperator}},
+	{method: echo.POST, path: "/admin/validators/reload", summary: "Reload validators", roles: []string{types.AdminRoleOperator}},
+	{method: echo.PUT, path: "/admin/token/txs", summary: "Rebuild token transfers of contract from logs", roles: []string{types.AdminRoleOperator}, query: []*openAPIParameter{
+		queryParam("from", "From block", &openAPISchema{Type: "integer", Minimum: float(0)}),
+		queryParam("to", "To block", &openAPISchema{Type: "integer", Minimum: float(0)}),
+		queryParam("remove", "1 to remove existing transfers first", &openAPISchema{Type: "integer"}),
+	}, body: &types.TxsFilter{}},
+	{method: echo.DELETE, path: "/admin/event/duplicate", summary: "Remove duplicated events", roles: []string{types.AdminRoleOperator}, data: []*types.Log{}},
+	{method: echo.PUT, path: "/admin/contracts/sync", summary: "Sync contracts from creation transactions", roles: []string{types.AdminRoleOperator}},
+	{method: echo.PUT, path: "/admin/contracts/kcr20/refresh", summary: "Refresh KRC20 info from chain", roles: []string{types.AdminRoleOperator}},
+	{method: echo.PUT, path: "/admin/contracts/kcr721/refresh", summary: "Refresh KRC721 info from chain", roles: []string{types.AdminRoleOperator}},
+	{method: echo.PUT, path: "/admin/contracts/refresh", summary: "Refresh type and status of contracts", roles: []string{types.AdminRoleOperator}},
+	{method: echo.DELETE, path: "/admin/contracts/nil", summary: "Remove contracts without address", roles: []string{types.AdminRoleOperator}},
+	{method: echo.DELETE, path: "/admin/holders/refresh", summary: "Remove KRC20 holders so they are rebuilt", roles: []string{types.AdminRoleOperator}},
+	{method: echo.POST, path: "/admin/api-keys", summary: "Issue API key, the key is only returned once", roles: []string{types.AdminRoleSuperAdmin},
+		body: &apiKeyRequest{}, data: &createdAPIKey{}},
+	{method: echo.GET, path: "/admin/api-keys", summary: "API keys", roles: []string{types.AdminRoleSuperAdmin}, paging: true, data: []*types.APIKey{}},
+	{method: echo.PUT, path: "/admin/api-keys/:id", summary: "Update API key", roles: []string{types.AdminRoleSuperAdmin}, body: &apiKeyRequest{}, data: &types.APIKey{}},
+	{method: echo.GET, path: "/admin/api-keys/:id/usage", summary: "Usage of API key, anonymous for requests without key", roles: []string{types.AdminRoleSuperAdmin},
+		query: usageParams, data: []*types.EndpointUsage{}},
+	{method: echo.POST, path: "/admin/users", summary: "Create admin user", roles: []string{types.AdminRoleSuperAdmin}, body: &adminUserRequest{}, data: &types.AdminUser{}},
+	{method: echo.GET, path: "/admin/users", summary: "Admin users", roles: []string{types.AdminRoleSuperAdmin}, data: []*types.AdminUser{}},
+	{method: echo.PUT, path: "/admin/users/:username", summary: "Update admin user", roles: []string{types.AdminRoleSuperAdmin}, body: &adminUserRequest{}, data: &types.AdminUser{}},
+	{method: echo.GET, path: "/admin/audit-logs", summary: "Audit logs, latest first", roles: []string{types.AdminRoleSuperAdmin}, paging: true, query: []*openAPIParameter{
+		queryParam("actor", "Admin username", &openAPISchema{Type: "string"}),
+		queryParam("resource", "e.g. contract:0x...", &openAPISchema{Type: "string"}),
+		queryParam("startTime", "RFC 3339 time", &openAPISchema{Type: "string", Format: "date-time"}),
+		queryParam("endTime", "RFC 3339 time", &openAPISchema{Type: "string", Format: "date-time"}),
+	}, data: []*types.AuditLog{}},
+}
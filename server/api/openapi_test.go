@@ -0,0 +1,98 @@
+// Package api
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOpenAPI_Routes fail when a route is registered without being documented in routeDocs, or the opposite
+func TestOpenAPI_Routes(t *testing.T) {
+	e := echo.New()
+	bind(e.Group(openAPIBaseURL), &Server{})
+	spec := apiSpec()
+
+	registered := make(map[string]bool)
+	for _, route := range e.Routes() {
+		// catch-all routes added by echo for group middlewares
+		if strings.Contains(route.Name, "(*Group).Use") {
+			continue
+		}
+		key := route.Method + " " + route.Path
+		registered[key] = true
+		if _, ok := spec.operations[key]; !ok {
+			t.Errorf("%s is missing from the OpenAPI spec, add it to routeDocs", key)
+		}
+	}
+	for key := range spec.operations {
+		assert.True(t, registered[key], "%s is documented but not registered", key)
+	}
+}
+
+func TestOpenAPI_Spec(t *testing.T) {
+	spec := newOpenAPISpec(routeDocs)
+	data, err := json.Marshal(spec)
+	require.NoError(t, err)
+
+	// every reference points to a component
+	refs := regexp.MustCompile(`"\$ref":"#/components/schemas/([^"]+)"`).FindAllStringSubmatch(string(data), -1)
+	require.NotEmpty(t, refs)
+	for _, ref := range refs {
+		assert.Contains(t, spec.Components.Schemas, ref[1])
+	}
+
+	ids := make(map[string]bool)
+	for _, item := range spec.Paths {
+		for _, op := range item {
+			assert.False(t, ids[op.OperationID], "duplicated operationId %s", op.OperationID)
+			ids[op.OperationID] = true
+		}
+	}
+
+	block := spec.Paths["/blocks/{block}"]["get"]
+	require.NotNil(t, block)
+	require.Len(t, block.Parameters, 1)
+	assert.Equal(t, "path", block.Parameters[0].In)
+	assert.NotNil(t, spec.Paths["/admin/users"]["post"].RequestBody)
+	assert.Equal(t, []map[string][]string{{securityAdminToken: {}}}, spec.Paths["/admin/users"]["post"].Security)
+}
+
+func TestValidateRequest(t *testing.T) {
+	e := echo.New()
+	e.Use(validateRequest())
+	gr := e.Group(openAPIBaseURL)
+	ok := func(c echo.Context) error { return OK.Build(c) }
+	gr.GET("/addresses/:address/txs", ok)
+	gr.GET("/addresses", ok)
+	gr.GET("/stream", ok)
+	gr.GET("/undocumented/:address", ok)
+
+	address := "0x" + strings.Repeat("a", 40)
+	cases := []struct {
+		target string
+		code   int
+	}{
+		{"/api/v1/addresses/" + address + "/txs?page=1&limit=20", http.StatusOK},
+		{"/api/v1/addresses/" + address + "/txs?unknown=x", http.StatusOK},
+		{"/api/v1/addresses/0xabc/txs", http.StatusBadRequest},
+		{"/api/v1/addresses/" + address + "/txs?page=one", http.StatusBadRequest},
+		{"/api/v1/addresses/" + address + "/txs?limit=1000", http.StatusBadRequest},
+		{"/api/v1/addresses?sort=-1", http.StatusOK},
+		{"/api/v1/addresses?sort=asc", http.StatusBadRequest},
+		{"/api/v1/stream?subscribe=blocks&subscribe=validators", http.StatusOK},
+		{"/api/v1/undocumented/0xabc", http.StatusOK},
+	}
+	for _, c := range cases {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(echo.GET, c.target, nil))
+		assert.Equal(t, c.code, rec.Code, c.target)
+	}
+}
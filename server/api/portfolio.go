@@ -0,0 +1,123 @@
+// Package api
+package api
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/labstack/echo"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+type IPortfolio interface {
+	AddressPortfolio(c echo.Context) error
+}
+
+func bindPortfolioAPIs(gr *echo.Group, srv RestServer) {
+	apis := []restDefinition{
+		{
+			method:      echo.GET,
+			path:        "/addresses/:address/portfolio",
+			fn:          srv.AddressPortfolio,
+			middlewares: nil,
+		},
+	}
+	for _, api := range apis {
+		gr.Add(api.method, api.path, api.fn, api.middlewares...)
+	}
+}
+
+// AddressPortfolio return KAI and KRC20 holdings of address with their current USD value and 24h change
+func (s *Server) AddressPortfolio(c echo.Context) error {
+	ctx := c.Request().Context()
+	address := c.Param("address")
+	if !common.IsHexAddress(address) {
+		return Invalid.Build(c)
+	}
+	address = common.HexToAddress(address).Hex()
+
+	kaiBalance, err := s.kaiClient.GetBalance(ctx, address)
+	if err != nil {
+		s.logger.Warn("Cannot get balance of address", zap.String("address", address), zap.Error(err))
+		return Invalid.Build(c)
+	}
+	holdings := []*PortfolioHolding{{
+		Token:    types.PriceTokenKAI,
+		Name:     "KardiaChain",
+		Symbol:   types.PriceTokenKAI,
+		Decimals: 18,
+		Balance:  kaiBalance,
+	}}
+	// balances of holders are kept up to date by indexing transfer events
+	holders, _, err := s.dbClient.KRC20Holders(ctx, &types.KRC20HolderFilter{HolderAddress: address})
+	if err != nil {
+		s.logger.Warn("Cannot get holders from db", zap.String("address", address), zap.Error(err))
+		return Invalid.Build(c)
+	}
+	for _, holder := range holders {
+		if balance, ok := new(big.Int).SetString(holder.BalanceString, 10); !ok || balance.Sign() <= 0 {
+			continue
+		}
+		holding := &PortfolioHolding{
+			Token:    common.HexToAddress(holder.ContractAddress).Hex(),
+			Name:     holder.TokenName,
+			Symbol:   holder.TokenSymbol,
+			Decimals: holder.TokenDecimals,
+			Balance:  holder.BalanceString,
+		}
+		if tokenInfo, err := s.getTokenInfo(ctx, holder.ContractAddress); err == nil {
+			holding.Name = tokenInfo.TokenName
+			holding.Symbol = tokenInfo.TokenSymbol
+			holding.Decimals = tokenInfo.Decimals
+			holding.Logo = tokenInfo.Logo
+		}
+		holdings = append(holdings, holding)
+	}
+
+	tokens := make([]string, len(holdings))
+	for i, h := range holdings {
+		tokens[i] = h.Token
+	}
+	now := time.Now()
+	prices, err := s.dbClient.LatestTokenPrices(ctx, tokens, now)
+	if err != nil {
+		s.logger.Warn("Cannot get token prices from db", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	prevPrices, err := s.dbClient.LatestTokenPrices(ctx, tokens, now.Add(-24*time.Hour))
+	if err != nil {
+		s.logger.Warn("Cannot get token prices from db", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	return OK.SetData(newPortfolio(address, holdings, prices, prevPrices)).Build(c)
+}
+
+// newPortfolio value holdings with latest prices and prices 24h ago, most valuable holdings first
+func newPortfolio(address string, holdings []*PortfolioHolding, prices, prevPrices map[string]*types.TokenPrice) *Portfolio {
+	portfolio := &Portfolio{Address: address, Holdings: holdings}
+	for _, h := range holdings {
+		price, ok := prices[h.Token]
+		if !ok {
+			continue
+		}
+		balance, _ := new(big.Int).SetString(h.Balance, 10)
+		amount := types.TokenAmount(balance, h.Decimals)
+		h.HasPrice = true
+		h.Price = price.Price
+		h.PriceUpdatedAt = price.UpdatedAt
+		h.Value = amount * price.Price
+		if prev, ok := prevPrices[h.Token]; ok {
+			h.Change24h = types.PriceChange(price.Price, prev.Price)
+			h.ValueChange24h = amount * (price.Price - prev.Price)
+		}
+		portfolio.TotalValue += h.Value
+		portfolio.ValueChange24h += h.ValueChange24h
+	}
+	portfolio.Change24h = types.PriceChange(portfolio.TotalValue, portfolio.TotalValue-portfolio.ValueChange24h)
+	sort.SliceStable(holdings, func(i, j int) bool { return holdings[i].Value > holdings[j].Value })
+	return portfolio
+}
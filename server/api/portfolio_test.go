@@ -0,0 +1,63 @@
+// Package api
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+func TestNewPortfolio(t *testing.T) {
+	const token = "0x00000000000000000000000000000000000000A1"
+	holdings := []*PortfolioHolding{
+		// 100 KAI
+		{Token: types.PriceTokenKAI, Decimals: 18, Balance: "100000000000000000000"},
+		// 50 tokens with 6 decimals
+		{Token: token, Decimals: 6, Balance: "50000000"},
+		{Token: "0x00000000000000000000000000000000000000B1", Decimals: 18, Balance: "1"},
+	}
+	prices := map[string]*types.TokenPrice{
+		types.PriceTokenKAI: {Token: types.PriceTokenKAI, Price: 0.05},
+		token:               {Token: token, Price: 2},
+	}
+	prevPrices := map[string]*types.TokenPrice{
+		types.PriceTokenKAI: {Token: types.PriceTokenKAI, Price: 0.04},
+	}
+	portfolio := newPortfolio("0x00000000000000000000000000000000000000C1", holdings, prices, prevPrices)
+
+	assert.InDelta(t, 105.0, portfolio.TotalValue, 1e-9)
+	assert.InDelta(t, 1.0, portfolio.ValueChange24h, 1e-9)
+	assert.InDelta(t, 1.0/104*100, portfolio.Change24h, 1e-9)
+
+	// most valuable first, tokens without price last
+	assert.Equal(t, token, portfolio.Holdings[0].Token)
+	assert.InDelta(t, 100.0, portfolio.Holdings[0].Value, 1e-9)
+	assert.Equal(t, 0.0, portfolio.Holdings[0].Change24h)
+	assert.Equal(t, types.PriceTokenKAI, portfolio.Holdings[1].Token)
+	assert.InDelta(t, 25.0, portfolio.Holdings[1].Change24h, 1e-9)
+	assert.False(t, portfolio.Holdings[2].HasPrice)
+}
+
+func TestSanitizeTokenPriceSource(t *testing.T) {
+	source := &types.TokenPriceSource{
+		Token:       "0x00000000000000000000000000000000000000a1",
+		Type:        types.PriceSourceDEX,
+		PairAddress: "0x00000000000000000000000000000000000000d1",
+		CoinGeckoID: "ignored",
+	}
+	assert.NoError(t, sanitizeTokenPriceSource(source))
+	assert.Equal(t, "0x00000000000000000000000000000000000000A1", source.Token)
+	assert.Equal(t, "0x00000000000000000000000000000000000000D1", source.PairAddress)
+	assert.Empty(t, source.CoinGeckoID)
+
+	assert.Equal(t, ErrInvalidPriceSource, sanitizeTokenPriceSource(&types.TokenPriceSource{
+		Token: "0x00000000000000000000000000000000000000a1",
+		Type:  types.PriceSourceMarket,
+	}))
+	assert.Equal(t, ErrInvalidPriceSource, sanitizeTokenPriceSource(&types.TokenPriceSource{
+		Token: "0x00000000000000000000000000000000000000a1",
+		Type:  "oracle",
+	}))
+}
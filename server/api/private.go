@@ -3,7 +3,6 @@ package api
 
 import (
 	"bytes"
-	"context"
 	"fmt"
 	"io/ioutil"
 	"strings"
@@ -36,69 +35,193 @@ type IPrivate interface {
 	RefreshHolders(c echo.Context) error
 }
 
+// bindPrivateAPIs register every API changing data in the admin group, requests must carry a token of an admin
+// user with one of the route roles, superadmin is allowed everywhere
 func bindPrivateAPIs(gr *echo.Group, srv RestServer) {
+	gr.Add(echo.POST, "/admin/login", srv.AdminLogin)
+
+	var (
+		editor     = requireRole(types.AdminRoleContentEditor)
+		operator   = requireRole(types.AdminRoleOperator)
+		superAdmin = requireRole(types.AdminRoleSuperAdmin)
+	)
 	apis := []restDefinition{
+		// Content
+		{
+			method:      echo.PUT,
+			path:        "/addresses",
+			fn:          srv.UpdateAddressName,
+			middlewares: []echo.MiddlewareFunc{editor},
+		},
+		{
+			method:      echo.PUT,
+			path:        "/contracts",
+			fn:          srv.UpdateContract,
+			middlewares: []echo.MiddlewareFunc{editor},
+		},
+		{
+			method:      echo.PUT,
+			path:        "/contracts/abi",
+			fn:          srv.UpdateSMCABIByType,
+			middlewares: []echo.MiddlewareFunc{editor},
+		},
+		{
+			method:      echo.PUT,
+			path:        "/tokens/:contractAddress/price-source",
+			fn:          srv.UpsertTokenPriceSource,
+			middlewares: []echo.MiddlewareFunc{editor},
+		},
+		{
+			method:      echo.DELETE,
+			path:        "/tokens/:contractAddress/price-source",
+			fn:          srv.RemoveTokenPriceSource,
+			middlewares: []echo.MiddlewareFunc{editor},
+		},
+		{
+			method:      echo.PUT,
+			path:        "/labels",
+			fn:          srv.UpsertAddressLabel,
+			middlewares: []echo.MiddlewareFunc{editor},
+		},
+		{
+			method: echo.DELETE,
+			// Query params: ?address=0x&category=exchange&label=
+			path:        "/labels",
+			fn:          srv.RemoveAddressLabel,
+			middlewares: []echo.MiddlewareFunc{editor},
+		},
+		{
+			method: echo.POST,
+			// Body: JSON array of labels, or CSV with Content-Type text/csv. Query params: ?source=
+			path:        "/labels/import",
+			fn:          srv.ImportAddressLabels,
+			middlewares: []echo.MiddlewareFunc{editor},
+		},
+		// Operation
+		{
+			method:      echo.PUT,
+			path:        "/status",
+			fn:          srv.UpdateServerStatus,
+			middlewares: []echo.MiddlewareFunc{operator},
+		},
+		{
+			method:      echo.PUT,
+			path:        "/dashboard/token/supplies",
+			fn:          srv.UpdateSupplyAmounts,
+			middlewares: []echo.MiddlewareFunc{operator},
+		},
+		{
+			method:      echo.PUT,
+			path:        "/nodes",
+			fn:          srv.UpsertNetworkNodes,
+			middlewares: []echo.MiddlewareFunc{operator},
+		},
+		{
+			method:      echo.DELETE,
+			path:        "/nodes/:nodeID",
+			fn:          srv.RemoveNetworkNodes,
+			middlewares: []echo.MiddlewareFunc{operator},
+		},
+		{
+			method:      echo.POST,
+			path:        "/addresses/reload",
+			fn:          srv.ReloadAddressesBalance,
+			middlewares: []echo.MiddlewareFunc{operator},
+		},
+		{
+			method:      echo.POST,
+			path:        "/validators/reload",
+			fn:          srv.ReloadValidators,
+			middlewares: []echo.MiddlewareFunc{operator},
+		},
+		{
+			method: echo.PUT,
+			// Query params: ?from=0&to=0
+			path:        "/token/txs",
+			fn:          srv.UpdateInternalTxs,
+			middlewares: []echo.MiddlewareFunc{operator},
+		},
+		{
+			method:      echo.DELETE,
+			path:        "/event/duplicate",
+			fn:          srv.RemoveDuplicateEvents,
+			middlewares: []echo.MiddlewareFunc{operator},
+		},
 		{
 			method:      echo.PUT,
 			path:        "/contracts/sync",
 			fn:          srv.SyncContractInfo,
-			middlewares: nil,
+			middlewares: []echo.MiddlewareFunc{operator},
 		},
 		{
 			method:      echo.PUT,
 			path:        "/contracts/kcr20/refresh",
 			fn:          srv.RefreshKRC20Info,
-			middlewares: nil,
+			middlewares: []echo.MiddlewareFunc{operator},
 		},
 
 		{
 			method:      echo.PUT,
 			path:        "/contracts/kcr721/refresh",
 			fn:          srv.RefreshKRC721Info,
-			middlewares: nil,
+			middlewares: []echo.MiddlewareFunc{operator},
 		},
 		{
 			method:      echo.PUT,
 			path:        "/contracts/refresh",
 			fn:          srv.RefreshContractsInfo,
-			middlewares: nil,
+			middlewares: []echo.MiddlewareFunc{operator},
 		},
 		{
 			method:      echo.DELETE,
 			path:        "/contracts/nil",
 			fn:          srv.RemoveNilContracts,
-			middlewares: nil,
+			middlewares: []echo.MiddlewareFunc{operator},
 		},
 		{
 			method:      echo.DELETE,
 			path:        "/holders/refresh",
 			fn:          srv.RefreshHolders,
-			middlewares: nil,
+			middlewares: []echo.MiddlewareFunc{operator},
 		},
+		// API keys
 		{
-			method:      echo.PUT,
-			path:        "/contracts",
-			fn:          srv.UpdateContract,
-			middlewares: nil,
+			method:      echo.POST,
+			path:        "/api-keys",
+			fn:          srv.CreateAPIKey,
+			middlewares: []echo.MiddlewareFunc{superAdmin},
+		},
+		{
+			method: echo.GET,
+			// Query params: ?page=0&limit=10
+			path:        "/api-keys",
+			fn:          srv.APIKeys,
+			middlewares: []echo.MiddlewareFunc{checkPagination(), superAdmin},
 		},
 		{
 			method:      echo.PUT,
-			path:        "/contracts/abi",
-			fn:          srv.UpdateSMCABIByType,
-			middlewares: nil,
+			path:        "/api-keys/:id",
+			fn:          srv.UpdateAPIKey,
+			middlewares: []echo.MiddlewareFunc{superAdmin},
+		},
+		{
+			method: echo.GET,
+			// Query params: ?from=2021-05-01&to=2021-05-31, today by default
+			path:        "/api-keys/:id/usage",
+			fn:          srv.APIKeyUsage,
+			middlewares: []echo.MiddlewareFunc{superAdmin},
 		},
 	}
+	admin := gr.Group("/admin", srv.AdminAuth(), srv.Audit())
 	for _, api := range apis {
-		gr.Add(api.method, api.path, api.fn, api.middlewares...)
+		admin.Add(api.method, api.path, api.fn, api.middlewares...)
 	}
+	bindAdminAPIs(admin, srv)
 }
 
 func (s *Server) RefreshContractsInfo(c echo.Context) error {
 	lgr := s.logger
-	ctx := context.Background()
-	if c.Request().Header.Get("Authorization") != s.authorizationSecret {
-		return Unauthorized.Build(c)
-	}
+	ctx := c.Request().Context()
 
 	contracts, err := s.dbClient.AllContracts(ctx)
 	if err != nil {
@@ -133,10 +256,7 @@ func (s *Server) RefreshContractsInfo(c echo.Context) error {
 func (s *Server) RefreshKRC721Info(c echo.Context) error {
 
 	lgr := s.logger
-	ctx := context.Background()
-	if c.Request().Header.Get("Authorization") != s.authorizationSecret {
-		return Unauthorized.Build(c)
-	}
+	ctx := c.Request().Context()
 
 	krc721Tokens, err := s.dbClient.ContractByType(ctx, cfg.SMCTypeKRC721)
 	if err != nil {
@@ -188,10 +308,7 @@ func (s *Server) RefreshKRC721Info(c echo.Context) error {
 func (s *Server) RefreshKRC20Info(c echo.Context) error {
 
 	lgr := s.logger
-	ctx := context.Background()
-	if c.Request().Header.Get("Authorization") != s.authorizationSecret {
-		return Unauthorized.Build(c)
-	}
+	ctx := c.Request().Context()
 
 	krc20Tokens, err := s.dbClient.ContractByType(ctx, cfg.SMCTypeKRC20)
 	if err != nil {
@@ -242,10 +359,7 @@ func (s *Server) RefreshKRC20Info(c echo.Context) error {
 func (s *Server) SyncContractInfo(c echo.Context) error {
 
 	lgr := s.logger
-	ctx := context.Background()
-	if c.Request().Header.Get("Authorization") != s.authorizationSecret {
-		return Unauthorized.Build(c)
-	}
+	ctx := c.Request().Context()
 
 	//  Select all txs which contractAddress != ''
 	contractCreationTxs, err := s.dbClient.FindContractCreationTxs(ctx)
@@ -301,10 +415,7 @@ func (s *Server) SyncContractInfo(c echo.Context) error {
 
 func (s *Server) RemoveNilContracts(c echo.Context) error {
 
-	ctx := context.Background()
-	if c.Request().Header.Get("Authorization") != s.authorizationSecret {
-		return Unauthorized.Build(c)
-	}
+	ctx := c.Request().Context()
 	if err := s.dbClient.RemoveContracts(ctx); err != nil {
 		return Invalid.Build(c)
 	}
@@ -314,9 +425,6 @@ func (s *Server) RemoveNilContracts(c echo.Context) error {
 
 func (s *Server) UpsertNetworkNodes(c echo.Context) error {
 	//ctx := context.Background()
-	if c.Request().Header.Get("Authorization") != s.authorizationSecret {
-		return Unauthorized.Build(c)
-	}
 	var nodeInfo *types.NodeInfo
 	if err := c.Bind(&nodeInfo); err != nil {
 		return Invalid.Build(c)
@@ -324,37 +432,33 @@ func (s *Server) UpsertNetworkNodes(c echo.Context) error {
 	if nodeInfo.ID == "" || nodeInfo.Moniker == "" {
 		return Invalid.Build(c)
 	}
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	if err := s.dbClient.UpsertNode(ctx, nodeInfo); err != nil {
 		return InternalServer.Build(c)
 	}
+	setAudit(c, "node:"+nodeInfo.ID, nil, nodeInfo)
 
 	return OK.Build(c)
 }
 
 func (s *Server) RemoveNetworkNodes(c echo.Context) error {
 	//ctx := context.Background()
-	if c.Request().Header.Get("Authorization") != s.authorizationSecret {
-		return Unauthorized.Build(c)
-	}
 	nodesID := c.Param("nodeID")
 	if nodesID == "" {
 		return Invalid.Build(c)
 	}
 
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	if err := s.dbClient.RemoveNode(ctx, nodesID); err != nil {
 		return InternalServer.Build(c)
 	}
+	setAudit(c, "node:"+nodesID, nil, nil)
 
 	return OK.Build(c)
 }
 
 func (s *Server) ReloadAddressesBalance(c echo.Context) error {
-	ctx := context.Background()
-	if c.Request().Header.Get("Authorization") != s.authorizationSecret {
-		return Unauthorized.Build(c)
-	}
+	ctx := c.Request().Context()
 
 	addresses, err := s.dbClient.Addresses(ctx)
 	if err != nil {
@@ -377,10 +481,7 @@ func (s *Server) ReloadAddressesBalance(c echo.Context) error {
 }
 
 func (s *Server) UpdateAddressName(c echo.Context) error {
-	ctx := context.Background()
-	if c.Request().Header.Get("Authorization") != s.authorizationSecret {
-		return Unauthorized.Build(c)
-	}
+	ctx := c.Request().Context()
 	var addressName types.UpdateAddress
 	if err := c.Bind(&addressName); err != nil {
 		fmt.Println("cannot bind ", err)
@@ -390,6 +491,7 @@ func (s *Server) UpdateAddressName(c echo.Context) error {
 	if err != nil {
 		return Invalid.Build(c)
 	}
+	before := *addressInfo
 
 	addressInfo.Name = addressName.Name
 
@@ -398,13 +500,11 @@ func (s *Server) UpdateAddressName(c echo.Context) error {
 		return Invalid.Build(c)
 	}
 	_ = s.cacheClient.UpdateAddressInfo(ctx, addressInfo)
+	setAudit(c, "address:"+addressInfo.Address, &before, addressInfo)
 	return OK.Build(c)
 }
 
 func (s *Server) ReloadValidators(c echo.Context) error {
-	if c.Request().Header.Get("Authorization") != s.authorizationSecret {
-		return Unauthorized.Build(c)
-	}
 
 	//todo longnd: rework reload validator API
 	//validators, err := s.kaiClient.Validators(ctx)
@@ -420,7 +520,7 @@ func (s *Server) ReloadValidators(c echo.Context) error {
 }
 
 func (s *Server) RemoveDuplicateEvents(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	data, err := s.dbClient.RemoveDuplicateEvents(ctx)
 	if err != nil {
 		return InternalServer.Build(c)
@@ -429,7 +529,7 @@ func (s *Server) RemoveDuplicateEvents(c echo.Context) error {
 }
 
 func (s *Server) RefreshHolders(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	if err := s.dbClient.RemoveKRC20Holders(ctx); err != nil {
 		return Invalid.Build(c)
 	}
@@ -439,9 +539,6 @@ func (s *Server) RefreshHolders(c echo.Context) error {
 
 func (s *Server) UpdateContract(c echo.Context) error {
 	lgr := s.logger.With(zap.String("method", "UpdateContract"))
-	if c.Request().Header.Get("Authorization") != s.authorizationSecret {
-		return Unauthorized.Build(c)
-	}
 
 	var (
 		contract     types.Contract
@@ -459,7 +556,12 @@ func (s *Server) UpdateContract(c echo.Context) error {
 		lgr.Error("cannot bind address data", zap.Error(err))
 		return Invalid.Build(c)
 	}
-	ctx := context.Background()
+	ctx := c.Request().Context()
+	before, _, err := s.dbClient.Contract(ctx, contract.Address)
+	if err != nil {
+		// contract is new
+		before = nil
+	}
 	krcTokenInfoFromRPC, err := s.getKRCTokenInfoFromRPC(ctx, addrInfo.Address, addrInfo.KrcTypes)
 	if err != nil && strings.HasPrefix(addrInfo.KrcTypes, "KRC") {
 		s.logger.Warn("Updating contract is not KRC type", zap.Any("smcInfo", addrInfo), zap.Error(err))
@@ -503,15 +605,13 @@ func (s *Server) UpdateContract(c echo.Context) error {
 		lgr.Error("cannot bind insert", zap.Error(err))
 		return InternalServer.Build(c)
 	}
+	setAudit(c, "contract:"+contract.Address, before, &contract)
 
 	return OK.SetData(addrInfo).Build(c)
 }
 
 func (s *Server) UpdateSMCABIByType(c echo.Context) error {
-	if c.Request().Header.Get("Authorization") != s.authorizationSecret {
-		return Unauthorized.Build(c)
-	}
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	var smcABI *types.ContractABI
 	if err := c.Bind(&smcABI); err != nil {
 		return Invalid.Build(c)
@@ -520,5 +620,6 @@ func (s *Server) UpdateSMCABIByType(c echo.Context) error {
 	if err != nil {
 		return Invalid.Build(c)
 	}
+	setAudit(c, "abi:"+smcABI.Type, nil, smcABI)
 	return OK.Build(c)
 }
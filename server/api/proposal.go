@@ -2,7 +2,6 @@
 package api
 
 import (
-	"context"
 	"fmt"
 	"math/big"
 
@@ -11,7 +10,7 @@ import (
 )
 
 func (s *Server) GetProposalsList(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	pagination, page, limit := getPagingOption(c)
 	//dbResult, dbTotal, dbErr := s.dbClient.GetListProposals(ctx, pagination)
 	//if dbErr != nil {
@@ -52,7 +51,7 @@ func (s *Server) GetProposalsList(c echo.Context) error {
 }
 
 func (s *Server) GetProposalDetails(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	proposalID, ok := new(big.Int).SetString(c.Param("id"), 10)
 	if !ok {
 		return Invalid.Build(c)
@@ -72,7 +71,7 @@ func (s *Server) GetProposalDetails(c echo.Context) error {
 }
 
 func (s *Server) GetParams(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	params, err := s.kaiClient.GetParams(ctx)
 	if err != nil {
 		return Invalid.Build(c)
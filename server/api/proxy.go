@@ -0,0 +1,122 @@
+// Package api
+package api
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/labstack/echo"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+	"github.com/kardiachain/kardia-explorer-backend/utils"
+)
+
+// ContractUpgrades return implementation history of a proxy, latest first
+func (s *Server) ContractUpgrades(c echo.Context) error {
+	ctx := c.Request().Context()
+	pagination, page, limit := getPagingOption(c)
+	filter := &types.ProxyUpgradesFilter{
+		Pagination: pagination,
+		Address:    common.HexToAddress(c.Param("contractAddress")).Hex(),
+	}
+	upgrades, total, err := s.dbClient.ProxyUpgrades(ctx, filter)
+	if err != nil {
+		s.logger.Warn("Cannot get proxy upgrades from db", zap.Error(err))
+		return Invalid.Build(c)
+	}
+	return OK.SetData(PagingResponse{
+		Page:  page,
+		Limit: limit,
+		Total: total,
+		Data:  upgrades,
+	}).Build(c)
+}
+
+// DetectProxy read proxy slots of the contract and record its current implementation
+func (s *Server) DetectProxy(c echo.Context) error {
+	ctx := c.Request().Context()
+	address := c.Param("contractAddress")
+	if !common.IsHexAddress(address) {
+		return Invalid.Build(c)
+	}
+	smc, _, err := s.dbClient.Contract(ctx, common.HexToAddress(address).Hex())
+	if err != nil {
+		return Invalid.Build(c)
+	}
+	if err := s.refreshProxy(ctx, smc); err != nil {
+		resp := Invalid
+		return resp.SetData(&verifyContractError{Error: err.Error()}).Build(c)
+	}
+	return OK.SetData(&types.ProxyInfo{
+		Type:           smc.ProxyType,
+		Implementation: smc.Implementation,
+		Beacon:         smc.Beacon,
+	}).Build(c)
+}
+
+// refreshProxy detect implementation of the contract, an upgrade is recorded and cached ABI
+// dropped when implementation changed. Return kardia.ErrNotProxy for regular contracts.
+func (s *Server) refreshProxy(ctx context.Context, smc *types.Contract) error {
+	info, err := s.kaiClient.DetectProxy(ctx, smc.Address)
+	if err != nil {
+		return err
+	}
+	if smc.ProxyType == info.Type && smc.Implementation == info.Implementation && smc.Beacon == info.Beacon {
+		return nil
+	}
+	upgrade := &types.ProxyUpgrade{
+		Address:        smc.Address,
+		ProxyType:      info.Type,
+		Implementation: info.Implementation,
+		Previous:       smc.Implementation,
+		Beacon:         info.Beacon,
+		CreatedAt:      time.Now(),
+	}
+	smc.ProxyType, smc.Implementation, smc.Beacon = info.Type, info.Implementation, info.Beacon
+	if err := s.dbClient.UpdateContract(ctx, smc, nil); err != nil {
+		return err
+	}
+	if err := s.dbClient.InsertProxyUpgrade(ctx, upgrade); err != nil {
+		s.logger.Warn("Cannot insert proxy upgrade", zap.Error(err), zap.String("address", smc.Address))
+	}
+	if err := s.cacheClient.RemoveSMCAbi(ctx, smc.Address); err != nil {
+		s.logger.Warn("Cannot remove cached proxy abi", zap.Error(err), zap.String("address", smc.Address))
+	}
+	return nil
+}
+
+// proxyABI return base64 ABI of a proxy merged with the ABI of its implementation
+func (s *Server) proxyABI(ctx context.Context, smc *types.Contract) (string, error) {
+	impl, _, err := s.dbClient.Contract(ctx, smc.Implementation)
+	if err != nil {
+		return "", err
+	}
+	implABI := impl.ABI
+	if implABI == "" && impl.Type != "" {
+		implABI, err = s.dbClient.SMCABIByType(ctx, impl.Type)
+		if err != nil {
+			return "", err
+		}
+	}
+	if implABI == "" {
+		return "", errors.New("implementation abi not found")
+	}
+	return utils.MergeABI(smc.ABI, implABI)
+}
+
+// dropProxiesABI remove cached ABI of proxies using the contract, called when its ABI changed
+func (s *Server) dropProxiesABI(ctx context.Context, address string) {
+	proxies, err := s.dbClient.ProxiesOf(ctx, address)
+	if err != nil {
+		s.logger.Warn("Cannot get proxies of contract", zap.Error(err), zap.String("address", address))
+		return
+	}
+	for _, p := range proxies {
+		if err := s.cacheClient.RemoveSMCAbi(ctx, p.Address); err != nil {
+			s.logger.Warn("Cannot remove cached proxy abi", zap.Error(err), zap.String("address", p.Address))
+		}
+	}
+}
@@ -13,6 +13,9 @@ var (
 	InternalServer = EchoResponse{StatusCode: http.StatusInternalServerError, Code: 1100, Msg: "Server busy..."}
 	Invalid        = EchoResponse{StatusCode: http.StatusBadRequest, Code: 1101, Msg: "Bad request"}
 	Unauthorized   = EchoResponse{StatusCode: http.StatusUnauthorized, Code: 401, Msg: "Unauthorized"}
+	Forbidden      = EchoResponse{StatusCode: http.StatusForbidden, Code: 403, Msg: "Forbidden"}
+	TooManyRequest = EchoResponse{StatusCode: http.StatusTooManyRequests, Code: 429, Msg: "Too many requests"}
+	Unavailable    = EchoResponse{StatusCode: http.StatusServiceUnavailable, Code: 503, Msg: "Service unavailable"}
 )
 
 type Pagination struct {
@@ -14,9 +14,26 @@ type RestServer interface {
 	IAddress
 	IKrc721
 	IKrc20
+	IStream
+	IEtherscan
+	ISignature
+	IInternalCall
+	IBalance
+	IChart
+	IGas
+	IMarket
+	IPortfolio
+	IExport
+	IAPIKey
+	IAdmin
+	IOpenAPI
+	IGraphQL
+	ILabel
 
 	// General
 	Ping(c echo.Context) error
+	Liveness(c echo.Context) error
+	Readiness(c echo.Context) error
 	ServerStatus(c echo.Context) error
 	UpdateServerStatus(c echo.Context) error
 	Stats(c echo.Context) error
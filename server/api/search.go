@@ -0,0 +1,256 @@
+// Package api
+package api
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"github.com/labstack/echo"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+const (
+	searchDefaultLimit = 10
+	searchMaxLimit     = 50
+)
+
+var searchHashPattern = regexp.MustCompile(`^(0x)?[0-9a-fA-F]{64}$`)
+
+// searchTypePriority break ties between hits matching the query equally well
+var searchTypePriority = map[string]int{
+	types.SearchTypeValidator: 4,
+	types.SearchTypeToken:     3,
+	types.SearchTypeContract:  2,
+	types.SearchTypeAddress:   1,
+}
+
+type searchHit struct {
+	*types.SearchResult
+	score int
+}
+
+// Search recognize block heights, block and tx hashes and addresses in q, otherwise match q as the prefix
+// or a word of validator, token, contract and address names. Hits are ranked best first.
+func (s *Server) Search(c echo.Context) error {
+	query := strings.TrimSpace(c.QueryParam("q"))
+	if query == "" {
+		// clients of the former name search
+		if c.QueryParam("name") != "" {
+			return s.SearchAddressByName(c)
+		}
+		return Invalid.Build(c)
+	}
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 {
+		limit = searchDefaultLimit
+	}
+	if limit > searchMaxLimit {
+		limit = searchMaxLimit
+	}
+
+	ctx := c.Request().Context()
+	var hits []*searchHit
+	switch {
+	case isBlockHeight(query):
+		hits = s.searchBlockHeight(ctx, query)
+	case searchHashPattern.MatchString(query):
+		hits = s.searchHash(ctx, common.HexToHash(query).Hex())
+	case common.IsHexAddress(query):
+		hits = s.searchAddress(ctx, common.HexToAddress(query).String())
+	default:
+		hits = s.searchNames(ctx, query, limit)
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].score > hits[j].score
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	result := make([]*types.SearchResult, len(hits))
+	for i, hit := range hits {
+		result[i] = hit.SearchResult
+	}
+	s.labelSearchResults(ctx, result)
+	return OK.SetData(result).Build(c)
+}
+
+func isBlockHeight(query string) bool {
+	_, err := strconv.ParseUint(query, 10, 64)
+	return err == nil
+}
+
+func (s *Server) searchBlockHeight(ctx context.Context, query string) []*searchHit {
+	height, _ := strconv.ParseUint(query, 10, 64)
+	block, err := s.dbClient.BlockByHeight(ctx, height)
+	if err != nil || block == nil {
+		return nil
+	}
+	return []*searchHit{{SearchResult: blockSearchResult(block)}}
+}
+
+func (s *Server) searchHash(ctx context.Context, hash string) []*searchHit {
+	var hits []*searchHit
+	if tx, err := s.dbClient.TxByHash(ctx, hash); err == nil && tx != nil {
+		to := tx.To
+		if to == "" {
+			to = tx.ContractAddress
+		}
+		hits = append(hits, &searchHit{SearchResult: &types.SearchResult{
+			Type:    types.SearchTypeTx,
+			ID:      tx.Hash,
+			Preview: fmt.Sprintf("Tx in block %d from %s to %s", tx.BlockNumber, tx.From, to),
+		}})
+	}
+	if block, err := s.dbClient.BlockByHash(ctx, hash); err == nil && block != nil {
+		hits = append(hits, &searchHit{SearchResult: blockSearchResult(block)})
+	}
+	return hits
+}
+
+func blockSearchResult(block *types.Block) *types.SearchResult {
+	return &types.SearchResult{
+		Type:    types.SearchTypeBlock,
+		ID:      strconv.FormatUint(block.Height, 10),
+		Preview: fmt.Sprintf("Block %d with %d txs at %s", block.Height, block.NumTxs, block.Time.UTC().Format(time.RFC3339)),
+	}
+}
+
+// searchAddress always return a hit, unknown addresses are valid accounts which have not been active yet
+func (s *Server) searchAddress(ctx context.Context, address string) []*searchHit {
+	if validator, err := s.dbClient.Validator(ctx, address); err == nil && validator != nil {
+		return []*searchHit{{SearchResult: validatorSearchResult(validator)}}
+	}
+	if contracts, err := s.dbClient.ContractsByAddresses(ctx, []string{address}); err == nil && len(contracts) > 0 {
+		return []*searchHit{{SearchResult: contractSearchResult(contracts[0])}}
+	}
+	result := &types.SearchResult{Type: types.SearchTypeAddress, ID: address, Preview: "Address"}
+	if addr, err := s.dbClient.AddressByHash(ctx, address); err == nil && addr != nil {
+		result = addressSearchResult(addr)
+	}
+	return []*searchHit{{SearchResult: result}}
+}
+
+func (s *Server) searchNames(ctx context.Context, query string, limit int) []*searchHit {
+	var (
+		wg         sync.WaitGroup
+		validators []*types.Validator
+		contracts  []*types.Contract
+		addresses  []*types.Address
+		lgr        = s.logger.With(zap.String("method", "Search"), zap.String("query", query))
+	)
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		var err error
+		if validators, err = s.dbClient.SearchValidators(ctx, query, limit); err != nil {
+			lgr.Warn("cannot search validators", zap.Error(err))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		var err error
+		if contracts, err = s.dbClient.SearchContracts(ctx, query, limit); err != nil {
+			lgr.Warn("cannot search contracts", zap.Error(err))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		var err error
+		if addresses, err = s.dbClient.SearchAddresses(ctx, query, limit); err != nil {
+			lgr.Warn("cannot search addresses", zap.Error(err))
+		}
+	}()
+	wg.Wait()
+
+	var (
+		hits []*searchHit
+		byID = make(map[string]*searchHit)
+		// validators are also stored as named contracts and addresses of their staking contract
+		validatorSMCs = make(map[string]bool)
+	)
+	add := func(result *types.SearchResult, names ...string) {
+		hit := &searchHit{SearchResult: result, score: searchScore(query, result.Type, names...)}
+		if prev, ok := byID[result.ID]; ok {
+			if prev.score < hit.score {
+				*prev = *hit
+			}
+			return
+		}
+		byID[result.ID] = hit
+		hits = append(hits, hit)
+	}
+	for _, v := range validators {
+		validatorSMCs[v.SmcAddress] = true
+		add(validatorSearchResult(v), v.Name, v.Address)
+	}
+	for _, smc := range contracts {
+		if smc.Type == cfg.SMCTypeValidator || validatorSMCs[smc.Address] {
+			continue
+		}
+		add(contractSearchResult(smc), smc.Name, smc.Symbol, smc.Address)
+	}
+	for _, addr := range addresses {
+		if validatorSMCs[addr.Address] {
+			continue
+		}
+		add(addressSearchResult(addr), addr.Name, addr.Address)
+	}
+	return hits
+}
+
+// searchScore rank exact matches of a name or address before prefix matches before word matches, then by entity type
+func searchScore(query, searchType string, names ...string) int {
+	match := 1
+	query = strings.ToLower(query)
+	for _, name := range names {
+		name = strings.ToLower(name)
+		switch {
+		case name == query:
+			match = 3
+		case strings.HasPrefix(name, query) && match < 2:
+			match = 2
+		}
+	}
+	return match*10 + searchTypePriority[searchType]
+}
+
+func validatorSearchResult(v *types.Validator) *types.SearchResult {
+	preview := "Validator"
+	if v.VotingPowerPercentage != "" {
+		preview = fmt.Sprintf("Validator with %s%% of voting power", v.VotingPowerPercentage)
+	}
+	return &types.SearchResult{Type: types.SearchTypeValidator, ID: v.Address, Name: v.Name, Preview: preview}
+}
+
+func contractSearchResult(smc *types.Contract) *types.SearchResult {
+	result := &types.SearchResult{Type: types.SearchTypeContract, ID: smc.Address, Name: smc.Name, Logo: smc.Logo, Preview: "Contract"}
+	switch {
+	case smc.Type == cfg.SMCTypeKRC20 || smc.Type == cfg.SMCTypeKRC721:
+		result.Type = types.SearchTypeToken
+		result.Symbol = smc.Symbol
+		result.Preview = strings.TrimSpace(fmt.Sprintf("%s token %s", smc.Type, smc.Symbol))
+	case smc.IsVerified:
+		result.Preview = "Verified contract"
+	}
+	return result
+}
+
+func addressSearchResult(addr *types.Address) *types.SearchResult {
+	result := &types.SearchResult{Type: types.SearchTypeAddress, ID: addr.Address, Name: addr.Name, Logo: addr.Logo, Preview: "Address"}
+	if addr.IsContract {
+		result.Type = types.SearchTypeContract
+		result.Preview = "Contract"
+	}
+	return result
+}
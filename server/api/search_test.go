@@ -0,0 +1,128 @@
+// Package api
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+// seedSearch index a block, a tx, a validator with its contract, a token and a router
+func seedSearch(d *fakeDB) {
+	d.blocks = []*types.Block{{
+		Height: 1200,
+		Hash:   "0x6f2ef6f0c2f94c6f5c6bd0f30a8e0e4a3c7d22a6e9b2a8e1d4b6f4b1f2e3d4c5",
+		NumTxs: 3,
+		Time:   time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+	}}
+	d.txs = []*types.Transaction{{
+		Hash:        "0x1b0f2d8a0c4e9b7f6a5d3c2b1a09f8e7d6c5b4a39281706f5e4d3c2b1a098f7e",
+		BlockNumber: 1200,
+		From:        "0xc1fe56E3F58D3244F606306611a5d10c8333f1f6",
+		To:          "0x14191195F9BB6e54465a341CeC6cce4491599ccC",
+	}}
+	d.validators = []*types.Validator{{
+		Address:               "0x7F0E7F2A3F4C6D4A9A0E5B7E9C3D2B1A0F9E8D7C",
+		SmcAddress:            "0x0000000000000000000000000000000000000101",
+		Name:                  "Kai Validator",
+		VotingPowerPercentage: "12.5",
+	}}
+	d.contracts = []*types.Contract{
+		{Address: "0x0000000000000000000000000000000000000101", Name: "Kai Validator", Type: cfg.SMCTypeValidator},
+		{Address: "0x3a6a1E6bE6aC2F0b6D8b8A0c35e1D7B5E0a1C2D3", Name: "Kaiswap Router", Type: cfg.SMCTypeNormal, IsVerified: true},
+		{Address: "0x14191195F9BB6e54465a341CeC6cce4491599ccC", Name: "Kai", Symbol: "KAI", Type: cfg.SMCTypeKRC20},
+	}
+	d.addresses = []*types.Address{
+		{Address: "0x0000000000000000000000000000000000000101", Name: "Kai Validator"},
+		{Address: "0x14191195F9BB6e54465a341CeC6cce4491599ccC", Name: "Kai", IsContract: true},
+		{Address: "0xc1fe56E3F58D3244F606306611a5d10c8333f1f6", Name: "Team Kai"},
+	}
+	d.labels = []*types.AddressLabel{
+		{Address: "0x14191195F9BB6e54465a341CeC6cce4491599ccC", Label: types.Label{Name: "KAI Bridge", Category: types.LabelCategoryBridge}},
+	}
+}
+
+func search(t *testing.T, f *fakeServer, target string) (int, []*types.SearchResult) {
+	var hits []*types.SearchResult
+	code := callData(t, f.Search, newRequest(echo.GET, target, nil, nil), &hits)
+	return code, hits
+}
+
+func TestSearch_Names(t *testing.T) {
+	f := newFakeServer()
+	seedSearch(f.db)
+	code, hits := search(t, f, "/api/v1/search?q=kai")
+	require.Equal(t, http.StatusOK, code)
+
+	var got [][2]string
+	for _, hit := range hits {
+		got = append(got, [2]string{hit.Type, hit.Name})
+	}
+	assert.Equal(t, [][2]string{
+		{types.SearchTypeToken, "Kai"},
+		{types.SearchTypeValidator, "Kai Validator"},
+		{types.SearchTypeContract, "Kaiswap Router"},
+		{types.SearchTypeAddress, "Team Kai"},
+	}, got)
+	assert.Equal(t, "KRC20 token KAI", hits[0].Preview)
+	assert.Equal(t, "KAI Bridge", hits[0].Labels[0].Name)
+	assert.Empty(t, hits[1].Labels)
+	assert.Equal(t, "Validator with 12.5% of voting power", hits[1].Preview)
+	assert.Equal(t, "Verified contract", hits[2].Preview)
+
+	_, hits = search(t, f, "/api/v1/search?q=kai&limit=2")
+	assert.Len(t, hits, 2)
+}
+
+func TestSearch_AddressPrefix(t *testing.T) {
+	f := newFakeServer()
+	seedSearch(f.db)
+	// partial addresses are searched as names, hits whose address start with the query come first
+	code, hits := search(t, f, "/api/v1/search?q=0xC1FE56")
+	require.Equal(t, http.StatusOK, code)
+	require.NotEmpty(t, hits)
+	assert.Equal(t, types.SearchTypeAddress, hits[0].Type)
+	assert.Equal(t, "0xc1fe56E3F58D3244F606306611a5d10c8333f1f6", hits[0].ID)
+}
+
+func TestSearch_Recognize(t *testing.T) {
+	f := newFakeServer()
+	seedSearch(f.db)
+
+	_, hits := search(t, f, "/api/v1/search?q=1200")
+	require.Len(t, hits, 1)
+	assert.Equal(t, types.SearchTypeBlock, hits[0].Type)
+	assert.Equal(t, "1200", hits[0].ID)
+	assert.Equal(t, "Block 1200 with 3 txs at 2021-05-01T00:00:00Z", hits[0].Preview)
+
+	_, hits = search(t, f, "/api/v1/search?q=0x6F2EF6F0C2F94C6F5C6BD0F30A8E0E4A3C7D22A6E9B2A8E1D4B6F4B1F2E3D4C5")
+	require.Len(t, hits, 1)
+	assert.Equal(t, types.SearchTypeBlock, hits[0].Type)
+
+	_, hits = search(t, f, "/api/v1/search?q="+f.db.txs[0].Hash)
+	require.Len(t, hits, 1)
+	assert.Equal(t, types.SearchTypeTx, hits[0].Type)
+
+	_, hits = search(t, f, "/api/v1/search?q=0x14191195f9bb6e54465a341cec6cce4491599ccc")
+	require.Len(t, hits, 1)
+	assert.Equal(t, types.SearchTypeToken, hits[0].Type)
+	assert.Equal(t, "0x14191195F9BB6e54465a341CeC6cce4491599ccC", hits[0].ID)
+
+	// valid addresses are hits even before they are indexed
+	_, hits = search(t, f, "/api/v1/search?q=0x00000000000000000000000000000000000000ff")
+	require.Len(t, hits, 1)
+	assert.Equal(t, types.SearchTypeAddress, hits[0].Type)
+
+	_, hits = search(t, f, "/api/v1/search?q=99999")
+	assert.Empty(t, hits)
+
+	code, _ := search(t, f, "/api/v1/search?q=")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -2,25 +2,46 @@
 package api
 
 import (
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql"
 	kClient "github.com/kardiachain/go-kaiclient/kardia"
 	"github.com/kardiachain/kardia-explorer-backend/cache"
 	"github.com/kardiachain/kardia-explorer-backend/db"
 	s3 "github.com/kardiachain/kardia-explorer-backend/driver/aws"
+	"github.com/kardiachain/kardia-explorer-backend/driver/solc"
+	"github.com/kardiachain/kardia-explorer-backend/external"
 	"github.com/kardiachain/kardia-explorer-backend/kardia"
 	"go.uber.org/zap"
 )
 
 type Server struct {
 	authorizationSecret string
+	adminTokenSecret    string
+	adminTokenTTL       time.Duration
+
+	graphQLMaxDepth      int
+	graphQLMaxComplexity int
+	graphQLOnce          sync.Once
+	graphQLSchema        graphql.Schema
+
+	healthMaxBlockLag uint64
+	healthMaxBlockAge time.Duration
 
 	node        kClient.Node
 	dbClient    db.Client
 	cacheClient cache.Client
 	kaiClient   kardia.ClientInterface
+	compiler    solc.Compiler
+	market      *external.Market
 
 	s3.ConfigUploader
 	fileStorage s3.FileStorage
 
+	streamOnce sync.Once
+	stream     *streamHub
+
 	logger *zap.Logger
 }
 
@@ -33,6 +54,28 @@ func (s *Server) SetSecret(secret string) *Server {
 	return s
 }
 
+// SetAdminAuth set key signing admin tokens and how long tokens are valid, admin APIs are disabled with empty secret
+func (s *Server) SetAdminAuth(secret string, ttl time.Duration) *Server {
+	s.adminTokenSecret = secret
+	s.adminTokenTTL = ttl
+	return s
+}
+
+// SetGraphQLLimits set the maximum depth and complexity of GraphQL queries, 0 disables the limit
+func (s *Server) SetGraphQLLimits(maxDepth, maxComplexity int) *Server {
+	s.graphQLMaxDepth = maxDepth
+	s.graphQLMaxComplexity = maxComplexity
+	return s
+}
+
+// SetHealthThresholds set how far behind the chain head and how old the newest indexed block can be
+// before the replica is reported not ready
+func (s *Server) SetHealthThresholds(maxLag uint64, maxAge time.Duration) *Server {
+	s.healthMaxBlockLag = maxLag
+	s.healthMaxBlockAge = maxAge
+	return s
+}
+
 func (s *Server) SetLogger(logger *zap.Logger) *Server {
 	s.logger = logger
 	return s
@@ -57,3 +100,13 @@ func (s *Server) SetNode(node kClient.Node) *Server {
 	s.node = node
 	return s
 }
+
+func (s *Server) SetCompiler(compiler solc.Compiler) *Server {
+	s.compiler = compiler
+	return s
+}
+
+func (s *Server) SetMarket(market *external.Market) *Server {
+	s.market = market
+	return s
+}
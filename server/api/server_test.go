@@ -0,0 +1,421 @@
+// Package api
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/cache"
+	"github.com/kardiachain/kardia-explorer-backend/db"
+	"github.com/kardiachain/kardia-explorer-backend/kardia"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+var errFakeNotFound = errors.New("mongo: no documents in result")
+
+// fakeServer is a Server backed by in memory db, cache and node clients
+type fakeServer struct {
+	*Server
+	db    *fakeDB
+	cache *fakeCache
+	kai   *fakeKai
+}
+
+func newFakeServer() *fakeServer {
+	f := &fakeServer{
+		db: &fakeDB{
+			calls: make(map[string]int),
+			keys:  make(map[string][]string),
+		},
+		cache: &fakeCache{
+			tokens:  make(map[string]int),
+			usage:   make(map[string]int64),
+			apiKeys: make(map[string]*types.APIKey),
+		},
+		kai: &fakeKai{},
+	}
+	f.Server = &Server{dbClient: f.db, cacheClient: f.cache, kaiClient: f.kai, logger: zap.NewNop()}
+	return f
+}
+
+// fakeDB keep documents in slices, methods which are not stubbed panic
+type fakeDB struct {
+	db.Client
+	pingErr    error
+	blocks     []*types.Block
+	txs        []*types.Transaction
+	addresses  []*types.Address
+	contracts  []*types.Contract
+	validators []*types.Validator
+	labels     []*types.AddressLabel
+	adminUsers []*types.AdminUser
+	auditLogs  []*types.AuditLog
+	apiKeys    []*types.APIKey
+	// internalCalls are returned by tx hash
+	internalCalls []*types.InternalCall
+	// exportErr stop export cursors after their rows
+	exportErr error
+
+	// calls and keys record queries to check batching
+	calls map[string]int
+	keys  map[string][]string
+}
+
+func (d *fakeDB) called(method string, keys ...string) {
+	d.calls[method]++
+	d.keys[method] = append(d.keys[method], keys...)
+}
+
+func (d *fakeDB) Ping(ctx context.Context) error {
+	d.called("Ping")
+	return d.pingErr
+}
+
+func (d *fakeDB) Blocks(ctx context.Context, pagination *types.Pagination) ([]*types.Block, error) {
+	d.called("Blocks")
+	if d.pingErr != nil {
+		return nil, d.pingErr
+	}
+	start, end := page(len(d.blocks), pagination)
+	return d.blocks[start:end], nil
+}
+
+func (d *fakeDB) BlockByHeight(ctx context.Context, height uint64) (*types.Block, error) {
+	d.called("BlockByHeight")
+	for _, b := range d.blocks {
+		if b.Height == height {
+			return b, nil
+		}
+	}
+	return nil, errFakeNotFound
+}
+
+func (d *fakeDB) BlockByHash(ctx context.Context, hash string) (*types.Block, error) {
+	d.called("BlockByHash", hash)
+	for _, b := range d.blocks {
+		if b.Hash == hash {
+			return b, nil
+		}
+	}
+	return nil, errFakeNotFound
+}
+
+func (d *fakeDB) BlocksByHeights(ctx context.Context, heights []uint64) ([]*types.Block, error) {
+	d.called("BlocksByHeights")
+	var blocks []*types.Block
+	for _, height := range heights {
+		for _, b := range d.blocks {
+			if b.Height == height {
+				blocks = append(blocks, b)
+			}
+		}
+	}
+	return blocks, nil
+}
+
+func (d *fakeDB) TxByHash(ctx context.Context, hash string) (*types.Transaction, error) {
+	d.called("TxByHash", hash)
+	for _, tx := range d.txs {
+		if tx.Hash == hash {
+			return tx, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *fakeDB) TxsByBlockHeights(ctx context.Context, blockHeights []uint64, pagination *types.Pagination) (map[uint64][]*types.Transaction, error) {
+	d.called("TxsByBlockHeights")
+	txs := make(map[uint64][]*types.Transaction)
+	for _, blockHeight := range blockHeights {
+		var blockTxs []*types.Transaction
+		for _, tx := range d.txs {
+			if tx.BlockNumber == blockHeight {
+				blockTxs = append(blockTxs, tx)
+			}
+		}
+		start, end := page(len(blockTxs), pagination)
+		txs[blockHeight] = blockTxs[start:end]
+	}
+	return txs, nil
+}
+
+func (d *fakeDB) ExportTxs(ctx context.Context, filter *types.ExportFilter, fn func(tx *types.Transaction) error) error {
+	d.called("ExportTxs")
+	for _, tx := range d.txs {
+		if err := fn(tx); err != nil {
+			return err
+		}
+	}
+	return d.exportErr
+}
+
+func (d *fakeDB) InternalCallsByTxHashes(ctx context.Context, txHashes []string) (map[string][]*types.InternalCall, error) {
+	d.called("InternalCallsByTxHashes", txHashes...)
+	calls := make(map[string][]*types.InternalCall)
+	for _, call := range d.internalCalls {
+		if containsString(txHashes, call.TxHash) {
+			calls[call.TxHash] = append(calls[call.TxHash], call)
+		}
+	}
+	return calls, nil
+}
+
+func (d *fakeDB) AddressByHash(ctx context.Context, address string) (*types.Address, error) {
+	d.called("AddressByHash", address)
+	for _, addr := range d.addresses {
+		if addr.Address == address {
+			return addr, nil
+		}
+	}
+	return nil, errFakeNotFound
+}
+
+func (d *fakeDB) AddressesByHashes(ctx context.Context, hashes []string) ([]*types.Address, error) {
+	d.called("AddressesByHashes", hashes...)
+	var addresses []*types.Address
+	for _, addr := range d.addresses {
+		if containsString(hashes, addr.Address) {
+			addresses = append(addresses, addr)
+		}
+	}
+	return addresses, nil
+}
+
+func (d *fakeDB) ContractsByAddresses(ctx context.Context, addresses []string) ([]*types.Contract, error) {
+	d.called("ContractsByAddresses", addresses...)
+	var contracts []*types.Contract
+	for _, smc := range d.contracts {
+		if containsString(addresses, smc.Address) {
+			contracts = append(contracts, smc)
+		}
+	}
+	return contracts, nil
+}
+
+func (d *fakeDB) Validator(ctx context.Context, address string) (*types.Validator, error) {
+	d.called("Validator", address)
+	for _, v := range d.validators {
+		if v.Address == address {
+			return v, nil
+		}
+	}
+	return nil, errFakeNotFound
+}
+
+func (d *fakeDB) Validators(ctx context.Context, filter db.ValidatorsFilter) ([]*types.Validator, error) {
+	d.called("Validators")
+	return d.validators, nil
+}
+
+func (d *fakeDB) SearchValidators(ctx context.Context, query string, limit int) ([]*types.Validator, error) {
+	d.called("SearchValidators", query)
+	return d.validators, nil
+}
+
+func (d *fakeDB) SearchContracts(ctx context.Context, query string, limit int) ([]*types.Contract, error) {
+	d.called("SearchContracts", query)
+	return d.contracts, nil
+}
+
+func (d *fakeDB) SearchAddresses(ctx context.Context, query string, limit int) ([]*types.Address, error) {
+	d.called("SearchAddresses", query)
+	return d.addresses, nil
+}
+
+func (d *fakeDB) UpsertAddressLabels(ctx context.Context, labels []*types.AddressLabel) error {
+	d.called("UpsertAddressLabels")
+	d.labels = append(d.labels, labels...)
+	return nil
+}
+
+func (d *fakeDB) AddressLabels(ctx context.Context, filter *types.AddressLabelsFilter) ([]*types.AddressLabel, uint64, error) {
+	d.called("AddressLabels", filter.Address)
+	var labels []*types.AddressLabel
+	for _, label := range d.labels {
+		if label.Address == filter.Address && label.Category == filter.Category && label.Name == filter.Name {
+			labels = append(labels, label)
+		}
+	}
+	return labels, uint64(len(labels)), nil
+}
+
+func (d *fakeDB) RemoveAddressLabel(ctx context.Context, address, category, name string) (bool, error) {
+	d.called("RemoveAddressLabel", address)
+	for i, label := range d.labels {
+		if label.Address == address && label.Category == category && label.Name == name {
+			d.labels = append(d.labels[:i], d.labels[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (d *fakeDB) LabelsOfAddresses(ctx context.Context, addresses []string) (map[string][]*types.Label, error) {
+	d.called("LabelsOfAddresses", addresses...)
+	labels := make(map[string][]*types.Label)
+	for _, label := range d.labels {
+		if containsString(addresses, label.Address) {
+			labels[label.Address] = append(labels[label.Address], &label.Label)
+		}
+	}
+	return labels, nil
+}
+
+func (d *fakeDB) AdminUser(ctx context.Context, username string) (*types.AdminUser, error) {
+	d.called("AdminUser", username)
+	for _, user := range d.adminUsers {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *fakeDB) InsertAuditLog(ctx context.Context, log *types.AuditLog) error {
+	d.called("InsertAuditLog")
+	d.auditLogs = append(d.auditLogs, log)
+	return nil
+}
+
+func (d *fakeDB) APIKeyByHash(ctx context.Context, keyHash string) (*types.APIKey, error) {
+	d.called("APIKeyByHash")
+	for _, key := range d.apiKeys {
+		if key.KeyHash == keyHash {
+			return key, nil
+		}
+	}
+	return nil, nil
+}
+
+// fakeCache allow requests while bucket has tokens left and report every list with listSize items
+type fakeCache struct {
+	cache.Client
+	err       error
+	tokens    map[string]int
+	usage     map[string]int64
+	endpoints []string
+	buckets   []string
+	apiKeys   map[string]*types.APIKey
+	listSize  int64
+}
+
+func (c *fakeCache) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (c *fakeCache) ListSize(ctx context.Context, key string) (int64, error) {
+	return c.listSize, nil
+}
+
+func (c *fakeCache) RateLimit(ctx context.Context, bucket string, tier *types.RateLimitTier, now time.Time) (*types.RateLimitResult, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.buckets = append(c.buckets, bucket)
+	left, ok := c.tokens[bucket]
+	if !ok {
+		left = tier.Burst
+	}
+	if left <= 0 {
+		return &types.RateLimitResult{Limit: tier.RequestsPerMinute, RetryAfter: 1500 * time.Millisecond}, nil
+	}
+	c.tokens[bucket] = left - 1
+	return &types.RateLimitResult{Allowed: true, Limit: tier.RequestsPerMinute, Remaining: left - 1}, nil
+}
+
+func (c *fakeCache) IncrUsage(ctx context.Context, subject, endpoint string, now time.Time) (int64, error) {
+	c.usage[subject]++
+	c.endpoints = append(c.endpoints, endpoint)
+	return c.usage[subject], nil
+}
+
+func (c *fakeCache) APIKey(ctx context.Context, keyHash string) (*types.APIKey, bool, error) {
+	key, ok := c.apiKeys[keyHash]
+	return key, ok, nil
+}
+
+func (c *fakeCache) SetAPIKey(ctx context.Context, keyHash string, key *types.APIKey) error {
+	c.apiKeys[keyHash] = key
+	return nil
+}
+
+type fakeKai struct {
+	kardia.ClientInterface
+	nodes []*types.NodeHealth
+}
+
+func (k *fakeKai) NodesHealth(ctx context.Context) []*types.NodeHealth {
+	return k.nodes
+}
+
+// page return bounds of the page of a list of size items
+func page(size int, pagination *types.Pagination) (int, int) {
+	start, end := pagination.Skip, pagination.Skip+pagination.Limit
+	if start > size {
+		start = size
+	}
+	if end > size {
+		end = size
+	}
+	return start, end
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func newRequest(method, target string, body io.Reader, header map[string]string) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	return req
+}
+
+// callHandler run handler on req without routing nor middlewares
+func callHandler(t *testing.T, handler echo.HandlerFunc, req *http.Request) (echo.Context, *httptest.ResponseRecorder) {
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	require.NoError(t, handler(c))
+	return c, rec
+}
+
+// callData run handler on req and decode the data of its response
+func callData(t *testing.T, handler echo.HandlerFunc, req *http.Request, data interface{}) int {
+	_, rec := callHandler(t, handler, req)
+	decodeData(t, rec, data)
+	return rec.Code
+}
+
+// decodeData decode the data field of a response built by respone.go
+func decodeData(t *testing.T, rec *httptest.ResponseRecorder, data interface{}) {
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	if len(body.Data) > 0 {
+		require.NoError(t, json.Unmarshal(body.Data, data))
+	}
+}
+
+// serveRequest route req through e with its middlewares
+func serveRequest(e *echo.Echo, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
@@ -0,0 +1,133 @@
+// Package api
+package api
+
+import (
+	"context"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/labstack/echo"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/kardia"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+	"github.com/kardiachain/kardia-explorer-backend/utils"
+)
+
+type ISignature interface {
+	Signatures(c echo.Context) error
+}
+
+func bindSignatureAPIs(gr *echo.Group, srv RestServer) {
+	apis := []restDefinition{
+		{
+			method: echo.GET,
+			// 4-byte function selector or 32-byte event topic
+			path:        "/signatures/:hash",
+			fn:          srv.Signatures,
+			middlewares: nil,
+		},
+	}
+	for _, api := range apis {
+		gr.Add(api.method, api.path, api.fn, api.middlewares...)
+	}
+}
+
+// Signatures return every known text signature of a selector or topic
+func (s *Server) Signatures(c echo.Context) error {
+	ctx := c.Request().Context()
+	hash := strings.ToLower(c.Param("hash"))
+	if !strings.HasPrefix(hash, "0x") {
+		hash = "0x" + hash
+	}
+	if len(hash) != 10 && len(hash) != 66 {
+		return Invalid.Build(c)
+	}
+	sigs, err := s.dbClient.SignaturesByHash(ctx, hash)
+	if err != nil {
+		s.logger.Warn("Cannot get signatures from db", zap.Error(err), zap.String("hash", hash))
+		return Invalid.Build(c)
+	}
+	return OK.SetData(sigs).Build(c)
+}
+
+// decodeInputWithSignatures decode call of a contract without ABI from signatures registry
+func (s *Server) decodeInputWithSignatures(ctx context.Context, to, input string) *types.FunctionCall {
+	if len(input) < 10 {
+		return nil
+	}
+	sigs, err := s.dbClient.SignaturesByHash(ctx, strings.ToLower(input[:10]))
+	if err != nil || len(sigs) == 0 {
+		return nil
+	}
+	decoded, err := s.kaiClient.DecodeInputWithSignatures(to, input, sigs)
+	if err != nil {
+		return nil
+	}
+	return decoded
+}
+
+// unpackLogWithSignatures unpack log of a contract without ABI from signatures registry
+func (s *Server) unpackLogWithSignatures(ctx context.Context, l *types.Log) *types.Log {
+	if len(l.Topics) == 0 {
+		return nil
+	}
+	sigs, err := s.dbClient.SignaturesByHash(ctx, strings.ToLower(l.Topics[0]))
+	if err != nil || len(sigs) == 0 {
+		return nil
+	}
+	unpacked, err := s.kaiClient.UnpackLogWithSignatures(l, sigs)
+	if err != nil {
+		return nil
+	}
+	return unpacked
+}
+
+// storeABISignatures add functions and events of a verified ABI to signatures registry
+func (s *Server) storeABISignatures(ctx context.Context, abiBase64 string) {
+	abiData, err := utils.DecodeABI(abiBase64)
+	if err != nil {
+		s.logger.Warn("Cannot decode abi for signatures", zap.Error(err))
+		return
+	}
+	sigs, err := kardia.SignaturesFromABI(abiData, types.SignatureSourceVerified)
+	if err != nil {
+		s.logger.Warn("Cannot read signatures from abi", zap.Error(err))
+		return
+	}
+	if err := s.dbClient.UpsertSignatures(ctx, sigs); err != nil {
+		s.logger.Warn("Cannot store signatures", zap.Error(err))
+	}
+}
+
+// loadSignatures seed signatures registry from bundled signature file and every known ABI
+func (s *Server) loadSignatures(ctx context.Context, typeABIs []*types.ContractABI) error {
+	wd, _ := os.Getwd()
+	f, err := os.Open(path.Join(wd, "/abi/signatures.txt"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	sigs, err := kardia.ParseSignatures(f, types.SignatureSourceBundled)
+	if err != nil {
+		return err
+	}
+	if err := s.dbClient.UpsertSignatures(ctx, sigs); err != nil {
+		return err
+	}
+
+	for _, smcABI := range typeABIs {
+		s.storeABISignatures(ctx, smcABI.ABI)
+	}
+	contracts, err := s.dbClient.AllContracts(ctx)
+	if err != nil {
+		return err
+	}
+	for _, smc := range contracts {
+		if smc.IsVerified && smc.ABI != "" {
+			s.storeABISignatures(ctx, smc.ABI)
+		}
+	}
+	return nil
+}
@@ -2,7 +2,6 @@
 package api
 
 import (
-	"context"
 	"math/big"
 	"sort"
 
@@ -67,7 +66,7 @@ func bindStakingAPIs(gr *echo.Group, srv RestServer) {
 
 func (s *Server) StakingStats(c echo.Context) error {
 	lgr := s.logger.With(zap.String("method", "StakingStats"))
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	stats, err := s.cacheClient.StakingStats(ctx)
 	if err != nil {
 		lgr.Debug("cannot get staking stats from cache", zap.Error(err))
@@ -77,7 +76,7 @@ func (s *Server) StakingStats(c echo.Context) error {
 }
 
 func (s *Server) Validators(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 
 	validators, err := s.dbClient.Validators(ctx, db.ValidatorsFilter{})
 	if err != nil {
@@ -95,12 +94,13 @@ func (s *Server) Validators(c echo.Context) error {
 			resp = append(resp, v)
 		}
 	}
+	s.labelValidators(ctx, resp)
 
 	return OK.SetData(resp).Build(c)
 }
 
 func (s *Server) ValidatorsByDelegator(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	delAddr := c.Param("address")
 	valsList, err := s.kaiClient.GetValidatorsByDelegator(ctx, common.HexToAddress(delAddr))
 	if err != nil {
@@ -110,11 +110,12 @@ func (s *Server) ValidatorsByDelegator(c echo.Context) error {
 }
 
 func (s *Server) Candidates(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	candidates, err := s.dbClient.Validators(ctx, db.ValidatorsFilter{Role: cfg.RoleCandidate})
 	if err != nil {
 		return Invalid.Build(c)
 	}
+	s.labelValidators(ctx, candidates)
 
 	return OK.SetData(candidates).Build(c)
 }
@@ -151,7 +152,7 @@ func RemoveIndexDelegator(s []*types.Delegator, index int) []*types.Delegator {
 
 func (s *Server) Validator(c echo.Context) error {
 	lgr := s.logger.With(zap.String("method", "Validator"))
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	var (
 		err error
 	)
@@ -179,6 +180,7 @@ func (s *Server) Validator(c echo.Context) error {
 	}
 
 	validator.Delegators = SortAscByStakeAmount(validatorSMCAddress, delegators)
+	s.labelValidators(ctx, []*types.Validator{validator})
 	total, err := s.dbClient.CountDelegators(ctx, filter)
 	if err != nil {
 		lgr.Error("cannot count delegator", zap.Error(err))
@@ -194,7 +196,7 @@ func (s *Server) Validator(c echo.Context) error {
 }
 
 func (s *Server) MobileValidators(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 
 	validators, err := s.dbClient.Validators(ctx, db.ValidatorsFilter{})
 	if err != nil {
@@ -212,6 +214,7 @@ func (s *Server) MobileValidators(c echo.Context) error {
 			resp = append(resp, v)
 		}
 	}
+	s.labelValidators(ctx, resp)
 	stats, err := s.cacheClient.StakingStats(ctx)
 	if err != nil {
 		stats = &types.StakingStats{}
@@ -226,11 +229,12 @@ func (s *Server) MobileValidators(c echo.Context) error {
 }
 
 func (s *Server) MobileCandidates(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	candidates, err := s.dbClient.Validators(ctx, db.ValidatorsFilter{Role: cfg.RoleCandidate})
 	if err != nil {
 		return Invalid.Build(c)
 	}
+	s.labelValidators(ctx, candidates)
 
 	stats, err := s.cacheClient.StakingStats(ctx)
 	if err != nil {
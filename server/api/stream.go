@@ -0,0 +1,189 @@
+// Package api
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/cache"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+const (
+	streamHeartbeatInterval = 15 * time.Second
+	streamSubscriberBuffer  = 64
+	// streamMaxTopics bound matching cost of each event per connection
+	streamMaxTopics = 20
+)
+
+type IStream interface {
+	Stream(c echo.Context) error
+}
+
+func bindStreamAPIs(gr *echo.Group, srv RestServer) {
+	apis := []restDefinition{
+		{
+			method: echo.GET,
+			// Server-sent events
+			// Query params: ?subscribe=blocks&subscribe=txs:0x...&subscribe=transfers:0x...&subscribe=validators
+			path:        "/stream",
+			fn:          srv.Stream,
+			middlewares: nil,
+		},
+	}
+	for _, api := range apis {
+		gr.Add(api.method, api.path, api.fn, api.middlewares...)
+	}
+}
+
+// Stream push realtime events to client until connection closed.
+// Txs are pushed once while pending in node mempool (status 2) and again when their block is imported.
+// Transfers are pushed only for blocks imported live, re-imported blocks are not replayed.
+func (s *Server) Stream(c echo.Context) error {
+	var topics []string
+	for _, param := range c.QueryParams()["subscribe"] {
+		for _, topic := range strings.Split(param, ",") {
+			topic = strings.TrimSpace(topic)
+			if topic == "" {
+				continue
+			}
+			if !isValidStreamTopic(topic) {
+				return Invalid.Build(c)
+			}
+			topics = append(topics, topic)
+		}
+	}
+	if len(topics) == 0 || len(topics) > streamMaxTopics {
+		return Invalid.Build(c)
+	}
+
+	sub := s.streamHub().subscribe(topics)
+	defer s.streamHub().unsubscribe(sub)
+
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	w.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return nil
+			}
+			w.Flush()
+		case e := <-sub.events:
+			data, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data); err != nil {
+				return nil
+			}
+			w.Flush()
+		}
+	}
+}
+
+func isValidStreamTopic(topic string) bool {
+	switch strings.SplitN(topic, ":", 2)[0] {
+	case types.StreamBlocks, types.StreamTxs, types.StreamTransfers, types.StreamValidators:
+		return true
+	}
+	return false
+}
+
+func (s *Server) streamHub() *streamHub {
+	s.streamOnce.Do(func() {
+		s.stream = newStreamHub(s.cacheClient, s.logger)
+		go s.stream.run(context.Background())
+	})
+	return s.stream
+}
+
+type streamSubscriber struct {
+	topics []string
+	events chan *types.StreamEvent
+}
+
+// streamHub keep single Redis subscription per API instance and fan out events to local clients
+type streamHub struct {
+	mu          sync.RWMutex
+	subscribers map[*streamSubscriber]struct{}
+
+	cacheClient cache.IStream
+	logger      *zap.Logger
+}
+
+func newStreamHub(cacheClient cache.IStream, logger *zap.Logger) *streamHub {
+	return &streamHub{
+		subscribers: make(map[*streamSubscriber]struct{}),
+		cacheClient: cacheClient,
+		logger:      logger.With(zap.String("component", "streamHub")),
+	}
+}
+
+func (h *streamHub) subscribe(topics []string) *streamSubscriber {
+	sub := &streamSubscriber{
+		topics: topics,
+		events: make(chan *types.StreamEvent, streamSubscriberBuffer),
+	}
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub
+}
+
+func (h *streamHub) unsubscribe(sub *streamSubscriber) {
+	h.mu.Lock()
+	delete(h.subscribers, sub)
+	h.mu.Unlock()
+}
+
+// run resubscribe to Redis whenever subscription is dropped
+func (h *streamHub) run(ctx context.Context) {
+	for ctx.Err() == nil {
+		events, err := h.cacheClient.SubscribeStreamEvents(ctx)
+		if err != nil {
+			h.logger.Warn("cannot subscribe stream events, retrying...", zap.Error(err))
+			time.Sleep(time.Second)
+			continue
+		}
+		for e := range events {
+			h.dispatch(e)
+		}
+	}
+}
+
+func (h *streamHub) dispatch(e *types.StreamEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for sub := range h.subscribers {
+		for _, topic := range sub.topics {
+			if !e.Match(topic) {
+				continue
+			}
+			select {
+			case sub.events <- e:
+			default:
+				// slow client, drop event instead of blocking others
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,23 @@
+// Package api
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStream_Topics(t *testing.T) {
+	s := newFakeServer()
+	tooMany := strings.TrimSuffix(strings.Repeat("txs:0xa,", streamMaxTopics+1), ",")
+	for _, target := range []string{
+		"/stream",
+		"/stream?subscribe=accounts",
+		"/stream?subscribe=" + tooMany,
+	} {
+		_, rec := callHandler(t, s.Stream, newRequest(http.MethodGet, target, nil, nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+	}
+}
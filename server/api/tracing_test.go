@@ -0,0 +1,47 @@
+// Package api
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/api/trace/tracetest"
+	"go.opentelemetry.io/otel/semconv"
+
+	"github.com/kardiachain/kardia-explorer-backend/tracing"
+)
+
+func TestTraceRequest(t *testing.T) {
+	recorder := new(tracetest.StandardSpanRecorder)
+	global.SetTraceProvider(tracetest.NewProvider(tracetest.WithSpanRecorder(recorder)))
+	defer global.SetTraceProvider(trace.NoopProvider{})
+
+	e := echo.New()
+	e.Use(traceRequest())
+	e.GET("/api/v1/blocks/:block", func(c echo.Context) error {
+		_, span := tracing.Start(c.Request().Context(), "mongo.find_one")
+		span.End()
+		return Invalid.Build(c)
+	})
+
+	req := httptest.NewRequest(echo.GET, "/api/v1/blocks/100", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	spans := recorder.Completed()
+	require.Len(t, spans, 2)
+	child, server := spans[0], spans[1]
+	assert.Equal(t, "GET /api/v1/blocks/:block", server.Name())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", server.ParentSpanID().String())
+	assert.Equal(t, server.SpanContext().SpanID, child.ParentSpanID())
+	assert.Equal(t, server.SpanContext().TraceID, child.SpanContext().TraceID)
+	assert.EqualValues(t, http.StatusBadRequest, server.Attributes()[semconv.HTTPStatusCodeKey].AsInt64())
+}
@@ -16,25 +16,42 @@ import (
 type ITx interface {
 	Txs(c echo.Context) error
 	TxByHash(c echo.Context) error
+	Search(c echo.Context) error
 	SearchAddressByName(c echo.Context) error
 	GetInternalTxs(c echo.Context) error
 	UpdateInternalTxs(c echo.Context) error
 }
 
 func (s *Server) Txs(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	pagination, page, limit := getPagingOption(c)
 	var (
-		err error
-		txs []*types.Transaction
+		err   error
+		txs   []*types.Transaction
+		total uint64
 	)
 
-	txs, err = s.cacheClient.LatestTransactions(ctx, pagination)
-	if err != nil || txs == nil || len(txs) < limit {
-		txs, err = s.dbClient.LatestTxs(ctx, pagination)
+	if filter := labelFilter(c); filter != nil {
+		// txs from or to labeled addresses
+		addresses, err := s.labeledAddresses(ctx, filter)
 		if err != nil {
 			return Invalid.Build(c)
 		}
+		if len(addresses) > 0 {
+			txs, total, err = s.dbClient.TxsByAddresses(ctx, addresses, pagination)
+			if err != nil {
+				return Invalid.Build(c)
+			}
+		}
+	} else {
+		txs, err = s.cacheClient.LatestTransactions(ctx, pagination)
+		if err != nil || txs == nil || len(txs) < limit {
+			txs, err = s.dbClient.LatestTxs(ctx, pagination)
+			if err != nil {
+				return Invalid.Build(c)
+			}
+		}
+		total = s.cacheClient.TotalTxs(ctx)
 	}
 
 	smcAddress := s.getValidatorsAddressAndRole(ctx)
@@ -68,18 +85,19 @@ func (s *Server) Txs(c echo.Context) error {
 		}
 		result = append(result, t)
 	}
+	s.labelTxs(ctx, result)
 
 	return OK.SetData(PagingResponse{
 		Page:  page,
 		Limit: limit,
-		Total: s.cacheClient.TotalTxs(ctx),
+		Total: total,
 		Data:  result,
 	}).Build(c)
 }
 
 func (s *Server) TxByHash(c echo.Context) error {
 	lgr := s.logger
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	txHash := c.Param("txHash")
 	if txHash == "" {
 		return Invalid.Build(c)
@@ -168,6 +186,9 @@ func (s *Server) TxByHash(c echo.Context) error {
 	if addrInfo != nil {
 		result.ToName = addrInfo.Name
 	}
+	labels := s.addressLabels(ctx, tx.From, tx.To)
+	result.FromLabels, result.ToLabels = labels[tx.From], labels[tx.To]
+	s.labelInternalTxs(ctx, result.Logs)
 
 	//smcAddress := s.getValidatorsAddressAndRole(ctx)
 	//if smcAddress[result.To] != nil {
@@ -187,51 +208,41 @@ func (s *Server) TxByHash(c echo.Context) error {
 }
 
 func (s *Server) buildFunctionCall(ctx context.Context, tx *types.Transaction) *types.FunctionCall {
-
-	var (
-		functionCall *types.FunctionCall
-	)
-
+	if tx.To == "" {
+		return nil
+	}
 	contractInfo, _, err := s.dbClient.Contract(ctx, tx.To)
 	if err != nil {
-		return nil
+		contractInfo = nil
 	}
+	return s.decodeFunctionCall(ctx, tx, contractInfo)
+}
 
+// decodeFunctionCall decode input of tx to contractInfo, nil contractInfo when the callee is not a known contract
+func (s *Server) decodeFunctionCall(ctx context.Context, tx *types.Transaction, contractInfo *types.Contract) *types.FunctionCall {
 	var contractABI *abi.ABI
-	if contractInfo.ABI != "" {
-		contractABI, err = s.decodeSMCABIFromBase64(ctx, contractInfo.ABI, contractInfo.Address)
+	if contractInfo != nil {
+		var err error
+		contractABI, err = s.contractABI(ctx, contractInfo)
 		if err != nil {
 			return nil
 		}
-	} else {
-		if contractInfo.Type == cfg.SMCTypeKRC20 {
-			contractABI, err = kClient.KRC20ABI()
-			if err != nil {
-				return nil
-			}
-		}
-
-		if contractInfo.Type == cfg.SMCTypeKRC721 {
-			contractABI, err = kClient.KRC721ABI()
-			if err != nil {
-				return nil
-			}
-		}
 	}
 
 	if contractABI == nil {
 		decoded, err := s.kaiClient.DecodeInputData(tx.To, tx.InputData)
 		if err == nil {
-			functionCall = decoded
+			return decoded
 		}
 	} else {
 		decoded, err := s.kaiClient.DecodeInputWithABI(tx.To, tx.InputData, contractABI)
 		if err == nil {
-			functionCall = decoded
+			return decoded
 		}
 	}
 
-	return functionCall
+	// contract without ABI or unknown method, look up signatures registry
+	return s.decodeInputWithSignatures(ctx, tx.To, tx.InputData)
 }
 
 func (s *Server) buildInternalTransaction(ctx context.Context, l *types.Log) *InternalTransaction {
@@ -242,30 +253,18 @@ func (s *Server) buildInternalTransaction(ctx context.Context, l *types.Log) *In
 		return nil
 	}
 
-	var contractABI *abi.ABI
-	if contractInfo.ABI != "" {
-		contractABI, err = s.decodeSMCABIFromBase64(ctx, contractInfo.ABI, contractInfo.Address)
-		if err != nil {
-			return nil
-		}
-	} else {
-		if contractInfo.Type == cfg.SMCTypeKRC20 {
-			contractABI, err = kClient.KRC20ABI()
-			if err != nil {
-				return nil
-			}
-		}
-
-		if contractInfo.Type == cfg.SMCTypeKRC721 {
-			contractABI, err = kClient.KRC721ABI()
-			if err != nil {
-				return nil
-			}
-		}
+	contractABI, err := s.contractABI(ctx, contractInfo)
+	if err != nil {
+		return nil
 	}
 	var unpackedLog *types.Log
-	unpackedLog, err = s.kaiClient.UnpackLog(l, contractABI)
-	if err != nil {
+	if contractABI != nil {
+		unpackedLog, _ = s.kaiClient.UnpackLog(l, contractABI)
+	}
+	if unpackedLog == nil {
+		unpackedLog = s.unpackLogWithSignatures(ctx, l)
+	}
+	if unpackedLog == nil {
 		return nil
 	}
 
@@ -296,6 +295,26 @@ func (s *Server) buildInternalTransaction(ctx context.Context, l *types.Log) *In
 	return internalTx
 }
 
+// contractABI return ABI used to decode calls and logs of the contract, nil when unknown.
+// Proxies are decoded with the ABI of their implementation.
+func (s *Server) contractABI(ctx context.Context, contractInfo *types.Contract) (*abi.ABI, error) {
+	if contractInfo.Implementation != "" {
+		if proxyABI, err := s.getSMCAbi(ctx, &types.Log{Address: contractInfo.Address}); err == nil {
+			return proxyABI, nil
+		}
+	}
+	if contractInfo.ABI != "" {
+		return s.decodeSMCABIFromBase64(ctx, contractInfo.ABI, contractInfo.Address)
+	}
+	switch contractInfo.Type {
+	case cfg.SMCTypeKRC20:
+		return kClient.KRC20ABI()
+	case cfg.SMCTypeKRC721:
+		return kClient.KRC721ABI()
+	}
+	return nil, nil
+}
+
 func (s *Server) getAddressDetail(ctx context.Context, address string) (*types.Address, error) {
 	lgr := s.logger
 	addressDetail := &types.Address{Address: address}
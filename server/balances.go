@@ -0,0 +1,63 @@
+// Package server
+package server
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"gopkg.in/yaml.v2"
+
+	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+// recordBalanceEntries write the reward minted by blocks and fee and value movements of their txs to balance ledger.
+// Internal and reward movements are written by receipts service after tracing.
+func (s *infoServer) recordBalanceEntries(ctx context.Context, blocks ...*types.Block) error {
+	var entries []*types.BalanceEntry
+	for _, block := range blocks {
+		entries = append(entries, types.BalanceEntriesFromBlock(block, cfg.StakingContractAddr)...)
+	}
+	return s.dbClient.UpsertBalanceEntries(ctx, entries)
+}
+
+// SeedGenesisBalances write opening balances of the node config at genesisFile to balance ledger,
+// so balances of genesis accounts do not start from zero. Seeding again is harmless.
+func (s *infoServer) SeedGenesisBalances(ctx context.Context, genesisFile string) error {
+	data, err := ioutil.ReadFile(genesisFile)
+	if err != nil {
+		return err
+	}
+	genesis, err := parseGenesis(data)
+	if err != nil {
+		return err
+	}
+	validators, err := s.kaiClient.Validators(ctx)
+	if err != nil {
+		return err
+	}
+	validatorSMCs := make(map[string]string, len(validators))
+	for _, v := range validators {
+		validatorSMCs[common.HexToAddress(v.Address).Hex()] = common.HexToAddress(v.SmcAddress).Hex()
+	}
+	entries, err := types.GenesisBalanceEntries(genesis, validatorSMCs)
+	if err != nil {
+		return err
+	}
+	return s.dbClient.UpsertBalanceEntries(ctx, entries)
+}
+
+func parseGenesis(data []byte) (*types.Genesis, error) {
+	var config struct {
+		Genesis *types.Genesis `yaml:"Genesis"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	if config.Genesis == nil {
+		return nil, errors.New("missing genesis section")
+	}
+	return config.Genesis, nil
+}
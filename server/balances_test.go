@@ -0,0 +1,38 @@
+// Package server
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+func TestParseGenesis(t *testing.T) {
+	genesis, err := parseGenesis([]byte(`
+Genesis:
+  Timestamp: 1605528000
+  Accounts:
+    - Address: 0xc1fe56E3F58D3244F606306611a5d10c8333f1f6
+      Amount: 1000000000000000000000000000
+  Validators:
+    - Name: val1
+      Address: 0xc1fe56E3F58D3244F606306611a5d10c8333f1f6
+      SelfDelegate: 12500000000000000000000000
+      StartWithGenesis: true
+      Delegators:
+        - Address: 0x7cefC13B6E2aedEeDFB7Cb6c32457240746BAEe5
+          Amount: 1000
+`))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1605528000), genesis.Timestamp)
+	assert.Equal(t, []*types.GenesisAccount{{Address: "0xc1fe56E3F58D3244F606306611a5d10c8333f1f6", Amount: "1000000000000000000000000000"}}, genesis.Accounts)
+	require.Len(t, genesis.Validators, 1)
+	assert.Equal(t, "12500000000000000000000000", genesis.Validators[0].SelfDelegate)
+	assert.Equal(t, "1000", genesis.Validators[0].Delegators[0].Amount)
+
+	_, err = parseGenesis([]byte("MainChain:\n  Port: 3000\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,23 @@
+// Package server
+package server
+
+import (
+	"context"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+// updateGasOracle add block to gas history and refresh oracle in cache
+func (s *infoServer) updateGasOracle(ctx context.Context, block *types.Block) error {
+	if s.gasOracleBlocks <= 0 {
+		return nil
+	}
+	if err := s.cacheClient.PushBlockGasStats(ctx, types.NewBlockGasStats(block), int64(s.gasOracleBlocks)); err != nil {
+		return err
+	}
+	history, err := s.cacheClient.BlockGasHistory(ctx)
+	if err != nil {
+		return err
+	}
+	return s.cacheClient.UpdateGasOracle(ctx, types.NewGasOracle(history, s.minGasPrice))
+}
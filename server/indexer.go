@@ -0,0 +1,54 @@
+// Package server
+package server
+
+import (
+	"context"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+// ImportBlocks write a batch of blocks and their txs. Writes are idempotent,
+// so a batch interrupted by crash can be imported again safely.
+func (s *infoServer) ImportBlocks(ctx context.Context, blocks []*types.Block) error {
+	var (
+		txs           []*types.Transaction
+		receiptHashes []string
+	)
+	for _, b := range blocks {
+		b.Txs = s.mergeAdditionalInfoToTxs(ctx, b.Txs, b.Receipts)
+		txs = append(txs, b.Txs...)
+		for _, r := range b.Receipts {
+			receiptHashes = append(receiptHashes, r.TransactionHash)
+		}
+	}
+	if err := s.dbClient.UpsertBlocks(ctx, blocks); err != nil {
+		return err
+	}
+	if err := s.dbClient.ReplaceTxs(ctx, txs); err != nil {
+		return err
+	}
+	if err := s.recordBalanceEntries(ctx, blocks...); err != nil {
+		return err
+	}
+	s.markRollupsDirty(ctx, blocks)
+	// token transfers and holders are handled by receipts service
+	if len(receiptHashes) > 0 {
+		if err := s.cacheClient.PushReceipts(ctx, receiptHashes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Checkpoint return last height processed by job `name`, 0 if job never run
+func (s *infoServer) Checkpoint(ctx context.Context, name string) uint64 {
+	checkpoint, err := s.dbClient.Checkpoint(ctx, name)
+	if err != nil || checkpoint == nil {
+		return 0
+	}
+	return checkpoint.Height
+}
+
+func (s *infoServer) SaveCheckpoint(ctx context.Context, name string, height uint64) error {
+	return s.dbClient.UpsertCheckpoint(ctx, name, height)
+}
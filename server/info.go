@@ -5,17 +5,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"math/big"
-	"net"
-	"net/http"
 	"strings"
 	"time"
 
 	"github.com/panjf2000/ants/v2"
+	"go.opentelemetry.io/otel/label"
 	"go.uber.org/zap"
 
 	"github.com/kardiachain/go-kardia/lib/abi"
@@ -26,7 +23,9 @@ import (
 	"github.com/kardiachain/kardia-explorer-backend/db"
 	"github.com/kardiachain/kardia-explorer-backend/kardia"
 	"github.com/kardiachain/kardia-explorer-backend/metrics"
+	"github.com/kardiachain/kardia-explorer-backend/tracing"
 	"github.com/kardiachain/kardia-explorer-backend/types"
+	"github.com/kardiachain/kardia-explorer-backend/utils"
 )
 
 type InfoServer interface {
@@ -67,109 +66,15 @@ type infoServer struct {
 
 	HttpRequestSecret string
 	verifyBlockParam  *types.VerifyBlockParam
+	gasOracleBlocks   int
+	minGasPrice       uint64
+	marketCMCAPIKey   string
+	wkaiAddress       string
+	tokenMarkets      *tokenMarkets
 
 	logger *zap.Logger
 }
 
-func (s *infoServer) TokenInfo(ctx context.Context) (*types.TokenInfo, error) {
-	type CMQuote struct {
-		Price            float64 `json:"price"`
-		Volume24h        float64 `json:"volume_24h"`
-		PercentChange1h  float64 `json:"percent_change_1h"`
-		PercentChange24h float64 `json:"percent_change_24h"`
-		PercentChange7d  float64 `json:"percent_change_7d"`
-		MarketCap        float64 `json:"market_cap"`
-		LastUpdated      string  `json:"last_updated"`
-	}
-	type CMTokenInfo struct {
-		ID                int                `json:"id"`
-		Name              string             `json:"name"`
-		Symbol            string             `json:"symbol"`
-		Slug              string             `json:"slug"`
-		NumMarketPairs    int                `json:"num_market_pairs"`
-		DateAdded         string             `json:"date_added"`
-		Tags              []string           `json:"tags"`
-		MaxSupply         int64              `json:"max_supply"`
-		CirculatingSupply int64              `json:"circulating_supply"`
-		TotalSupply       int64              `json:"total_supply"`
-		IsActive          int                `json:"is_active"`
-		Platform          interface{}        `json:"platform"`
-		CmcRank           int                `json:"cmc_rank"`
-		IsFiat            int                `json:"is_fiat"`
-		LastUpdated       string             `json:"last_updated"`
-		Quote             map[string]CMQuote `json:"quote"`
-	}
-
-	type CMResponseStatus struct {
-		Timestamp    string `json:"timestamp"`
-		ErrorCode    int    `json:"error_code"`
-		ErrorMessage string `json:"error_message"`
-		Elapsed      int    `json:"elapsed"`
-		CreditCount  int    `json:"credit_count"`
-		Notice       string `json:"notice"`
-	}
-
-	type CMResponse struct {
-		Status CMResponseStatus       `json:"status"`
-		Data   map[string]CMTokenInfo `json:"data"`
-	}
-
-	coinMarketUrl := "https://pro-api.coinmarketcap.com/v1/cryptocurrency/quotes/latest?id=5453"
-	var netTransport = &http.Transport{
-		Dial: (&net.Dialer{
-			Timeout: 5 * time.Second,
-		}).Dial,
-		TLSHandshakeTimeout: 5 * time.Second,
-	}
-	var netClient = &http.Client{
-		Timeout:   time.Second * 10,
-		Transport: netTransport,
-	}
-
-	req, _ := http.NewRequest(http.MethodGet, coinMarketUrl, nil)
-	req.Header.Set("X-CMC_PRO_API_KEY", "a9aaf65c-1d6f-4daf-8e2e-df30bd405b66")
-
-	response, _ := netClient.Do(req)
-	defer response.Body.Close()
-	body, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	var cmResponse CMResponse
-
-	if err := json.Unmarshal(body, &cmResponse); err != nil {
-		return nil, err
-	}
-
-	if cmResponse.Status.ErrorCode != 0 {
-		return nil, errors.New("api failed")
-	}
-	cmData := cmResponse.Data["5453"]
-	cmQuote := cmData.Quote["USD"]
-
-	// Cast to internal
-	tokenInfo := &types.TokenInfo{
-		Name:                     cmData.Name,
-		Symbol:                   cmData.Symbol,
-		Decimal:                  18,
-		TotalSupply:              cmData.TotalSupply,
-		ERC20CirculatingSupply:   cmData.CirculatingSupply,
-		MainnetCirculatingSupply: 0,
-		Price:                    cmQuote.Price,
-		Volume24h:                cmQuote.Volume24h,
-		Change1h:                 cmQuote.PercentChange1h,
-		Change24h:                cmQuote.PercentChange24h,
-		Change7d:                 cmQuote.PercentChange7d,
-		MarketCap:                cmQuote.MarketCap,
-	}
-	if err := s.cacheClient.UpdateTokenInfo(ctx, tokenInfo); err != nil {
-		return nil, err
-	}
-
-	return tokenInfo, nil
-}
-
 func (s *infoServer) GetCurrentStats(ctx context.Context) uint64 {
 	stats := s.dbClient.Stats(ctx)
 	s.logger.Info("Current stats of network", zap.Uint64("UpdatedAtBlock", stats.UpdatedAtBlock),
@@ -295,7 +200,9 @@ func (s *infoServer) BlockByHeightFromRPC(ctx context.Context, blockHeight uint6
 }
 
 // ImportBlock handle workflow of import block into system
-func (s *infoServer) ImportBlock(ctx context.Context, block *types.Block, writeToCache bool) error {
+func (s *infoServer) ImportBlock(ctx context.Context, block *types.Block, writeToCache bool) (err error) {
+	ctx, span := tracing.Start(ctx, "ImportBlock", label.Uint64("block.height", block.Height))
+	defer func() { tracing.End(ctx, span, err) }()
 	lgr := s.logger.With(zap.String("method", "ImportBlock"))
 	lgr.Info("Importing block:", zap.Uint64("Height", block.Height),
 		zap.Int("Txs length", len(block.Txs)), zap.Int("Receipts length", len(block.Receipts)))
@@ -331,10 +238,16 @@ func (s *infoServer) ImportBlock(ctx context.Context, block *types.Block, writeT
 	endTime := time.Since(startTime)
 	s.metrics.RecordInsertBlockTime(endTime)
 	s.logger.Info("Total time for import block", zap.Duration("TimeConsumed", endTime), zap.String("Avg", s.metrics.GetInsertBlockTime()))
+	// only push blocks imported by live flow
+	if writeToCache {
+		s.publishStreamEvents(ctx, blockStreamEvent(block))
+	}
 	return nil
 }
 
-func (s *infoServer) ProcessTxs(ctx context.Context, block *types.Block, writeToCache bool) error {
+func (s *infoServer) ProcessTxs(ctx context.Context, block *types.Block, writeToCache bool) (err error) {
+	ctx, span := tracing.Start(ctx, "ProcessTxs", label.Uint64("block.height", block.Height), label.Int("block.txs", len(block.Txs)))
+	defer func() { tracing.End(ctx, span, err) }()
 	lgr := s.logger
 	startTime := time.Now()
 	// merge receipts into corresponding transactions
@@ -346,6 +259,9 @@ func (s *infoServer) ProcessTxs(ctx context.Context, block *types.Block, writeTo
 	if err := s.dbClient.InsertTxs(ctx, block.Txs); err != nil {
 		return err
 	}
+	if err := s.recordBalanceEntries(ctx, block); err != nil {
+		return err
+	}
 	endTime := time.Since(startTime)
 	s.metrics.RecordInsertTxsTime(endTime)
 	s.logger.Info("Total time for import tx", zap.Duration("TimeConsumed", endTime), zap.String("Avg", s.metrics.GetInsertTxsTime()))
@@ -357,6 +273,14 @@ func (s *infoServer) ProcessTxs(ctx context.Context, block *types.Block, writeTo
 		if err := s.cacheClient.InsertTxsOfBlock(ctx, block); err != nil {
 			return err
 		}
+		s.publishStreamEvents(ctx, txsStreamEvents(block.Txs)...)
+		s.publishStreamEvents(ctx, transfersStreamEvents(block)...)
+		if err := s.updateGasOracle(ctx, block); err != nil {
+			lgr.Warn("cannot update gas oracle", zap.Error(err))
+		}
+	} else {
+		// block filled by backfill may be in a bucket already rolled up
+		s.markRollupsDirty(ctx, []*types.Block{block})
 	}
 	var receiptHashes []string
 	for _, r := range block.Receipts {
@@ -373,7 +297,9 @@ func (s *infoServer) ProcessTxs(ctx context.Context, block *types.Block, writeTo
 	return nil
 }
 
-func (s *infoServer) ProcessLogsOfTxs(ctx context.Context, txs []*types.Transaction, blockTime time.Time) error {
+func (s *infoServer) ProcessLogsOfTxs(ctx context.Context, txs []*types.Transaction, blockTime time.Time) (err error) {
+	ctx, span := tracing.Start(ctx, "ProcessLogsOfTxs", label.Int("block.txs", len(txs)))
+	defer func() { tracing.End(ctx, span, err) }()
 	lgr := s.logger.With(zap.String("method", "processLogsOfTxs"))
 	poolSize := 4
 	p, err := ants.NewPoolWithFunc(poolSize, func(i interface{}) {
@@ -570,19 +496,7 @@ func (s *infoServer) PopUnverifiedBlockHeight(ctx context.Context) (uint64, erro
 //	return nil
 //}
 
-func (s *infoServer) blockVerifier(db, network *types.Block) bool {
-	if s.verifyBlockParam.VerifyTxCount {
-		if db.NumTxs != network.NumTxs {
-			return false
-		}
-	}
-	if s.verifyBlockParam.VerifyBlockHash {
-		return true
-	}
-	return true
-}
-
-// VerifyBlock called by verifier. It returns `true` if the block is upserted; otherwise it return `false`
+// VerifyBlock called by verifier. It returns `true` if the block is repaired; otherwise it return `false`
 func (s *infoServer) VerifyBlock(ctx context.Context, blockHeight uint64, networkBlock *types.Block) (bool, error) {
 	isBlockImported, err := s.dbClient.IsBlockExist(ctx, blockHeight)
 	if err != nil || !isBlockImported {
@@ -603,30 +517,33 @@ func (s *infoServer) VerifyBlock(ctx context.Context, blockHeight uint64, networ
 		s.logger.Warn("Cannot get block by height from database", zap.Uint64("height", blockHeight))
 		return false, err
 	}
-	_, total, err := s.dbClient.TxsByBlockHeight(ctx, blockHeight, nil)
+	dbTxs, total, err := s.dbClient.TxsByBlockHeight(ctx, blockHeight, nil)
 	if err != nil {
 		s.logger.Warn("Cannot get total transactions in block by height from database", zap.Uint64("height", blockHeight))
 		return false, err
 	}
 	dbBlock.NumTxs = total
+	dbBlock.Txs = dbTxs
 
-	if !s.blockVerifier(dbBlock, networkBlock) {
-		s.logger.Warn("Block in database is corrupted, upserting...", zap.Uint64("db numTxs", dbBlock.NumTxs), zap.Uint64("network numTxs", networkBlock.NumTxs), zap.Error(err))
-		// Minus network block reward and total txs before re-importing this block
-		totalTxs := s.cacheClient.TotalTxs(ctx)
-		totalTxs -= networkBlock.NumTxs
-		//_ = s.cacheClient.SetTotalTxs(ctx, totalTxs)
-		// Force replace dbBlock with new information from network block
-		startTime := time.Now()
-		if err := s.UpsertBlock(ctx, networkBlock); err != nil {
-			s.logger.Warn("Cannot upsert block", zap.Uint64("height", blockHeight), zap.Error(err))
-			return false, err
-		}
-		endTime := time.Since(startTime)
-		s.metrics.RecordUpsertBlockTime(endTime)
-		return true, nil
+	mismatches := s.blockVerifier(dbBlock, networkBlock)
+	if len(mismatches) == 0 {
+		return false, nil
+	}
+	s.metrics.RecordInvalidBlock()
+	for _, m := range mismatches {
+		s.logger.Warn("Block in database is corrupted", zap.Uint64("height", blockHeight), zap.String("check", m.Check),
+			zap.String("target", m.Target), zap.String("txHash", m.TxHash), zap.String("expected", m.Expected), zap.String("actual", m.Actual))
+	}
+	// Only repair collections affected by failed checks
+	startTime := time.Now()
+	repaired, err := s.repairBlock(ctx, dbBlock, networkBlock, mismatches)
+	s.auditVerifyResult(ctx, networkBlock, mismatches, repaired, err)
+	if err != nil {
+		s.logger.Warn("Cannot repair block", zap.Uint64("height", blockHeight), zap.Error(err))
+		return false, err
 	}
-	return false, nil
+	s.metrics.RecordUpsertBlockTime(time.Since(startTime))
+	return true, nil
 }
 
 func (s *infoServer) getAddressBalances(ctx context.Context, addrs map[string]*types.Address) []*types.Address {
@@ -877,7 +794,7 @@ func (s *infoServer) storeEvents(ctx context.Context, logs []types.Log, blockTim
 	//		s.logger.Warn("Cannot set total accounts to cache", zap.Error(err))
 	//	}
 	//}
-	//return s.dbClient.InsertEvents(logs)
+	//return s.dbClient.InsertEvents(ctx, logs)
 }
 
 func (s *infoServer) getSMCAbi(ctx context.Context, log *types.Log) (*abi.ABI, error) {
@@ -888,6 +805,14 @@ func (s *infoServer) getSMCAbi(ctx context.Context, log *types.Log) (*abi.ABI, e
 			s.logger.Debug("Cannot get smc info from db", zap.Error(err), zap.String("smcAddr", log.Address))
 			return nil, err
 		}
+		if smc.Implementation != "" {
+			// decode calls and events of proxy with its implementation ABI
+			proxyABIStr, err := s.proxyABI(ctx, smc)
+			if err == nil {
+				return s.decodeSMCABIFromBase64(ctx, proxyABIStr, log.Address)
+			}
+			s.logger.Debug("Cannot get implementation abi of proxy", zap.Error(err), zap.String("smcAddr", log.Address))
+		}
 		if smc.Type != "" {
 			err = s.cacheClient.UpdateSMCAbi(ctx, log.Address, cfg.SMCTypePrefix+smc.Type)
 			if err != nil {
@@ -920,6 +845,25 @@ func (s *infoServer) getSMCAbi(ctx context.Context, log *types.Log) (*abi.ABI, e
 	return s.decodeSMCABIFromBase64(ctx, smcABIStr, log.Address)
 }
 
+// proxyABI return base64 ABI of a proxy merged with the ABI of its implementation
+func (s *infoServer) proxyABI(ctx context.Context, smc *types.Contract) (string, error) {
+	impl, _, err := s.dbClient.Contract(ctx, smc.Implementation)
+	if err != nil {
+		return "", err
+	}
+	implABI := impl.ABI
+	if implABI == "" && impl.Type != "" {
+		implABI, err = s.dbClient.SMCABIByType(ctx, impl.Type)
+		if err != nil {
+			return "", err
+		}
+	}
+	if implABI == "" {
+		return "", errors.New("implementation abi not found")
+	}
+	return utils.MergeABI(smc.ABI, implABI)
+}
+
 func (s *infoServer) decodeSMCABIFromBase64(ctx context.Context, abiStr, smcAddr string) (*abi.ABI, error) {
 	abiData, err := base64.StdEncoding.DecodeString(abiStr)
 	if err != nil {
@@ -0,0 +1,172 @@
+// Package server
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/external"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+var ErrUnknownPriceSource = errors.New("unknown token price source")
+
+// tokenMarkets keep a market per token, so rate limits of providers are remembered between refreshes
+type tokenMarkets struct {
+	mu      sync.Mutex
+	markets map[string]*external.Market
+}
+
+// SaveMarketQuote refresh token info in cache and record KAI price of the current hour
+func (s *infoServer) SaveMarketQuote(ctx context.Context, quote *external.Quote) error {
+	if err := s.cacheClient.UpdateTokenInfo(ctx, quote.TokenInfo()); err != nil {
+		return err
+	}
+	return s.dbClient.UpsertTokenPrice(ctx, &types.TokenPrice{
+		Token:     types.PriceTokenKAI,
+		Time:      quote.UpdatedAt.UTC().Truncate(time.Hour),
+		Price:     quote.Price,
+		Volume24h: quote.Volume24h,
+		MarketCap: quote.MarketCap,
+		Source:    quote.Source,
+		UpdatedAt: time.Now(),
+	})
+}
+
+// RefreshTokenPrices record price of the current hour for every KRC20 token which has a price source.
+// Market sources are refreshed first, so DEX pairs quoted against them use the latest prices.
+func (s *infoServer) RefreshTokenPrices(ctx context.Context) error {
+	sources, err := s.dbClient.TokenPriceSources(ctx)
+	if err != nil {
+		return err
+	}
+	var dexSources []*types.TokenPriceSource
+	for _, source := range sources {
+		if source.Type == types.PriceSourceDEX {
+			dexSources = append(dexSources, source)
+			continue
+		}
+		if err := s.refreshTokenPrice(ctx, source); err != nil {
+			s.logger.Warn("Cannot refresh token price", zap.String("token", source.Token), zap.Error(err))
+		}
+	}
+	for _, source := range dexSources {
+		if err := s.refreshTokenPrice(ctx, source); err != nil {
+			s.logger.Warn("Cannot refresh token price", zap.String("token", source.Token), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (s *infoServer) refreshTokenPrice(ctx context.Context, source *types.TokenPriceSource) error {
+	var (
+		price *types.TokenPrice
+		err   error
+	)
+	switch source.Type {
+	case types.PriceSourceMarket:
+		price, err = s.marketTokenPrice(ctx, source)
+	case types.PriceSourceDEX:
+		price, err = s.dexTokenPrice(ctx, source)
+	default:
+		err = ErrUnknownPriceSource
+	}
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	price.Token = source.Token
+	price.Time = now.UTC().Truncate(time.Hour)
+	price.UpdatedAt = now
+	return s.dbClient.UpsertTokenPrice(ctx, price)
+}
+
+func (s *infoServer) marketTokenPrice(ctx context.Context, source *types.TokenPriceSource) (*types.TokenPrice, error) {
+	s.tokenMarkets.mu.Lock()
+	market, ok := s.tokenMarkets.markets[source.Token]
+	if !ok {
+		var providers []external.Provider
+		if s.marketCMCAPIKey != "" && source.CoinMarketCapID != 0 {
+			providers = append(providers, external.NewCoinMarketCap(external.CoinMarketCapConfig{
+				APIKey: s.marketCMCAPIKey,
+				ID:     source.CoinMarketCapID,
+			}))
+		}
+		if source.CoinGeckoID != "" {
+			providers = append(providers, external.NewCoinGecko(external.CoinGeckoConfig{ID: source.CoinGeckoID}))
+		}
+		market = external.NewMarket(external.MarketConfig{
+			Providers: providers,
+			Logger:    s.logger.With(zap.String("token", source.Token)),
+		})
+		s.tokenMarkets.markets[source.Token] = market
+	}
+	s.tokenMarkets.mu.Unlock()
+
+	quote, err := market.Quote(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &types.TokenPrice{
+		Price:     quote.Price,
+		Volume24h: quote.Volume24h,
+		MarketCap: quote.MarketCap,
+		Source:    quote.Source,
+	}, nil
+}
+
+// dexTokenPrice price token by reserves of its pair and latest price of the other token, WKAI use KAI price
+func (s *infoServer) dexTokenPrice(ctx context.Context, source *types.TokenPriceSource) (*types.TokenPrice, error) {
+	pair, err := s.kaiClient.PairReserves(ctx, source.PairAddress)
+	if err != nil {
+		return nil, err
+	}
+	reserve, quoteToken, quoteReserve := pair.Reserve0, pair.Token1, pair.Reserve1
+	switch source.Token {
+	case pair.Token0:
+	case pair.Token1:
+		reserve, quoteToken, quoteReserve = pair.Reserve1, pair.Token0, pair.Reserve0
+	default:
+		return nil, fmt.Errorf("token %s is not in pair %s", source.Token, source.PairAddress)
+	}
+
+	quotePriceToken := quoteToken
+	if s.wkaiAddress != "" && quoteToken == common.HexToAddress(s.wkaiAddress).Hex() {
+		quotePriceToken = types.PriceTokenKAI
+	}
+	quotePrices, err := s.dbClient.LatestTokenPrices(ctx, []string{quotePriceToken}, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	quotePrice, ok := quotePrices[quotePriceToken]
+	if !ok {
+		return nil, fmt.Errorf("quote token %s has no price", quoteToken)
+	}
+
+	decimals, err := s.tokenDecimals(ctx, source.Token)
+	if err != nil {
+		return nil, err
+	}
+	quoteDecimals, err := s.tokenDecimals(ctx, quoteToken)
+	if err != nil {
+		return nil, err
+	}
+	return &types.TokenPrice{
+		Price:  types.PairPrice(reserve, quoteReserve, decimals, quoteDecimals, quotePrice.Price),
+		Source: types.PriceSourceDEX,
+	}, nil
+}
+
+func (s *infoServer) tokenDecimals(ctx context.Context, token string) (int64, error) {
+	contract, _, err := s.dbClient.Contract(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+	return int64(contract.Decimals), nil
+}
@@ -87,7 +87,10 @@ func (s *Server) insertKRC20Transfer(ctx context.Context, log *kClient.Log) erro
 		Time:            log.Time,
 	}
 	//lgr.Info("New KRC20 transfer", zap.Any("TX", internalTx))
-	return s.db.InsertInternalTxs(ctx, internalTx)
+	if err := s.db.InsertInternalTxs(ctx, internalTx); err != nil {
+		return err
+	}
+	return nil
 }
 
 func (s *Server) insertKRC721Transfer(ctx context.Context, log *kClient.Log) error {
@@ -123,7 +126,10 @@ func (s *Server) insertKRC721Transfer(ctx context.Context, log *kClient.Log) err
 		Time:            log.Time,
 	}
 	//lgr.Info("New KRC721 transfer", zap.Any("TX", internalTx))
-	return s.db.InsertInternalTxs(ctx, internalTx)
+	if err := s.db.InsertInternalTxs(ctx, internalTx); err != nil {
+		return err
+	}
+	return nil
 }
 
 func (s *Server) upsertKRC20Holder(ctx context.Context, log *kClient.Log) error {
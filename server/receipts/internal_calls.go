@@ -0,0 +1,77 @@
+// Package receipts
+package receipts
+
+import (
+	"context"
+
+	kClient "github.com/kardiachain/go-kaiclient/kardia"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+	"github.com/kardiachain/kardia-explorer-backend/utils"
+)
+
+// processInternalCalls trace the tx and store calls made by contracts which move KAI,
+// create contracts or self destruct, with their balance ledger entries. Plain transfers to
+// accounts are not traced.
+func (s *Server) processInternalCalls(ctx context.Context, r *kClient.Receipt) error {
+	lgr := s.logger.With(zap.String("method", "processInternalCalls"), zap.String("txHash", r.TransactionHash))
+	tx, err := s.node.GetTransaction(ctx, r.TransactionHash)
+	if err != nil {
+		return err
+	}
+	if !s.isContractCall(ctx, tx) {
+		return nil
+	}
+	frame, err := s.kaiClient.TraceCalls(ctx, tx.Hash)
+	if err != nil {
+		return err
+	}
+	calls := frame.InternalCalls()
+	for _, call := range calls {
+		call.TxHash = tx.Hash
+		call.BlockHeight = tx.BlockNumber
+		call.Time = tx.Time
+		call.From = common.HexToAddress(call.From).Hex()
+		call.To = common.HexToAddress(call.To).Hex()
+	}
+	if len(calls) > 0 {
+		lgr.Debug("Store internal calls", zap.Int("calls", len(calls)))
+	}
+	if err := s.db.UpsertInternalCalls(ctx, calls); err != nil {
+		return err
+	}
+	return s.db.UpsertBalanceEntries(ctx, types.BalanceEntriesFromInternalCalls(calls, s.rewardSenders(ctx, calls)))
+}
+
+// rewardSenders return staking and validator contracts among senders of calls
+func (s *Server) rewardSenders(ctx context.Context, calls []*types.InternalCall) map[string]bool {
+	senders := make(map[string]bool)
+	for _, call := range calls {
+		if _, ok := senders[call.From]; ok {
+			continue
+		}
+		if call.From == common.HexToAddress(cfg.StakingContractAddr).Hex() {
+			senders[call.From] = true
+			continue
+		}
+		smc, _, err := s.db.Contract(ctx, call.From)
+		senders[call.From] = err == nil && smc != nil && smc.Type == cfg.SMCTypeValidator
+	}
+	return senders
+}
+
+// isContractCall report whether tx create a contract or call one, which may make internal calls
+func (s *Server) isContractCall(ctx context.Context, tx *kClient.Transaction) bool {
+	if tx.To == "" || (tx.ContractAddress != "" && !utils.IsNilAddress(tx.ContractAddress)) {
+		return true
+	}
+	if len(tx.InputData) > 2 {
+		return true
+	}
+	// value sent to a contract run its receive or fallback function
+	code, err := s.node.Code(ctx, tx.To)
+	return err == nil && len(code) > 0
+}
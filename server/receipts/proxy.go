@@ -0,0 +1,154 @@
+// Package receipts
+package receipts
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	kClient "github.com/kardiachain/go-kaiclient/kardia"
+	"github.com/kardiachain/go-kardia/lib/common"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/kardia"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+// nodeProxyReader adapt kClient.Node to kardia.ProxyReader
+type nodeProxyReader struct {
+	node kClient.Node
+}
+
+func (r *nodeProxyReader) StorageAt(ctx context.Context, address, slot string) ([]byte, error) {
+	return r.node.StorageAt(ctx, address, slot)
+}
+
+func (r *nodeProxyReader) Call(ctx context.Context, address string, data []byte) ([]byte, error) {
+	return r.node.KardiaCall(ctx, kClient.SMCCallArgs{
+		From:     address,
+		To:       &address,
+		Gas:      100000000,
+		GasPrice: big.NewInt(0),
+		Value:    big.NewInt(0),
+		Data:     common.Bytes(data).String(),
+	})
+}
+
+// processUpgradeLog follow implementation changes announced by Upgraded and BeaconUpgraded events.
+// Upgraded is emitted by proxies and by beacons, proxies reading an upgraded beacon are updated too.
+func (s *Server) processUpgradeLog(ctx context.Context, l *kClient.Log) error {
+	lgr := s.logger.With(zap.String("method", "processUpgradeLog"), zap.String("address", l.Address))
+	if len(l.Topics) < 2 {
+		return nil
+	}
+	tx, err := s.node.GetTransaction(ctx, l.TxHash)
+	if err != nil {
+		lgr.Error("cannot get tx", zap.Error(err))
+		return nil
+	}
+	l.Time = tx.Time
+	address := common.HexToAddress(l.Address).Hex()
+
+	if l.Topics[0] == cfg.ProxyUpgradedTopic {
+		proxies, err := s.db.ProxiesOf(ctx, address)
+		if err != nil {
+			return err
+		}
+		impl := common.HexToAddress(l.Topics[1]).Hex()
+		for _, p := range proxies {
+			if p.Beacon != address {
+				continue
+			}
+			info := &types.ProxyInfo{Type: types.ProxyTypeBeacon, Implementation: impl, Beacon: address}
+			if err := s.updateProxy(ctx, p, info, l); err != nil {
+				lgr.Error("cannot update beacon proxy", zap.Error(err), zap.String("proxy", p.Address))
+			}
+		}
+	}
+
+	// read slots to confirm emitter is a proxy, a beacon has none
+	detected, err := kardia.DetectProxy(ctx, &nodeProxyReader{node: s.node}, address)
+	if err != nil {
+		if err != kardia.ErrNotProxy {
+			lgr.Error("cannot detect proxy", zap.Error(err))
+		}
+		return nil
+	}
+	info := upgradedProxyInfo(detected, l)
+	if info.Beacon != detected.Beacon {
+		// proxy moved to another beacon since this event, implementation is the one of the event beacon
+		if info.Implementation, err = kardia.BeaconImplementation(ctx, &nodeProxyReader{node: s.node}, info.Beacon); err != nil {
+			lgr.Error("cannot get beacon implementation", zap.Error(err), zap.String("beacon", info.Beacon))
+			return nil
+		}
+	}
+	smc, _, err := s.db.Contract(ctx, address)
+	if err != nil {
+		smc = &types.Contract{
+			Address:   address,
+			Type:      cfg.SMCTypeNormal,
+			CreatedAt: tx.Time.Unix(),
+			Status:    types.ContractStatusUnverified,
+		}
+	}
+	return s.updateProxy(ctx, smc, info, l)
+}
+
+// upgradedProxyInfo return the proxy state announced by the event. Slots are read at the latest block,
+// so they only classify the proxy, the implementation of an Upgraded event and the beacon of a
+// BeaconUpgraded event come from the event itself to keep history right when logs are processed late.
+func upgradedProxyInfo(detected *types.ProxyInfo, l *kClient.Log) *types.ProxyInfo {
+	info := *detected
+	topic := common.HexToAddress(l.Topics[1]).Hex()
+	switch {
+	case l.Topics[0] == cfg.ProxyUpgradedTopic && info.Type != types.ProxyTypeBeacon:
+		info.Implementation = topic
+	case l.Topics[0] == cfg.BeaconUpgradedTopic && info.Type == types.ProxyTypeBeacon:
+		info.Beacon = topic
+	}
+	return &info
+}
+
+// detectProxy record implementation of a newly found contract, if it is a proxy
+func (s *Server) detectProxy(ctx context.Context, smc *types.Contract) {
+	info, err := kardia.DetectProxy(ctx, &nodeProxyReader{node: s.node}, smc.Address)
+	if err != nil {
+		if err != kardia.ErrNotProxy {
+			s.logger.Warn("cannot detect proxy", zap.Error(err), zap.String("address", smc.Address))
+		}
+		return
+	}
+	if err := s.updateProxy(ctx, smc, info, nil); err != nil {
+		s.logger.Warn("cannot update proxy", zap.Error(err), zap.String("address", smc.Address))
+	}
+}
+
+// updateProxy store new implementation of the proxy with an upgrade record, then drop cached ABI
+// so calls are decoded with the new implementation. l is nil when no event announced the upgrade.
+func (s *Server) updateProxy(ctx context.Context, smc *types.Contract, info *types.ProxyInfo, l *kClient.Log) error {
+	if smc.ProxyType == info.Type && smc.Implementation == info.Implementation && smc.Beacon == info.Beacon {
+		return nil
+	}
+	upgrade := &types.ProxyUpgrade{
+		Address:        smc.Address,
+		ProxyType:      info.Type,
+		Implementation: info.Implementation,
+		Previous:       smc.Implementation,
+		Beacon:         info.Beacon,
+		CreatedAt:      time.Now(),
+	}
+	if l != nil {
+		upgrade.BlockHeight = l.BlockHeight
+		upgrade.TxHash = l.TxHash
+		upgrade.CreatedAt = l.Time
+	}
+	smc.ProxyType, smc.Implementation, smc.Beacon = info.Type, info.Implementation, info.Beacon
+	if err := s.db.UpdateContract(ctx, smc, nil); err != nil {
+		return err
+	}
+	if err := s.db.InsertProxyUpgrade(ctx, upgrade); err != nil {
+		return err
+	}
+	return s.cache.RemoveSMCAbi(ctx, smc.Address)
+}
@@ -0,0 +1,34 @@
+// Package receipts
+package receipts
+
+import (
+	"testing"
+
+	kClient "github.com/kardiachain/go-kaiclient/kardia"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+func TestUpgradedProxyInfo(t *testing.T) {
+	const (
+		implV1 = "0x3A6a1e6be6Ac2f0b6D8B8A0c35E1d7b5e0A1c2d3"
+		implV2 = "0x14191195F9BB6e54465a341CeC6cce4491599ccC"
+		beacon = "0xc1fe56E3F58D3244F606306611a5d10c8333f1f6"
+	)
+	topic := func(address string) string {
+		return "0x000000000000000000000000" + address[2:]
+	}
+
+	// slots already point to v2 when the upgrade to v1 is processed late
+	detected := &types.ProxyInfo{Type: types.ProxyTypeEIP1967, Implementation: implV2}
+	info := upgradedProxyInfo(detected, &kClient.Log{Topics: []string{cfg.ProxyUpgradedTopic, topic(implV1)}})
+	assert.Equal(t, &types.ProxyInfo{Type: types.ProxyTypeEIP1967, Implementation: implV1}, info)
+	assert.Equal(t, implV2, detected.Implementation)
+
+	detected = &types.ProxyInfo{Type: types.ProxyTypeBeacon, Implementation: implV2, Beacon: "0x0000000000000000000000000000000000000101"}
+	info = upgradedProxyInfo(detected, &kClient.Log{Topics: []string{cfg.BeaconUpgradedTopic, topic(beacon)}})
+	assert.Equal(t, beacon, info.Beacon)
+	assert.Equal(t, types.ProxyTypeBeacon, info.Type)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/kardiachain/kardia-explorer-backend/cache"
 	"github.com/kardiachain/kardia-explorer-backend/cfg"
 	"github.com/kardiachain/kardia-explorer-backend/db"
+	"github.com/kardiachain/kardia-explorer-backend/kardia"
 	"github.com/panjf2000/ants/v2"
 	"go.uber.org/zap"
 )
@@ -17,10 +18,11 @@ import (
 type Server struct {
 	db db.Client
 
-	node   kClient.Node
-	cache  cache.Client
-	logger *zap.Logger
-	p      ants.PoolWithFunc
+	node      kClient.Node
+	kaiClient kardia.ClientInterface
+	cache     cache.Client
+	logger    *zap.Logger
+	p         ants.PoolWithFunc
 }
 
 func (s *Server) SetLogger(logger *zap.Logger) *Server {
@@ -43,6 +45,11 @@ func (s *Server) SetNode(node kClient.Node) *Server {
 	return s
 }
 
+func (s *Server) SetKaiClient(kaiClient kardia.ClientInterface) *Server {
+	s.kaiClient = kaiClient
+	return s
+}
+
 var ErrRedisNil = errors.New("redis: nil")
 var ErrNotFoundReceipt = errors.New("not found")
 
@@ -133,9 +140,53 @@ func (s *Server) processReceipt(ctx context.Context, r *kClient.Receipt) error {
 			}
 		}
 
+		// Process if proxy or beacon upgraded
+		if l.Topics[0] == cfg.ProxyUpgradedTopic || l.Topics[0] == cfg.BeaconUpgradedTopic {
+			if err := s.processUpgradeLog(ctx, l); err != nil {
+				lgr.Error("cannot process upgrade logs", zap.Error(err))
+			}
+		}
+
 		// Process if mint/burn event
 
 	}
 
+	if s.kaiClient != nil {
+		if err := s.processInternalCalls(ctx, r); err != nil {
+			lgr.Error("cannot process internal calls, retry later", zap.String("txHash", r.TransactionHash), zap.Error(err))
+			// logs are processed already, only the trace is retried
+			if err := s.cache.PushTraceRetries(ctx, []string{r.TransactionHash}); err != nil {
+				lgr.Error("cannot push to trace retries", zap.Error(err))
+			}
+		}
+	}
+
 	return nil
 }
+
+// HandleTraceRetries trace again txs whose internal calls failed, one per interval so a node
+// which cannot trace is not flooded. Txs failing again go back to the end of the list.
+func (s *Server) HandleTraceRetries(ctx context.Context, interval time.Duration) {
+	lgr := s.logger.With(zap.String("task", "handle_trace_retries"))
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+			txHash, err := s.cache.PopTraceRetry(ctx)
+			if err != nil || txHash == "" {
+				continue
+			}
+			r, err := s.node.GetTransactionReceipt(ctx, txHash)
+			if err == nil {
+				err = s.processInternalCalls(ctx, r)
+			}
+			if err != nil {
+				lgr.Warn("cannot trace tx, retry later", zap.String("txHash", txHash), zap.Error(err))
+				if err := s.cache.PushTraceRetries(ctx, []string{txHash}); err != nil {
+					lgr.Error("cannot push back to trace retries", zap.Error(err))
+				}
+			}
+		}
+	}
+}
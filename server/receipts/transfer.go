@@ -40,6 +40,8 @@ func (s *Server) processTransferLog(ctx context.Context, l *kClient.Log) error {
 			lgr.Error("cannot insert contract", zap.Error(err))
 			return nil
 		}
+		// tokens are often deployed behind a proxy
+		s.detectProxy(ctx, newToken)
 		contract = newToken
 	}
 	switch contract.Type {
@@ -0,0 +1,259 @@
+// Package server
+package server
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	kClient "github.com/kardiachain/go-kaiclient/kardia"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+	"github.com/kardiachain/kardia-explorer-backend/utils"
+)
+
+var ErrReorgTooDeep = errors.New("cannot find common ancestor within max reorg depth")
+
+// DetectReorg compare the parent hash of the incoming network block with the hash we stored
+// for our current head. It returns the height of the common ancestor when a reorg happened.
+func (s *Server) DetectReorg(ctx context.Context, head uint64, block *types.Block) (bool, uint64, error) {
+	if head == 0 || block.Height <= head {
+		return false, 0, nil
+	}
+	dbHead, err := s.dbClient.BlockByHeight(ctx, head)
+	if err == mongo.ErrNoDocuments || (err == nil && dbHead == nil) {
+		// nothing to compare with, the gap will be filled by backfill
+		return false, 0, nil
+	}
+	if err != nil {
+		return false, 0, err
+	}
+	networkHash := block.LastBlock
+	if block.Height != head+1 {
+		networkHash, err = s.networkBlockHash(ctx, head)
+		if err != nil {
+			return false, 0, err
+		}
+	}
+	if dbHead.Hash == networkHash {
+		return false, 0, nil
+	}
+
+	s.Logger.Warn("Detect chain reorg", zap.Uint64("head", head), zap.String("dbHash", dbHead.Hash), zap.String("networkHash", networkHash))
+	ancestor, err := findCommonAncestor(head, cfg.MaxReorgDepth, s.dbBlockHash(ctx), func(height uint64) (string, error) {
+		return s.networkBlockHash(ctx, height)
+	})
+	if err != nil {
+		return true, 0, err
+	}
+	return true, ancestor, nil
+}
+
+// RollbackBlocks remove every orphaned block in range [from, to] with its txs, events,
+// token transfers and restore holders, address balances and cache to canonical state.
+// Blocks are rolled back from the highest one so KRC721 ownership is reverted in order.
+func (s *Server) RollbackBlocks(ctx context.Context, from, to uint64) error {
+	lgr := s.Logger.With(zap.String("method", "RollbackBlocks"), zap.Uint64("from", from), zap.Uint64("to", to))
+	if from == 0 || from > to {
+		return nil
+	}
+	var removedTxs uint64
+	for height := to; height >= from; height-- {
+		numTxs, err := s.rollbackBlock(ctx, height)
+		if err != nil {
+			lgr.Error("cannot rollback block", zap.Uint64("height", height), zap.Error(err))
+			return err
+		}
+		removedTxs += numTxs
+		s.metrics.RecordReorgedBlock()
+	}
+
+	if err := s.cacheClient.DeleteBlocksFromHeight(ctx, from); err != nil {
+		lgr.Warn("cannot remove orphaned blocks from cache", zap.Error(err))
+	}
+	totalTxs := s.cacheClient.TotalTxs(ctx)
+	if totalTxs > removedTxs {
+		totalTxs -= removedTxs
+	} else {
+		totalTxs = 0
+	}
+	if err := s.cacheClient.SetTotalTxs(ctx, totalTxs); err != nil {
+		lgr.Warn("cannot update total txs after rollback", zap.Error(err))
+	}
+	lgr.Info("Rollback orphaned blocks", zap.Uint64("removedTxs", removedTxs))
+	return nil
+}
+
+func (s *Server) rollbackBlock(ctx context.Context, height uint64) (uint64, error) {
+	lgr := s.Logger.With(zap.Uint64("height", height))
+	txs, _, err := s.dbClient.TxsByBlockHeight(ctx, height, nil)
+	if err != nil {
+		return 0, err
+	}
+	transfers, _, err := s.dbClient.GetListInternalTxs(ctx, &types.InternalTxsFilter{BlockHeight: height})
+	if err != nil {
+		return 0, err
+	}
+	if err := s.revertTokenTransfers(ctx, transfers); err != nil {
+		lgr.Warn("cannot revert token holders", zap.Error(err))
+	}
+	if err := s.dbClient.RemoveInternalTxs(ctx, &types.InternalTxsFilter{BlockHeight: height}); err != nil {
+		return 0, err
+	}
+	if err := s.dbClient.RemoveInternalCallsByBlockHeight(ctx, height); err != nil {
+		return 0, err
+	}
+	if err := s.dbClient.RemoveBalanceEntriesByBlockHeight(ctx, height); err != nil {
+		return 0, err
+	}
+	if err := s.dbClient.DeleteEventsByBlockHeight(ctx, height); err != nil {
+		return 0, err
+	}
+	for _, tx := range txs {
+		if tx.ContractAddress == "" || utils.IsNilAddress(tx.ContractAddress) {
+			continue
+		}
+		if err := s.dbClient.RemoveContract(ctx, tx.ContractAddress); err != nil {
+			lgr.Warn("cannot remove orphaned contract", zap.String("address", tx.ContractAddress), zap.Error(err))
+		}
+	}
+
+	if dbBlock, err := s.dbClient.BlockByHeight(ctx, height); err == nil && dbBlock != nil {
+		numOfBlocks, err := s.cacheClient.CountBlocksOfProposer(ctx, dbBlock.ProposerAddress)
+		if err == nil && numOfBlocks > 0 {
+			_ = s.cacheClient.UpdateNumOfBlocksByProposer(ctx, dbBlock.ProposerAddress, numOfBlocks-1)
+		}
+	}
+	if err := s.dbClient.DeleteBlockByHeight(ctx, height); err != nil {
+		return 0, err
+	}
+
+	// balances are read from the canonical chain now that orphaned txs are gone
+	if addrs := s.getAddressBalances(ctx, filterAddrSet(txs)); len(addrs) > 0 {
+		if err := s.dbClient.UpdateAddresses(ctx, addrs); err != nil {
+			lgr.Warn("cannot refresh balances of orphaned txs addresses", zap.Error(err))
+		}
+	}
+	return uint64(len(txs)), nil
+}
+
+// revertTokenTransfers give KRC721 tokens back to the sender of the orphaned transfer
+// and refresh KRC20 holder balances from network
+func (s *Server) revertTokenTransfers(ctx context.Context, transfers []*types.TokenTransfer) error {
+	type holderKey struct {
+		contract string
+		holder   string
+	}
+	krc20Holders := make(map[holderKey]struct{})
+	for _, t := range transfers {
+		if t.TokenID != "" {
+			if utils.IsNilAddress(t.From) {
+				if err := s.dbClient.RemoveKRC721HolderByTokenID(ctx, t.Contract, t.TokenID); err != nil {
+					return err
+				}
+				continue
+			}
+			holder := &types.KRC721Holder{
+				Address:         t.From,
+				ContractAddress: t.Contract,
+				TokenID:         t.TokenID,
+				UpdatedAt:       time.Now().Unix(),
+			}
+			if err := s.dbClient.UpsertKRC721Holders(ctx, []*types.KRC721Holder{holder}); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, addr := range []string{t.From, t.To} {
+			if !utils.IsNilAddress(addr) {
+				krc20Holders[holderKey{contract: t.Contract, holder: addr}] = struct{}{}
+			}
+		}
+	}
+
+	zero := new(big.Int)
+	for k := range krc20Holders {
+		token, err := kClient.NewToken(s.node, k.contract)
+		if err != nil {
+			return err
+		}
+		krc20Info, err := token.KRC20Info(ctx)
+		if err != nil {
+			return err
+		}
+		balance, err := token.HolderBalance(ctx, k.holder)
+		if err != nil {
+			return err
+		}
+		holder := &types.KRC20Holder{
+			ContractAddress: k.contract,
+			HolderAddress:   k.holder,
+			BalanceString:   balance.String(),
+			BalanceFloat:    utils.BalanceToFloatWithDecimals(balance, int64(krc20Info.Decimals)),
+			UpdatedAt:       time.Now().Unix(),
+		}
+		if balance.Cmp(zero) == 0 {
+			if err := s.dbClient.RemoveKRC20Holder(ctx, holder); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := s.dbClient.UpsertKRC20Holders(ctx, []*types.KRC20Holder{holder}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dbBlockHash return an empty hash for blocks missing in database
+func (s *Server) dbBlockHash(ctx context.Context) func(height uint64) (string, error) {
+	return func(height uint64) (string, error) {
+		b, err := s.dbClient.BlockByHeight(ctx, height)
+		if err == mongo.ErrNoDocuments {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		return b.Hash, nil
+	}
+}
+
+func (s *Server) networkBlockHash(ctx context.Context, height uint64) (string, error) {
+	header, err := s.kaiClient.BlockHeaderByNumber(ctx, height)
+	if err != nil {
+		return "", err
+	}
+	return header.Hash, nil
+}
+
+// findCommonAncestor walk back from `head` until the stored hash matches the network hash.
+// Heights missing in database, with an empty stored hash, are skipped since they have nothing
+// to roll back. Database errors abort the search so readable blocks are never rolled back.
+func findCommonAncestor(head, maxDepth uint64, dbHash, networkHash func(height uint64) (string, error)) (uint64, error) {
+	for height := head; height > 0 && head-height < maxDepth; height-- {
+		stored, err := dbHash(height)
+		if err != nil {
+			return 0, err
+		}
+		if stored == "" {
+			continue
+		}
+		canonical, err := networkHash(height)
+		if err != nil {
+			return 0, err
+		}
+		if stored == canonical {
+			return height, nil
+		}
+	}
+	if head <= maxDepth {
+		// reorg reaches genesis
+		return 0, nil
+	}
+	return 0, ErrReorgTooDeep
+}
@@ -0,0 +1,95 @@
+// Package server
+package server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errDBUnavailable = errors.New("server selection timeout")
+
+func TestFindCommonAncestor(t *testing.T) {
+	network := map[uint64]string{1: "a1", 2: "a2", 3: "a3", 4: "b4", 5: "b5"}
+	networkHash := func(height uint64) (string, error) {
+		return network[height], nil
+	}
+	type testCase struct {
+		name     string
+		head     uint64
+		maxDepth uint64
+		stored   map[uint64]string
+		expected uint64
+		err      error
+
+		dbErr       error
+		dbErrHeight uint64
+	}
+	cases := []testCase{
+		{
+			name:     "fork after block 3",
+			head:     5,
+			maxDepth: 100,
+			stored:   map[uint64]string{1: "a1", 2: "a2", 3: "a3", 4: "c4", 5: "c5"},
+			expected: 3,
+		},
+		{
+			name:     "skip missing blocks in database",
+			head:     5,
+			maxDepth: 100,
+			stored:   map[uint64]string{1: "a1", 2: "a2", 5: "c5"},
+			expected: 2,
+		},
+		{
+			name:     "fork at genesis",
+			head:     3,
+			maxDepth: 100,
+			stored:   map[uint64]string{1: "c1", 2: "c2", 3: "c3"},
+			expected: 0,
+		},
+		{
+			name:     "fork at genesis with head at max depth",
+			head:     5,
+			maxDepth: 5,
+			stored:   map[uint64]string{1: "c1", 2: "c2", 3: "c3", 4: "c4", 5: "c5"},
+			expected: 0,
+		},
+		{
+			name:     "too deep with head past max depth",
+			head:     5,
+			maxDepth: 4,
+			stored:   map[uint64]string{1: "c1", 2: "c2", 3: "c3", 4: "c4", 5: "c5"},
+			err:      ErrReorgTooDeep,
+		},
+		{
+			name:        "database error aborts near genesis",
+			head:        3,
+			maxDepth:    100,
+			stored:      map[uint64]string{1: "a1", 2: "c2", 3: "c3"},
+			dbErr:       errDBUnavailable,
+			dbErrHeight: 2,
+			err:         errDBUnavailable,
+		},
+		{
+			name:     "too deep",
+			head:     5,
+			maxDepth: 2,
+			stored:   map[uint64]string{1: "a1", 2: "a2", 3: "c3", 4: "c4", 5: "c5"},
+			err:      ErrReorgTooDeep,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dbHash := func(height uint64) (string, error) {
+				if c.dbErr != nil && height == c.dbErrHeight {
+					return "", c.dbErr
+				}
+				return c.stored[height], nil
+			}
+			ancestor, err := findCommonAncestor(c.head, c.maxDepth, dbHash, networkHash)
+			assert.Equal(t, c.err, err)
+			assert.Equal(t, c.expected, ancestor)
+		})
+	}
+}
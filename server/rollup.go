@@ -0,0 +1,118 @@
+// Package server
+package server
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+const dirtyRollupsBatch = 100
+
+// BuildRollups build hour then day rollups from the latest built bucket up to now, the latest bucket
+// is built again since it may be partial. Buckets marked dirty by backfill are rebuilt after that.
+func (s *infoServer) BuildRollups(ctx context.Context, now time.Time) error {
+	for _, interval := range []string{types.RollupIntervalHour, types.RollupIntervalDay} {
+		start, err := s.nextRollupStart(ctx, interval)
+		if err != nil {
+			return err
+		}
+		for ; !start.After(now); start = types.RollupEnd(interval, start) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := s.buildRollup(ctx, interval, start); err != nil {
+				return err
+			}
+		}
+	}
+
+	dirty, err := s.dbClient.DirtyRollups(ctx, dirtyRollupsBatch)
+	if err != nil {
+		return err
+	}
+	for _, r := range dirty {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.buildRollup(ctx, r.Interval, r.Time); err != nil {
+			return err
+		}
+	}
+	if len(dirty) > 0 {
+		s.logger.Info("Rebuilt dirty rollups", zap.Int("buckets", len(dirty)))
+	}
+	return nil
+}
+
+// RebuildRollups mark buckets in [from, to] dirty so they are rebuilt by next BuildRollups
+func (s *infoServer) RebuildRollups(ctx context.Context, from, to time.Time) error {
+	var times []time.Time
+	for t := types.RollupStart(types.RollupIntervalHour, from); !t.After(to); t = t.Add(time.Hour) {
+		times = append(times, t)
+	}
+	return s.dbClient.MarkRollupsDirty(ctx, times)
+}
+
+// markRollupsDirty flag buckets of blocks imported out of live flow
+func (s *infoServer) markRollupsDirty(ctx context.Context, blocks []*types.Block) {
+	times := make([]time.Time, len(blocks))
+	for i, b := range blocks {
+		times[i] = b.Time
+	}
+	if err := s.dbClient.MarkRollupsDirty(ctx, times); err != nil {
+		s.logger.Warn("cannot mark rollups dirty", zap.Error(err))
+	}
+}
+
+func (s *infoServer) nextRollupStart(ctx context.Context, interval string) (time.Time, error) {
+	latest, err := s.dbClient.LatestRollup(ctx, interval)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if latest != nil {
+		return latest.Time, nil
+	}
+	// start from the first block in db
+	height, err := s.dbClient.BlockHeightByTime(ctx, time.Unix(0, 0), false)
+	if err != nil {
+		return time.Time{}, err
+	}
+	block, err := s.dbClient.BlockByHeight(ctx, height)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return types.RollupStart(interval, block.Time), nil
+}
+
+func (s *infoServer) buildRollup(ctx context.Context, interval string, start time.Time) error {
+	end := types.RollupEnd(interval, start)
+	rollup, err := s.dbClient.AggregateRollup(ctx, interval, start)
+	if err != nil {
+		return err
+	}
+
+	// hour buckets cover every tx, so first seen times are updated once per tx
+	if interval == types.RollupIntervalHour {
+		firstSeen, err := s.dbClient.ActiveAddressesFirstSeen(ctx, start, end)
+		if err != nil {
+			return err
+		}
+		moved, err := s.dbClient.UpdateAddressesFirstSeen(ctx, firstSeen)
+		if err != nil {
+			return err
+		}
+		// addresses found earlier are no longer new in their previous buckets
+		if err := s.dbClient.MarkRollupsDirty(ctx, moved); err != nil {
+			return err
+		}
+	}
+	if rollup.NewAddresses, err = s.dbClient.CountAddressesFirstSeen(ctx, start, end); err != nil {
+		return err
+	}
+	rollup.UpdatedAt = time.Now()
+	return s.dbClient.UpsertRollup(ctx, rollup)
+}
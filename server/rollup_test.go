@@ -0,0 +1,18 @@
+// Package server
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+func TestRollupStart(t *testing.T) {
+	tm := time.Date(2021, 5, 1, 10, 42, 7, 0, time.FixedZone("ICT", 7*3600))
+	assert.Equal(t, time.Date(2021, 5, 1, 3, 0, 0, 0, time.UTC), types.RollupStart(types.RollupIntervalHour, tm))
+	assert.Equal(t, time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC), types.RollupStart(types.RollupIntervalDay, tm))
+	assert.Equal(t, time.Date(2021, 5, 2, 0, 0, 0, 0, time.UTC), types.RollupEnd(types.RollupIntervalDay, types.RollupStart(types.RollupIntervalDay, tm)))
+}
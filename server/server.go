@@ -19,12 +19,15 @@
 package server
 
 import (
+	"context"
+
 	kClient "github.com/kardiachain/go-kaiclient/kardia"
 	"go.uber.org/zap"
 
 	"github.com/kardiachain/kardia-explorer-backend/cache"
 	"github.com/kardiachain/kardia-explorer-backend/db"
 	s3 "github.com/kardiachain/kardia-explorer-backend/driver/aws"
+	"github.com/kardiachain/kardia-explorer-backend/external"
 	"github.com/kardiachain/kardia-explorer-backend/kardia"
 	"github.com/kardiachain/kardia-explorer-backend/metrics"
 	"github.com/kardiachain/kardia-explorer-backend/types"
@@ -52,6 +55,12 @@ type Config struct {
 
 	VerifyBlockParam *types.VerifyBlockParam
 
+	GasOracleBlocks      int
+	GasOracleMinGasPrice uint64
+
+	MarketCMCAPIKey string
+	WKAIAddress     string
+
 	Metrics *metrics.Provider
 	Logger  *zap.Logger
 
@@ -116,6 +125,9 @@ func New(cfg Config) (*Server, error) {
 	if err != nil {
 		return nil, err
 	}
+	metrics.RegisterQueueLengths(func(ctx context.Context) map[string]int64 {
+		return cache.QueueLengths(ctx, cacheClient)
+	})
 	avgMetrics := metrics.New()
 
 	infoServer := infoServer{
@@ -124,6 +136,11 @@ func New(cfg Config) (*Server, error) {
 		kaiClient:         kaiClient,
 		HttpRequestSecret: cfg.HttpRequestSecret,
 		verifyBlockParam:  cfg.VerifyBlockParam,
+		gasOracleBlocks:   cfg.GasOracleBlocks,
+		minGasPrice:       cfg.GasOracleMinGasPrice,
+		marketCMCAPIKey:   cfg.MarketCMCAPIKey,
+		wkaiAddress:       cfg.WKAIAddress,
+		tokenMarkets:      &tokenMarkets{markets: make(map[string]*external.Market)},
 		logger:            cfg.Logger,
 		metrics:           avgMetrics,
 	}
@@ -0,0 +1,103 @@
+// Package server
+package server
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+// publishStreamEvents push events to realtime API subscribers, failures don't affect import flow
+func (s *infoServer) publishStreamEvents(ctx context.Context, events ...*types.StreamEvent) {
+	for _, e := range events {
+		if err := s.cacheClient.PublishStreamEvent(ctx, e); err != nil {
+			s.logger.Warn("cannot publish stream event", zap.String("type", e.Type), zap.Error(err))
+		}
+	}
+}
+
+// PublishPendingTxs push txs of node mempool to realtime API subscribers once. published holds hashes pushed by
+// previous calls, returned set only keeps hashes still pending so it doesn't grow with confirmed txs.
+func (s *infoServer) PublishPendingTxs(ctx context.Context, published map[string]bool) (map[string]bool, error) {
+	txs, err := s.kaiClient.PendingTransactions(ctx)
+	if err != nil {
+		return published, err
+	}
+	pending := make(map[string]bool, len(txs))
+	var events []*types.StreamEvent
+	for _, tx := range txs {
+		pending[tx.Hash] = true
+		if published[tx.Hash] {
+			continue
+		}
+		tx.Status = types.TxStatusPending
+		if tx.Time.IsZero() {
+			tx.Time = time.Now()
+		}
+		events = append(events, types.NewStreamEvent(types.StreamTxs, tx, tx.From, tx.To))
+	}
+	s.publishStreamEvents(ctx, events...)
+	return pending, nil
+}
+
+func blockStreamEvent(block *types.Block) *types.StreamEvent {
+	header := *block
+	header.Txs = nil
+	header.Receipts = nil
+	return types.NewStreamEvent(types.StreamBlocks, header)
+}
+
+func txsStreamEvents(txs []*types.Transaction) []*types.StreamEvent {
+	events := make([]*types.StreamEvent, 0, len(txs))
+	for _, tx := range txs {
+		events = append(events, types.NewStreamEvent(types.StreamTxs, tx, tx.From, tx.To, tx.ContractAddress))
+	}
+	return events
+}
+
+// transfersStreamEvents decode token transfers from logs of a live block, receipts service also processes
+// re-imported blocks so it must not publish them
+func transfersStreamEvents(block *types.Block) []*types.StreamEvent {
+	var events []*types.StreamEvent
+	for _, tx := range block.Txs {
+		for _, l := range tx.Logs {
+			transfer := transferOfLog(l)
+			if transfer == nil {
+				continue
+			}
+			if transfer.TransactionHash == "" {
+				transfer.TransactionHash = tx.Hash
+			}
+			transfer.BlockHeight = block.Height
+			transfer.Time = block.Time
+			events = append(events, types.NewStreamEvent(types.StreamTransfers, transfer, transfer.Contract))
+		}
+	}
+	return events
+}
+
+// transferOfLog decode Transfer event of KRC20 (value in data) or KRC721 (indexed tokenId) token, nil for other logs
+func transferOfLog(l types.Log) *types.TokenTransfer {
+	if len(l.Topics) < 3 || l.Topics[0] != cfg.KRCTransferTopic {
+		return nil
+	}
+	transfer := &types.TokenTransfer{
+		TransactionHash: l.TxHash,
+		Contract:        l.Address,
+		From:            common.HexToAddress(l.Topics[1]).Hex(),
+		To:              common.HexToAddress(l.Topics[2]).Hex(),
+		LogIndex:        l.Index,
+	}
+	if len(l.Topics) == 4 {
+		transfer.TokenID = new(big.Int).SetBytes(common.FromHex(l.Topics[3])).String()
+	} else {
+		transfer.Value = new(big.Int).SetBytes(common.FromHex(l.Data)).String()
+	}
+	return transfer
+}
@@ -0,0 +1,41 @@
+// Package server
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kardiachain/kardia-explorer-backend/cfg"
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+func TestTransfersStreamEvents(t *testing.T) {
+	from := "0x000000000000000000000000c1fe56e3f58d3244f606306611a5d10c8333f1f6"
+	to := "0x0000000000000000000000004f36a53dc32272b97ae5ff511387e2741d727bdb"
+	block := &types.Block{Height: 10, Time: time.Unix(1600000000, 0), Txs: []*types.Transaction{{
+		Hash: "0xt1",
+		Logs: []types.Log{
+			{Address: "0xkrc20", Topics: []string{cfg.KRCTransferTopic, from, to}, Data: "0x00000000000000000000000000000000000000000000000000000000000003e8", Index: 1},
+			{Address: "0xkrc721", Topics: []string{cfg.KRCTransferTopic, from, to, "0x0000000000000000000000000000000000000000000000000000000000000007"}, Index: 2},
+			{Address: "0xother", Topics: []string{"0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925", from, to}},
+		},
+	}}}
+
+	events := transfersStreamEvents(block)
+	if assert.Len(t, events, 2) {
+		krc20 := events[0].Data.(*types.TokenTransfer)
+		assert.Equal(t, []string{"0xkrc20"}, events[0].Keys)
+		assert.Equal(t, "0xc1fe56E3F58D3244F606306611a5d10c8333f1f6", krc20.From)
+		assert.Equal(t, "0x4f36A53DC32272b97Ae5FF511387E2741D727bdb", krc20.To)
+		assert.Equal(t, "1000", krc20.Value)
+		assert.Equal(t, "0xt1", krc20.TransactionHash)
+		assert.Equal(t, uint64(10), krc20.BlockHeight)
+		assert.Equal(t, block.Time, krc20.Time)
+
+		krc721 := events[1].Data.(*types.TokenTransfer)
+		assert.Equal(t, "7", krc721.TokenID)
+		assert.Empty(t, krc721.Value)
+	}
+}
@@ -0,0 +1,273 @@
+// Package server
+package server
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+// blockVerifyRule compare a block in database with the same block from network.
+// Database block is expected to carry its stored txs.
+type blockVerifyRule func(db, network *types.Block) []*types.BlockMismatch
+
+// buildVerifyRules return rules enabled by verify params
+func buildVerifyRules(p *types.VerifyBlockParam) []blockVerifyRule {
+	if p == nil {
+		return nil
+	}
+	var rules []blockVerifyRule
+	if p.VerifyBlockHash {
+		rules = append(rules, verifyBlockHash)
+	}
+	if p.VerifyParentHash {
+		rules = append(rules, verifyParentHash)
+	}
+	if p.VerifyReceiptsRoot {
+		rules = append(rules, verifyReceiptsRoot)
+	}
+	if p.VerifyGasUsed {
+		rules = append(rules, verifyGasUsed)
+	}
+	if p.VerifyTxCount {
+		rules = append(rules, verifyTxCount)
+	}
+	if p.VerifyTxHashes {
+		rules = append(rules, verifyTxHashes)
+	}
+	if p.VerifyReceipts {
+		rules = append(rules, verifyReceipts)
+	}
+	return rules
+}
+
+func verifyBlockHash(db, network *types.Block) []*types.BlockMismatch {
+	if db.Hash == network.Hash {
+		return nil
+	}
+	return []*types.BlockMismatch{{Check: "blockHash", Target: types.VerifyTargetBlock, Expected: network.Hash, Actual: db.Hash}}
+}
+
+func verifyParentHash(db, network *types.Block) []*types.BlockMismatch {
+	if db.LastBlock == network.LastBlock {
+		return nil
+	}
+	return []*types.BlockMismatch{{Check: "parentHash", Target: types.VerifyTargetBlock, Expected: network.LastBlock, Actual: db.LastBlock}}
+}
+
+func verifyReceiptsRoot(db, network *types.Block) []*types.BlockMismatch {
+	if db.ReceiptsRoot == network.ReceiptsRoot {
+		return nil
+	}
+	return []*types.BlockMismatch{{Check: "receiptsRoot", Target: types.VerifyTargetBlock, Expected: network.ReceiptsRoot, Actual: db.ReceiptsRoot}}
+}
+
+func verifyGasUsed(db, network *types.Block) []*types.BlockMismatch {
+	if db.GasUsed == network.GasUsed {
+		return nil
+	}
+	return []*types.BlockMismatch{{
+		Check:    "gasUsed",
+		Target:   types.VerifyTargetBlock,
+		Expected: strconv.FormatUint(network.GasUsed, 10),
+		Actual:   strconv.FormatUint(db.GasUsed, 10),
+	}}
+}
+
+func verifyTxCount(db, network *types.Block) []*types.BlockMismatch {
+	if db.NumTxs == network.NumTxs {
+		return nil
+	}
+	return []*types.BlockMismatch{{
+		Check:    "txCount",
+		Target:   types.VerifyTargetTxs,
+		Expected: strconv.FormatUint(network.NumTxs, 10),
+		Actual:   strconv.FormatUint(db.NumTxs, 10),
+	}}
+}
+
+func verifyTxHashes(db, network *types.Block) []*types.BlockMismatch {
+	var mismatches []*types.BlockMismatch
+	dbTxs := make(map[string]bool, len(db.Txs))
+	for _, tx := range db.Txs {
+		dbTxs[tx.Hash] = true
+	}
+	networkTxs := make(map[string]bool, len(network.Txs))
+	for _, tx := range network.Txs {
+		networkTxs[tx.Hash] = true
+		if !dbTxs[tx.Hash] {
+			mismatches = append(mismatches, &types.BlockMismatch{Check: "txHash", Target: types.VerifyTargetTxs, TxHash: tx.Hash, Expected: tx.Hash, Actual: ""})
+		}
+	}
+	for _, tx := range db.Txs {
+		if !networkTxs[tx.Hash] {
+			mismatches = append(mismatches, &types.BlockMismatch{Check: "txHash", Target: types.VerifyTargetTxs, TxHash: tx.Hash, Expected: "", Actual: tx.Hash})
+		}
+	}
+	return mismatches
+}
+
+func verifyReceipts(db, network *types.Block) []*types.BlockMismatch {
+	var mismatches []*types.BlockMismatch
+	receipts := make(map[string]*types.Receipt, len(network.Receipts))
+	for _, r := range network.Receipts {
+		receipts[r.TransactionHash] = r
+	}
+	for _, tx := range db.Txs {
+		r, ok := receipts[tx.Hash]
+		if !ok {
+			// missing txs are reported by tx hash rule
+			continue
+		}
+		if tx.Status != r.Status {
+			mismatches = append(mismatches, &types.BlockMismatch{
+				Check:    "receiptStatus",
+				Target:   types.VerifyTargetReceipts,
+				TxHash:   tx.Hash,
+				Expected: strconv.FormatUint(uint64(r.Status), 10),
+				Actual:   strconv.FormatUint(uint64(tx.Status), 10),
+			})
+		}
+		if len(tx.Logs) != len(r.Logs) {
+			mismatches = append(mismatches, &types.BlockMismatch{
+				Check:    "receiptLogs",
+				Target:   types.VerifyTargetReceipts,
+				TxHash:   tx.Hash,
+				Expected: strconv.Itoa(len(r.Logs)),
+				Actual:   strconv.Itoa(len(tx.Logs)),
+			})
+		}
+	}
+	return mismatches
+}
+
+// blockVerifier run all enabled rules and return every failed check
+func (s *infoServer) blockVerifier(db, network *types.Block) []*types.BlockMismatch {
+	var mismatches []*types.BlockMismatch
+	for _, rule := range buildVerifyRules(s.verifyBlockParam) {
+		mismatches = append(mismatches, rule(db, network)...)
+	}
+	return mismatches
+}
+
+// repairTargets return collections affected by mismatches and txs whose receipt is corrupted.
+// A block whose hash or parent hash differ belongs to an orphaned fork, so its txs and
+// receipts are replaced too whatever checks are enabled.
+func repairTargets(mismatches []*types.BlockMismatch) (map[string]bool, map[string]bool) {
+	targets := make(map[string]bool)
+	corruptedTxs := make(map[string]bool)
+	for _, m := range mismatches {
+		targets[m.Target] = true
+		if m.Target == types.VerifyTargetReceipts {
+			corruptedTxs[m.TxHash] = true
+		}
+		if m.Check == "blockHash" || m.Check == "parentHash" {
+			targets[types.VerifyTargetTxs] = true
+		}
+	}
+	return targets, corruptedTxs
+}
+
+// repairBlock only rewrite collections affected by mismatches and return repaired targets
+func (s *infoServer) repairBlock(ctx context.Context, db, network *types.Block, mismatches []*types.BlockMismatch) ([]string, error) {
+	var (
+		repaired       []string
+		receiptsHashes []string
+	)
+	targets, corruptedTxs := repairTargets(mismatches)
+
+	if targets[types.VerifyTargetBlock] {
+		if err := s.dbClient.ReplaceBlock(ctx, network); err != nil {
+			return repaired, err
+		}
+		// the reward minted by block follow the repaired block
+		if err := s.recordBalanceEntries(ctx, &types.Block{Height: network.Height, Time: network.Time, Rewards: network.Rewards}); err != nil {
+			return repaired, err
+		}
+		repaired = append(repaired, types.VerifyTargetBlock)
+	}
+
+	if targets[types.VerifyTargetTxs] || targets[types.VerifyTargetReceipts] {
+		network.Txs = s.mergeAdditionalInfoToTxs(ctx, network.Txs, network.Receipts)
+	}
+	if targets[types.VerifyTargetTxs] {
+		// txs set is wrong, replace the whole txs of this block
+		if err := s.dbClient.ReplaceTxsOfBlock(ctx, network.Height, network.Txs); err != nil {
+			return repaired, err
+		}
+		// events, token transfers and internal calls of replaced txs are written again from receipts
+		if err := s.dbClient.RemoveInternalTxs(ctx, &types.InternalTxsFilter{BlockHeight: network.Height}); err != nil {
+			return repaired, err
+		}
+		if err := s.dbClient.RemoveInternalCallsByBlockHeight(ctx, network.Height); err != nil {
+			return repaired, err
+		}
+		if err := s.dbClient.DeleteEventsByBlockHeight(ctx, network.Height); err != nil {
+			return repaired, err
+		}
+		// internal movements are written again when receipts are re-processed
+		if err := s.dbClient.RemoveBalanceEntriesByBlockHeight(ctx, network.Height); err != nil {
+			return repaired, err
+		}
+		if err := s.recordBalanceEntries(ctx, network); err != nil {
+			return repaired, err
+		}
+		totalTxs := s.cacheClient.TotalTxs(ctx) + network.NumTxs
+		if totalTxs >= db.NumTxs {
+			totalTxs -= db.NumTxs
+		}
+		if err := s.cacheClient.SetTotalTxs(ctx, totalTxs); err != nil {
+			s.logger.Warn("cannot update total txs after repair", zap.Error(err))
+		}
+		for _, r := range network.Receipts {
+			receiptsHashes = append(receiptsHashes, r.TransactionHash)
+		}
+		repaired = append(repaired, types.VerifyTargetTxs)
+	} else if targets[types.VerifyTargetReceipts] {
+		var txs []*types.Transaction
+		for _, tx := range network.Txs {
+			if corruptedTxs[tx.Hash] {
+				txs = append(txs, tx)
+				receiptsHashes = append(receiptsHashes, tx.Hash)
+			}
+		}
+		if err := s.dbClient.ReplaceTxs(ctx, txs); err != nil {
+			return repaired, err
+		}
+		if err := s.recordBalanceEntries(ctx, network); err != nil {
+			return repaired, err
+		}
+		repaired = append(repaired, types.VerifyTargetReceipts)
+	}
+
+	// re-process receipts so token transfers and holders follow repaired txs
+	if len(receiptsHashes) > 0 {
+		if err := s.cacheClient.PushReceipts(ctx, receiptsHashes); err != nil {
+			return repaired, err
+		}
+	}
+	if len(repaired) > 0 {
+		s.markRollupsDirty(ctx, []*types.Block{network})
+	}
+	return repaired, nil
+}
+
+func (s *infoServer) auditVerifyResult(ctx context.Context, network *types.Block, mismatches []*types.BlockMismatch, repaired []string, repairErr error) {
+	audit := &types.VerifyAudit{
+		BlockHeight: network.Height,
+		BlockHash:   network.Hash,
+		Mismatches:  mismatches,
+		Repaired:    repaired,
+		CreatedAt:   time.Now(),
+	}
+	if repairErr != nil {
+		audit.RepairError = repairErr.Error()
+	}
+	if err := s.dbClient.InsertVerifyAudit(ctx, audit); err != nil {
+		s.logger.Warn("Cannot insert verify audit", zap.Uint64("height", network.Height), zap.Error(err))
+	}
+}
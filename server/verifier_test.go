@@ -0,0 +1,81 @@
+// Package server
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kardiachain/kardia-explorer-backend/types"
+)
+
+func TestBlockVerifier(t *testing.T) {
+	network := &types.Block{
+		Hash:      "0xb1",
+		LastBlock: "0xb0",
+		GasUsed:   42000,
+		NumTxs:    2,
+		Txs:       []*types.Transaction{{Hash: "0xt1"}, {Hash: "0xt2"}},
+		Receipts: []*types.Receipt{
+			{TransactionHash: "0xt1", Status: 1, Logs: []types.Log{{}}},
+			{TransactionHash: "0xt2", Status: 1},
+		},
+	}
+	type testCase struct {
+		name   string
+		param  *types.VerifyBlockParam
+		db     *types.Block
+		checks []string
+	}
+	cases := []testCase{
+		{
+			name:  "valid block",
+			param: &types.VerifyBlockParam{VerifyTxCount: true, VerifyBlockHash: true, VerifyParentHash: true, VerifyGasUsed: true, VerifyTxHashes: true, VerifyReceipts: true},
+			db: &types.Block{Hash: "0xb1", LastBlock: "0xb0", GasUsed: 42000, NumTxs: 2,
+				Txs: []*types.Transaction{{Hash: "0xt1", Status: 1, Logs: []types.Log{{}}}, {Hash: "0xt2", Status: 1}}},
+		},
+		{
+			name:   "header mismatches",
+			param:  &types.VerifyBlockParam{VerifyBlockHash: true, VerifyParentHash: true, VerifyGasUsed: true},
+			db:     &types.Block{Hash: "0xc1", LastBlock: "0xc0", GasUsed: 21000},
+			checks: []string{"blockHash", "parentHash", "gasUsed"},
+		},
+		{
+			name:   "disabled rules are skipped",
+			param:  &types.VerifyBlockParam{VerifyTxCount: true},
+			db:     &types.Block{Hash: "0xc1", NumTxs: 2},
+			checks: nil,
+		},
+		{
+			name:  "missing tx and corrupted receipt",
+			param: &types.VerifyBlockParam{VerifyTxCount: true, VerifyTxHashes: true, VerifyReceipts: true},
+			db: &types.Block{Hash: "0xb1", NumTxs: 1,
+				Txs: []*types.Transaction{{Hash: "0xt1", Status: 0}}},
+			checks: []string{"txCount", "txHash", "receiptStatus", "receiptLogs"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := &infoServer{verifyBlockParam: c.param}
+			var checks []string
+			for _, m := range s.blockVerifier(c.db, network) {
+				checks = append(checks, m.Check)
+			}
+			assert.Equal(t, c.checks, checks)
+		})
+	}
+}
+
+func TestRepairTargets(t *testing.T) {
+	targets, corruptedTxs := repairTargets([]*types.BlockMismatch{{Check: "gasUsed", Target: types.VerifyTargetBlock}})
+	assert.Equal(t, map[string]bool{types.VerifyTargetBlock: true}, targets)
+	assert.Empty(t, corruptedTxs)
+
+	// an orphaned block carries orphaned txs and receipts even when tx checks are disabled
+	targets, _ = repairTargets([]*types.BlockMismatch{{Check: "parentHash", Target: types.VerifyTargetBlock}})
+	assert.Equal(t, map[string]bool{types.VerifyTargetBlock: true, types.VerifyTargetTxs: true}, targets)
+
+	targets, corruptedTxs = repairTargets([]*types.BlockMismatch{{Check: "receiptStatus", Target: types.VerifyTargetReceipts, TxHash: "0xt1"}})
+	assert.Equal(t, map[string]bool{types.VerifyTargetReceipts: true}, targets)
+	assert.Equal(t, map[string]bool{"0xt1": true}, corruptedTxs)
+}
@@ -0,0 +1,111 @@
+/*
+ *  Copyright 2018 KardiaChain
+ *  This file is part of the go-kardia library.
+ *
+ *  The go-kardia library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU Lesser General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The go-kardia library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+ *  GNU Lesser General Public License for more details.
+ *
+ *  You should have received a copy of the GNU Lesser General Public License
+ *  along with the go-kardia library. If not, see <http://www.gnu.org/licenses/>.
+ */
+// Package tracing export OpenTelemetry spans of services to an OTLP collector
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/propagation"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/label"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.uber.org/zap"
+)
+
+const instrumentationName = "github.com/kardiachain/kardia-explorer-backend"
+
+type Config struct {
+	ServiceName string
+	// Endpoint is the OTLP gRPC collector address, spans are dropped by a no-op tracer when empty
+	Endpoint    string
+	Insecure    bool
+	SampleRatio float64
+
+	Logger *zap.Logger
+}
+
+// Init install the global tracer provider of the service. The returned func flush buffered spans
+// and must be called before the service exit.
+func Init(cfg Config) (func(), error) {
+	if cfg.Endpoint == "" {
+		return func() {}, nil
+	}
+	opts := []otlp.ExporterOption{otlp.WithAddress(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlp.WithInsecure())
+	}
+	exporter, err := otlp.NewExporter(opts...)
+	if err != nil {
+		return nil, err
+	}
+	processor, err := sdktrace.NewBatchSpanProcessor(exporter)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := sdktrace.NewProvider(
+		sdktrace.WithConfig(sdktrace.Config{
+			// follow the decision of the caller, e.g. an upstream proxy, when there is one
+			DefaultSampler: sdktrace.ParentSample(sdktrace.ProbabilitySampler(cfg.SampleRatio)),
+		}),
+		sdktrace.WithResource(resource.New(semconv.ServiceNameKey.String(cfg.ServiceName))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	provider.RegisterSpanProcessor(processor)
+	global.SetTraceProvider(provider)
+	cfg.Logger.Info("Exporting traces", zap.String("endpoint", cfg.Endpoint), zap.Float64("sampleRatio", cfg.SampleRatio))
+
+	return func() {
+		// unregistering the processor export the queued spans
+		provider.UnregisterSpanProcessor(processor)
+		if err := exporter.Stop(); err != nil {
+			cfg.Logger.Warn("cannot stop trace exporter", zap.Error(err))
+		}
+	}, nil
+}
+
+// Tracer return the tracer of the explorer instrumentation
+func Tracer() trace.Tracer {
+	return global.Tracer(instrumentationName)
+}
+
+// Start create a child span of the span in ctx
+func Start(ctx context.Context, name string, attrs ...label.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End record err on span then end it
+func End(ctx context.Context, span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(ctx, err, trace.WithErrorStatus(codes.Internal))
+	}
+	span.End()
+}
+
+// Extract return ctx carrying the remote span context found in headers, if any
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return propagation.ExtractHTTP(ctx, global.Propagators(), header)
+}
@@ -0,0 +1,57 @@
+// Package types
+package types
+
+import (
+	"time"
+)
+
+const (
+	// AdminRoleContentEditor edit names, contracts and token metadata
+	AdminRoleContentEditor = "content_editor"
+	// AdminRoleOperator run maintenance jobs such as reloading, syncing and rewriting indexed data
+	AdminRoleOperator = "operator"
+	// AdminRoleSuperAdmin is allowed everything, including managing admins, API keys and reading audit logs
+	AdminRoleSuperAdmin = "superadmin"
+)
+
+// AdminRoles is roles which can be assigned to admin users
+var AdminRoles = map[string]bool{
+	AdminRoleContentEditor: true,
+	AdminRoleOperator:      true,
+	AdminRoleSuperAdmin:    true,
+}
+
+type AdminUser struct {
+	Username     string    `json:"username" bson:"username"`
+	PasswordHash string    `json:"-" bson:"passwordHash"`
+	Role         string    `json:"role" bson:"role"`
+	Disabled     bool      `json:"disabled" bson:"disabled"`
+	CreatedAt    time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// AuditLog is an admin request which changes data, entries are never updated nor removed
+type AuditLog struct {
+	Actor    string            `json:"actor" bson:"actor"`
+	Role     string            `json:"role" bson:"role"`
+	Method   string            `json:"method" bson:"method"`
+	Path     string            `json:"path" bson:"path"`
+	Params   map[string]string `json:"params,omitempty" bson:"params,omitempty"`
+	Query    string            `json:"query,omitempty" bson:"query,omitempty"`
+	Status   int               `json:"status" bson:"status"`
+	IP       string            `json:"ip" bson:"ip"`
+	Resource string            `json:"resource,omitempty" bson:"resource,omitempty"`
+
+	Before  map[string]interface{} `json:"before,omitempty" bson:"before,omitempty"`
+	After   map[string]interface{} `json:"after,omitempty" bson:"after,omitempty"`
+	Changes []*AuditChange         `json:"changes,omitempty" bson:"changes,omitempty"`
+
+	Time time.Time `json:"time" bson:"time"`
+}
+
+// AuditChange is a top level field of a resource changed by an admin request
+type AuditChange struct {
+	Field  string      `json:"field" bson:"field"`
+	Before interface{} `json:"before" bson:"before"`
+	After  interface{} `json:"after" bson:"after"`
+}
@@ -0,0 +1,82 @@
+// Package types
+package types
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+const (
+	RateLimitTierAnonymous = "anonymous"
+	RateLimitTierFree      = "free"
+	RateLimitTierStandard  = "standard"
+	RateLimitTierPro       = "pro"
+	// RateLimitTierInternal is for keys issued to our own services, e.g. frontend SSR and bots
+	RateLimitTierInternal = "internal"
+
+	// APIKeyPrefixLength is the number of leading characters of a key kept to recognize it
+	APIKeyPrefixLength = 8
+)
+
+// RateLimitTier is quotas of a class of clients, Burst is the number of requests accepted at once. Zero
+// RequestsPerMinute or DailyQuota means unlimited
+type RateLimitTier struct {
+	Name              string `json:"name"`
+	RequestsPerMinute int    `json:"requestsPerMinute"`
+	Burst             int    `json:"burst"`
+	DailyQuota        int64  `json:"dailyQuota"`
+}
+
+// RateLimitTiers is tiers which can be assigned to API keys
+var RateLimitTiers = map[string]*RateLimitTier{
+	RateLimitTierFree:     {Name: RateLimitTierFree, RequestsPerMinute: 120, Burst: 30, DailyQuota: 100000},
+	RateLimitTierStandard: {Name: RateLimitTierStandard, RequestsPerMinute: 600, Burst: 100, DailyQuota: 1000000},
+	RateLimitTierPro:      {Name: RateLimitTierPro, RequestsPerMinute: 3000, Burst: 500},
+	RateLimitTierInternal: {Name: RateLimitTierInternal},
+}
+
+// APIKey identify a client, only hash of the key is stored and the key itself is shown once at creation
+type APIKey struct {
+	ID       string `json:"id" bson:"id"`
+	Name     string `json:"name" bson:"name"`
+	Owner    string `json:"owner" bson:"owner"`
+	Prefix   string `json:"prefix" bson:"prefix"`
+	KeyHash  string `json:"-" bson:"keyHash"`
+	Tier     string `json:"tier" bson:"tier"`
+	Disabled bool   `json:"disabled" bson:"disabled"`
+
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// RateLimitResult is the decision on a request, RetryAfter is set when it is rejected
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// EndpointUsage is the number of requests of a client to a route during a day
+type EndpointUsage struct {
+	Day      string `json:"day"`
+	Endpoint string `json:"endpoint"`
+	Requests int64  `json:"requests"`
+}
+
+// NewAPIKey generate a random key and its id
+func NewAPIKey() (id string, key string, err error) {
+	buf := make([]byte, 28)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(buf[:8]), "kai_" + hex.EncodeToString(buf[8:]), nil
+}
+
+// HashAPIKey return hash of key used to look it up
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,198 @@
+// Package types
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/kardiachain/go-kardia/lib/common"
+)
+
+const (
+	BalanceEntryTypeValue    = "value"
+	BalanceEntryTypeFee      = "fee"
+	BalanceEntryTypeInternal = "internal"
+	BalanceEntryTypeReward   = "reward"
+	BalanceEntryTypeMint     = "mint"
+	BalanceEntryTypeGenesis  = "genesis"
+)
+
+// BalanceEntry is a debit or credit of native KAI of an address, balance at a block is the sum of
+// entries up to that block
+type BalanceEntry struct {
+	Address     string    `json:"address" bson:"address"`
+	BlockHeight uint64    `json:"blockHeight" bson:"blockHeight"`
+	Time        time.Time `json:"time" bson:"time"`
+	TxHash      string    `json:"txHash" bson:"txHash"`
+	Type        string    `json:"type" bson:"type"`
+	// Key identify the movement in tx, e.g. "fee", "value_in" or "call_0_2_out"
+	Key          string `json:"key" bson:"key"`
+	Counterparty string `json:"counterparty,omitempty" bson:"counterparty,omitempty"`
+	// Amount is signed amount in hydro, negative for debit
+	Amount string `json:"amount" bson:"amount"`
+}
+
+// BalanceChange is the net change of an address balance in a block
+type BalanceChange struct {
+	BlockHeight uint64    `json:"blockHeight" bson:"blockHeight"`
+	Time        time.Time `json:"time" bson:"time"`
+	Change      string    `json:"change" bson:"change"`
+	// Balance is balance of address after the block
+	Balance string `json:"balance" bson:"-"`
+}
+
+// Genesis is the genesis section of a node config, which set opening balances of the chain
+type Genesis struct {
+	Timestamp  int64               `yaml:"Timestamp"`
+	Accounts   []*GenesisAccount   `yaml:"Accounts"`
+	Validators []*GenesisValidator `yaml:"Validators"`
+}
+
+type GenesisAccount struct {
+	Address string `yaml:"Address"`
+	Amount  string `yaml:"Amount"`
+}
+
+// GenesisValidator is a validator created at genesis with its self delegation and delegations
+type GenesisValidator struct {
+	Address      string            `yaml:"Address"`
+	SelfDelegate string            `yaml:"SelfDelegate"`
+	Delegators   []*GenesisAccount `yaml:"Delegators"`
+}
+
+// GenesisBalanceEntries return opening balances of genesis accounts at block 0, and the self delegations
+// and delegations of genesis validators moved to validator contracts, which are looked up in validatorSMCs
+// by owner address. Gas of genesis staking calls is negligible and not recorded.
+func GenesisBalanceEntries(genesis *Genesis, validatorSMCs map[string]string) ([]*BalanceEntry, error) {
+	var (
+		entries []*BalanceEntry
+		t       = time.Unix(genesis.Timestamp, 0)
+	)
+	for _, account := range genesis.Accounts {
+		amount, ok := new(big.Int).SetString(account.Amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid genesis amount %q of %s", account.Amount, account.Address)
+		}
+		entries = append(entries, &BalanceEntry{
+			Address: common.HexToAddress(account.Address).Hex(),
+			Time:    t,
+			Type:    BalanceEntryTypeGenesis,
+			Key:     "genesis",
+			Amount:  amount.String(),
+		})
+	}
+	for _, val := range genesis.Validators {
+		owner := common.HexToAddress(val.Address).Hex()
+		smc, ok := validatorSMCs[owner]
+		if !ok {
+			return nil, fmt.Errorf("unknown contract of genesis validator %s", owner)
+		}
+		delegations := append([]*GenesisAccount{{Address: owner, Amount: val.SelfDelegate}}, val.Delegators...)
+		for _, del := range delegations {
+			delegator := common.HexToAddress(del.Address).Hex()
+			entries = append(entries, transferEntries("", 0, t, BalanceEntryTypeGenesis, "genesis_delegate_"+smc+"_"+delegator, delegator, smc, del.Amount)...)
+		}
+	}
+	return entries, nil
+}
+
+// BalanceEntriesFromBlock return the reward minted to stakingContract by block, fee movements of txs
+// and value movements of successful txs. Fees are paid to the block proposer, the coinbase of Kardia blocks.
+func BalanceEntriesFromBlock(block *Block, stakingContract string) []*BalanceEntry {
+	var (
+		entries  []*BalanceEntry
+		proposer = common.HexToAddress(block.ProposerAddress).Hex()
+	)
+	if reward, ok := new(big.Int).SetString(block.Rewards, 10); ok && reward.Sign() > 0 {
+		entries = append(entries, &BalanceEntry{
+			Address:     common.HexToAddress(stakingContract).Hex(),
+			BlockHeight: block.Height,
+			Time:        block.Time,
+			Type:        BalanceEntryTypeMint,
+			Key:         "mint_" + strconv.FormatUint(block.Height, 10),
+			Amount:      reward.String(),
+		})
+	}
+	for _, tx := range block.Txs {
+		if fee, ok := new(big.Int).SetString(tx.TxFee, 10); ok && fee.Sign() > 0 {
+			entries = append(entries,
+				&BalanceEntry{
+					Address:      tx.From,
+					BlockHeight:  tx.BlockNumber,
+					Time:         tx.Time,
+					TxHash:       tx.Hash,
+					Type:         BalanceEntryTypeFee,
+					Key:          "fee",
+					Counterparty: proposer,
+					Amount:       new(big.Int).Neg(fee).String(),
+				},
+				&BalanceEntry{
+					Address:      proposer,
+					BlockHeight:  tx.BlockNumber,
+					Time:         tx.Time,
+					TxHash:       tx.Hash,
+					Type:         BalanceEntryTypeFee,
+					Key:          "fee_in",
+					Counterparty: tx.From,
+					Amount:       fee.String(),
+				})
+		}
+		if tx.Status != TransactionStatusSuccess {
+			continue
+		}
+		to := tx.To
+		if to == "" {
+			to = tx.ContractAddress
+		}
+		entries = append(entries, transferEntries(tx.Hash, tx.BlockNumber, tx.Time, BalanceEntryTypeValue, "value", tx.From, to, tx.Value)...)
+	}
+	return entries
+}
+
+// BalanceEntriesFromInternalCalls return movements of calls which did not revert. Calls sent by
+// addresses in rewardSenders, e.g. staking and validator contracts, are recorded as reward.
+func BalanceEntriesFromInternalCalls(calls []*InternalCall, rewardSenders map[string]bool) []*BalanceEntry {
+	var entries []*BalanceEntry
+	for _, call := range calls {
+		if call.Error != "" {
+			continue
+		}
+		entryType := BalanceEntryTypeInternal
+		if rewardSenders[call.From] {
+			entryType = BalanceEntryTypeReward
+		}
+		entries = append(entries, transferEntries(call.TxHash, call.BlockHeight, call.Time, entryType, "call_"+call.Path, call.From, call.To, call.Value)...)
+	}
+	return entries
+}
+
+func transferEntries(txHash string, blockHeight uint64, t time.Time, entryType, key, from, to, value string) []*BalanceEntry {
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() <= 0 || to == "" {
+		return nil
+	}
+	return []*BalanceEntry{
+		{
+			Address:      from,
+			BlockHeight:  blockHeight,
+			Time:         t,
+			TxHash:       txHash,
+			Type:         entryType,
+			Key:          key + "_out",
+			Counterparty: to,
+			Amount:       new(big.Int).Neg(amount).String(),
+		},
+		{
+			Address:      to,
+			BlockHeight:  blockHeight,
+			Time:         t,
+			TxHash:       txHash,
+			Type:         entryType,
+			Key:          key + "_in",
+			Counterparty: from,
+			Amount:       amount.String(),
+		},
+	}
+}
@@ -0,0 +1,103 @@
+package types
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sumBalanceEntries(t *testing.T, entries []*BalanceEntry) map[string]int64 {
+	balances := make(map[string]int64)
+	for _, e := range entries {
+		amount, err := strconv.ParseInt(e.Amount, 10, 64)
+		assert.NoError(t, err)
+		balances[e.Address] += amount
+	}
+	return balances
+}
+
+func TestBalanceEntriesFromBlock(t *testing.T) {
+	const (
+		a        = "0x000000000000000000000000000000000000000A"
+		b        = "0x000000000000000000000000000000000000000B"
+		c        = "0x000000000000000000000000000000000000000C"
+		proposer = "0x00000000000000000000000000000000000000ff"
+		staking  = "0x0000000000000000000000000000000000001337"
+	)
+	block := &Block{
+		Height:          10,
+		Rewards:         "1000",
+		ProposerAddress: "0x00000000000000000000000000000000000000FF",
+		Txs: []*Transaction{
+			{Hash: "0x1", BlockNumber: 10, From: a, To: b, Value: "100", TxFee: "21", Status: TransactionStatusSuccess},
+			// failed tx only pay fee
+			{Hash: "0x2", BlockNumber: 10, From: a, To: b, Value: "100", TxFee: "30", Status: TransactionStatusFailed},
+			// contract creation credit the new contract
+			{Hash: "0x3", BlockNumber: 10, From: a, ContractAddress: c, Value: "5", TxFee: "0", Status: TransactionStatusSuccess},
+		},
+	}
+	entries := BalanceEntriesFromBlock(block, staking)
+
+	balances := sumBalanceEntries(t, entries)
+	assert.Len(t, entries, 9)
+	assert.Equal(t, int64(-156), balances[a])
+	assert.Equal(t, int64(100), balances[b])
+	assert.Equal(t, int64(5), balances[c])
+	assert.Equal(t, int64(51), balances[proposer])
+	assert.Equal(t, int64(1000), balances[staking])
+	assert.Equal(t, BalanceEntryTypeMint, entries[0].Type)
+	assert.Equal(t, "mint_10", entries[0].Key)
+}
+
+func TestGenesisBalanceEntries(t *testing.T) {
+	const (
+		owner     = "0xc1fe56E3F58D3244F606306611a5d10c8333f1f6"
+		delegator = "0x7cefC13B6E2aedEeDFB7Cb6c32457240746BAEe5"
+		smc       = "0x0000000000000000000000000000000000000101"
+	)
+	genesis := &Genesis{
+		Timestamp: 1605528000,
+		Accounts: []*GenesisAccount{
+			{Address: "0xc1fe56e3f58d3244f606306611a5d10c8333f1f6", Amount: "1000"},
+			{Address: delegator, Amount: "500"},
+		},
+		Validators: []*GenesisValidator{{
+			Address:      owner,
+			SelfDelegate: "300",
+			Delegators:   []*GenesisAccount{{Address: delegator, Amount: "200"}},
+		}},
+	}
+	entries, err := GenesisBalanceEntries(genesis, map[string]string{owner: smc})
+	assert.NoError(t, err)
+
+	balances := sumBalanceEntries(t, entries)
+	assert.Equal(t, int64(700), balances[owner])
+	assert.Equal(t, int64(300), balances[delegator])
+	assert.Equal(t, int64(500), balances[smc])
+	for _, e := range entries {
+		assert.Equal(t, uint64(0), e.BlockHeight)
+		assert.Equal(t, BalanceEntryTypeGenesis, e.Type)
+	}
+
+	_, err = GenesisBalanceEntries(genesis, nil)
+	assert.Error(t, err)
+}
+
+func TestBalanceEntriesFromInternalCalls(t *testing.T) {
+	calls := []*InternalCall{
+		{TxHash: "0x1", From: "0xS", To: "0xA", Value: "7", Path: "0"},
+		{TxHash: "0x1", From: "0xB", To: "0xA", Value: "3", Path: "1"},
+		{TxHash: "0x1", From: "0xB", To: "0xC", Value: "3", Path: "2", Error: "execution reverted"},
+		{TxHash: "0x1", From: "0xB", To: "0xD", Value: "0", Path: "3"},
+	}
+	entries := BalanceEntriesFromInternalCalls(calls, map[string]bool{"0xS": true})
+
+	assert.Len(t, entries, 4)
+	assert.Equal(t, BalanceEntryTypeReward, entries[0].Type)
+	assert.Equal(t, "-7", entries[0].Amount)
+	assert.Equal(t, "call_0_out", entries[0].Key)
+	assert.Equal(t, BalanceEntryTypeInternal, entries[3].Type)
+	assert.Equal(t, "0xA", entries[3].Address)
+	assert.Equal(t, "3", entries[3].Amount)
+}
@@ -69,8 +69,13 @@ type Block struct {
 }
 
 type VerifyBlockParam struct {
-	VerifyTxCount   bool
-	VerifyBlockHash bool
+	VerifyTxCount      bool
+	VerifyBlockHash    bool
+	VerifyParentHash   bool
+	VerifyReceiptsRoot bool
+	VerifyGasUsed      bool
+	VerifyTxHashes     bool
+	VerifyReceipts     bool
 }
 
 func (b *Block) String() string {
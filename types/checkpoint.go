@@ -0,0 +1,10 @@
+package types
+
+import "time"
+
+// Checkpoint keep the last height processed by a long running job, so it can resume after restart
+type Checkpoint struct {
+	Name      string    `json:"name" bson:"name"`
+	Height    uint64    `json:"height" bson:"height"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
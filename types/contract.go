@@ -33,10 +33,34 @@ type Contract struct {
 	CompilerVersion string `json:"compilerVersion" bson:"compilerVersion,omitempty"`
 	IsOptimize      bool   `json:"isOptimize" bson:"isOptimize,omitempty"`
 
+	// Multi-file sources and compiler settings of verified contract
+	SourceFiles  []*ContractSource  `json:"sourceFiles,omitempty" bson:"sourceFiles,omitempty"`
+	OptimizeRuns int                `json:"optimizeRuns,omitempty" bson:"optimizeRuns,omitempty"`
+	EVMVersion   string             `json:"evmVersion,omitempty" bson:"evmVersion,omitempty"`
+	Remappings   []string           `json:"remappings,omitempty" bson:"remappings,omitempty"`
+	Libraries    []*ContractLibrary `json:"libraries,omitempty" bson:"libraries,omitempty"`
+
+	// Proxy information, calls and events are decoded with the implementation ABI
+	ProxyType      string `json:"proxyType,omitempty" bson:"proxyType,omitempty"`
+	Implementation string `json:"implementation,omitempty" bson:"implementation,omitempty"`
+	Beacon         string `json:"beacon,omitempty" bson:"beacon,omitempty"`
+
 	CreatedAt int64 `json:"createdAt" bson:"createdAt,omitempty"`
 	UpdatedAt int64 `json:"updatedAt" bson:"updatedAt,omitempty"`
 }
 
+// ContractSource is a source file of a verified contract, Name is the path used in imports
+type ContractSource struct {
+	Name    string `json:"name" bson:"name"`
+	Content string `json:"content" bson:"content"`
+}
+
+// ContractLibrary is a linked library, Name may be qualified by source file ("contracts/Math.sol:Math")
+type ContractLibrary struct {
+	Name    string `json:"name" bson:"name"`
+	Address string `json:"address" bson:"address"`
+}
+
 type ContractABI struct {
 	Type string `json:"type" bson:"type"`
 	ABI  string `json:"abi" bson:"abi"`
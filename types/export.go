@@ -0,0 +1,34 @@
+// Package types
+package types
+
+import "time"
+
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+
+	// MaxExportRows cap rows of an export request, clients continue from the block of the last row
+	MaxExportRows = 10000
+)
+
+// ExportFilter select records of an address to export, zero values are not filtered
+type ExportFilter struct {
+	Address   string
+	StartTime time.Time
+	EndTime   time.Time
+	FromBlock uint64
+	ToBlock   uint64
+	Limit     int
+
+	// ToAddresses restrict txs sent by Address to these receivers
+	ToAddresses []string
+	// TokenType select KRC20 or KRC721 transfers, empty means both
+	TokenType string
+}
+
+// Sanitize cap limit at MaxExportRows
+func (f *ExportFilter) Sanitize() {
+	if f.Limit <= 0 || f.Limit > MaxExportRows {
+		f.Limit = MaxExportRows
+	}
+}
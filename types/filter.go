@@ -62,7 +62,21 @@ type InternalTxsFilter struct {
 	TransactionHash string          `bson:"txHash,omitempty"`
 	Contract        string          `json:"contractAddress" bson:"contractAddress,omitempty"`
 	Address         string          `bson:"address,omitempty"`
+	BlockHeight     uint64          `json:"blockHeight" bson:"blockHeight,omitempty"`
 	Topics          [][]common.Hash `json:"topics" bson:"-"`
+
+	BlockRange *BlockRangeFilter `json:"blockRange" bson:"-"`
+	// TokenType select KRC20 (fungible) or KRC721 (has tokenID) transfers, empty means both
+	TokenType string `json:"tokenType" bson:"-"`
+	// Addresses match transfers from or to any of them
+	Addresses []string `json:"-" bson:"-"`
+}
+
+// BlockRangeFilter restrict records in [FromBlock, ToBlock], zero ToBlock means latest
+type BlockRangeFilter struct {
+	FromBlock uint64 `json:"fromBlock"`
+	ToBlock   uint64 `json:"toBlock"`
+	Asc       bool   `json:"asc"`
 }
 
 type TxsFilter struct {
@@ -104,3 +118,52 @@ type EventsFilter struct {
 	MethodName      string `bson:"methodName,omitempty"`
 	TxHash          string `bson:"transactionHash,omitempty"`
 }
+
+type VerifyAuditsFilter struct {
+	Pagination *Pagination `bson:"-"`
+
+	BlockHeight uint64 `bson:"blockHeight,omitempty"`
+}
+
+type ProxyUpgradesFilter struct {
+	Pagination *Pagination `bson:"-"`
+
+	Address string `bson:"address,omitempty"`
+}
+
+type InternalCallsFilter struct {
+	Pagination *Pagination `bson:"-"`
+
+	// Address match sender or receiver
+	Address string `bson:"-"`
+	TxHash  string `bson:"txHash,omitempty"`
+}
+
+type BalanceChangesFilter struct {
+	Pagination *Pagination `bson:"-"`
+
+	Address   string    `bson:"address"`
+	StartTime time.Time `bson:"-"`
+	EndTime   time.Time `bson:"-"`
+}
+
+type RollupsFilter struct {
+	Interval  string    `bson:"interval"`
+	StartTime time.Time `bson:"-"`
+	EndTime   time.Time `bson:"-"`
+}
+
+type TokenPricesFilter struct {
+	Token     string    `bson:"token"`
+	StartTime time.Time `bson:"-"`
+	EndTime   time.Time `bson:"-"`
+}
+
+type AuditLogsFilter struct {
+	Pagination *Pagination `bson:"-"`
+
+	Actor     string    `bson:"actor,omitempty"`
+	Resource  string    `bson:"resource,omitempty"`
+	StartTime time.Time `bson:"-"`
+	EndTime   time.Time `bson:"-"`
+}
@@ -0,0 +1,138 @@
+// Package types
+package types
+
+import (
+	"sort"
+	"time"
+)
+
+// BlockGasStats is gas price percentiles and fullness of a block, prices are in hydro
+type BlockGasStats struct {
+	Height   uint64    `json:"height"`
+	Time     time.Time `json:"time"`
+	GasUsed  uint64    `json:"gasUsed"`
+	GasLimit uint64    `json:"gasLimit"`
+	// Fullness is GasUsed / GasLimit
+	Fullness float64 `json:"fullness"`
+	NumTxs   int     `json:"numTxs"`
+
+	MinGasPrice uint64 `json:"minGasPrice"`
+	P25GasPrice uint64 `json:"p25GasPrice"`
+	P50GasPrice uint64 `json:"p50GasPrice"`
+	P75GasPrice uint64 `json:"p75GasPrice"`
+	P90GasPrice uint64 `json:"p90GasPrice"`
+	MaxGasPrice uint64 `json:"maxGasPrice"`
+}
+
+// GasOracle is gas price suggestion computed over the latest blocks, prices are in hydro
+type GasOracle struct {
+	SafeGasPrice     uint64 `json:"safeGasPrice"`
+	StandardGasPrice uint64 `json:"standardGasPrice"`
+	FastGasPrice     uint64 `json:"fastGasPrice"`
+
+	LatestBlock uint64 `json:"latestBlock"`
+	// Blocks is the number of blocks in window, AvgFullness is average fullness of them
+	Blocks      int       `json:"blocks"`
+	AvgFullness float64   `json:"avgFullness"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// NewBlockGasStats compute gas stats of block from its txs
+func NewBlockGasStats(block *Block) *BlockGasStats {
+	stats := &BlockGasStats{
+		Height:   block.Height,
+		Time:     block.Time,
+		GasUsed:  block.GasUsed,
+		GasLimit: block.GasLimit,
+		NumTxs:   len(block.Txs),
+	}
+	if block.GasLimit > 0 {
+		stats.Fullness = float64(block.GasUsed) / float64(block.GasLimit)
+	}
+	prices := make([]uint64, len(block.Txs))
+	for i, tx := range block.Txs {
+		prices[i] = tx.GasPrice
+	}
+	if len(prices) == 0 {
+		return stats
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i] < prices[j] })
+	stats.MinGasPrice = prices[0]
+	stats.P25GasPrice = percentile(prices, 25)
+	stats.P50GasPrice = percentile(prices, 50)
+	stats.P75GasPrice = percentile(prices, 75)
+	stats.P90GasPrice = percentile(prices, 90)
+	stats.MaxGasPrice = prices[len(prices)-1]
+	return stats
+}
+
+// NewGasOracle suggest gas prices from block stats of window, latest first. Safe, standard and fast
+// are the median of blocks 25th, 50th and 90th percentile. Blocks without txs only count in fullness.
+// While blocks are less than half full any included price is accepted quickly, so standard and fast
+// fall back to safe and median prices.
+func NewGasOracle(history []*BlockGasStats, minGasPrice uint64) *GasOracle {
+	// a height imported again after reorg keep only its latest stats
+	var (
+		window []*BlockGasStats
+		seen   = make(map[uint64]bool)
+	)
+	for _, stats := range history {
+		if !seen[stats.Height] {
+			seen[stats.Height] = true
+			window = append(window, stats)
+		}
+	}
+	oracle := &GasOracle{
+		Blocks:    len(window),
+		UpdatedAt: time.Now(),
+	}
+	var safe, standard, fast []uint64
+	for _, stats := range window {
+		if stats.Height > oracle.LatestBlock {
+			oracle.LatestBlock = stats.Height
+		}
+		oracle.AvgFullness += stats.Fullness
+		if stats.NumTxs == 0 {
+			continue
+		}
+		safe = append(safe, stats.P25GasPrice)
+		standard = append(standard, stats.P50GasPrice)
+		fast = append(fast, stats.P90GasPrice)
+	}
+	if len(window) > 0 {
+		oracle.AvgFullness /= float64(len(window))
+	}
+	oracle.SafeGasPrice = maxUint64(median(safe), minGasPrice)
+	oracle.StandardGasPrice = maxUint64(median(standard), oracle.SafeGasPrice)
+	oracle.FastGasPrice = maxUint64(median(fast), oracle.StandardGasPrice)
+	if oracle.AvgFullness < 0.5 {
+		oracle.StandardGasPrice = oracle.SafeGasPrice
+		oracle.FastGasPrice = maxUint64(median(standard), oracle.SafeGasPrice)
+	}
+	return oracle
+}
+
+// percentile use nearest rank of sorted values
+func percentile(sorted []uint64, p int) uint64 {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func median(values []uint64) uint64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]uint64{}, values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return percentile(sorted, 50)
+}
+
+func maxUint64(a, b uint64) uint64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,59 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBlockGasStats(t *testing.T) {
+	block := &Block{Height: 10, GasUsed: 750, GasLimit: 1000}
+	for _, price := range []uint64{5, 1, 4, 2, 3, 10, 9, 8, 7, 6} {
+		block.Txs = append(block.Txs, &Transaction{GasPrice: price})
+	}
+	stats := NewBlockGasStats(block)
+
+	assert.Equal(t, 0.75, stats.Fullness)
+	assert.Equal(t, 10, stats.NumTxs)
+	assert.Equal(t, uint64(1), stats.MinGasPrice)
+	assert.Equal(t, uint64(3), stats.P25GasPrice)
+	assert.Equal(t, uint64(5), stats.P50GasPrice)
+	assert.Equal(t, uint64(8), stats.P75GasPrice)
+	assert.Equal(t, uint64(9), stats.P90GasPrice)
+	assert.Equal(t, uint64(10), stats.MaxGasPrice)
+
+	empty := NewBlockGasStats(&Block{Height: 11})
+	assert.Equal(t, 0.0, empty.Fullness)
+	assert.Equal(t, uint64(0), empty.P50GasPrice)
+}
+
+func TestNewGasOracle(t *testing.T) {
+	busy := []*BlockGasStats{
+		{Height: 3, Fullness: 0.9, NumTxs: 10, P25GasPrice: 3, P50GasPrice: 5, P90GasPrice: 9},
+		{Height: 2, Fullness: 0.8, NumTxs: 10, P25GasPrice: 2, P50GasPrice: 4, P90GasPrice: 8},
+		{Height: 1, Fullness: 0.7, NumTxs: 10, P25GasPrice: 4, P50GasPrice: 6, P90GasPrice: 10},
+		// old stats of height 2 before reorg
+		{Height: 2, Fullness: 0.1, NumTxs: 1, P25GasPrice: 100, P50GasPrice: 100, P90GasPrice: 100},
+	}
+	oracle := NewGasOracle(busy, 1)
+	assert.Equal(t, 3, oracle.Blocks)
+	assert.Equal(t, uint64(3), oracle.LatestBlock)
+	assert.Equal(t, uint64(3), oracle.SafeGasPrice)
+	assert.Equal(t, uint64(5), oracle.StandardGasPrice)
+	assert.Equal(t, uint64(9), oracle.FastGasPrice)
+
+	// network minimum is the floor
+	oracle = NewGasOracle(busy, 7)
+	assert.Equal(t, uint64(7), oracle.SafeGasPrice)
+	assert.Equal(t, uint64(7), oracle.StandardGasPrice)
+	assert.Equal(t, uint64(9), oracle.FastGasPrice)
+
+	idle := []*BlockGasStats{
+		{Height: 2, Fullness: 0.1, NumTxs: 2, P25GasPrice: 1, P50GasPrice: 2, P90GasPrice: 9},
+		{Height: 1, Fullness: 0},
+	}
+	oracle = NewGasOracle(idle, 1)
+	assert.Equal(t, uint64(1), oracle.SafeGasPrice)
+	assert.Equal(t, uint64(1), oracle.StandardGasPrice)
+	assert.Equal(t, uint64(2), oracle.FastGasPrice)
+}
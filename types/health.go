@@ -0,0 +1,39 @@
+package types
+
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusUnavailable = "unavailable"
+)
+
+// DependencyHealth is the result of a reachability check of Mongo or Redis
+type DependencyHealth struct {
+	Reachable bool    `json:"reachable"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// NodeHealth is the result of a reachability check of a RPC node. Nodes are named by their
+// position in config, e.g. trusted-0, so node URLs are not disclosed.
+type NodeHealth struct {
+	Name        string  `json:"name"`
+	Reachable   bool    `json:"reachable"`
+	LatestBlock uint64  `json:"latestBlock"`
+	LatencyMs   float64 `json:"latencyMs"`
+	Error       string  `json:"error,omitempty"`
+}
+
+// HealthReport is the readiness of an API replica
+type HealthReport struct {
+	Status  string   `json:"status"`
+	Reasons []string `json:"reasons,omitempty"`
+
+	IndexedHeight uint64 `json:"indexedHeight"`
+	ChainHeight   uint64 `json:"chainHeight"`
+	Lag           uint64 `json:"lag"`
+	// LatestBlockAge is the number of seconds since the newest indexed block was produced
+	LatestBlockAge float64 `json:"latestBlockAge"`
+
+	Queues       map[string]int64             `json:"queues"`
+	Dependencies map[string]*DependencyHealth `json:"dependencies"`
+	Nodes        []*NodeHealth                `json:"nodes"`
+}
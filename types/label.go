@@ -0,0 +1,61 @@
+package types
+
+import (
+	"time"
+)
+
+const (
+	LabelCategoryExchange   = "exchange"
+	LabelCategoryBridge     = "bridge"
+	LabelCategoryTeamWallet = "team_wallet"
+	LabelCategoryScam       = "scam"
+	LabelCategoryValidator  = "validator"
+	LabelCategoryDEXRouter  = "dex_router"
+
+	// LabelSourceManual is the source of labels set by admins without one
+	LabelSourceManual = "manual"
+)
+
+// LabelCategories is categories an address label can have
+var LabelCategories = map[string]bool{
+	LabelCategoryExchange:   true,
+	LabelCategoryBridge:     true,
+	LabelCategoryTeamWallet: true,
+	LabelCategoryScam:       true,
+	LabelCategoryValidator:  true,
+	LabelCategoryDEXRouter:  true,
+}
+
+// Label is what is shown next to a labeled address. Source tell who asserted it, e.g. manual or the name
+// of an imported label file, and Confidence is in [0, 1].
+type Label struct {
+	Name       string  `json:"name" bson:"name"`
+	Category   string  `json:"category" bson:"category"`
+	Source     string  `json:"source" bson:"source"`
+	Confidence float64 `json:"confidence" bson:"confidence"`
+}
+
+// AddressLabel is a label of an address, an address has at most one label of a name per category
+type AddressLabel struct {
+	Address string `json:"address" bson:"address"`
+	Label   `bson:",inline"`
+
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// AddressLabelsFilter select labels, empty fields match every label
+type AddressLabelsFilter struct {
+	Pagination *Pagination
+
+	Address  string
+	Category string
+	Name     string
+	Source   string
+}
+
+// LabelImportResult summarize a bulk import, Errors hold the rejected rows by their 1-based position in the file
+type LabelImportResult struct {
+	Imported int            `json:"imported"`
+	Rejected int            `json:"rejected"`
+	Errors   map[int]string `json:"errors,omitempty"`
+}
@@ -20,6 +20,10 @@ type Log struct {
 	BlockHash     string                 `json:"blockHash,omitempty" bson:"blockHash"`
 	Index         uint                   `json:"logIndex,omitempty" bson:"logIndex"`
 	Removed       bool                   `json:"removed,omitempty" bson:"removed"`
+
+	// Ambiguous is set when log was decoded from signatures registry and several signatures match
+	Ambiguous  bool     `json:"ambiguous,omitempty" bson:"ambiguous,omitempty"`
+	Candidates []string `json:"candidates,omitempty" bson:"candidates,omitempty"`
 }
 
 type Receipt struct {
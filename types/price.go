@@ -0,0 +1,82 @@
+// Package types
+package types
+
+import (
+	"math"
+	"math/big"
+	"time"
+)
+
+// PriceTokenKAI is the token of native coin prices, KRC20 prices use checksum contract address as token
+const PriceTokenKAI = "KAI"
+
+const (
+	// PriceSourceMarket quote token from market data providers
+	PriceSourceMarket = "market"
+	// PriceSourceDEX derive token price from reserves of an on-chain pair
+	PriceSourceDEX = "dex"
+)
+
+// TokenPrice is USD price of a token at an hour, Time is start of the hour in UTC and keeps the
+// latest quote received during it
+type TokenPrice struct {
+	Token     string    `json:"token" bson:"token"`
+	Time      time.Time `json:"time" bson:"time"`
+	Price     float64   `json:"price" bson:"price"`
+	Volume24h float64   `json:"volume24h" bson:"volume24h"`
+	MarketCap float64   `json:"marketCap" bson:"marketCap"`
+	Source    string    `json:"source" bson:"source"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// TokenPriceSource configure how price of a KRC20 token is pulled
+type TokenPriceSource struct {
+	Token string `json:"token" bson:"token"`
+	Type  string `json:"type" bson:"type"`
+
+	// Market source, CoinMarketCap is tried first when its id is set
+	CoinMarketCapID int    `json:"coinMarketCapId,omitempty" bson:"coinMarketCapId,omitempty"`
+	CoinGeckoID     string `json:"coinGeckoId,omitempty" bson:"coinGeckoId,omitempty"`
+
+	// DEX source, token is priced against the other token of the pair, which must have a price itself
+	PairAddress string `json:"pairAddress,omitempty" bson:"pairAddress,omitempty"`
+
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// PairReserves is tokens and reserves of an Uniswap V2 like pair
+type PairReserves struct {
+	Token0   string
+	Token1   string
+	Reserve0 *big.Int
+	Reserve1 *big.Int
+}
+
+// PairPrice return USD price of a token from pair reserves and USD price of the quote token
+func PairPrice(reserve, quoteReserve *big.Int, decimals, quoteDecimals int64, quotePrice float64) float64 {
+	if reserve == nil || quoteReserve == nil || reserve.Sign() <= 0 {
+		return 0
+	}
+	amount := TokenAmount(reserve, decimals)
+	if amount == 0 {
+		return 0
+	}
+	return TokenAmount(quoteReserve, quoteDecimals) / amount * quotePrice
+}
+
+// TokenAmount convert raw balance to float amount of token with decimals
+func TokenAmount(balance *big.Int, decimals int64) float64 {
+	if balance == nil {
+		return 0
+	}
+	amount, _ := new(big.Float).Quo(new(big.Float).SetInt(balance), big.NewFloat(math.Pow10(int(decimals)))).Float64()
+	return amount
+}
+
+// PriceChange return percentage change from previous to current price, 0 when previous is unknown
+func PriceChange(current, previous float64) float64 {
+	if previous == 0 {
+		return 0
+	}
+	return (current - previous) / previous * 100
+}
@@ -0,0 +1,62 @@
+// Package types
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPairPrice(t *testing.T) {
+	e18 := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	type testCase struct {
+		name          string
+		reserve       *big.Int
+		quoteReserve  *big.Int
+		decimals      int64
+		quoteDecimals int64
+		quotePrice    float64
+		price         float64
+	}
+	cases := []testCase{
+		{
+			name:          "same decimals",
+			reserve:       new(big.Int).Mul(big.NewInt(1000), e18),
+			quoteReserve:  new(big.Int).Mul(big.NewInt(4000), e18),
+			decimals:      18,
+			quoteDecimals: 18,
+			quotePrice:    0.05,
+			price:         0.2,
+		},
+		{
+			name:          "quote with 6 decimals",
+			reserve:       new(big.Int).Mul(big.NewInt(500), e18),
+			quoteReserve:  big.NewInt(1000 * 1000000),
+			decimals:      18,
+			quoteDecimals: 6,
+			quotePrice:    1,
+			price:         2,
+		},
+		{
+			name:          "empty pair",
+			reserve:       big.NewInt(0),
+			quoteReserve:  big.NewInt(0),
+			decimals:      18,
+			quoteDecimals: 18,
+			quotePrice:    1,
+			price:         0,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.InDelta(t, c.price, PairPrice(c.reserve, c.quoteReserve, c.decimals, c.quoteDecimals, c.quotePrice), 1e-9)
+		})
+	}
+}
+
+func TestPriceChange(t *testing.T) {
+	assert.InDelta(t, 10.0, PriceChange(1.1, 1), 1e-9)
+	assert.InDelta(t, -50.0, PriceChange(0.5, 1), 1e-9)
+	assert.Equal(t, 0.0, PriceChange(1, 0))
+}
@@ -0,0 +1,30 @@
+// Package types
+package types
+
+import "time"
+
+const (
+	ProxyTypeEIP1967 = "EIP1967"
+	ProxyTypeEIP1822 = "EIP1822"
+	ProxyTypeBeacon  = "Beacon"
+)
+
+// ProxyInfo is what stored in a proxy's standard storage slots
+type ProxyInfo struct {
+	Type           string `json:"type"`
+	Implementation string `json:"implementation"`
+	Beacon         string `json:"beacon,omitempty"`
+}
+
+// ProxyUpgrade record an implementation change of a proxy contract
+type ProxyUpgrade struct {
+	Address        string `json:"address" bson:"address"`
+	ProxyType      string `json:"proxyType" bson:"proxyType"`
+	Implementation string `json:"implementation" bson:"implementation"`
+	Previous       string `json:"previous,omitempty" bson:"previous,omitempty"`
+	Beacon         string `json:"beacon,omitempty" bson:"beacon,omitempty"`
+	// BlockHeight and TxHash are empty when the upgrade was found by reading storage instead of an event
+	BlockHeight uint64    `json:"blockHeight,omitempty" bson:"blockHeight,omitempty"`
+	TxHash      string    `json:"txHash,omitempty" bson:"txHash,omitempty"`
+	CreatedAt   time.Time `json:"createdAt" bson:"createdAt"`
+}
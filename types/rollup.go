@@ -0,0 +1,54 @@
+// Package types
+package types
+
+import "time"
+
+const (
+	RollupIntervalHour = "hour"
+	RollupIntervalDay  = "day"
+)
+
+// Rollup is chain activity aggregated over an hour or a day, Time is start of the bucket in UTC
+type Rollup struct {
+	Interval string    `json:"interval" bson:"interval"`
+	Time     time.Time `json:"time" bson:"time"`
+
+	Blocks              uint64 `json:"blocks" bson:"blocks"`
+	Txs                 uint64 `json:"txs" bson:"txs"`
+	ActiveAddresses     uint64 `json:"activeAddresses" bson:"activeAddresses"`
+	NewAddresses        uint64 `json:"newAddresses" bson:"newAddresses"`
+	ContractDeployments uint64 `json:"contractDeployments" bson:"contractDeployments"`
+	GasUsed             uint64 `json:"gasUsed" bson:"gasUsed"`
+	// AvgGasPrice is in hydro, Fees is total tx fee in hydro
+	AvgGasPrice float64 `json:"avgGasPrice" bson:"avgGasPrice"`
+	Fees        string  `json:"fees" bson:"fees"`
+	// AvgBlockTime is in seconds
+	AvgBlockTime float64 `json:"avgBlockTime" bson:"avgBlockTime"`
+
+	// Dirty is set when blocks of the bucket are imported after it was built
+	Dirty     bool      `json:"-" bson:"dirty"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// RollupStart return start of the bucket which contain t
+func RollupStart(interval string, t time.Time) time.Time {
+	t = t.UTC()
+	if interval == RollupIntervalDay {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return t.Truncate(time.Hour)
+}
+
+// RollupEnd return start of the bucket next to the one start at start
+func RollupEnd(interval string, start time.Time) time.Time {
+	if interval == RollupIntervalDay {
+		return start.AddDate(0, 0, 1)
+	}
+	return start.Add(time.Hour)
+}
+
+// AddressFirstSeen is time of the first tx sent or received by an address
+type AddressFirstSeen struct {
+	Address string    `json:"address" bson:"address"`
+	Time    time.Time `json:"time" bson:"time"`
+}
@@ -0,0 +1,22 @@
+package types
+
+const (
+	SearchTypeBlock     = "block"
+	SearchTypeTx        = "tx"
+	SearchTypeValidator = "validator"
+	SearchTypeToken     = "token"
+	SearchTypeContract  = "contract"
+	SearchTypeAddress   = "address"
+)
+
+// SearchResult is a hit of the universal search, ID is the block height, hash or address the entity page is keyed by
+type SearchResult struct {
+	Type    string `json:"type"`
+	ID      string `json:"id"`
+	Name    string `json:"name,omitempty"`
+	Symbol  string `json:"symbol,omitempty"`
+	Logo    string `json:"logo,omitempty"`
+	Preview string `json:"preview"`
+	// Labels is set on hits identified by an address
+	Labels []*Label `json:"labels,omitempty"`
+}
@@ -0,0 +1,25 @@
+// Package types
+package types
+
+import "time"
+
+const (
+	SignatureTypeFunction = "function"
+	SignatureTypeEvent    = "event"
+
+	SignatureSourceBundled  = "bundled"
+	SignatureSourceVerified = "verified"
+)
+
+// Signature map a 4-byte function selector or an event topic to its text signature
+type Signature struct {
+	// Hash is the 4-byte selector of a function or the topic hash of an event
+	Hash string `json:"hash" bson:"hash"`
+	Type string `json:"type" bson:"type"`
+	// Text is the canonical signature, e.g. transfer(address,uint256)
+	Text string `json:"text" bson:"text"`
+	// ABI is the JSON ABI entry when seeded from a verified contract, which keep argument names and indexed flags
+	ABI       string    `json:"abi,omitempty" bson:"abi,omitempty"`
+	Source    string    `json:"source" bson:"source"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
@@ -0,0 +1,59 @@
+package types
+
+import (
+	"strings"
+	"time"
+)
+
+// Stream event types, also used as subscription topics
+const (
+	StreamBlocks     = "blocks"
+	StreamTxs        = "txs"
+	StreamTransfers  = "transfers"
+	StreamValidators = "validators"
+)
+
+// TxStatusPending mark txs pushed from node mempool, confirmed ones carry status of their receipt
+const TxStatusPending = 2
+
+// StreamEvent is pushed to subscribers of realtime API
+type StreamEvent struct {
+	Type string `json:"type"`
+	// Keys narrow down subscription, e.g address of tx or contract of token transfer
+	Keys []string    `json:"keys,omitempty"`
+	Data interface{} `json:"data"`
+	Time time.Time   `json:"time"`
+}
+
+// NewStreamEvent create event with lower-cased keys so subscriptions are case insensitive
+func NewStreamEvent(eventType string, data interface{}, keys ...string) *StreamEvent {
+	e := &StreamEvent{
+		Type: eventType,
+		Data: data,
+		Time: time.Now(),
+	}
+	for _, k := range keys {
+		if k != "" {
+			e.Keys = append(e.Keys, strings.ToLower(k))
+		}
+	}
+	return e
+}
+
+// Match check if event belongs to topic. Topic is either `type` or `type:key`
+func (e *StreamEvent) Match(topic string) bool {
+	parts := strings.SplitN(topic, ":", 2)
+	if parts[0] != e.Type {
+		return false
+	}
+	if len(parts) == 1 || parts[1] == "" {
+		return true
+	}
+	key := strings.ToLower(parts[1])
+	for _, k := range e.Keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
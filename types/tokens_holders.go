@@ -1,15 +1,16 @@
 package types
 
 type KRC20Holder struct {
-	TokenName       string  `json:"tokenName,omitempty" bson:"tokenName,omitempty"`
-	TokenSymbol     string  `json:"tokenSymbol,omitempty" bson:"tokenSymbol,omitempty"`
-	TokenDecimals   int64   `json:"tokenDecimals" bson:"tokenDecimals,omitempty"`
-	Logo            string  `json:"logo,omitempty" bson:"-"`
-	ContractAddress string  `json:"contractAddress,omitempty" bson:"contractAddress,omitempty"`
-	HolderAddress   string  `json:"holderAddress" bson:"holderAddress,omitempty"`
-	HolderName      string  `json:"holderName" bson:"-"`
-	BalanceString   string  `json:"balance" bson:"balance,omitempty"`
-	BalanceFloat    float64 `json:"-" bson:"balanceFloat,omitempty"`
+	TokenName       string   `json:"tokenName,omitempty" bson:"tokenName,omitempty"`
+	TokenSymbol     string   `json:"tokenSymbol,omitempty" bson:"tokenSymbol,omitempty"`
+	TokenDecimals   int64    `json:"tokenDecimals" bson:"tokenDecimals,omitempty"`
+	Logo            string   `json:"logo,omitempty" bson:"-"`
+	ContractAddress string   `json:"contractAddress,omitempty" bson:"contractAddress,omitempty"`
+	HolderAddress   string   `json:"holderAddress" bson:"holderAddress,omitempty"`
+	HolderName      string   `json:"holderName" bson:"-"`
+	HolderLabels    []*Label `json:"holderLabels,omitempty" bson:"-"`
+	BalanceString   string   `json:"balance" bson:"balance,omitempty"`
+	BalanceFloat    float64  `json:"-" bson:"balanceFloat,omitempty"`
 
 	UpdatedAt int64 `json:"updatedAt" bson:"updatedAt,omitempty"`
 }
@@ -34,6 +35,8 @@ type KRC721Holder struct {
 	ContractAddress string `json:"contractAddress" bson:"contractAddress"`
 	TokenID         string `json:"tokenID" bson:"tokenID"`
 
+	Labels []*Label `json:"labels,omitempty" bson:"-"`
+
 	CreatedAt int64 `json:"createdAt" bson:"createdAt,omitempty"`
 	UpdatedAt int64 `json:"updatedAt" bson:"updatedAt,omitempty"`
 }
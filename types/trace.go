@@ -0,0 +1,97 @@
+// Package types
+package types
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	CallTypeCall         = "CALL"
+	CallTypeCallCode     = "CALLCODE"
+	CallTypeCreate       = "CREATE"
+	CallTypeCreate2      = "CREATE2"
+	CallTypeSelfDestruct = "SELFDESTRUCT"
+)
+
+// CallFrame is a call of callTracer output, nested calls are in Calls
+type CallFrame struct {
+	Type    string       `json:"type"`
+	From    string       `json:"from"`
+	To      string       `json:"to"`
+	Value   string       `json:"value"`
+	Gas     string       `json:"gas"`
+	GasUsed string       `json:"gasUsed"`
+	Input   string       `json:"input"`
+	Output  string       `json:"output"`
+	Error   string       `json:"error"`
+	Calls   []*CallFrame `json:"calls"`
+}
+
+// InternalCall is a call made by a contract which move native KAI, create a contract or self destruct
+type InternalCall struct {
+	TxHash      string    `json:"txHash" bson:"txHash"`
+	BlockHeight uint64    `json:"blockHeight" bson:"blockHeight"`
+	Time        time.Time `json:"time" bson:"time"`
+	Type        string    `json:"type" bson:"type"`
+	From        string    `json:"from" bson:"from"`
+	To          string    `json:"to" bson:"to"`
+	// Value is amount in hydro
+	Value string `json:"value" bson:"value"`
+	// Depth is 1 for calls made by the tx receiver
+	Depth int `json:"depth" bson:"depth"`
+	// Path is the index of each call from the tx root, e.g. "0_2" is the third call of the first call
+	Path string `json:"path" bson:"path"`
+	// Index is the execution order of the call in the tx
+	Index int    `json:"index" bson:"index"`
+	Error string `json:"error,omitempty" bson:"error,omitempty"`
+}
+
+// InternalCalls flatten nested calls of the tx root, keep value-bearing calls, contract creations
+// and self destructs. Calls reverted with an ancestor keep the ancestor error.
+func (f *CallFrame) InternalCalls() []*InternalCall {
+	var calls []*InternalCall
+	for i, child := range f.Calls {
+		calls = child.collectInternalCalls(calls, 1, strconv.Itoa(i), f.Error)
+	}
+	for i := range calls {
+		calls[i].Index = i
+	}
+	return calls
+}
+
+func (f *CallFrame) collectInternalCalls(calls []*InternalCall, depth int, path, parentErr string) []*InternalCall {
+	callErr := f.Error
+	if parentErr != "" {
+		callErr = parentErr
+	}
+	value := hexToDecimal(f.Value)
+	callType := strings.ToUpper(f.Type)
+	switch {
+	case callType == CallTypeCreate, callType == CallTypeCreate2, callType == CallTypeSelfDestruct,
+		(callType == CallTypeCall || callType == CallTypeCallCode) && value != "0":
+		calls = append(calls, &InternalCall{
+			Type:  callType,
+			From:  f.From,
+			To:    f.To,
+			Value: value,
+			Depth: depth,
+			Path:  path,
+			Error: callErr,
+		})
+	}
+	for i, child := range f.Calls {
+		calls = child.collectInternalCalls(calls, depth+1, path+"_"+strconv.Itoa(i), callErr)
+	}
+	return calls
+}
+
+func hexToDecimal(s string) string {
+	value, ok := new(big.Int).SetString(strings.TrimPrefix(s, "0x"), 16)
+	if !ok {
+		return "0"
+	}
+	return value.String()
+}
@@ -0,0 +1,34 @@
+// Package types
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallFrame_InternalCalls(t *testing.T) {
+	trace := `{
+		"type": "CALL", "from": "0xa", "to": "0xb", "value": "0x0",
+		"calls": [
+			{"type": "CALL", "from": "0xb", "to": "0xc", "value": "0xde0b6b3a7640000"},
+			{"type": "STATICCALL", "from": "0xb", "to": "0xd",
+				"calls": [{"type": "CALL", "from": "0xd", "to": "0xe", "value": "0x0"}]},
+			{"type": "CREATE2", "from": "0xb", "to": "0xf", "value": "0x0",
+				"calls": [{"type": "SELFDESTRUCT", "from": "0xf", "to": "0xa", "value": "0x2a"}]},
+			{"type": "CALL", "from": "0xb", "to": "0xc", "value": "0x1", "error": "execution reverted",
+				"calls": [{"type": "CALL", "from": "0xc", "to": "0xd", "value": "0x2"}]}
+		]
+	}`
+	var frame CallFrame
+	assert.Nil(t, json.Unmarshal([]byte(trace), &frame))
+
+	assert.Equal(t, []*InternalCall{
+		{Type: CallTypeCall, From: "0xb", To: "0xc", Value: "1000000000000000000", Depth: 1, Path: "0", Index: 0},
+		{Type: CallTypeCreate2, From: "0xb", To: "0xf", Value: "0", Depth: 1, Path: "2", Index: 1},
+		{Type: CallTypeSelfDestruct, From: "0xf", To: "0xa", Value: "42", Depth: 2, Path: "2_0", Index: 2},
+		{Type: CallTypeCall, From: "0xb", To: "0xc", Value: "1", Depth: 1, Path: "3", Index: 3, Error: "execution reverted"},
+		{Type: CallTypeCall, From: "0xc", To: "0xd", Value: "2", Depth: 2, Path: "3_0", Index: 4, Error: "execution reverted"},
+	}, frame.InternalCalls())
+}
@@ -41,6 +41,10 @@ type FunctionCall struct {
 	MethodID   string                 `json:"methodID"`
 	MethodName string                 `json:"methodName"`
 	Arguments  map[string]interface{} `json:"arguments"`
+
+	// Ambiguous is set when input was decoded from signatures registry and several signatures match
+	Ambiguous  bool     `json:"ambiguous,omitempty"`
+	Candidates []string `json:"candidates,omitempty"`
 }
 
 type TransactionByAddress struct {
@@ -56,6 +56,8 @@ type Validator struct {
 	MaxChangeRate         string       `json:"maxChangeRate" bson:"maxChangeRate,omitempty"`
 	SigningInfo           *SigningInfo `json:"signingInfo" bson:"signingInfo,omitempty"`
 	Delegators            []*Delegator `json:"delegators,omitempty" bson:"delegators,omitempty"`
+	// Labels is labels of the owner and the staking contract
+	Labels []*Label `json:"labels,omitempty" bson:"-"`
 }
 
 type RPCValidator struct {
@@ -86,10 +88,11 @@ type RPCDelegator struct {
 }
 
 type Delegator struct {
-	ValidatorSMCAddress string `json:"validatorSMCAddress" bson:"validatorSMCAddress,omitempty"`
-	Address             string `json:"address" bson:"address,omitempty"`
-	StakedAmount        string `json:"stakedAmount" bson:"stakedAmount,omitempty"`
-	Reward              string `json:"reward" bson:"reward,omitempty"`
+	ValidatorSMCAddress string   `json:"validatorSMCAddress" bson:"validatorSMCAddress,omitempty"`
+	Address             string   `json:"address" bson:"address,omitempty"`
+	StakedAmount        string   `json:"stakedAmount" bson:"stakedAmount,omitempty"`
+	Reward              string   `json:"reward" bson:"reward,omitempty"`
+	Labels              []*Label `json:"labels,omitempty" bson:"-"`
 }
 
 type SlashEvents struct {
@@ -0,0 +1,29 @@
+package types
+
+import "time"
+
+// Target collections that a block mismatch affects
+const (
+	VerifyTargetBlock    = "block"
+	VerifyTargetTxs      = "txs"
+	VerifyTargetReceipts = "receipts"
+)
+
+// BlockMismatch describe a failed verification check between database and network block
+type BlockMismatch struct {
+	Check    string `json:"check" bson:"check"`
+	Target   string `json:"target" bson:"target"`
+	TxHash   string `json:"txHash,omitempty" bson:"txHash,omitempty"`
+	Expected string `json:"expected" bson:"expected"`
+	Actual   string `json:"actual" bson:"actual"`
+}
+
+// VerifyAudit keep what verifier found and repaired for a block
+type VerifyAudit struct {
+	BlockHeight uint64           `json:"blockHeight" bson:"blockHeight"`
+	BlockHash   string           `json:"blockHash" bson:"blockHash"`
+	Mismatches  []*BlockMismatch `json:"mismatches" bson:"mismatches"`
+	Repaired    []string         `json:"repaired" bson:"repaired"`
+	RepairError string           `json:"repairError,omitempty" bson:"repairError,omitempty"`
+	CreatedAt   time.Time        `json:"createdAt" bson:"createdAt"`
+}
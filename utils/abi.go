@@ -3,6 +3,7 @@ package utils
 
 import (
 	"encoding/base64"
+	"encoding/json"
 )
 
 func EncodeABI(abi string) string {
@@ -12,3 +13,55 @@ func EncodeABI(abi string) string {
 func DecodeABI(encodedABI string) ([]byte, error) {
 	return base64.StdEncoding.DecodeString(encodedABI)
 }
+
+type abiEntry struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// MergeABI merge base64 encoded ABIs of a proxy and its implementation. Implementation entries
+// take precedence, proxy entries are kept when no implementation entry has the same type and name.
+func MergeABI(proxyABI, implABI string) (string, error) {
+	var proxyEntries, implEntries []json.RawMessage
+	if err := unmarshalABI(proxyABI, &proxyEntries); err != nil {
+		return "", err
+	}
+	if err := unmarshalABI(implABI, &implEntries); err != nil {
+		return "", err
+	}
+	merged := implEntries
+	seen := make(map[abiEntry]bool, len(implEntries))
+	for _, raw := range implEntries {
+		var e abiEntry
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return "", err
+		}
+		seen[e] = true
+	}
+	for _, raw := range proxyEntries {
+		var e abiEntry
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return "", err
+		}
+		if seen[e] {
+			continue
+		}
+		merged = append(merged, raw)
+	}
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+	return EncodeABI(string(data)), nil
+}
+
+func unmarshalABI(encodedABI string, entries *[]json.RawMessage) error {
+	if encodedABI == "" {
+		return nil
+	}
+	data, err := DecodeABI(encodedABI)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, entries)
+}
@@ -0,0 +1,29 @@
+// Package utils
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeABI(t *testing.T) {
+	proxyABI := EncodeABI(`[{"type":"function","name":"upgradeTo"},{"type":"fallback"},{"type":"function","name":"admin"}]`)
+	implABI := EncodeABI(`[{"type":"function","name":"transfer"},{"type":"function","name":"admin","inputs":[]}]`)
+
+	merged, err := MergeABI(proxyABI, implABI)
+	assert.Nil(t, err)
+	data, err := DecodeABI(merged)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `[{"type":"function","name":"transfer"},{"type":"function","name":"admin","inputs":[]},{"type":"function","name":"upgradeTo"},{"type":"fallback"}]`, string(data))
+
+	// unverified proxy only expose implementation entries
+	merged, err = MergeABI("", implABI)
+	assert.Nil(t, err)
+	data, err = DecodeABI(merged)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `[{"type":"function","name":"transfer"},{"type":"function","name":"admin","inputs":[]}]`, string(data))
+
+	_, err = MergeABI("not base64", implABI)
+	assert.NotNil(t, err)
+}